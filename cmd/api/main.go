@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -23,6 +24,7 @@ import (
 	v2handler "github.com/damoang/angple-backend/internal/handler/v2"
 	"github.com/damoang/angple-backend/internal/middleware"
 	"github.com/damoang/angple-backend/internal/migration"
+	"github.com/damoang/angple-backend/internal/payment"
 	"github.com/damoang/angple-backend/internal/plugin"
 	pluginstoreHandler "github.com/damoang/angple-backend/internal/pluginstore/handler"
 	pluginstoreRepo "github.com/damoang/angple-backend/internal/pluginstore/repository"
@@ -39,7 +41,9 @@ import (
 	pkges "github.com/damoang/angple-backend/pkg/elasticsearch"
 	"github.com/damoang/angple-backend/pkg/i18n"
 	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/damoang/angple-backend/pkg/license"
 	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	pkgmailer "github.com/damoang/angple-backend/pkg/mailer"
 	pkgredis "github.com/damoang/angple-backend/pkg/redis"
 	pkgsphinx "github.com/damoang/angple-backend/pkg/sphinx"
 	pkgstorage "github.com/damoang/angple-backend/pkg/storage"
@@ -112,6 +116,24 @@ func filterItems(items []map[string]any, blockedIDs []string) []map[string]any {
 	return filtered
 }
 
+// hookString Hook 결과에서 문자열 값 조회 (없거나 타입이 다르면 fallback)
+func hookString(data map[string]interface{}, key, fallback string) string {
+	if v, ok := data[key].(string); ok {
+		return v
+	}
+	return fallback
+}
+
+// applyContentHook 렌더링 직전 item["content"]에 플러그인 콘텐츠 필터 적용
+func applyContentHook(hm *plugin.HookManager, event string, item map[string]any, data map[string]interface{}) {
+	content, ok := item["content"].(string)
+	if !ok || content == "" {
+		return
+	}
+	data["content"] = content
+	item["content"] = hookString(hm.Apply(event, data), "content", content)
+}
+
 func main() {
 	dotenvFiles := config.LoadDotEnv()
 
@@ -136,7 +158,7 @@ func main() {
 	// MySQL 연결 (DNS 실패 등 일시적 장애에 대비하여 최대 5회 재시도)
 	var db *gorm.DB
 	for attempt := 1; attempt <= 5; attempt++ {
-		db, err = initDB(cfg)
+		db, err = initDB(&cfg.Database)
 		if err == nil {
 			break
 		}
@@ -203,25 +225,27 @@ func main() {
 		}
 	}
 
-	// S3-compatible storage
-	var s3Client *pkgstorage.S3Client
-	if cfg.Storage.Enabled && cfg.Storage.Bucket != "" {
-		var s3Err error
-		s3Client, s3Err = pkgstorage.NewS3Client(pkgstorage.S3Config{
-			Endpoint:        cfg.Storage.Endpoint,
-			Region:          cfg.Storage.Region,
-			AccessKeyID:     cfg.Storage.AccessKeyID,
-			SecretAccessKey: cfg.Storage.SecretAccessKey,
-			Bucket:          cfg.Storage.Bucket,
-			CDNURL:          cfg.Storage.CDNURL,
-			BasePath:        cfg.Storage.BasePath,
-			ForcePathStyle:  cfg.Storage.ForcePathStyle,
-		})
-		if s3Err != nil {
-			pkglogger.Info("Warning: S3 storage init failed: %v (continuing without S3)", s3Err)
-			s3Client = nil
+	// Media storage (S3-compatible or local disk)
+	var store pkgstorage.Backend
+	if backend := cfg.StorageBackend(); backend != "" {
+		opened, storeErr := pkgstorage.Open(cfg.StorageOptions())
+		if storeErr != nil {
+			pkglogger.Info("Warning: %s storage init failed: %v (continuing without storage)", backend, storeErr)
+		} else {
+			store = opened
+			pkglogger.Info("Connected to %s storage", backend)
+		}
+	}
+
+	// Outbound mail (가입 인증, 비밀번호 재설정)
+	var mailSender pkgmailer.Mailer
+	if backend := cfg.MailBackend(); backend != "" {
+		opened, mailErr := pkgmailer.Open(cfg.MailerOptions())
+		if mailErr != nil {
+			pkglogger.Info("Warning: %s mailer init failed: %v (continuing without mail)", backend, mailErr)
 		} else {
-			pkglogger.Info("Connected to S3 storage")
+			mailSender = opened
+			pkglogger.Info("Mail backend: %s", backend)
 		}
 	}
 
@@ -242,9 +266,9 @@ func main() {
 	// Ban check middleware (제재 회원 글/댓글 작성 차단)
 	banCheck := middleware.BanCheck(db)
 
-	// Plugin HookManager
+	// Plugin HookManager (코어 쓰기 경로와 플러그인 Manager가 공유)
 	pluginLogger := plugin.NewDefaultLogger("plugin")
-	_ = plugin.NewHookManager(pluginLogger)
+	hookManager := plugin.NewHookManager(pluginLogger)
 
 	// Gin 라우터 생성
 	router := gin.Default()
@@ -294,6 +318,20 @@ func main() {
 		// router.Use(middleware.WriteRateLimit(redisClient, middleware.WriteRateLimitConfig(), "/api/v2/media"))
 	}
 
+	// 내부 서명 인증 (SvelteKit SSR, CloudFront → Backend). 키가 없으면 비활성
+	internalAuth := middleware.NewInternalAuth(cfg.InternalAuth.Keys,
+		time.Duration(cfg.InternalAuth.MaxSkew)*time.Second, redisClient)
+	if internalAuth == nil {
+		pkglogger.Info("Internal auth disabled (INTERNAL_AUTH_KEYS not set)")
+	}
+	router.Use(internalAuth.Middleware())
+
+	// 관리자 라우트 2단계 인증 요구 (REQUIRE_ADMIN_2FA)
+	middleware.SetAdminMFARequired(cfg.TwoFactor.RequireForAdmin)
+	if cfg.TwoFactor.RequireForAdmin {
+		pkglogger.Info("Admin routes require two-factor login")
+	}
+
 	// Prometheus metrics
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
@@ -347,6 +385,40 @@ func main() {
 		v2UserRepo := v2repo.NewUserRepository(db)
 		siteRepo := repository.NewSiteRepository(db)
 
+		// 멀티테넌트: 서브도메인/커스텀 도메인 → 사이트 → 테넌트 DB (shared/schema/dedicated)
+		// v2 게시판/게시글/댓글은 site_id로 격리되며, schema/dedicated 사이트는 별도 연결 풀을 사용한다.
+		tenantDBResolver := middleware.NewTenantDBResolver(db)
+		tenantDBResolver.SetConnector(func(host string, port int, database string) (*gorm.DB, error) {
+			tenantDBCfg := cfg.Database
+			if host != "" {
+				tenantDBCfg.Host = host
+			}
+			if port > 0 {
+				tenantDBCfg.Port = port
+			}
+			tenantDBCfg.DBName = database
+			tenantDBCfg.MaxOpenConns = min(tenantDBCfg.MaxOpenConns, 10)
+			tenantDBCfg.MaxIdleConns = min(tenantDBCfg.MaxIdleConns, 2)
+			return initDB(&tenantDBCfg)
+		})
+		if err := repository.MigrateTenantColumns(db); err != nil {
+			pkglogger.Info("Warning: tenant site_id migration failed: %v (tenant routing disabled)", err)
+		} else if err := repository.RegisterTenantScope(db); err != nil {
+			pkglogger.Info("Warning: tenant scope registration failed: %v (tenant routing disabled)", err)
+		} else {
+			router.Use(middleware.TenantMiddleware(siteRepo, cfg.Server.BaseDomain, tenantDBResolver))
+		}
+
+		// 테넌트 사용량 집계 (API 호출/전송량/방문자 → Redis → 일별 SiteUsage)
+		usageMeter := service.NewUsageMeter(db, redisClient)
+		router.Use(middleware.UsageMetering(usageMeter))
+		usageMeter.RegisterHooks(hookManager)
+		usageMeter.Start()
+		defer usageMeter.Stop()
+
+		// 테넌트 플랜 한도 (PlanLimits): 게시판/게시글/업로드/플러그인/회원 가입 시 검사
+		quotaSvc := service.NewQuotaService(siteRepo, db, tenantDBResolver, redisClient)
+
 		// Sphinx full-text search (SphinxQL on port 9306)
 		sphinxHost := os.Getenv("SPHINX_HOST")
 		if sphinxHost == "" {
@@ -377,6 +449,31 @@ func main() {
 		gnuMemberRepo := gnurepo.NewMemberRepository(db)
 		scheduledDeleteRepo := gnurepo.NewScheduledDeleteRepository(db)
 
+		// Elasticsearch 색인 파이프라인 (ES 비활성 시 searchIndexer는 nil → Enqueue no-op)
+		var searchSvc *service.SearchService
+		var searchIndexer *service.SearchIndexer
+		if esClient != nil {
+			searchSvc = service.NewSearchService(esClient, db)
+			searchIndexer = service.NewSearchIndexer(searchSvc, redisClient)
+			searchIndexer.Start()
+			defer searchIndexer.Stop()
+		}
+
+		// 업로드 미디어 중복 제거/참조 추적 (게시글 작성·수정·삭제 훅으로 참조 갱신, 미참조 객체는 유예 후 GC)
+		var mediaLibrary *service.MediaLibrary
+		if store != nil {
+			mediaRepo := repository.NewMediaRepository(db)
+			if err := mediaRepo.AutoMigrate(); err != nil {
+				pkglogger.Info("Warning: media tables migration failed: %v", err)
+			}
+			mediaLibrary = service.NewMediaLibrary(mediaRepo, store, service.DefaultMediaGCGrace)
+			mediaLibrary.SetUsageMeter(usageMeter)
+			mediaLibrary.SetQuotaService(quotaSvc)
+			mediaLibrary.RegisterHooks(hookManager)
+			mediaLibrary.Start()
+			defer mediaLibrary.Stop()
+		}
+
 		// v2 Core API
 		v2PostRepo := v2repo.NewPostRepository(db)
 		v2CommentRepo := v2repo.NewCommentRepository(db)
@@ -386,27 +483,45 @@ func main() {
 		// 리비전
 		v2RevisionRepo := v2repo.NewRevisionRepository(db)
 
+		// 알림 (g5_na_noti): 알림 설정 확인 + WebSocket 실시간 전송
+		notiPrefRepo := gnurepo.NewNotiPreferenceRepository(db)
+		notiRepo := service.NewNotiDispatcher(gnurepo.NewNotiRepository(db), notiPrefRepo, wsHub)
+		notiRepo.SetPayloadFormatter(handler.NotificationPayload)
+
 		// 권한 체크
 		permChecker := middleware.NewDBBoardPermissionChecker(v2BoardRepo)
 		v2Handler := v2handler.NewV2Handler(v2UserRepo, v2PostRepo, v2CommentRepo, v2BoardRepo, permChecker)
 		v2Handler.SetPointRepository(v2PointRepo)
 		v2Handler.SetRevisionRepository(v2RevisionRepo)
-		v2Handler.SetNotiRepository(gnurepo.NewNotiRepository(db))
-		v2Handler.SetNotiPreferenceRepository(gnurepo.NewNotiPreferenceRepository(db))
+		v2Handler.SetNotiRepository(notiRepo)
+		v2Handler.SetNotiPreferenceRepository(notiPrefRepo)
 		v2Handler.SetGnuDB(db)
 		v2Handler.SetBlockRepository(v2repo.NewBlockRepository(db))
+		v2Handler.SetHookManager(hookManager)
+		v2Handler.SetQuotaService(quotaSvc)
 
 		// XP: DI into V2Handler (set after expRepo is created below)
 
-		v2routes.Setup(router, v2Handler, jwtManager, permChecker, db)
-		v2routes.SetupAdminPosts(router, v2Handler, jwtManager)
+		// 외부 연동용 API 키 인증 (X-API-Key / Authorization: ApiKey)
+		oauthService := service.NewOAuthService(db, jwtManager)
+		oauthService.SetQuotaService(quotaSvc)
+		totpIssuer := cfg.TwoFactor.Issuer
+		if totpIssuer == "" {
+			totpIssuer = "Angple"
+		}
+		twoFactorSvc := v2svc.NewTwoFactorService(v2repo.NewTwoFactorRepository(db), jwtManager, totpIssuer)
+		oauthService.SetTwoFactorService(twoFactorSvc)
+		apiKeyAuth := middleware.NewAPIKeyAuth(oauthService, redisClient)
+
+		v2routes.Setup(router, v2Handler, jwtManager, permChecker, db, apiKeyAuth)
+		v2routes.SetupAdminPosts(router, v2Handler, jwtManager, apiKeyAuth)
 
 		// MyPage routes (point, exp, posts, comments, stats)
 		v2ExpRepo := v2repo.NewExpRepository(db)
 		gnuPointRepo := v2repo.NewGnuboardPointRepository(db)
 		pointHandler := v2handler.NewPointHandler(gnuPointRepo)
 		expHandler := v2handler.NewExpHandler(v2ExpRepo)
-		expHandler.SetNotiRepository(gnurepo.NewNotiRepository(db))
+		expHandler.SetNotiRepository(notiRepo)
 
 		// Point config + write repos
 		pointConfigRepo := v2repo.NewPointConfigRepository(db)
@@ -431,13 +546,36 @@ func main() {
 
 		// v2 Auth (with ExpRepo for daily login XP + auto-promotion on login)
 		v2AuthSvc := v2svc.NewV2AuthService(v2UserRepo, jwtManager, v2ExpRepo)
-		v2AuthSvc.SetPromotionDeps(db, gnurepo.NewNotiRepository(db))
+		v2AuthSvc.SetPromotionDeps(db, notiRepo)
+		v2AuthSvc.SetSessionRepository(v2repo.NewSessionRepository(db))
+		v2AuthSvc.SetLoginProtection(v2svc.NewLoginGuard(redisClient, v2svc.DefaultLoginGuardConfig()),
+			v2repo.NewLoginAttemptRepository(db))
+		v2AuthSvc.SetTwoFactorService(twoFactorSvc)
+		v2AuthSvc.SetExternalAccountResolver(oauthService)
+		oauthService.SetSessionService(v2AuthSvc)
+		requireEmailVerification := cfg.Registration.RequireEmailVerification
+		if requireEmailVerification && mailSender == nil {
+			pkglogger.Info("Warning: email verification required but no mail backend configured (verification disabled)")
+			requireEmailVerification = false
+		}
+		v2AuthSvc.SetRegistration(v2repo.NewUserTokenRepository(db), mailSender, v2svc.RegistrationPolicy{
+			Closed:                   cfg.Registration.Closed,
+			Level:                    cfg.Registration.Level,
+			RequireEmailVerification: requireEmailVerification,
+			LinkBaseURL:              cfg.Registration.LinkBaseURL,
+			ReservedNames:            cfg.Registration.ReservedNames,
+			BlockedEmailDomains:      cfg.Registration.BlockedEmailDomains,
+		})
+		v2AuthSvc.SetHookManager(hookManager)
+		defer v2AuthSvc.WaitPendingMail()
 		v2AuthHandler := v2handler.NewV2AuthHandler(v2AuthSvc)
 		v2routes.SetupAuth(router, v2AuthHandler, jwtManager)
 
 		// v1 compatibility routes (frontend calls /api/v1/*)
 		v1Auth := router.Group("/api/v1/auth")
 		v1Auth.POST("/login", v2AuthHandler.Login)
+		v1Auth.POST("/2fa/verify", v2AuthHandler.VerifyTwoFactor)
+		v1Auth.POST("/register", v2AuthHandler.Register)
 		v1Auth.POST("/refresh", v2AuthHandler.RefreshToken)
 		v1Auth.POST("/logout", v2AuthHandler.Logout)
 		v1Auth.GET("/me", middleware.JWTAuth(jwtManager), v2AuthHandler.GetMe)
@@ -763,8 +901,6 @@ func main() {
 			c.JSON(http.StatusOK, gin.H{"success": true, "data": nil})
 		})
 		// v1 notifications (g5_na_noti)
-		notiRepo := gnurepo.NewNotiRepository(db)
-		notiPrefRepo := gnurepo.NewNotiPreferenceRepository(db)
 		notiHandler := handler.NewNotiHandler(notiRepo, notiPrefRepo)
 		notiGroup := router.Group("/api/v1/notifications", middleware.JWTAuth(jwtManager))
 		notiGroup.GET("/unread-count", notiHandler.GetUnreadCount)
@@ -1543,6 +1679,10 @@ func main() {
 			}
 
 			postDetail := v1handler.TransformToV1PostDetail(post, isNotice)
+			applyContentHook(hookManager, plugin.HookPostContent, postDetail, map[string]interface{}{
+				"board_id": slug,
+				"post_id":  post.WrID,
+			})
 
 			// 태그 조회
 			if tags, err := gnuTagRepo.GetPostTags(slug, id); err == nil && len(tags) > 0 {
@@ -1676,6 +1816,11 @@ func main() {
 				if cnt, ok := editCountMap[comment.WrID]; ok && cnt > 0 {
 					transformed[i]["edit_count"] = cnt
 				}
+				applyContentHook(hookManager, plugin.HookCommentContent, transformed[i], map[string]interface{}{
+					"board_id":   slug,
+					"post_id":    id,
+					"comment_id": comment.WrID,
+				})
 			}
 
 			c.JSON(http.StatusOK, gin.H{
@@ -1800,10 +1945,8 @@ func main() {
 				return
 			}
 
-			userLevel := middleware.GetUserLevel(c)
-
 			// 관리자: full revision 데이터 반환
-			if userLevel >= 10 {
+			if middleware.IsAdmin(c) {
 				type Revision struct {
 					ID           int64     `json:"id"`
 					BoardID      string    `json:"board_id"`
@@ -1928,6 +2071,20 @@ func main() {
 				return
 			}
 
+			// 플러그인 Before Hook (거부 또는 제목/본문 변경 가능)
+			hookData, err := hookManager.ApplyBefore(plugin.HookPostBeforeCreate, map[string]interface{}{
+				"board_id": slug,
+				"user_id":  mbID,
+				"title":    req.Title,
+				"content":  req.Content,
+			})
+			if err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
+				return
+			}
+			req.Title = hookString(hookData, "title", req.Title)
+			req.Content = hookString(hookData, "content", req.Content)
+
 			// 작성자 닉네임 조회
 			authorName := middleware.GetNickname(c)
 			if authorName == "" {
@@ -2024,6 +2181,15 @@ func main() {
 				return true
 			})
 
+			hookManager.Do(plugin.HookPostAfterCreate, map[string]interface{}{
+				"board_id": slug,
+				"post_id":  post.WrID,
+				"user_id":  mbID,
+				"title":    post.WrSubject,
+				"content":  post.WrContent,
+			})
+			searchIndexer.EnqueuePost(slug, post.WrID, false)
+
 			// 팔로워/구독자 알림 (비동기)
 			go func() {
 				authorName := post.WrName
@@ -2033,13 +2199,11 @@ func main() {
 				subject := post.WrSubject
 				now := time.Now()
 
+				// 알림 설정(noti_follow 등) 확인과 실시간 전송은 NotiDispatcher가 처리
 				// 1. 팔로워 알림: 이 작성자를 팔로우한 사람들
 				var followerIDs []string
 				db.Table("g5_member_follow").Select("mb_id").Where("target_id = ?", mbID).Pluck("mb_id", &followerIDs)
 				for _, fid := range followerIDs {
-					if pref, _ := notiPrefRepo.Get(fid); !pref.NotiFollow {
-						continue
-					}
 					_ = notiRepo.Create(&gnurepo.Notification{
 						PhToCase: "follow", PhFromCase: "write", BoTable: slug,
 						WrID: post.WrID, MbID: fid, RelMbID: mbID,
@@ -2063,9 +2227,6 @@ func main() {
 					if followerSet[sid] {
 						continue // 이미 팔로워 알림 받음
 					}
-					if pref, _ := notiPrefRepo.Get(sid); !pref.NotiFollow {
-						continue
-					}
 					_ = notiRepo.Create(&gnurepo.Notification{
 						PhToCase: "subscribe", PhFromCase: "write", BoTable: slug,
 						WrID: post.WrID, MbID: sid, RelMbID: mbID,
@@ -2139,6 +2300,20 @@ func main() {
 				}
 			}
 
+			// 플러그인 Before Hook (거부 또는 본문 변경 가능)
+			hookData, err := hookManager.ApplyBefore(plugin.HookCommentBeforeCreate, map[string]interface{}{
+				"board_id":  slug,
+				"post_id":   postID,
+				"parent_id": req.ParentID,
+				"user_id":   mbID,
+				"content":   req.Content,
+			})
+			if err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
+				return
+			}
+			req.Content = hookString(hookData, "content", req.Content)
+
 			// 작성자 닉네임 조회
 			authorName := middleware.GetNickname(c)
 			if authorName == "" {
@@ -2250,6 +2425,16 @@ func main() {
 			}
 			comment := createdComment
 
+			hookManager.Do(plugin.HookCommentAfterCreate, map[string]interface{}{
+				"board_id":   slug,
+				"post_id":    postID,
+				"comment_id": comment.WrID,
+				"parent_id":  req.ParentID,
+				"user_id":    mbID,
+				"content":    comment.WrContent,
+			})
+			searchIndexer.EnqueueComment(slug, postID, comment.WrID)
+
 			// 포인트 처리 (g5_point 기반 FIFO 소비)
 			if board.BoCommentPoint != 0 {
 				var pc *v2repo.PointConfig
@@ -2292,38 +2477,15 @@ func main() {
 				if req.ParentID != nil && *req.ParentID > 0 {
 					var parentAuthorMbID string
 					if err := db.Table(tableName).Select("mb_id").Where("wr_id = ?", *req.ParentID).Scan(&parentAuthorMbID).Error; err == nil && parentAuthorMbID != "" && parentAuthorMbID != mbID {
-						if pref, _ := notiPrefRepo.Get(parentAuthorMbID); pref.NotiReply {
-							_ = notiRepo.Create(&gnurepo.Notification{
-								PhToCase:      "comment_reply",
-								PhFromCase:    "comment",
-								BoTable:       slug,
-								WrID:          comment.WrID,
-								MbID:          parentAuthorMbID,
-								RelMbID:       mbID,
-								RelMbNick:     authorName,
-								RelMsg:        fmt.Sprintf("%s님이 회원님의 댓글에 답글을 남겼습니다.", authorName),
-								RelURL:        fmt.Sprintf("/%s/%d#comment_%d", slug, postID, comment.WrID),
-								PhReaded:      "N",
-								PhDatetime:    now,
-								ParentSubject: postAuthor.WrSubject,
-								WrParent:      postID,
-							})
-						}
-					}
-				}
-
-				// 게시글 작성자에게 알림 (자기 댓글은 제외)
-				if postAuthor.MbID != mbID {
-					if pref, _ := notiPrefRepo.Get(postAuthor.MbID); pref.NotiComment {
 						_ = notiRepo.Create(&gnurepo.Notification{
-							PhToCase:      "comment",
+							PhToCase:      "comment_reply",
 							PhFromCase:    "comment",
 							BoTable:       slug,
 							WrID:          comment.WrID,
-							MbID:          postAuthor.MbID,
+							MbID:          parentAuthorMbID,
 							RelMbID:       mbID,
 							RelMbNick:     authorName,
-							RelMsg:        fmt.Sprintf("%s님이 회원님의 글에 댓글을 남겼습니다.", authorName),
+							RelMsg:        fmt.Sprintf("%s님이 회원님의 댓글에 답글을 남겼습니다.", authorName),
 							RelURL:        fmt.Sprintf("/%s/%d#comment_%d", slug, postID, comment.WrID),
 							PhReaded:      "N",
 							PhDatetime:    now,
@@ -2332,6 +2494,25 @@ func main() {
 						})
 					}
 				}
+
+				// 게시글 작성자에게 알림 (자기 댓글은 제외)
+				if postAuthor.MbID != mbID {
+					_ = notiRepo.Create(&gnurepo.Notification{
+						PhToCase:      "comment",
+						PhFromCase:    "comment",
+						BoTable:       slug,
+						WrID:          comment.WrID,
+						MbID:          postAuthor.MbID,
+						RelMbID:       mbID,
+						RelMbNick:     authorName,
+						RelMsg:        fmt.Sprintf("%s님이 회원님의 글에 댓글을 남겼습니다.", authorName),
+						RelURL:        fmt.Sprintf("/%s/%d#comment_%d", slug, postID, comment.WrID),
+						PhReaded:      "N",
+						PhDatetime:    now,
+						ParentSubject: postAuthor.WrSubject,
+						WrParent:      postID,
+					})
+				}
 			}()
 
 			c.JSON(http.StatusCreated, gin.H{
@@ -2382,8 +2563,8 @@ func main() {
 
 			// 작성자 또는 관리자 확인
 			userID := middleware.GetUserID(c)
-			userLevel := middleware.GetUserLevel(c)
-			if post.MbID != userID && userLevel < 10 {
+			isAdmin := middleware.IsAdmin(c)
+			if post.MbID != userID && !isAdmin {
 				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "수정 권한이 없습니다"})
 				return
 			}
@@ -2405,6 +2586,32 @@ func main() {
 				return
 			}
 
+			// 플러그인 Before Hook (거부 또는 제목/본문 변경 가능)
+			title, content := post.WrSubject, post.WrContent
+			if req.Title != nil {
+				title = *req.Title
+			}
+			if req.Content != nil {
+				content = *req.Content
+			}
+			hookData, err := hookManager.ApplyBefore(plugin.HookPostBeforeUpdate, map[string]interface{}{
+				"board_id": slug,
+				"post_id":  postID,
+				"user_id":  post.MbID,
+				"title":    title,
+				"content":  content,
+			})
+			if err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
+				return
+			}
+			if v := hookString(hookData, "title", title); req.Title != nil || v != title {
+				req.Title = &v
+			}
+			if v := hookString(hookData, "content", content); req.Content != nil || v != content {
+				req.Content = &v
+			}
+
 			// 수정 전 내용을 리비전에 저장
 			var nextVersion int
 			db.Raw("SELECT COALESCE(MAX(version), 0) + 1 FROM g5_write_revisions WHERE board_id = ? AND wr_id = ?", slug, postID).Scan(&nextVersion)
@@ -2470,6 +2677,22 @@ func main() {
 				return true
 			})
 
+			updated := map[string]interface{}{
+				"board_id": slug,
+				"post_id":  postID,
+				"user_id":  post.MbID,
+				"title":    post.WrSubject,
+				"content":  post.WrContent,
+			}
+			if req.Title != nil {
+				updated["title"] = *req.Title
+			}
+			if req.Content != nil {
+				updated["content"] = *req.Content
+			}
+			hookManager.Do(plugin.HookPostAfterUpdate, updated)
+			searchIndexer.EnqueuePost(slug, postID, false)
+
 			c.JSON(http.StatusOK, gin.H{"success": true, "message": "수정 완료"})
 		})
 
@@ -2491,8 +2714,8 @@ func main() {
 
 			// 작성자 또는 관리자 확인
 			userID := middleware.GetUserID(c)
-			userLevel := middleware.GetUserLevel(c)
-			if comment.MbID != userID && userLevel < 10 {
+			isAdmin := middleware.IsAdmin(c)
+			if comment.MbID != userID && !isAdmin {
 				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "수정 권한이 없습니다"})
 				return
 			}
@@ -2506,6 +2729,20 @@ func main() {
 				return
 			}
 
+			// 플러그인 Before Hook (거부 또는 본문 변경 가능)
+			hookData, err := hookManager.ApplyBefore(plugin.HookCommentBeforeUpdate, map[string]interface{}{
+				"board_id":   slug,
+				"post_id":    comment.WrParent,
+				"comment_id": commentID,
+				"user_id":    comment.MbID,
+				"content":    req.Content,
+			})
+			if err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
+				return
+			}
+			req.Content = hookString(hookData, "content", req.Content)
+
 			// 수정 전 내용을 리비전에 저장
 			var nextVersion int
 			db.Raw("SELECT COALESCE(MAX(version), 0) + 1 FROM g5_write_revisions WHERE board_id = ? AND wr_id = ?",
@@ -2531,11 +2768,20 @@ func main() {
 				_ = cacheService.InvalidateComments(c.Request.Context(), slug, postID)
 			}
 
+			hookManager.Do(plugin.HookCommentAfterUpdate, map[string]interface{}{
+				"board_id":   slug,
+				"post_id":    comment.WrParent,
+				"comment_id": commentID,
+				"user_id":    comment.MbID,
+				"content":    req.Content,
+			})
+			searchIndexer.EnqueueComment(slug, comment.WrParent, commentID)
+
 			c.JSON(http.StatusOK, gin.H{"success": true, "message": "수정 완료"})
 		})
 
 		// GET /api/v1/boards/:slug/posts/:id/comments/:comment_id/revisions - Get comment revision history
-		v1Boards.GET("/:slug/posts/:id/comments/:comment_id/revisions", middleware.JWTAuth(jwtManager), func(c *gin.Context) {
+		v1Boards.GET("/:slug/posts/:id/comments/:comment_id/revisions", middleware.JWTAuth(jwtManager), middleware.RequireAdmin(), func(c *gin.Context) {
 			slug := c.Param("slug")
 			commentID, err := strconv.Atoi(c.Param("comment_id"))
 			if err != nil {
@@ -2543,13 +2789,6 @@ func main() {
 				return
 			}
 
-			// 관리자만 리비전 열람 가능
-			userLevel := middleware.GetUserLevel(c)
-			if userLevel < 10 {
-				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "관리자만 조회 가능합니다"})
-				return
-			}
-
 			type Revision struct {
 				ID           int64     `json:"id"`
 				BoardID      string    `json:"board_id"`
@@ -2618,18 +2857,32 @@ func main() {
 
 			// 작성자 또는 관리자 확인
 			userID := middleware.GetUserID(c)
-			userLevel := middleware.GetUserLevel(c)
-			if post.MbID != userID && userLevel < 10 {
+			isAdmin := middleware.IsAdmin(c)
+			if post.MbID != userID && !isAdmin {
 				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "삭제 권한이 없습니다"})
 				return
 			}
 
-			// 관리자(level >= 10)는 즉시 삭제
-			if userLevel >= 10 {
+			// 플러그인 Before Hook (거부 가능, 지연 삭제 예약도 포함)
+			postHookData := map[string]interface{}{
+				"board_id":   slug,
+				"post_id":    postID,
+				"user_id":    post.MbID,
+				"deleted_by": userID,
+			}
+			if _, err := hookManager.ApplyBefore(plugin.HookPostBeforeDelete, postHookData); err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
+				return
+			}
+
+			// 관리자는 즉시 삭제
+			if isAdmin {
 				if err := gnuWriteRepo.SoftDeletePost(slug, postID, userID); err != nil {
 					c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "게시글 삭제 실패"})
 					return
 				}
+				hookManager.Do(plugin.HookPostAfterDelete, postHookData)
+				searchIndexer.EnqueuePost(slug, postID, false)
 				// 캐시 무효화: 게시글 상세 + 목록
 				if cacheService != nil {
 					_ = cacheService.InvalidatePost(c.Request.Context(), slug, postID)
@@ -2691,7 +2944,7 @@ func main() {
 		})
 
 		// POST /api/v1/boards/:slug/posts/:id/restore - Restore soft deleted post (admin only)
-		v1Boards.POST("/:slug/posts/:id/restore", middleware.JWTAuth(jwtManager), func(c *gin.Context) {
+		v1Boards.POST("/:slug/posts/:id/restore", middleware.JWTAuth(jwtManager), middleware.RequireAdmin(), func(c *gin.Context) {
 			slug := c.Param("slug")
 			postID, err := strconv.Atoi(c.Param("id"))
 			if err != nil {
@@ -2699,13 +2952,6 @@ func main() {
 				return
 			}
 
-			// 관리자 확인
-			userLevel := middleware.GetUserLevel(c)
-			if userLevel < 10 {
-				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "관리자만 복구할 수 있습니다"})
-				return
-			}
-
 			// 게시글 조회 (삭제된 게시글 포함)
 			post, err := gnuWriteRepo.FindPostByIDIncludeDeleted(slug, postID)
 			if err != nil {
@@ -2724,12 +2970,14 @@ func main() {
 				c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "게시글 복구 실패"})
 				return
 			}
+			searchIndexer.EnqueuePost(slug, postID, true)
+			mediaLibrary.SyncPost(c.Request.Context(), slug, postID, post.WrContent)
 
 			c.JSON(http.StatusOK, gin.H{"success": true, "message": "복구 완료"})
 		})
 
 		// DELETE /api/v1/boards/:slug/posts/:id/permanent - Permanently delete post (admin only)
-		v1Boards.DELETE("/:slug/posts/:id/permanent", middleware.JWTAuth(jwtManager), func(c *gin.Context) {
+		v1Boards.DELETE("/:slug/posts/:id/permanent", middleware.JWTAuth(jwtManager), middleware.RequireAdmin(), func(c *gin.Context) {
 			slug := c.Param("slug")
 			postID, err := strconv.Atoi(c.Param("id"))
 			if err != nil {
@@ -2737,25 +2985,33 @@ func main() {
 				return
 			}
 
-			// 관리자 확인
-			userLevel := middleware.GetUserLevel(c)
-			if userLevel < 10 {
-				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "관리자만 영구 삭제할 수 있습니다"})
-				return
-			}
-
 			// 게시글 조회 (삭제된 게시글 포함)
-			_, err = gnuWriteRepo.FindPostByIDIncludeDeleted(slug, postID)
+			post, err := gnuWriteRepo.FindPostByIDIncludeDeleted(slug, postID)
 			if err != nil {
 				c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "게시글을 찾을 수 없습니다"})
 				return
 			}
 
+			// 플러그인 Before Hook (거부 가능)
+			postHookData := map[string]interface{}{
+				"board_id":   slug,
+				"post_id":    postID,
+				"user_id":    post.MbID,
+				"deleted_by": middleware.GetUserID(c),
+				"permanent":  true,
+			}
+			if _, err := hookManager.ApplyBefore(plugin.HookPostBeforeDelete, postHookData); err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
+				return
+			}
+
 			// 게시글 영구 삭제
 			if err := gnuWriteRepo.DeletePost(slug, postID); err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "게시글 영구 삭제 실패"})
 				return
 			}
+			hookManager.Do(plugin.HookPostAfterDelete, postHookData)
+			searchIndexer.EnqueuePost(slug, postID, false)
 
 			c.JSON(http.StatusOK, gin.H{"success": true, "message": "영구 삭제 완료"})
 		})
@@ -2778,19 +3034,34 @@ func main() {
 
 			// 작성자 또는 관리자 확인
 			userID := middleware.GetUserID(c)
-			userLevel := middleware.GetUserLevel(c)
-			if comment.MbID != userID && userLevel < 10 {
+			isAdmin := middleware.IsAdmin(c)
+			if comment.MbID != userID && !isAdmin {
 				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "삭제 권한이 없습니다"})
 				return
 			}
 
-			// 관리자(level >= 10)는 즉시 삭제
+			// 플러그인 Before Hook (거부 가능, 지연 삭제 예약도 포함)
 			postID, _ := strconv.Atoi(c.Param("id"))
-			if userLevel >= 10 {
+			commentHookData := map[string]interface{}{
+				"board_id":   slug,
+				"post_id":    postID,
+				"comment_id": commentID,
+				"user_id":    comment.MbID,
+				"deleted_by": userID,
+			}
+			if _, err := hookManager.ApplyBefore(plugin.HookCommentBeforeDelete, commentHookData); err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
+				return
+			}
+
+			// 관리자는 즉시 삭제
+			if isAdmin {
 				if err := gnuWriteRepo.SoftDeleteComment(slug, commentID, userID); err != nil {
 					c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "댓글 삭제 실패"})
 					return
 				}
+				hookManager.Do(plugin.HookCommentAfterDelete, commentHookData)
+				searchIndexer.EnqueueComment(slug, postID, commentID)
 				// 캐시 무효화: 댓글 + 게시글 목록 (댓글 수 변경)
 				if cacheService != nil {
 					_ = cacheService.InvalidateComments(c.Request.Context(), slug, postID)
@@ -2815,6 +3086,8 @@ func main() {
 					c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "댓글 삭제 실패"})
 					return
 				}
+				hookManager.Do(plugin.HookCommentAfterDelete, commentHookData)
+				searchIndexer.EnqueueComment(slug, postID, commentID)
 				// 캐시 무효화: 댓글 + 게시글 목록 (댓글 수 변경)
 				if cacheService != nil {
 					_ = cacheService.InvalidateComments(c.Request.Context(), slug, postID)
@@ -2866,7 +3139,7 @@ func main() {
 		})
 
 		// POST /api/v1/boards/:slug/posts/:id/comments/:comment_id/restore - Restore soft deleted comment (admin only)
-		v1Boards.POST("/:slug/posts/:id/comments/:comment_id/restore", middleware.JWTAuth(jwtManager), func(c *gin.Context) {
+		v1Boards.POST("/:slug/posts/:id/comments/:comment_id/restore", middleware.JWTAuth(jwtManager), middleware.RequireAdmin(), func(c *gin.Context) {
 			slug := c.Param("slug")
 			commentID, err := strconv.Atoi(c.Param("comment_id"))
 			if err != nil {
@@ -2874,13 +3147,6 @@ func main() {
 				return
 			}
 
-			// 관리자 확인
-			userLevel := middleware.GetUserLevel(c)
-			if userLevel < 10 {
-				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "관리자만 복구할 수 있습니다"})
-				return
-			}
-
 			// 댓글 복구
 			if err := gnuWriteRepo.RestoreComment(slug, commentID); err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "댓글 복구 실패"})
@@ -2893,6 +3159,7 @@ func main() {
 				_ = cacheService.InvalidateComments(c.Request.Context(), slug, postID)
 				_ = cacheService.InvalidatePosts(c.Request.Context(), slug)
 			}
+			searchIndexer.EnqueueComment(slug, postID, commentID)
 
 			c.JSON(http.StatusOK, gin.H{"success": true, "message": "댓글 복구 완료"})
 		})
@@ -2914,8 +3181,8 @@ func main() {
 
 			// 작성자 또는 관리자만 취소 가능
 			userID := middleware.GetUserID(c)
-			userLevel := middleware.GetUserLevel(c)
-			if sd.RequestedBy != userID && userLevel < 10 {
+			isAdmin := middleware.IsAdmin(c)
+			if sd.RequestedBy != userID && !isAdmin {
 				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "취소 권한이 없습니다"})
 				return
 			}
@@ -3044,7 +3311,7 @@ func main() {
 		})
 
 		// POST /api/v1/boards/:slug/posts/:id/move - Move post to another board (admin only)
-		v1Boards.POST("/:slug/posts/:id/move", middleware.JWTAuth(jwtManager), func(c *gin.Context) {
+		v1Boards.POST("/:slug/posts/:id/move", middleware.JWTAuth(jwtManager), middleware.RequireAdmin(), func(c *gin.Context) {
 			srcBoard := c.Param("slug")
 			postID, err := strconv.Atoi(c.Param("id"))
 			if err != nil {
@@ -3052,13 +3319,6 @@ func main() {
 				return
 			}
 
-			// 관리자 확인
-			userLevel := middleware.GetUserLevel(c)
-			if userLevel < 10 {
-				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "관리자만 게시글을 이동할 수 있습니다"})
-				return
-			}
-
 			var req struct {
 				TargetBoardID string `json:"target_board_id" binding:"required"`
 			}
@@ -3155,6 +3415,12 @@ func main() {
 				return
 			}
 
+			// 검색 색인: 원본 제거 + 대상 게시판에 댓글 포함 재색인
+			searchIndexer.EnqueuePost(srcBoard, postID, false)
+			searchIndexer.EnqueuePost(req.TargetBoardID, newPost.WrID, true)
+			mediaLibrary.RemovePost(c.Request.Context(), srcBoard, postID)
+			mediaLibrary.SyncPost(c.Request.Context(), req.TargetBoardID, newPost.WrID, newPost.WrContent)
+
 			// 캐시 무효화
 			if cacheService != nil {
 				ctx := c.Request.Context()
@@ -3776,7 +4042,8 @@ func main() {
 		// v2 Admin
 		v2AdminSvc := v2svc.NewAdminService(v2UserRepo, v2BoardRepo, v2PostRepo, v2CommentRepo)
 		v2AdminHandler := v2handler.NewAdminHandler(v2AdminSvc)
-		v2routes.SetupAdmin(router, v2AdminHandler, jwtManager)
+		v2AdminHandler.SetQuotaService(quotaSvc)
+		v2routes.SetupAdmin(router, v2AdminHandler, jwtManager, apiKeyAuth)
 
 		// Admin Settings (report-lock threshold etc.)
 		adminSettingsHandler := v2handler.NewAdminSettingsHandler(db)
@@ -3809,8 +4076,6 @@ func main() {
 		promotionRepo := v2repo.NewPromotionRepository(db)
 		v2routes.SetupPromotion(router, v2handler.NewPromotionHandler(promotionRepo, redisClient))
 
-		v2routes.SetupLicense(router, v2handler.NewLicenseHandler())
-
 		// Content pages (g5_content)
 		contentRepo := v2repo.NewContentRepository(db)
 		contentHandler := v2handler.NewContentHandler(contentRepo)
@@ -3821,11 +4086,10 @@ func main() {
 		v2routes.SetupInstall(router, v2InstallHandler)
 
 		// Tenant Management
-		tenantDBResolver := middleware.NewTenantDBResolver(db)
 		tenantSvc := service.NewTenantService(siteRepo, db, tenantDBResolver)
 		tenantHandler := handler.NewTenantHandler(tenantSvc)
 
-		adminTenants := router.Group("/api/v2/admin/tenants")
+		adminTenants := router.Group("/api/v2/admin/tenants", middleware.JWTAuth(jwtManager), middleware.RequireAdmin())
 		adminTenants.GET("", tenantHandler.ListTenants)
 		adminTenants.GET("/plans", middleware.CacheWithTTL(redisClient, 10*time.Minute), tenantHandler.GetPlanLimits)
 		adminTenants.GET("/:id", tenantHandler.GetTenant)
@@ -3842,17 +4106,26 @@ func main() {
 		provisioningSvc := service.NewProvisioningService(siteRepo, subRepo, tenantDBResolver, db, "angple.com")
 		provisioningHandler := handler.NewProvisioningHandler(provisioningSvc)
 
-		saas := router.Group("/api/v2/saas")
-		saas.GET("/pricing", middleware.CacheWithTTL(redisClient, 10*time.Minute), provisioningHandler.GetPricing)
-		saas.POST("/communities", provisioningHandler.ProvisionCommunity)
-		saas.DELETE("/communities/:id", provisioningHandler.DeleteCommunity)
-		saas.GET("/communities/:id/subscription", provisioningHandler.GetSubscription)
-		saas.PUT("/communities/:id/subscription/plan", provisioningHandler.ChangePlan)
-		saas.POST("/communities/:id/subscription/cancel", provisioningHandler.CancelSubscription)
-		saas.GET("/communities/:id/invoices", provisioningHandler.GetInvoices)
+		// Subscription billing (Toss Payments, 개발 환경은 fake 결제사)
+		billingSvc := service.NewBillingService(subRepo, siteRepo)
+		if secretKey := os.Getenv("TOSS_SECRET_KEY"); secretKey != "" {
+			billingSvc.RegisterProvider(payment.NewTossProvider(payment.TossConfig{
+				SecretKey:     secretKey,
+				ClientKey:     os.Getenv("TOSS_CLIENT_KEY"),
+				WebhookSecret: os.Getenv("TOSS_WEBHOOK_SECRET"),
+			}))
+		}
+		if cfg.IsDevelopment() {
+			billingSvc.RegisterProvider(payment.NewFakeProvider(os.Getenv("FAKE_PAYMENT_WEBHOOK_SECRET")))
+		}
+		provisioningSvc.SetBillingService(billingSvc)
+		billingHandler := handler.NewBillingHandler(billingSvc)
+		dunningSvc := service.NewDunningService(subRepo, siteRepo, billingSvc, tenantSvc)
+		dunningSvc.SetNotifier(service.NewNotiOwnerNotifier(gnuMemberRepo, notiRepo))
+
+		v2routes.SetupSaaS(router, provisioningHandler, billingHandler, jwtManager, service.NewSiteService(siteRepo), redisClient)
 
 		// OAuth2 Social Login
-		oauthService := service.NewOAuthService(db, jwtManager)
 		if clientID := os.Getenv("NAVER_CLIENT_ID"); clientID != "" {
 			oauthService.RegisterProvider(domain.OAuthProviderNaver, &domain.OAuthConfig{
 				ClientID:     clientID,
@@ -3880,14 +4153,18 @@ func main() {
 		oauth := router.Group("/api/v2/auth/oauth")
 		oauth.GET("/:provider", oauthHandler.Redirect)
 		oauth.GET("/:provider/callback", oauthHandler.Callback)
+		oauth.POST("/2fa/verify", oauthHandler.VerifyTwoFactor)
 
 		apiKeys := router.Group("/api/v2/auth/api-keys", middleware.JWTAuth(jwtManager))
 		apiKeys.POST("", oauthHandler.GenerateAPIKey)
+		apiKeys.GET("", oauthHandler.ListAPIKeys)
+		apiKeys.DELETE("/:id", oauthHandler.RevokeAPIKey)
+		apiKeys.POST("/:id/rotate", oauthHandler.RotateAPIKey)
 
 		// Elasticsearch Search (optional)
-		if esClient != nil {
-			searchSvc := service.NewSearchService(esClient, db)
+		if searchSvc != nil {
 			searchHandler := handler.NewSearchHandler(searchSvc)
+			searchHandler.SetIndexer(searchIndexer)
 
 			search := router.Group("/api/v2/search")
 			search.GET("", searchHandler.Search)
@@ -3898,15 +4175,24 @@ func main() {
 			searchV1.GET("", searchHandler.Search)
 			searchV1.GET("/autocomplete", searchHandler.Autocomplete)
 
-			adminSearch := router.Group("/api/v2/admin/search")
+			adminSearch := router.Group("/api/v2/admin/search", middleware.JWTAuth(jwtManager), middleware.RequireAdmin())
 			adminSearch.POST("/index", searchHandler.BulkIndex)
 			adminSearch.POST("/index-post", searchHandler.IndexPost)
 			adminSearch.DELETE("/index/:board_id/:post_id", searchHandler.DeletePostIndex)
+			adminSearch.GET("/index-lag", searchHandler.IndexLag)
 		}
 
-		// Media Pipeline (S3 storage, optional)
-		if s3Client != nil {
-			mediaSvc := service.NewMediaService(s3Client)
+		// Media Pipeline (S3/local storage, optional)
+		if store != nil {
+			mediaSvc := service.NewMediaService(store)
+			mediaSvc.SetQuotaService(quotaSvc)
+			mediaSvc.SetUsageMeter(usageMeter)
+			// 이미지 변형(thumb/small/medium)은 요청과 분리된 워커 풀에서 생성
+			variantPool := service.NewImageVariantPool(store, 2, 256)
+			variantPool.Start()
+			defer variantPool.Stop()
+			mediaSvc.SetVariantPool(variantPool)
+			mediaSvc.SetMediaLibrary(mediaLibrary)
 			mediaHandler := handler.NewMediaHandler(mediaSvc)
 
 			// TODO: UploadRateLimitConfig 구현 후 활성화
@@ -3916,9 +4202,17 @@ func main() {
 			media.POST("/attachments", mediaHandler.UploadAttachment)
 			media.POST("/videos", mediaHandler.UploadVideo)
 			media.DELETE("/files", mediaHandler.DeleteFile)
+			media.GET("/usage", mediaHandler.GetUsage)
+			router.GET("/api/v2/admin/media/usage", middleware.JWTAuth(jwtManager), middleware.RequireAdmin(), mediaHandler.TopUsage)
+			// 기존 이미지의 변형은 첫 요청 시 생성 후 리다이렉트 (인증 없음 — <img src> 용, images/ 키만, IP별 제한)
+			router.GET("/api/v2/media/variants/:name", middleware.RateLimit(redisClient, middleware.VariantRateLimitConfig()), mediaHandler.GetVariant)
+			if cfg.StorageBackend() == pkgstorage.BackendLocal {
+				// 로컬 저장소 파일은 서명 URL로만 제공 (인증 없음)
+				router.GET(config.StorageDownloadPath+"/*key", mediaHandler.DownloadFile)
+			}
 
 			// Member profile image
-			memberSvc := service.NewMemberService(s3Client, gnuMemberRepo)
+			memberSvc := service.NewMemberService(store, gnuMemberRepo)
 			memberHandler := handler.NewMemberHandler(memberSvc)
 			memberImage := router.Group("/api/v2/members/me", middleware.JWTAuth(jwtManager))
 			memberImage.POST("/image", memberHandler.UploadImage)
@@ -4175,6 +4469,11 @@ func main() {
 
 		permRepo := pluginstoreRepo.NewPermissionRepository(db)
 		storeSvc := pluginstoreSvc.NewStoreService(installRepo, eventRepo, settingRepo, catalogSvc, pluginLogger)
+		storeSvc.SetQuota(quotaSvc)
+		// 플러그인 수 한도는 설치 레코드 수가 기준 (Redis 카운터 초기화/야간 보정, Redis 없을 때 집계)
+		quotaSvc.SetCounter(common.QuotaPlugins, func(ctx context.Context, _ *domain.Site) (int64, error) {
+			return installRepo.Count(ctx)
+		})
 		settingSvc := pluginstoreSvc.NewSettingService(settingRepo, eventRepo, catalogSvc)
 		permSvc := pluginstoreSvc.NewPermissionService(permRepo, catalogSvc)
 
@@ -4191,10 +4490,22 @@ func main() {
 		))
 
 		settingSvc.SetReloader(pluginManager)
+		pluginManager.SetCapabilityProvider(storeSvc)
+		pluginManager.SetViolationRecorder(storeSvc)
 		pluginManager.SetJWTManager(jwtManager)
+		pluginManager.SetHookManager(hookManager)
 		if err := pluginManager.RegisterAllFactories(); err != nil {
 			pkglogger.Info("Failed to register plugin factories: %v", err)
 		}
+		// plugins/ 디렉토리의 plugin.yaml (runtime이 있으면 외부 프로세스 플러그인)
+		if err := pluginManager.LoadAll(); err != nil {
+			pkglogger.Info("Failed to load plugins from disk: %v", err)
+		}
+		for _, info := range pluginManager.GetAllPlugins() {
+			if !info.IsBuiltIn && info.Manifest != nil {
+				catalogSvc.RegisterManifest(info.Manifest)
+			}
+		}
 		if err := storeSvc.BootEnabledPlugins(pluginManager); err != nil {
 			pkglogger.Info("Failed to boot enabled plugins: %v", err)
 		}
@@ -4210,14 +4521,18 @@ func main() {
 			adminPlugins.GET("/dashboard", storeHandler.Dashboard)
 			adminPlugins.GET("/health", storeHandler.HealthCheck)
 			adminPlugins.GET("/schedules", storeHandler.ScheduledTasks)
+			adminPlugins.GET("/schedules/runs", storeHandler.ScheduleRuns)
+			adminPlugins.POST("/schedules/:plugin/:task/run", storeHandler.RunScheduledTask)
 			adminPlugins.GET("/rate-limits", storeHandler.RateLimitConfigs)
 			adminPlugins.GET("/metrics", storeHandler.PluginMetrics)
 			adminPlugins.GET("/event-subscriptions", storeHandler.EventSubscriptions)
 			adminPlugins.GET("/overview", storeHandler.PluginOverview)
 			adminPlugins.GET("/settings/export", settingHandler.ExportAllSettings)
 			adminPlugins.POST("/settings/import", settingHandler.ImportSettings)
+			adminPlugins.POST("/marketplace/:id/install", storeHandler.InstallFromMarketplace)
 			adminPlugins.GET("/:name", storeHandler.GetPlugin)
 			adminPlugins.POST("/:name/install", storeHandler.InstallPlugin)
+			adminPlugins.POST("/:name/upgrade", storeHandler.UpgradePlugin)
 			adminPlugins.POST("/:name/enable", storeHandler.EnablePlugin)
 			adminPlugins.POST("/:name/disable", storeHandler.DisablePlugin)
 			adminPlugins.DELETE("/:name", storeHandler.UninstallPlugin)
@@ -4225,6 +4540,9 @@ func main() {
 			adminPlugins.PUT("/:name/settings", settingHandler.SaveSettings)
 			adminPlugins.GET("/:name/settings/export", settingHandler.ExportSettings)
 			adminPlugins.GET("/:name/events", storeHandler.GetEvents)
+			adminPlugins.GET("/:name/migrations/pending", storeHandler.PendingMigrations)
+			adminPlugins.GET("/:name/capabilities", storeHandler.GetCapabilities)
+			adminPlugins.POST("/:name/capabilities/approve", storeHandler.ApproveCapabilities)
 			adminPlugins.GET("/:name/permissions", permHandler.GetPermissions)
 			adminPlugins.PUT("/:name/permissions/:permId", permHandler.UpdatePermission)
 			adminPlugins.GET("/:name/health", storeHandler.HealthCheckSingle)
@@ -4238,6 +4556,29 @@ func main() {
 			pkglogger.Info("Marketplace migration warning: %v", err)
 		}
 		marketplaceSvc := pluginstoreSvc.NewMarketplaceService(marketplaceRepo)
+		if store != nil {
+			marketplaceSvc.SetStorage(store)
+		}
+		storeHandler.SetMarketplace(marketplaceSvc)
+
+		// Plugin licenses (Ed25519 signed tokens)
+		licenseRepo := pluginstoreRepo.NewLicenseRepository(db)
+		if err := licenseRepo.AutoMigrate(); err != nil {
+			pkglogger.Info("License migration warning: %v", err)
+		}
+		licenseKey, err := loadLicenseSigningKey(cfg.License.SigningKey)
+		var licenseSvc *pluginstoreSvc.LicenseService
+		switch {
+		case err != nil:
+			// 잘못된 키로 임시 키를 쓰면 재시작마다 발급한 라이선스가 무효가 되므로 기동 중단
+			log.Fatalf("Invalid LICENSE_SIGNING_KEY: %v", err)
+		case licenseKey == nil:
+			// 라우트는 유지하고 503으로 응답 (플러그인이 404와 구분할 수 있도록)
+			pkglogger.Info("LICENSE_SIGNING_KEY not set, plugin license service unavailable")
+		default:
+			licenseSvc = pluginstoreSvc.NewLicenseService(licenseRepo, marketplaceRepo, licenseKey)
+		}
+		v2routes.SetupLicense(router, v2handler.NewLicenseHandler(licenseSvc), jwtManager)
 		marketplaceHandler := pluginstoreHandler.NewMarketplaceHandler(marketplaceSvc)
 
 		mp := router.Group("/api/v2/marketplace")
@@ -4252,6 +4593,8 @@ func main() {
 		mpDev.GET("/me", marketplaceHandler.GetMyProfile)
 		mpDev.POST("/submissions", marketplaceHandler.SubmitPlugin)
 		mpDev.GET("/submissions", marketplaceHandler.ListMySubmissions)
+		mpDev.PUT("/me/signing-key", middleware.JWTAuth(jwtManager), marketplaceHandler.SetSigningKey)
+		mpDev.POST("/submissions/package", middleware.JWTAuth(jwtManager), marketplaceHandler.UploadPackage)
 
 		mpAdmin := router.Group("/api/v2/admin/marketplace")
 		mpAdmin.GET("/submissions/pending", marketplaceHandler.ListPendingSubmissions)
@@ -4269,7 +4612,9 @@ func main() {
 
 		// Internal cron endpoints (curl-based cron jobs)
 		cronHandler := cron.NewHandler(db)
-		cronHandler.SetPointExpiryDeps(pointConfigRepo, gnuPointWriteRepo, gnurepo.NewNotiRepository(db))
+		cronHandler.SetPointExpiryDeps(pointConfigRepo, gnuPointWriteRepo, notiRepo)
+		cronHandler.SetQuotaService(quotaSvc)
+		cronHandler.SetDunningService(dunningSvc)
 		cronGroup := router.Group("/api/internal/cron")
 		cronGroup.POST("/member-lock-release", cronHandler.MemberLockRelease)
 		cronGroup.POST("/update-member-levels", cronHandler.UpdateMemberLevels)
@@ -4279,9 +4624,15 @@ func main() {
 		cronGroup.POST("/point-expiry", cronHandler.PointExpiry)
 		cronGroup.POST("/point-expiry-notify", cronHandler.PointExpiryNotify)
 		cronGroup.POST("/auto-promote", cronHandler.AutoPromote)
+		cronGroup.POST("/quota-reconcile", cronHandler.QuotaReconcile)
+		cronGroup.POST("/billing-dunning", cronHandler.BillingDunning)
 
 		// Start delete worker for delayed deletion processing
 		deleteWorker := worker.NewDeleteWorker(gnuWriteRepo, scheduledDeleteRepo)
+		deleteWorker.SetHookManager(hookManager)
+		if searchIndexer != nil {
+			deleteWorker.SetSearchIndexer(searchIndexer)
+		}
 		deleteWorker.Start()
 		defer deleteWorker.Stop()
 	} else {
@@ -4365,9 +4716,18 @@ func trimSpace(s string) string {
 	return s[start:end]
 }
 
-// initDB MySQL 연결 초기화
-func initDB(cfg *config.Config) (*gorm.DB, error) {
-	mysqlCfg, err := mysqldriver.ParseDSN(cfg.Database.GetDSN())
+// loadLicenseSigningKey parses the license signing key.
+// 키가 없으면 nil (라이선스 발급 비활성), 잘못된 키는 에러 — 임시 키로 대체하지 않는다.
+func loadLicenseSigningKey(encoded string) (ed25519.PrivateKey, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	return license.ParsePrivateKey(encoded)
+}
+
+// initDB MySQL 연결 초기화 (테넌트 schema/dedicated DB 연결에도 사용)
+func initDB(dbCfg *config.DatabaseConfig) (*gorm.DB, error) {
+	mysqlCfg, err := mysqldriver.ParseDSN(dbCfg.GetDSN())
 	if err != nil {
 		return nil, fmt.Errorf("DSN 파싱 실패: %w", err)
 	}
@@ -4400,11 +4760,11 @@ func initDB(cfg *config.Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
-	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(dbCfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(dbCfg.MaxOpenConns)
 	// ConnMaxLifetime: 풀에서 연결 최대 수명. 짧을수록 stale connection 위험 감소.
 	// k3s 재시작 등으로 TCP 끊김 시 빠른 복구를 위해 5분 권장.
-	connMaxLifetime := time.Duration(cfg.Database.ConnMaxLifetime) * time.Second
+	connMaxLifetime := time.Duration(dbCfg.ConnMaxLifetime) * time.Second
 	if connMaxLifetime > 5*time.Minute {
 		connMaxLifetime = 5 * time.Minute
 	}
@@ -28,6 +28,7 @@ func main() {
 	limit := flag.Int("limit", 5, "example row limit per board")
 	sampleOnly := flag.Bool("sample-only", false, "skip full count scans and only fetch example row ids")
 	verbose := flag.Bool("verbose", false, "verbose SQL logging")
+	fromURL := flag.String("from-url", "", "previous storage base URL to rewrite to CDN_URL (backend migration)")
 	flag.Parse()
 
 	loaded := config.LoadDotEnv()
@@ -44,6 +45,10 @@ func main() {
 	if cdnURL == "" {
 		log.Fatal("CDN_URL is empty; refusing to continue")
 	}
+	oldURL := strings.TrimRight(*fromURL, "/")
+	if oldURL == cdnURL {
+		log.Fatal("-from-url equals CDN_URL; nothing to rewrite")
+	}
 
 	logLevel := gormlogger.Warn
 	if *verbose {
@@ -82,7 +87,7 @@ func main() {
 			continue
 		}
 
-		plan, err := buildPlan(db, boardID, tableName, *limit, *sampleOnly)
+		plan, err := buildPlan(db, boardID, tableName, oldURL, *limit, *sampleOnly)
 		if err != nil {
 			log.Printf("[audit] %s failed: %v", tableName, err)
 			continue
@@ -103,7 +108,7 @@ func main() {
 			continue
 		}
 
-		if err := applyRewrites(db, tableName, cdnURL); err != nil {
+		if err := applyRewrites(db, tableName, oldURL, cdnURL); err != nil {
 			log.Printf("[apply] %s failed: %v", tableName, err)
 			continue
 		}
@@ -114,14 +119,14 @@ func main() {
 		changedTables, totalContent, totalWr10, *apply)
 }
 
-func buildPlan(db *gorm.DB, boardID, tableName string, limit int, sampleOnly bool) (*rewritePlan, error) {
+func buildPlan(db *gorm.DB, boardID, tableName, oldURL string, limit int, sampleOnly bool) (*rewritePlan, error) {
 	plan := &rewritePlan{
 		boardID:   boardID,
 		tableName: tableName,
 	}
 
-	contentWhere := mediaContentWhereClause()
-	wr10Where := mediaWr10WhereClause()
+	contentWhere := mediaContentWhereClause(oldURL)
+	wr10Where := mediaWr10WhereClause(oldURL)
 
 	if !sampleOnly {
 		if err := db.Table(tableName).Where(contentWhere).Count(&plan.contentMatches).Error; err != nil {
@@ -157,19 +162,19 @@ func buildPlan(db *gorm.DB, boardID, tableName string, limit int, sampleOnly boo
 	return plan, nil
 }
 
-func applyRewrites(db *gorm.DB, tableName, cdnURL string) error {
-	contentExpr := normalizeContentSQL("wr_content", cdnURL)
-	wr10Expr := normalizeWr10SQL("wr_10", cdnURL)
+func applyRewrites(db *gorm.DB, tableName, oldURL, cdnURL string) error {
+	contentExpr := normalizeContentSQL("wr_content", oldURL, cdnURL)
+	wr10Expr := normalizeWr10SQL("wr_10", oldURL, cdnURL)
 
 	return db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Exec(
-			fmt.Sprintf("UPDATE `%s` SET wr_content = %s WHERE %s", tableName, contentExpr, mediaContentWhereClause()),
+			fmt.Sprintf("UPDATE `%s` SET wr_content = %s WHERE %s", tableName, contentExpr, mediaContentWhereClause(oldURL)),
 		).Error; err != nil {
 			return err
 		}
 
 		if err := tx.Exec(
-			fmt.Sprintf("UPDATE `%s` SET wr_10 = %s WHERE %s", tableName, wr10Expr, mediaWr10WhereClause()),
+			fmt.Sprintf("UPDATE `%s` SET wr_10 = %s WHERE %s", tableName, wr10Expr, mediaWr10WhereClause(oldURL)),
 		).Error; err != nil {
 			return err
 		}
@@ -178,7 +183,8 @@ func applyRewrites(db *gorm.DB, tableName, cdnURL string) error {
 	})
 }
 
-func normalizeContentSQL(column, cdnURL string) string {
+// normalizeContentSQL 상대 /data/ 경로와 이전 저장소 URL(oldURL)을 CDN URL로 치환
+func normalizeContentSQL(column, oldURL, cdnURL string) string {
 	replacements := [][2]string{
 		{`src="/data/`, `src="` + cdnURL + `/data/`},
 		{`src='/data/`, `src='` + cdnURL + `/data/`},
@@ -189,6 +195,9 @@ func normalizeContentSQL(column, cdnURL string) string {
 		{`href="data/`, `href="` + cdnURL + `/data/`},
 		{`href='data/`, `href='` + cdnURL + `/data/`},
 	}
+	if oldURL != "" {
+		replacements = append(replacements, [2]string{oldURL + "/", cdnURL + "/"})
+	}
 
 	expr := column
 	for _, pair := range replacements {
@@ -197,20 +206,27 @@ func normalizeContentSQL(column, cdnURL string) string {
 	return expr
 }
 
-func normalizeWr10SQL(column, cdnURL string) string {
+func normalizeWr10SQL(column, oldURL, cdnURL string) string {
+	migrated := ""
+	if oldURL != "" {
+		migrated = fmt.Sprintf("WHEN %s LIKE '%s/%%' THEN CONCAT('%s/', SUBSTRING(%s, %d)) ",
+			column, escapeLike(oldURL), escapeSQL(cdnURL), column, len(oldURL)+2)
+	}
 	return fmt.Sprintf(
 		"CASE "+
 			"WHEN %s LIKE '/data/%%' THEN CONCAT('%s', %s) "+
 			"WHEN %s LIKE 'data/%%' THEN CONCAT('%s/', %s) "+
+			"%s"+
 			"ELSE %s END",
 		column, escapeSQL(cdnURL), column,
 		column, escapeSQL(cdnURL), column,
+		migrated,
 		column,
 	)
 }
 
-func mediaContentWhereClause() string {
-	return strings.Join([]string{
+func mediaContentWhereClause(oldURL string) string {
+	clauses := []string{
 		"wr_content LIKE '%src=\"/data/%'",
 		"wr_content LIKE '%src=''/data/%'",
 		"wr_content LIKE '%src=\"data/%'",
@@ -219,11 +235,19 @@ func mediaContentWhereClause() string {
 		"wr_content LIKE '%href=''/data/%'",
 		"wr_content LIKE '%href=\"data/%'",
 		"wr_content LIKE '%href=''data/%'",
-	}, " OR ")
+	}
+	if oldURL != "" {
+		clauses = append(clauses, "wr_content LIKE '%"+escapeLike(oldURL)+"/%'")
+	}
+	return strings.Join(clauses, " OR ")
 }
 
-func mediaWr10WhereClause() string {
-	return "wr_10 LIKE '/data/%' OR wr_10 LIKE 'data/%'"
+func mediaWr10WhereClause(oldURL string) string {
+	clause := "wr_10 LIKE '/data/%' OR wr_10 LIKE 'data/%'"
+	if oldURL != "" {
+		clause += " OR wr_10 LIKE '" + escapeLike(oldURL) + "/%'"
+	}
+	return clause
 }
 
 func getBoardIDs(db *gorm.DB) ([]string, error) {
@@ -243,3 +267,9 @@ func tableExists(db *gorm.DB, tableName string) bool {
 func escapeSQL(s string) string {
 	return strings.ReplaceAll(s, "'", "''")
 }
+
+// escapeLike LIKE 와일드카드 이스케이프 (MySQL 문자열 리터럴에서 \%, \_ 는 그대로 유지됨)
+func escapeLike(s string) string {
+	s = strings.NewReplacer("%", `\%`, "_", `\_`).Replace(s)
+	return escapeSQL(s)
+}
@@ -1,27 +1,36 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"mime"
 	"net/http"
 	"os"
-	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/damoang/angple-backend/internal/config"
+	"github.com/damoang/angple-backend/pkg/storage"
 )
 
 const (
 	defaultLocalDir = "/home/damoang/www/data/nariya/image"
 	defaultBucket   = "damoang-data-v1"
 	defaultPrefix   = "data/nariya/image"
+	sourceDir       = "dir"
+	cacheControl    = "public, max-age=31536000, immutable"
 )
 
+// errLimitReached stops listing once -limit files were checked
+var errLimitReached = errors.New("limit reached")
+
 type syncResult struct {
 	checked  int
 	missing  int
@@ -29,141 +38,174 @@ type syncResult struct {
 	failed   int
 }
 
+// syncer copies missing objects from a source (local directory or storage backend) to a target backend.
+// Keys are absolute object keys (base_path is not applied) so URLs rewritten by rewrite-media-urls keep working.
+type syncer struct {
+	target storage.Backend
+	source storage.Backend // nil when copying from -dir
+	dir    string
+	match  string
+	limit  int
+	apply  bool
+	result syncResult
+}
+
 func main() {
-	localDir := flag.String("dir", defaultLocalDir, "local nariya image directory")
-	bucket := flag.String("bucket", defaultBucket, "target S3 bucket")
-	prefix := flag.String("prefix", defaultPrefix, "target S3 prefix")
+	configPath := flag.String("config", "configs/config.prod.yaml", "config file path")
+	from := flag.String("from", sourceDir, "source: dir (local nariya directory), s3 or local (configured backends)")
+	to := flag.String("to", "", "target backend: s3 or local (default: configured storage backend)")
+	localDir := flag.String("dir", defaultLocalDir, "local nariya image directory (-from dir)")
+	bucket := flag.String("bucket", defaultBucket, "S3 bucket")
+	prefix := flag.String("prefix", defaultPrefix, "object key prefix")
 	match := flag.String("match", "", "only process files containing this substring")
 	limit := flag.Int("limit", 0, "maximum number of files to process (0 = unlimited)")
 	apply := flag.Bool("apply", false, "upload missing files")
 	flag.Parse()
 
-	entries, err := os.ReadDir(*localDir)
+	loaded := config.LoadDotEnv()
+	if len(loaded) > 0 {
+		log.Printf("Loaded env files: %v", loaded)
+	}
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatalf("failed to read directory: %v", err)
+		log.Fatalf("failed to load config: %v", err)
 	}
 
-	result := syncResult{}
+	opts := cfg.StorageOptions()
+	opts.S3.Bucket = *bucket
+	opts.S3.BasePath, opts.Local.BasePath = "", ""
+	opts.S3.CacheControl = cacheControl
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		name := entry.Name()
-		if *match != "" && !strings.Contains(name, *match) {
-			continue
-		}
+	targetName := *to
+	if targetName == "" {
+		targetName = opts.Backend
+	}
+	if targetName == *from {
+		log.Fatalf("source and target are both %q", targetName)
+	}
 
-		result.checked++
-		if *limit > 0 && result.checked > *limit {
-			break
+	s := &syncer{dir: *localDir, match: *match, limit: *limit, apply: *apply}
+	if s.target, err = openBackend(opts, targetName); err != nil {
+		log.Fatalf("failed to open target storage: %v", err)
+	}
+	if *from != sourceDir {
+		if s.source, err = openBackend(opts, *from); err != nil {
+			log.Fatalf("failed to open source storage: %v", err)
 		}
+	}
 
-		localPath, err := safeJoin(*localDir, name)
-		if err != nil {
-			result.failed++
-			log.Printf("[path] %s failed: %v", name, err)
-			continue
-		}
-		key := strings.TrimPrefix(filepath.ToSlash(filepath.Join(*prefix, name)), "/")
+	keyPrefix := strings.Trim(*prefix, "/")
+	if s.source != nil {
+		err = s.source.List(context.Background(), keyPrefix+"/", s.process)
+	} else {
+		err = s.syncDir(keyPrefix)
+	}
+	if err != nil && !errors.Is(err, errLimitReached) {
+		log.Printf("[list] failed: %v", err)
+	}
 
-		exists, err := objectExists(*bucket, key)
-		if err != nil {
-			result.failed++
-			log.Printf("[check] %s failed: %v", key, err)
-			continue
-		}
-		if exists {
-			log.Printf("[exists] %s", key)
-			continue
-		}
+	log.Printf("[summary] from=%s to=%s checked=%d missing=%d uploaded=%d failed=%d apply=%v",
+		*from, targetName, s.result.checked, s.result.missing, s.result.uploaded, s.result.failed, *apply)
+}
 
-		result.missing++
-		log.Printf("[missing] %s", key)
+func openBackend(opts storage.Options, name string) (storage.Backend, error) {
+	opts.Backend = name
+	return storage.Open(opts)
+}
 
-		if !*apply {
+// syncDir processes the files directly under -dir as <prefix>/<name>
+func (s *syncer) syncDir(prefix string) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
 			continue
 		}
-
-		if err := uploadFile(localPath, *bucket, key); err != nil {
-			result.failed++
-			log.Printf("[upload] %s failed: %v", key, err)
-			continue
+		if err := s.process(strings.TrimPrefix(path.Join(prefix, entry.Name()), "/")); err != nil {
+			return err
 		}
-
-		result.uploaded++
-		log.Printf("[upload] %s uploaded", key)
 	}
-
-	log.Printf("[summary] checked=%d missing=%d uploaded=%d failed=%d apply=%v",
-		result.checked, result.missing, result.uploaded, result.failed, *apply)
+	return nil
 }
 
-func objectExists(bucket, key string) (bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	// #nosec G204 -- bucket and key are validated application inputs, and CommandContext avoids shell expansion.
-	cmd := exec.CommandContext(ctx, "aws", "s3api", "head-object", "--bucket", bucket, "--key", key)
-	output, err := cmd.CombinedOutput()
-	if err == nil {
-		return true, nil
+func (s *syncer) process(key string) error {
+	name := path.Base(key)
+	if s.match != "" && !strings.Contains(name, s.match) {
+		return nil
 	}
 
-	text := string(output)
-	if strings.Contains(text, "Not Found") || strings.Contains(text, "404") {
-		return false, nil
+	s.result.checked++
+	if s.limit > 0 && s.result.checked > s.limit {
+		s.result.checked--
+		return errLimitReached
 	}
 
-	return false, fmt.Errorf("%w: %s", err, strings.TrimSpace(text))
-}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
 
-func uploadFile(localPath, bucket, key string) error {
-	contentType, err := detectContentType(localPath)
+	exists, err := s.target.Exists(ctx, key)
 	if err != nil {
-		return err
+		s.result.failed++
+		log.Printf("[check] %s failed: %v", key, err)
+		return nil
+	}
+	if exists {
+		log.Printf("[exists] %s", key)
+		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
+	s.result.missing++
+	log.Printf("[missing] %s", key)
 
-	// #nosec G204 -- localPath is validated by safeJoin and the command is executed without a shell.
-	cmd := exec.CommandContext(
-		ctx,
-		"aws", "s3", "cp", localPath, fmt.Sprintf("s3://%s/%s", bucket, key),
-		"--cache-control", "public, max-age=31536000, immutable",
-		"--content-type", contentType,
-	)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	if !s.apply {
+		return nil
+	}
+
+	if err := s.copy(ctx, key); err != nil {
+		s.result.failed++
+		log.Printf("[upload] %s failed: %v", key, err)
+		return nil
 	}
+
+	s.result.uploaded++
+	log.Printf("[upload] %s uploaded", key)
 	return nil
 }
 
-func detectContentType(path string) (string, error) {
-	ext := strings.ToLower(filepath.Ext(path))
-	if contentType := mime.TypeByExtension(ext); contentType != "" {
-		return contentType, nil
+func (s *syncer) copy(ctx context.Context, key string) error {
+	data, err := s.read(ctx, key)
+	if err != nil {
+		return err
 	}
+	_, err = s.target.Upload(ctx, key, bytes.NewReader(data), detectContentType(key, data), int64(len(data)))
+	return err
+}
 
-	cleanPath := filepath.Clean(path)
+func (s *syncer) read(ctx context.Context, key string) ([]byte, error) {
+	if s.source != nil {
+		body, err := s.source.Download(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
 
-	// #nosec G304 -- path is prevalidated by safeJoin and cleaned here before opening.
-	file, err := os.Open(cleanPath)
+	localPath, err := safeJoin(s.dir, path.Base(key))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer file.Close()
+	// #nosec G304 -- localPath is validated by safeJoin.
+	return os.ReadFile(localPath)
+}
 
-	buf := make([]byte, 512)
-	n, err := bufio.NewReader(file).Read(buf)
-	if err != nil && err.Error() != "EOF" {
-		return "", err
+func detectContentType(key string, data []byte) string {
+	if contentType := mime.TypeByExtension(strings.ToLower(path.Ext(key))); contentType != "" {
+		return contentType
 	}
-
-	return http.DetectContentType(bytes.TrimSpace(buf[:n])), nil
+	return http.DetectContentType(data)
 }
 
 func safeJoin(baseDir, name string) (string, error) {
@@ -0,0 +1,54 @@
+package common
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrLoginThrottled is returned when login attempts are temporarily refused (backoff or lockout)
+var ErrLoginThrottled = errors.New("too many login attempts")
+
+// LoginThrottleError carries brute-force protection state for a refused or failed login.
+// Err는 ErrLoginThrottled(시도 거부) 또는 ErrInvalidCredentials(실패 후 상태 안내)다.
+type LoginThrottleError struct {
+	Err             error `json:"-"`
+	RetryAfter      int   `json:"retry_after,omitempty"` // 초
+	Locked          bool  `json:"locked,omitempty"`
+	CaptchaRequired bool  `json:"captcha_required,omitempty"`
+}
+
+func (e *LoginThrottleError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoginThrottleError) Unwrap() error {
+	return e.Err
+}
+
+// RespondLoginThrottled writes a login error response with retry/CAPTCHA hints if err is a LoginThrottleError.
+// 응답을 썼으면 true를 반환한다.
+func RespondLoginThrottled(c *gin.Context, err error) bool {
+	var le *LoginThrottleError
+	if !errors.As(err, &le) {
+		return false
+	}
+
+	status, code, message := http.StatusUnauthorized, "UNAUTHORIZED", "로그인에 실패했습니다"
+	if errors.Is(le.Err, ErrLoginThrottled) {
+		status, code, message = http.StatusTooManyRequests, "LOGIN_THROTTLED", "로그인 시도가 너무 많습니다. 잠시 후 다시 시도해주세요"
+		if le.Locked {
+			code, message = "ACCOUNT_LOCKED", "로그인 실패가 반복되어 일시적으로 잠겼습니다"
+		}
+	}
+	if le.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(le.RetryAfter))
+	}
+	c.JSON(status, V2Response{
+		Success: false,
+		Error:   &V2Error{Code: code, Message: message, Details: le},
+	})
+	return true
+}
@@ -0,0 +1,49 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Quota resources (middleware.PlanLimits 항목)
+const (
+	QuotaPosts   = "posts"
+	QuotaBoards  = "boards"
+	QuotaUsers   = "users"
+	QuotaStorage = "storage"   // bytes
+	QuotaFile    = "file_size" // bytes per file
+	QuotaPlugins = "plugins"
+)
+
+// QuotaExceededError is returned when a write would exceed the tenant's plan limits
+type QuotaExceededError struct {
+	Resource string `json:"resource"`
+	Plan     string `json:"plan"`
+	Limit    int64  `json:"limit"`
+	Used     int64  `json:"used"`
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded: %s (plan %s, limit %d, used %d)", e.Resource, e.Plan, e.Limit, e.Used)
+}
+
+// RespondQuotaExceeded writes a "quota_exceeded" error response if err is a QuotaExceededError.
+// 응답을 썼으면 true를 반환한다.
+func RespondQuotaExceeded(c *gin.Context, err error) bool {
+	var qe *QuotaExceededError
+	if !errors.As(err, &qe) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, V2Response{
+		Success: false,
+		Error: &V2Error{
+			Code:    "quota_exceeded",
+			Message: fmt.Sprintf("현재 플랜(%s)의 %s 한도를 초과했습니다", qe.Plan, qe.Resource),
+			Details: qe,
+		},
+	})
+	return true
+}
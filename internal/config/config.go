@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -19,10 +21,56 @@ type Config struct {
 	Plugins       PluginsConfig       `yaml:"plugins"`
 	Elasticsearch ElasticsearchConfig `yaml:"elasticsearch"`
 	Storage       StorageConfig       `yaml:"storage"`
+	License       LicenseConfig       `yaml:"license"`
+	InternalAuth  InternalAuthConfig  `yaml:"internal_auth"`
+	TwoFactor     TwoFactorConfig     `yaml:"two_factor"`
+	Mail          MailConfig          `yaml:"mail"`
+	Registration  RegistrationConfig  `yaml:"registration"`
 }
 
-// StorageConfig S3-compatible object storage 설정
+// MailConfig 발신 메일 설정 (가입 인증, 비밀번호 재설정)
+type MailConfig struct {
+	Backend      string `yaml:"backend"` // "smtp" | "file" (비어 있으면 smtp_host 여부로 결정)
+	From         string `yaml:"from"`    // 발신 주소 ("다모앙 <noreply@damoang.net>")
+	SMTPHost     string `yaml:"smtp_host"`
+	SMTPPort     int    `yaml:"smtp_port"`
+	SMTPUsername string `yaml:"smtp_username"`
+	SMTPPassword string `yaml:"smtp_password"`
+	DropDir      string `yaml:"drop_dir"` // file 백엔드: .eml 파일을 쓸 디렉터리
+}
+
+// RegistrationConfig 회원가입 설정
+type RegistrationConfig struct {
+	Closed                   bool     `yaml:"closed"`                     // true면 신규 가입 중단
+	Level                    int      `yaml:"level"`                      // 가입 시 회원 레벨 (0이면 기본값 2, 그누보드 cf_register_level)
+	RequireEmailVerification bool     `yaml:"require_email_verification"` // true면 메일 인증 전 로그인 불가
+	LinkBaseURL              string   `yaml:"link_base_url"`              // 메일 링크의 프론트엔드 주소 (https://damoang.net)
+	ReservedNames            []string `yaml:"reserved_names"`             // 사용 금지 아이디/닉네임 (비어 있으면 그누보드 기본 목록)
+	BlockedEmailDomains      []string `yaml:"blocked_email_domains"`      // 가입 금지 메일 도메인
+}
+
+// TwoFactorConfig 2단계 인증(TOTP) 설정
+type TwoFactorConfig struct {
+	Issuer          string `yaml:"issuer"`            // OTP 앱에 표시되는 서비스 이름 (기본값 "Angple")
+	RequireForAdmin bool   `yaml:"require_for_admin"` // true면 모든 관리자 라우트(RequireAdmin)에 2FA 로그인 필요
+}
+
+// InternalAuthConfig 내부 서비스(SvelteKit SSR, CloudFront) → Backend 서명 인증 설정
+type InternalAuthConfig struct {
+	Keys    map[string]string `yaml:"keys"`     // key ID → HMAC 비밀 키 (교체 중에는 여러 개 등록)
+	MaxSkew int               `yaml:"max_skew"` // 서명 유효 시간(초), 0이면 기본값 30초
+}
+
+// LicenseConfig 플러그인 라이선스 서명 설정
+type LicenseConfig struct {
+	SigningKey string `yaml:"signing_key"` // base64 Ed25519 seed(32B) 또는 private key(64B)
+}
+
+// StorageConfig 미디어 저장소 설정 (S3 호환 또는 로컬 디스크)
 type StorageConfig struct {
+	Backend         string `yaml:"backend"`        // "s3" | "local" (비어 있으면 S3 설정 여부로 결정)
+	PublicURL       string `yaml:"public_url"`     // 로컬 저장소 다운로드 URL의 API 기준 주소
+	SigningSecret   string `yaml:"signing_secret"` // 로컬 저장소 다운로드 URL 서명 키 (비어 있으면 JWT secret에서 HKDF로 파생)
 	Endpoint        string `yaml:"endpoint"`
 	Region          string `yaml:"region"`
 	AccessKeyID     string `yaml:"access_key_id"`
@@ -59,9 +107,10 @@ type DataPathsConfig struct {
 
 // ServerConfig 서버 설정
 type ServerConfig struct {
-	Mode string `yaml:"mode"`
-	Env  string `yaml:"env"`
-	Port int    `yaml:"port"`
+	Mode       string `yaml:"mode"`
+	Env        string `yaml:"env"`
+	BaseDomain string `yaml:"base_domain"` // 멀티테넌트 서브도메인 기준 도메인 (예: angple.com)
+	Port       int    `yaml:"port"`
 }
 
 // DatabaseConfig 데이터베이스 설정
@@ -154,6 +203,9 @@ func overrideFromEnv(cfg *Config) {
 	if port := os.Getenv("API_PORT"); port != "" {
 		_, _ = fmt.Sscanf(port, "%d", &cfg.Server.Port) //nolint:errcheck // 파싱 실패 시 기본값 유지
 	}
+	if baseDomain := os.Getenv("TENANT_BASE_DOMAIN"); baseDomain != "" {
+		cfg.Server.BaseDomain = baseDomain
+	}
 
 	// 데이터 경로 설정
 	if uploadPath := os.Getenv("UPLOAD_PATH"); uploadPath != "" {
@@ -197,6 +249,77 @@ func overrideFromEnv(cfg *Config) {
 	if cdnURL := os.Getenv("CDN_URL"); cdnURL != "" {
 		cfg.Storage.CDNURL = cdnURL
 	}
+	if backend := os.Getenv("STORAGE_BACKEND"); backend != "" {
+		cfg.Storage.Backend = backend
+	}
+	if publicURL := os.Getenv("STORAGE_PUBLIC_URL"); publicURL != "" {
+		cfg.Storage.PublicURL = publicURL
+	}
+	if signingSecret := os.Getenv("STORAGE_SIGNING_SECRET"); signingSecret != "" {
+		cfg.Storage.SigningSecret = signingSecret
+	}
+
+	// 라이선스 서명 키
+	if signingKey := os.Getenv("LICENSE_SIGNING_KEY"); signingKey != "" {
+		cfg.License.SigningKey = signingKey
+	}
+
+	// 내부 서명 인증 키 ("id:secret,id2:secret2")
+	if keys := os.Getenv("INTERNAL_AUTH_KEYS"); keys != "" {
+		cfg.InternalAuth.Keys = parseKeyList(keys)
+	}
+
+	// 2단계 인증
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		cfg.TwoFactor.Issuer = issuer
+	}
+	if required, err := strconv.ParseBool(os.Getenv("REQUIRE_ADMIN_2FA")); err == nil {
+		cfg.TwoFactor.RequireForAdmin = required
+	}
+
+	// 메일 / 회원가입
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		cfg.Mail.SMTPHost = host
+	}
+	if port, err := strconv.Atoi(os.Getenv("SMTP_PORT")); err == nil {
+		cfg.Mail.SMTPPort = port
+	}
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		cfg.Mail.SMTPUsername = username
+	}
+	if password := os.Getenv("SMTP_PASSWORD"); password != "" {
+		cfg.Mail.SMTPPassword = password
+	}
+	if from := os.Getenv("MAIL_FROM"); from != "" {
+		cfg.Mail.From = from
+	}
+	if backend := os.Getenv("MAIL_BACKEND"); backend != "" {
+		cfg.Mail.Backend = backend
+	}
+	if dir := os.Getenv("MAIL_DROP_DIR"); dir != "" {
+		cfg.Mail.DropDir = dir
+	}
+	if baseURL := os.Getenv("REGISTER_LINK_BASE_URL"); baseURL != "" {
+		cfg.Registration.LinkBaseURL = baseURL
+	}
+	if required, err := strconv.ParseBool(os.Getenv("REQUIRE_EMAIL_VERIFICATION")); err == nil {
+		cfg.Registration.RequireEmailVerification = required
+	}
+	if closed, err := strconv.ParseBool(os.Getenv("REGISTRATION_CLOSED")); err == nil {
+		cfg.Registration.Closed = closed
+	}
+}
+
+// parseKeyList parses "id:secret,id2:secret2" into a key ID → secret map
+func parseKeyList(s string) map[string]string {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		id, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if ok && id != "" && secret != "" {
+			keys[id] = secret
+		}
+	}
+	return keys
 }
 
 // LogResolved logs the resolved configuration values (secrets masked).
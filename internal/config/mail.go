@@ -0,0 +1,34 @@
+package config
+
+import (
+	"github.com/damoang/angple-backend/pkg/mailer"
+)
+
+// MailBackend 사용할 메일 백엔드 ("" 이면 메일 발송 없음)
+// backend 미지정 시 smtp_host가 있으면 smtp, drop_dir가 있으면 file
+func (c *Config) MailBackend() string {
+	switch {
+	case c.Mail.Backend != "":
+		return c.Mail.Backend
+	case c.Mail.SMTPHost != "":
+		return mailer.BackendSMTP
+	case c.Mail.DropDir != "":
+		return mailer.BackendFile
+	}
+	return ""
+}
+
+// MailerOptions 메일 백엔드 생성 옵션
+func (c *Config) MailerOptions() mailer.Options {
+	return mailer.Options{
+		Backend: c.MailBackend(),
+		From:    c.Mail.From,
+		SMTP: mailer.SMTPConfig{
+			Host:     c.Mail.SMTPHost,
+			Port:     c.Mail.SMTPPort,
+			Username: c.Mail.SMTPUsername,
+			Password: c.Mail.SMTPPassword,
+		},
+		File: mailer.FileConfig{Dir: c.Mail.DropDir},
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/damoang/angple-backend/pkg/storage"
+)
+
+// StorageDownloadPath 로컬 저장소 서명 다운로드 라우트
+const StorageDownloadPath = "/api/v2/media/files"
+
+// storageSigningInfo signing_secret 미설정 시 JWT secret에서 서명 키를 파생할 때의 용도 라벨
+const storageSigningInfo = "angple/storage/local-url-signing/v1"
+
+// StorageBackend 사용할 저장소 백엔드 ("" 이면 저장소 없음)
+// backend 미지정 시 S3 설정이 있으면 s3, data_paths.upload_path가 있으면 local
+func (c *Config) StorageBackend() string {
+	switch {
+	case c.Storage.Backend != "":
+		return c.Storage.Backend
+	case c.Storage.Enabled && c.Storage.Bucket != "":
+		return storage.BackendS3
+	case c.DataPaths.UploadPath != "":
+		return storage.BackendLocal
+	}
+	return ""
+}
+
+// StorageOptions 저장소 백엔드 생성 옵션
+func (c *Config) StorageOptions() storage.Options {
+	return storage.Options{
+		Backend: c.StorageBackend(),
+		S3: storage.S3Config{
+			Endpoint:        c.Storage.Endpoint,
+			Region:          c.Storage.Region,
+			AccessKeyID:     c.Storage.AccessKeyID,
+			SecretAccessKey: c.Storage.SecretAccessKey,
+			Bucket:          c.Storage.Bucket,
+			CDNURL:          c.Storage.CDNURL,
+			BasePath:        c.Storage.BasePath,
+			ForcePathStyle:  c.Storage.ForcePathStyle,
+		},
+		Local: storage.LocalConfig{
+			Root:          c.DataPaths.UploadPath,
+			DownloadURL:   strings.TrimRight(c.Storage.PublicURL, "/") + StorageDownloadPath,
+			CDNURL:        c.Storage.CDNURL,
+			BasePath:      c.Storage.BasePath,
+			SigningSecret: c.storageSigningSecret(),
+		},
+	}
+}
+
+// storageSigningSecret 로컬 다운로드 URL 서명 키
+// signing_secret이 없으면 JWT secret을 그대로 쓰지 않고 용도 라벨로 HKDF 파생한 키를 쓴다
+// (URL 서명이 노출돼도 JWT 서명 키와 같은 값이 되지 않도록).
+func (c *Config) storageSigningSecret() string {
+	if c.Storage.SigningSecret != "" {
+		return c.Storage.SigningSecret
+	}
+	if c.JWT.Secret == "" {
+		return ""
+	}
+	key, err := hkdf.Key(sha256.New, []byte(c.JWT.Secret), nil, storageSigningInfo, sha256.Size)
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(key)
+}
@@ -7,6 +7,7 @@ import (
 
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/internal/service"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -18,6 +19,8 @@ type Handler struct {
 	pointConfigRepo   v2repo.PointConfigRepository
 	gnuPointWriteRepo v2repo.GnuboardPointWriteRepository
 	notiRepo          gnurepo.NotiRepository
+	quotaService      *service.QuotaService
+	dunningService    *service.DunningService
 }
 
 // NewHandler creates a new cron Handler
@@ -40,6 +43,16 @@ func (h *Handler) SetPointExpiryDeps(
 	h.notiRepo = notiRepo
 }
 
+// SetQuotaService sets the quota service for the nightly quota reconcile job
+func (h *Handler) SetQuotaService(quotaService *service.QuotaService) {
+	h.quotaService = quotaService
+}
+
+// SetDunningService sets the dunning service for the subscription billing job
+func (h *Handler) SetDunningService(dunningService *service.DunningService) {
+	h.dunningService = dunningService
+}
+
 // verifySecret checks the secret query parameter
 func (h *Handler) verifySecret(c *gin.Context) bool {
 	if c.Query("secret") != h.secret {
@@ -154,3 +167,49 @@ func (h *Handler) AutoPromote(c *gin.Context) {
 	log.Printf("[Cron:auto-promote] promoted %d members: %v", result.PromotedCount, result.PromotedIDs)
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
 }
+
+// QuotaReconcile handles POST /api/internal/cron/quota-reconcile
+// Resets per-tenant quota counters in Redis from DB counts and SiteUsage (매일 새벽 실행)
+func (h *Handler) QuotaReconcile(c *gin.Context) {
+	if !h.verifySecret(c) {
+		return
+	}
+	if h.quotaService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"success": false, "error": "quota service not configured"})
+		return
+	}
+
+	result, err := h.quotaService.Reconcile(c.Request.Context())
+	if err != nil {
+		log.Printf("[Cron:quota-reconcile] error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	log.Printf("[Cron:quota-reconcile] sites %d, counters %d, errors %d", result.Sites, result.Counters, result.Errors)
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+}
+
+// BillingDunning handles POST /api/internal/cron/billing-dunning
+// Trial expiry notices/downgrades, renewals and failed payment retries (매시간 실행)
+func (h *Handler) BillingDunning(c *gin.Context) {
+	if !h.verifySecret(c) {
+		return
+	}
+	if h.dunningService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"success": false, "error": "dunning service not configured"})
+		return
+	}
+
+	result, err := h.dunningService.Run(c.Request.Context())
+	if err != nil {
+		log.Printf("[Cron:billing-dunning] error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error(), "data": result})
+		return
+	}
+
+	log.Printf("[Cron:billing-dunning] notices %d, converted %d, downgraded %d, renewed %d, retried %d, recovered %d, suspended %d, errors %d",
+		result.TrialNotices, result.TrialsConverted, result.TrialsDowngraded, result.Renewed,
+		result.Retried, result.Recovered, result.Suspended, result.Errors)
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+}
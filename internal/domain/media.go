@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// 미디어 참조 종류
+const (
+	MediaRefPost = "post" // RefID: <board_id>/<post_id>
+)
+
+// MediaObject is an uploaded storage object, deduplicated by content hash per site
+type MediaObject struct {
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	UnreferencedAt *time.Time `gorm:"column:unreferenced_at;index" json:"unreferenced_at,omitempty"` // 참조가 없어진 시각 (GC 유예 기준, 참조 중이면 nil)
+
+	SiteID      string `gorm:"column:site_id;type:varchar(255);uniqueIndex:idx_media_site_hash" json:"site_id,omitempty"`
+	Hash        string `gorm:"column:hash;type:char(64);uniqueIndex:idx_media_site_hash" json:"hash"` // SHA-256 (hex)
+	Key         string `gorm:"column:storage_key;type:varchar(512);uniqueIndex" json:"key"`
+	OwnerID     string `gorm:"column:owner_id;type:varchar(255);index" json:"owner_id"` // 최초 업로드 회원 (mb_id)
+	ContentType string `gorm:"column:content_type;type:varchar(100)" json:"content_type"`
+
+	ID   int64 `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Size int64 `gorm:"column:size" json:"size"`
+}
+
+func (MediaObject) TableName() string {
+	return "media_objects"
+}
+
+// MediaReference records that a post uses a media object
+type MediaReference struct {
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	RefType string `gorm:"column:ref_type;type:varchar(20);uniqueIndex:idx_media_ref" json:"ref_type"`
+	RefID   string `gorm:"column:ref_id;type:varchar(255);uniqueIndex:idx_media_ref" json:"ref_id"`
+
+	MediaID int64 `gorm:"column:media_id;uniqueIndex:idx_media_ref;index" json:"media_id"`
+}
+
+func (MediaReference) TableName() string {
+	return "media_references"
+}
+
+// MediaUsage is a member's deduplicated storage total
+type MediaUsage struct {
+	OwnerID string `json:"owner_id"`
+	Objects int64  `json:"objects"`
+	Bytes   int64  `json:"bytes"`
+}
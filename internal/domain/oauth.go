@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // OAuthProvider represents supported OAuth providers
 type OAuthProvider string
@@ -55,16 +58,34 @@ type OAuthLoginResponse struct {
 	RefreshToken string `json:"refresh_token"`
 	IsNewUser    bool   `json:"is_new_user"`
 	UserID       string `json:"user_id"`
+	// 2단계 인증 사용 회원: 토큰 대신 challenge_token으로 POST /api/v2/auth/oauth/2fa/verify 호출
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
 }
 
+// API key scopes ("<action>:<resource>", "*"는 해당 action의 모든 리소스)
+const (
+	ScopeReadPosts  = "read:posts"
+	ScopeWritePosts = "write:posts"
+	ScopeAdminAll   = "admin:*"
+)
+
+// APIKeyScopes lists the scopes that can be granted to an API key
+var APIKeyScopes = []string{ScopeReadPosts, ScopeWritePosts, ScopeAdminAll}
+
+// DefaultAPIKeyRateLimit is the per-key request limit per minute when RateLimit is 0
+const DefaultAPIKeyRateLimit = 120
+
 // APIKey represents an API key for external integrations
 type APIKey struct {
 	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
 	Key       string     `gorm:"column:api_key;uniqueIndex;size:64" json:"key"`
 	Name      string     `gorm:"column:name" json:"name"`
 	UserID    string     `gorm:"column:user_id;index" json:"user_id"`
-	Scopes    string     `gorm:"column:scopes" json:"scopes"` // comma-separated: read,write,admin
+	Scopes    string     `gorm:"column:scopes" json:"scopes"`                   // comma-separated: read:posts,write:posts,admin:*
+	RateLimit int        `gorm:"column:rate_limit;default:0" json:"rate_limit"` // requests per minute (0 = default)
 	Active    bool       `gorm:"column:active;default:true" json:"active"`
+	MFA       bool       `gorm:"column:mfa;default:false" json:"mfa"` // 2단계 인증 로그인에서 발급·재발급됨 (관리자 2FA 정책)
 	ExpiresAt *time.Time `gorm:"column:expires_at" json:"expires_at"`
 	LastUsed  *time.Time `gorm:"column:last_used_at" json:"last_used_at"`
 	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
@@ -73,3 +94,49 @@ type APIKey struct {
 func (APIKey) TableName() string {
 	return "api_keys"
 }
+
+// ScopeList returns the key's scopes; legacy scopes (read, write, admin) become "<scope>:*"
+func (k *APIKey) ScopeList() []string {
+	var scopes []string
+	for _, s := range strings.Split(k.Scopes, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !strings.Contains(s, ":") {
+			s += ":*"
+		}
+		scopes = append(scopes, s)
+	}
+	return scopes
+}
+
+// HasScope reports whether the key grants the required scope (e.g. "write:posts")
+// "admin:*" 는 admin 범위 전체를, "read:*" 는 모든 read 범위를 허용한다.
+func (k *APIKey) HasScope(required string) bool {
+	for _, s := range k.ScopeList() {
+		if s == required {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(s, "*"); ok && strings.HasPrefix(required, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveRateLimit returns the per-minute request limit for this key
+func (k *APIKey) EffectiveRateLimit() int {
+	if k.RateLimit > 0 {
+		return k.RateLimit
+	}
+	return DefaultAPIKeyRateLimit
+}
+
+// MaskedKey returns the key with everything but the prefix and last 4 characters hidden
+func (k *APIKey) MaskedKey() string {
+	if len(k.Key) <= 12 {
+		return "****"
+	}
+	return k.Key[:7] + "..." + k.Key[len(k.Key)-4:]
+}
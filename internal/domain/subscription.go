@@ -33,20 +33,43 @@ type Invoice struct {
 	PeriodEnd   time.Time  `gorm:"column:period_end" json:"period_end"`
 
 	SiteID          string `gorm:"column:site_id;type:varchar(255);index" json:"site_id"`
-	Status          string `gorm:"column:status;default:pending" json:"status"` // pending, paid, failed, refunded
+	Status          string `gorm:"column:status;default:pending" json:"status"` // pending, paid, failed, refunded, credit, applied
 	PaymentProvider string `gorm:"column:payment_provider" json:"payment_provider"`
-	ExternalInvID   string `gorm:"column:external_inv_id" json:"external_inv_id"`
+	ExternalInvID   string `gorm:"column:external_inv_id;index" json:"external_inv_id"` // 결제 주문 ID (orderId)
+	PaymentKey      string `gorm:"column:payment_key" json:"payment_key,omitempty"`
 	Description     string `gorm:"column:description" json:"description"`
 	Currency        string `gorm:"column:currency;default:KRW" json:"currency"`
 
+	NextRetryAt *time.Time `gorm:"column:next_retry_at;index" json:"next_retry_at,omitempty"` // 결제 실패 재시도 예정 시각 (nil = 재시도 없음)
+
 	ID        int64 `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
 	AmountKRW int   `gorm:"column:amount_krw" json:"amount_krw"`
+	Attempts  int   `gorm:"column:attempts;default:0" json:"attempts"`
 }
 
 func (Invoice) TableName() string {
 	return "invoices"
 }
 
+// SubscriptionEvent is an audit trail entry for subscription billing (상태 변경, 알림, 결제 재시도, 정지)
+type SubscriptionEvent struct {
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;index" json:"created_at"`
+
+	SiteID     string `gorm:"column:site_id;type:varchar(255);index" json:"site_id"`
+	Type       string `gorm:"column:type;type:varchar(50);index" json:"type"` // status_changed, trial_ending_notice, trial_downgraded, payment_retry, suspended
+	FromStatus string `gorm:"column:from_status" json:"from_status,omitempty"`
+	ToStatus   string `gorm:"column:to_status" json:"to_status,omitempty"`
+	Actor      string `gorm:"column:actor" json:"actor"` // system:dunning, webhook:toss, checkout, plan_change
+	Message    string `gorm:"column:message;type:text" json:"message"`
+
+	InvoiceID *int64 `gorm:"column:invoice_id" json:"invoice_id,omitempty"`
+	ID        int64  `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+}
+
+func (SubscriptionEvent) TableName() string {
+	return "subscription_events"
+}
+
 // ProvisionRequest is the request body for one-click community creation
 type ProvisionRequest struct {
 	Subdomain  string  `json:"subdomain" binding:"required,min=3,max=50"`
@@ -84,6 +107,19 @@ type ChangePlanRequest struct {
 	BillingCycle string `json:"billing_cycle" binding:"omitempty,oneof=monthly yearly"`
 }
 
+// CheckoutRequest starts payment method registration for the current plan
+type CheckoutRequest struct {
+	Provider   string `json:"provider"` // 비어 있으면 기본 결제사
+	SuccessURL string `json:"success_url" binding:"required,url"`
+	FailURL    string `json:"fail_url" binding:"required,url"`
+}
+
+// CheckoutConfirmRequest completes checkout with the gateway's auth result
+type CheckoutConfirmRequest struct {
+	OrderID string `json:"order_id" binding:"required"`
+	AuthKey string `json:"auth_key" binding:"required"`
+}
+
 // PlanPricing defines pricing for each plan
 type PlanPricing struct {
 	Plan       string `json:"plan"`
@@ -6,21 +6,22 @@ import (
 
 // V2User represents a user in the v2 schema
 type V2User struct {
-	ID          uint64    `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
-	Username    string    `gorm:"column:username;type:varchar(50);uniqueIndex" json:"username"`
-	Email       string    `gorm:"column:email;type:varchar(255);uniqueIndex" json:"email"`
-	Password    string    `gorm:"column:password;type:varchar(255)" json:"-"`
-	Nickname    string    `gorm:"column:nickname;type:varchar(100)" json:"nickname"`
-	Level       uint8     `gorm:"column:level;default:1" json:"level"`
-	Point       int       `gorm:"column:point;default:0" json:"point"`
-	Exp         int       `gorm:"column:exp;default:0" json:"exp"`
-	NariyaLevel uint8     `gorm:"column:nariya_level;default:1" json:"nariya_level"`
-	NariyaMax   int       `gorm:"column:nariya_max;default:1000" json:"nariya_max"`
-	Status      string    `gorm:"column:status;type:enum('active','inactive','banned');default:'active'" json:"status"`
-	AvatarURL   *string   `gorm:"column:avatar_url;type:varchar(500)" json:"avatar_url,omitempty"`
-	Bio         *string   `gorm:"column:bio;type:text" json:"bio,omitempty"`
-	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	ID           uint64    `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Username     string    `gorm:"column:username;type:varchar(50);uniqueIndex" json:"username"`
+	Email        string    `gorm:"column:email;type:varchar(255);uniqueIndex" json:"email"`
+	Password     string    `gorm:"column:password;type:varchar(255)" json:"-"`
+	Nickname     string    `gorm:"column:nickname;type:varchar(100)" json:"nickname"`
+	Level        uint8     `gorm:"column:level;default:1" json:"level"`
+	Point        int       `gorm:"column:point;default:0" json:"point"`
+	Exp          int       `gorm:"column:exp;default:0" json:"exp"`
+	NariyaLevel  uint8     `gorm:"column:nariya_level;default:1" json:"nariya_level"`
+	NariyaMax    int       `gorm:"column:nariya_max;default:1000" json:"nariya_max"`
+	Status       string    `gorm:"column:status;type:enum('active','inactive','banned');default:'active'" json:"status"`
+	AvatarURL    *string   `gorm:"column:avatar_url;type:varchar(500)" json:"avatar_url,omitempty"`
+	Bio          *string   `gorm:"column:bio;type:text" json:"bio,omitempty"`
+	EmailPending bool      `gorm:"column:email_pending;default:false" json:"email_pending,omitempty"` // 가입 메일 인증 전 (기존/이관 회원은 false)
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 }
 
 func (V2User) TableName() string { return "v2_users" }
@@ -28,7 +29,8 @@ func (V2User) TableName() string { return "v2_users" }
 // V2Board represents a board in the v2 schema
 type V2Board struct {
 	ID          uint64  `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
-	Slug        string  `gorm:"column:slug;type:varchar(50);uniqueIndex" json:"slug"`
+	SiteID      string  `gorm:"column:site_id;type:varchar(36);not null;default:'';uniqueIndex:idx_v2_boards_site_slug,priority:1" json:"-"` // 테넌트 (''=메인 사이트)
+	Slug        string  `gorm:"column:slug;type:varchar(50);uniqueIndex:idx_v2_boards_site_slug,priority:2" json:"slug"`
 	Name        string  `gorm:"column:name;type:varchar(100)" json:"name"`
 	Description *string `gorm:"column:description;type:text" json:"description,omitempty"`
 	CategoryID  *uint64 `gorm:"column:category_id" json:"category_id,omitempty"`
@@ -70,6 +72,7 @@ func (V2Board) TableName() string { return "v2_boards" }
 // V2Post represents a post in the v2 schema
 type V2Post struct {
 	ID           uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	SiteID       string     `gorm:"column:site_id;type:varchar(36);not null;default:'';index" json:"-"`
 	BoardID      uint64     `gorm:"column:board_id;index" json:"board_id"`
 	UserID       uint64     `gorm:"column:user_id;index" json:"user_id"`
 	Title        string     `gorm:"column:title;type:varchar(255)" json:"title"`
@@ -90,6 +93,7 @@ func (V2Post) TableName() string { return "v2_posts" }
 // V2Comment represents a comment in the v2 schema
 type V2Comment struct {
 	ID        uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	SiteID    string     `gorm:"column:site_id;type:varchar(36);not null;default:'';index" json:"-"`
 	PostID    uint64     `gorm:"column:post_id;index" json:"post_id"`
 	UserID    uint64     `gorm:"column:user_id;index" json:"user_id"`
 	ParentID  *uint64    `gorm:"column:parent_id" json:"parent_id,omitempty"`
@@ -168,18 +172,94 @@ type V2Notification struct {
 
 func (V2Notification) TableName() string { return "v2_notifications" }
 
-// V2Session represents a user session
+// V2Session represents a user session (로그인 기기 1개 = 리프레시 토큰 회전 family 1개)
 type V2Session struct {
-	ID        string    `gorm:"column:id;type:varchar(128);primaryKey" json:"id"`
-	UserID    uint64    `gorm:"column:user_id;index" json:"user_id"`
-	UserAgent *string   `gorm:"column:user_agent;type:varchar(500)" json:"user_agent,omitempty"`
-	IPAddress *string   `gorm:"column:ip_address;type:varchar(45)" json:"ip_address,omitempty"`
-	ExpiresAt time.Time `gorm:"column:expires_at;index" json:"expires_at"`
-	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	ID          string     `gorm:"column:id;type:varchar(128);primaryKey" json:"id"`
+	UserID      uint64     `gorm:"column:user_id;index" json:"user_id"`
+	Subject     string     `gorm:"column:subject;type:varchar(100);index" json:"-"` // v2 회원이 아닌 계정(OAuth)의 JWT subject, v2 회원은 빈 값
+	RefreshHash string     `gorm:"column:refresh_hash;type:char(64)" json:"-"`      // 현재 유효한 리프레시 토큰의 SHA-256
+	UserAgent   *string    `gorm:"column:user_agent;type:varchar(500)" json:"user_agent,omitempty"`
+	IPAddress   *string    `gorm:"column:ip_address;type:varchar(45)" json:"ip_address,omitempty"`
+	ExpiresAt   time.Time  `gorm:"column:expires_at;index" json:"expires_at"`
+	LastUsedAt  time.Time  `gorm:"column:last_used_at" json:"last_used_at"`
+	RevokedAt   *time.Time `gorm:"column:revoked_at" json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 }
 
 func (V2Session) TableName() string { return "v2_sessions" }
 
+// 로그인 시도 결과
+const (
+	LoginResultSuccess   = "success"
+	LoginResultFailed    = "invalid_credentials"
+	LoginResultInactive  = "inactive"
+	LoginResultThrottled = "throttled"
+	LoginResultLocked    = "locked"
+	// 비밀번호 확인 후 2단계 인증 대기 / 2단계 인증 실패
+	LoginResultTwoFactorPending = "2fa_required"
+	LoginResultTwoFactorFailed  = "invalid_otp"
+	// 가입 메일 인증 전
+	LoginResultEmailUnverified = "email_unverified"
+)
+
+// V2LoginAttempt is an audit log entry for a login attempt
+type V2LoginAttempt struct {
+	ID        uint64    `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Username  string    `gorm:"column:username;type:varchar(50);index" json:"username"`
+	UserID    *uint64   `gorm:"column:user_id" json:"user_id,omitempty"`
+	IPAddress string    `gorm:"column:ip_address;type:varchar(45);index" json:"ip_address"`
+	UserAgent *string   `gorm:"column:user_agent;type:varchar(500)" json:"user_agent,omitempty"`
+	Result    string    `gorm:"column:result;type:varchar(30)" json:"result"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;index" json:"created_at"`
+}
+
+func (V2LoginAttempt) TableName() string { return "v2_login_attempts" }
+
+// V2TwoFactor is a member's TOTP second factor.
+// UserID는 JWT의 user_id 문자열이다 (v2 회원은 숫자 ID, OAuth 회원은 "oauth_<provider>_<uid>").
+type V2TwoFactor struct {
+	UserID       string     `gorm:"column:user_id;type:varchar(100);primaryKey" json:"-"`
+	Secret       string     `gorm:"column:secret;type:varchar(64)" json:"-"`       // base32
+	EnabledAt    *time.Time `gorm:"column:enabled_at" json:"enabled_at,omitempty"` // nil이면 등록 확인 전
+	LastUsedStep int64      `gorm:"column:last_used_step" json:"-"`                // 마지막으로 사용된 OTP time step (재사용 방지)
+	FailedCount  int        `gorm:"column:failed_count" json:"-"`                  // 연속 인증 실패 횟수
+	LockedUntil  *time.Time `gorm:"column:locked_until" json:"-"`
+	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (V2TwoFactor) TableName() string { return "v2_two_factors" }
+
+// V2RecoveryCode is a one-time 2FA recovery code (SHA-256 해시만 저장)
+type V2RecoveryCode struct {
+	ID        uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID    string     `gorm:"column:user_id;type:varchar(100);index" json:"user_id"`
+	CodeHash  string     `gorm:"column:code_hash;type:char(64)" json:"-"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (V2RecoveryCode) TableName() string { return "v2_recovery_codes" }
+
+// 회원 토큰 용도
+const (
+	UserTokenVerifyEmail   = "verify_email"
+	UserTokenResetPassword = "reset_password"
+)
+
+// V2UserToken is a single-use emailed token (메일 인증, 비밀번호 재설정). SHA-256 해시만 저장
+type V2UserToken struct {
+	ID        uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID    uint64     `gorm:"column:user_id;index" json:"user_id"`
+	Purpose   string     `gorm:"column:purpose;type:varchar(20)" json:"purpose"`
+	TokenHash string     `gorm:"column:token_hash;type:char(64);uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"column:expires_at" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (V2UserToken) TableName() string { return "v2_user_tokens" }
+
 // Meta tables for plugin extensibility
 
 // UserMeta represents user metadata for plugins
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/payment"
+	"github.com/damoang/angple-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// BillingHandler handles subscription checkout and payment webhooks
+type BillingHandler struct {
+	billingSvc *service.BillingService
+}
+
+// NewBillingHandler creates a new BillingHandler
+func NewBillingHandler(billingSvc *service.BillingService) *BillingHandler {
+	return &BillingHandler{billingSvc: billingSvc}
+}
+
+// CreateCheckout godoc
+// @Summary 결제 수단 등록 (체크아웃 세션 생성)
+// @Tags saas
+// @Accept json
+// @Param id path string true "사이트 ID"
+// @Param body body domain.CheckoutRequest true "체크아웃 정보"
+// @Success 201 {object} common.V2Response
+// @Router /api/v2/saas/communities/{id}/checkout [post]
+func (h *BillingHandler) CreateCheckout(c *gin.Context) {
+	var req domain.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "요청 형식이 올바르지 않습니다", err)
+		return
+	}
+
+	session, err := h.billingSvc.CreateCheckout(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	common.V2Created(c, session)
+}
+
+// ConfirmCheckout godoc
+// @Summary 결제 수단 등록 완료 (빌링키 발급 및 첫 결제)
+// @Tags saas
+// @Accept json
+// @Param id path string true "사이트 ID"
+// @Param body body domain.CheckoutConfirmRequest true "결제사 인증 결과"
+// @Success 200 {object} common.V2Response
+// @Router /api/v2/saas/communities/{id}/checkout/confirm [post]
+func (h *BillingHandler) ConfirmCheckout(c *gin.Context) {
+	var req domain.CheckoutConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "요청 형식이 올바르지 않습니다", err)
+		return
+	}
+
+	invoice, err := h.billingSvc.ConfirmCheckout(c.Request.Context(), c.Param("id"), &req)
+	var declined *payment.ChargeError
+	if errors.As(err, &declined) {
+		common.V2ErrorResponse(c, http.StatusPaymentRequired, "결제가 거절되었습니다", err)
+		return
+	}
+	var gatewayErr *payment.GatewayError
+	if errors.As(err, &gatewayErr) {
+		common.V2ErrorResponse(c, http.StatusBadGateway, "결제사 응답 오류입니다. 잠시 후 다시 시도해주세요", err)
+		return
+	}
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	common.V2Success(c, invoice)
+}
+
+// ListEvents godoc
+// @Summary 구독 감사 로그 조회 (상태 변경, 결제 재시도, 정지)
+// @Tags saas
+// @Param id path string true "사이트 ID"
+// @Param page query int false "페이지" default(1)
+// @Param per_page query int false "페이지당 항목" default(20)
+// @Success 200 {object} common.V2Response
+// @Router /api/v2/saas/communities/{id}/subscription/events [get]
+func (h *BillingHandler) ListEvents(c *gin.Context) {
+	page := parseIntQuery(c, "page", 1)
+	perPage := parseIntQuery(c, "per_page", 20)
+
+	events, total, err := h.billingSvc.ListEvents(c.Request.Context(), c.Param("id"), page, perPage)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "구독 이력 조회 실패", err)
+		return
+	}
+	common.V2SuccessWithMeta(c, events, common.NewV2Meta(page, perPage, total))
+}
+
+// Webhook godoc
+// @Summary 결제사 웹훅 수신 (서명 검증)
+// @Tags saas
+// @Param provider path string true "결제사 (toss)"
+// @Success 200 {object} common.V2Response
+// @Router /api/v2/saas/billing/webhooks/{provider} [post]
+func (h *BillingHandler) Webhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "요청 본문을 읽을 수 없습니다", err)
+		return
+	}
+
+	err = h.billingSvc.HandleWebhook(c.Request.Context(), c.Param("provider"), c.Request.Header, body)
+	switch {
+	case errors.Is(err, service.ErrUnknownPaymentProvider):
+		common.V2ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, payment.ErrInvalidSignature):
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "웹훅 서명이 올바르지 않습니다", nil)
+	case err != nil:
+		// 5xx를 반환하면 결제사가 재전송한다
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "웹훅 처리 실패", err)
+	default:
+		common.V2Success(c, gin.H{"received": true})
+	}
+}
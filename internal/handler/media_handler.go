@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"path"
 	"strconv"
+	"strings"
 
 	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/middleware"
 	"github.com/damoang/angple-backend/internal/service"
+	"github.com/damoang/angple-backend/pkg/storage"
 	"github.com/gin-gonic/gin"
 )
 
@@ -33,9 +38,11 @@ func (h *MediaHandler) UploadImage(c *gin.Context) {
 		maxWidth = val
 	}
 
-	result, err := h.mediaService.UploadImage(c.Request.Context(), file, maxWidth)
+	result, err := h.mediaService.UploadImage(c.Request.Context(), middleware.GetUserID(c), file, maxWidth)
 	if err != nil {
-		common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		if !common.RespondQuotaExceeded(c, err) {
+			common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		}
 		return
 	}
 
@@ -51,9 +58,11 @@ func (h *MediaHandler) UploadAttachment(c *gin.Context) {
 		return
 	}
 
-	result, err := h.mediaService.UploadAttachment(c.Request.Context(), file)
+	result, err := h.mediaService.UploadAttachment(c.Request.Context(), middleware.GetUserID(c), file)
 	if err != nil {
-		common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		if !common.RespondQuotaExceeded(c, err) {
+			common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		}
 		return
 	}
 
@@ -69,9 +78,11 @@ func (h *MediaHandler) UploadVideo(c *gin.Context) {
 		return
 	}
 
-	result, err := h.mediaService.UploadVideo(c.Request.Context(), file)
+	result, err := h.mediaService.UploadVideo(c.Request.Context(), middleware.GetUserID(c), file)
 	if err != nil {
-		common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		if !common.RespondQuotaExceeded(c, err) {
+			common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		}
 		return
 	}
 
@@ -89,10 +100,118 @@ func (h *MediaHandler) DeleteFile(c *gin.Context) {
 		return
 	}
 
-	if err := h.mediaService.DeleteFile(c.Request.Context(), req.Key); err != nil {
+	err := h.mediaService.DeleteFile(c.Request.Context(), req.Key, middleware.GetUserID(c), middleware.IsAdmin(c))
+	switch {
+	case errors.Is(err, service.ErrMediaInUse):
+		common.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+		return
+	case errors.Is(err, service.ErrMediaNotOwner):
+		common.ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+		return
+	case err != nil:
 		common.ErrorResponse(c, http.StatusInternalServerError, "Delete failed: "+err.Error(), nil)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "file deleted"})
 }
+
+// DownloadFile serves a local-storage file through a signed URL
+// GET /api/v2/media/files/*key?expires=&signature=
+func (h *MediaHandler) DownloadFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	expires := c.Query("expires")
+
+	f, err := h.mediaService.OpenSignedFile(key, expires, c.Query("signature"))
+	switch {
+	case errors.Is(err, storage.ErrInvalidSignature):
+		common.ErrorResponse(c, http.StatusForbidden, "Invalid or expired download URL", nil)
+		return
+	case errors.Is(err, storage.ErrNotFound), errors.Is(err, storage.ErrInvalidKey):
+		common.ErrorResponse(c, http.StatusNotFound, "File not found", nil)
+		return
+	case err != nil:
+		common.ErrorResponse(c, http.StatusInternalServerError, "Download failed", err)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		common.ErrorResponse(c, http.StatusInternalServerError, "Download failed", err)
+		return
+	}
+
+	// 사용자 업로드 파일(SVG 등)이 API 출처에서 스크립트로 실행되지 않도록 격리
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	if expires == "" {
+		c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		c.Header("Cache-Control", "private, no-store")
+	}
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime(), f)
+}
+
+// GetVariant redirects to an image variant, generating it on first request
+// GET /api/v2/media/variants/:name?key=
+func (h *MediaHandler) GetVariant(c *gin.Context) {
+	key := c.Query("key")
+	if key == "" {
+		common.ErrorResponse(c, http.StatusBadRequest, "key is required", nil)
+		return
+	}
+
+	url, err := h.mediaService.VariantURL(c.Request.Context(), key, c.Param("name"))
+	switch {
+	case errors.Is(err, service.ErrUnknownVariant), errors.Is(err, service.ErrInvalidVariantKey):
+		common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	case errors.Is(err, storage.ErrNotFound), errors.Is(err, storage.ErrInvalidKey):
+		common.ErrorResponse(c, http.StatusNotFound, "File not found", nil)
+		return
+	case errors.Is(err, service.ErrVariantQueueFull), errors.Is(err, service.ErrVariantPoolStopped):
+		common.ErrorResponse(c, http.StatusServiceUnavailable, err.Error(), nil)
+		return
+	case err != nil:
+		common.ErrorResponse(c, http.StatusInternalServerError, "Variant generation failed", err)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Redirect(http.StatusFound, url)
+}
+
+// GetUsage returns the current member's storage total
+// GET /api/v2/media/usage
+func (h *MediaHandler) GetUsage(c *gin.Context) {
+	usage, err := h.mediaService.Usage(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		h.usageError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": usage})
+}
+
+// TopUsage lists the members using the most storage (관리자)
+// GET /api/v2/admin/media/usage?limit=
+func (h *MediaHandler) TopUsage(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+	usage, err := h.mediaService.TopUsage(c.Request.Context(), limit)
+	if err != nil {
+		h.usageError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": usage})
+}
+
+func (h *MediaHandler) usageError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrMediaLibraryDisabled) {
+		common.ErrorResponse(c, http.StatusNotImplemented, err.Error(), nil)
+		return
+	}
+	common.ErrorResponse(c, http.StatusInternalServerError, "Failed to load storage usage", err)
+}
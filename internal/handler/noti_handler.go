@@ -122,6 +122,11 @@ func convertLegacyURL(rawURL string) string {
 	return result
 }
 
+// NotificationPayload formats a notification like the v1 list API (실시간 "notification" 이벤트용)
+func NotificationPayload(n gnurepo.Notification) interface{} {
+	return toV1Notification(n)
+}
+
 func toV1Notification(n gnurepo.Notification) v1NotificationResponse {
 	return v1NotificationResponse{
 		ID:            n.PhID,
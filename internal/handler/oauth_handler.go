@@ -3,12 +3,17 @@ package handler
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 
 	"github.com/damoang/angple-backend/internal/common"
 	"github.com/damoang/angple-backend/internal/domain"
 	"github.com/damoang/angple-backend/internal/middleware"
 	"github.com/damoang/angple-backend/internal/service"
+	v2svc "github.com/damoang/angple-backend/internal/service/v2"
 	"github.com/gin-gonic/gin"
 )
 
@@ -69,7 +74,10 @@ func (h *OAuthHandler) Callback(c *gin.Context) {
 		return
 	}
 
-	result, err := h.oauthService.HandleCallback(c.Request.Context(), provider, code)
+	result, err := h.oauthService.HandleCallback(c.Request.Context(), provider, code, oauthSessionClient(c))
+	if common.RespondQuotaExceeded(c, err) {
+		return
+	}
 	if err != nil {
 		common.ErrorResponse(c, http.StatusInternalServerError, "OAuth login failed: "+err.Error(), nil)
 		return
@@ -78,6 +86,36 @@ func (h *OAuthHandler) Callback(c *gin.Context) {
 	common.SuccessResponse(c, result, nil)
 }
 
+// VerifyTwoFactor completes an OAuth login that requires a second factor
+// POST /api/v2/auth/oauth/2fa/verify
+func (h *OAuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code" binding:"required"` // OTP 6자리 또는 복구 코드
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.ErrorResponse(c, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	result, err := h.oauthService.VerifyTwoFactor(req.ChallengeToken, req.Code, oauthSessionClient(c))
+	if common.RespondLoginThrottled(c, err) {
+		return
+	}
+	switch {
+	case err == nil:
+		common.SuccessResponse(c, result, nil)
+	case errors.Is(err, v2svc.ErrInvalidOTP):
+		common.ErrorResponse(c, http.StatusUnauthorized, "Invalid verification code", nil)
+	case errors.Is(err, v2svc.ErrInvalidChallenge), errors.Is(err, v2svc.ErrTwoFactorNotEnabled):
+		common.ErrorResponse(c, http.StatusUnauthorized, "Two-factor challenge expired, please sign in again", nil)
+	case errors.Is(err, v2svc.ErrTwoFactorUnavailable):
+		common.ErrorResponse(c, http.StatusNotImplemented, "Two-factor authentication is not available", nil)
+	default:
+		common.ErrorResponse(c, http.StatusInternalServerError, "OAuth login failed: "+err.Error(), nil)
+	}
+}
+
 // GenerateAPIKey creates a new API key for the authenticated user
 // POST /api/v2/auth/api-keys
 func (h *OAuthHandler) GenerateAPIKey(c *gin.Context) {
@@ -88,8 +126,9 @@ func (h *OAuthHandler) GenerateAPIKey(c *gin.Context) {
 	}
 
 	var req struct {
-		Name   string `json:"name" binding:"required"`
-		Scopes string `json:"scopes"` // comma-separated: read,write
+		Name      string `json:"name" binding:"required"`
+		Scopes    string `json:"scopes"`     // comma-separated: read:posts,write:posts,admin:*
+		RateLimit int    `json:"rate_limit"` // requests per minute (0 = default)
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.ErrorResponse(c, http.StatusBadRequest, "Invalid request", nil)
@@ -97,10 +136,31 @@ func (h *OAuthHandler) GenerateAPIKey(c *gin.Context) {
 	}
 
 	if req.Scopes == "" {
-		req.Scopes = "read"
+		req.Scopes = domain.ScopeReadPosts
+	}
+	if req.RateLimit < 0 || req.RateLimit > maxAPIKeyRateLimit {
+		common.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("rate_limit must be between 0 and %d", maxAPIKeyRateLimit), nil)
+		return
 	}
 
-	apiKey, err := h.oauthService.GenerateAPIKey(c.Request.Context(), userID, req.Name, req.Scopes)
+	candidate := &domain.APIKey{Scopes: req.Scopes}
+	for _, scope := range candidate.ScopeList() {
+		if !slices.Contains(domain.APIKeyScopes, scope) && scope != "read:*" && scope != "write:*" {
+			common.ErrorResponse(c, http.StatusBadRequest, "Unknown scope: "+scope, nil)
+			return
+		}
+	}
+	// 관리자 범위는 관리자만 발급 가능 (관리자 2FA 요구 시 2단계 인증 로그인 필요 — 키로 우회 방지)
+	if candidate.HasScope(domain.ScopeAdminAll) && middleware.GetUserLevel(c) < 10 {
+		common.ErrorResponse(c, http.StatusForbidden, "Only administrators can issue admin scope keys", nil)
+		return
+	}
+	if candidate.HasScope(domain.ScopeAdminAll) && middleware.AdminMFARequired() && !middleware.IsMFAAuthenticated(c) {
+		common.ErrorResponse(c, http.StatusForbidden, "Two-factor login is required to issue admin scope keys", nil)
+		return
+	}
+
+	apiKey, err := h.oauthService.GenerateAPIKey(c.Request.Context(), userID, req.Name, req.Scopes, req.RateLimit, middleware.IsMFAAuthenticated(c))
 	if err != nil {
 		common.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate API key", nil)
 		return
@@ -108,3 +168,86 @@ func (h *OAuthHandler) GenerateAPIKey(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{"success": true, "data": apiKey})
 }
+
+// maxAPIKeyRateLimit 키별 분당 요청 한도 상한
+const maxAPIKeyRateLimit = 6000
+
+// ListAPIKeys returns the authenticated user's API keys (키 값은 마스킹)
+// GET /api/v2/auth/api-keys
+func (h *OAuthHandler) ListAPIKeys(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		common.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	keys, err := h.oauthService.ListAPIKeys(c.Request.Context(), userID)
+	if err != nil {
+		common.ErrorResponse(c, http.StatusInternalServerError, "Failed to list API keys", nil)
+		return
+	}
+	for i := range keys {
+		keys[i].Key = keys[i].MaskedKey()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": keys})
+}
+
+// RevokeAPIKey deactivates one of the authenticated user's API keys
+// DELETE /api/v2/auth/api-keys/:id
+func (h *OAuthHandler) RevokeAPIKey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		common.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.ErrorResponse(c, http.StatusBadRequest, "Invalid key ID", nil)
+		return
+	}
+
+	if err := h.oauthService.RevokeAPIKey(c.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			common.ErrorResponse(c, http.StatusNotFound, "API key not found", nil)
+			return
+		}
+		common.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke API key", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// RotateAPIKey issues a new secret for an API key, invalidating the old one
+// 2단계 인증 로그인에서 재발급한 키만 관리자 2FA 요구를 충족한다.
+// POST /api/v2/auth/api-keys/:id/rotate
+func (h *OAuthHandler) RotateAPIKey(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		common.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.ErrorResponse(c, http.StatusBadRequest, "Invalid key ID", nil)
+		return
+	}
+
+	apiKey, err := h.oauthService.RotateAPIKey(c.Request.Context(), userID, id, middleware.IsMFAAuthenticated(c))
+	if err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			common.ErrorResponse(c, http.StatusNotFound, "API key not found", nil)
+			return
+		}
+		common.ErrorResponse(c, http.StatusInternalServerError, "Failed to rotate API key", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": apiKey})
+}
+
+// oauthSessionClient returns the login client recorded on the OAuth session
+func oauthSessionClient(c *gin.Context) v2svc.SessionClient {
+	return v2svc.SessionClient{UserAgent: c.Request.UserAgent(), IPAddress: c.ClientIP()}
+}
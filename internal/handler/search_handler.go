@@ -12,6 +12,7 @@ import (
 // SearchHandler handles Elasticsearch-based search endpoints
 type SearchHandler struct {
 	searchService *service.SearchService
+	indexer       *service.SearchIndexer
 }
 
 // NewSearchHandler creates a new SearchHandler
@@ -19,6 +20,11 @@ func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
 	return &SearchHandler{searchService: searchService}
 }
 
+// SetIndexer sets the indexing pipeline for index lag reporting
+func (h *SearchHandler) SetIndexer(indexer *service.SearchIndexer) {
+	h.indexer = indexer
+}
+
 // Search performs unified search across posts and comments
 // GET /api/v2/search?q=keyword&board_id=free&type=posts&page=1&per_page=20
 func (h *SearchHandler) Search(c *gin.Context) {
@@ -155,3 +161,15 @@ func (h *SearchHandler) DeletePostIndex(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "post removed from index"})
 }
+
+// IndexLag reports the indexing pipeline backlog (admin)
+// GET /api/v2/admin/search/index-lag
+func (h *SearchHandler) IndexLag(c *gin.Context) {
+	lag, err := h.indexer.Lag(c.Request.Context())
+	if err != nil {
+		common.ErrorResponse(c, http.StatusInternalServerError, "Index lag lookup failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": lag})
+}
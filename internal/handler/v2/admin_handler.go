@@ -6,6 +6,9 @@ import (
 
 	"github.com/damoang/angple-backend/internal/common"
 	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	"github.com/damoang/angple-backend/internal/middleware"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/internal/service"
 	v2svc "github.com/damoang/angple-backend/internal/service/v2"
 	"github.com/gin-gonic/gin"
 )
@@ -13,6 +16,7 @@ import (
 // AdminHandler handles v2 admin API endpoints
 type AdminHandler struct {
 	adminService *v2svc.AdminService
+	quotaService *service.QuotaService
 }
 
 // NewAdminHandler creates a new AdminHandler
@@ -20,9 +24,22 @@ func NewAdminHandler(adminService *v2svc.AdminService) *AdminHandler {
 	return &AdminHandler{adminService: adminService}
 }
 
+// SetQuotaService sets the tenant plan quota service (게시판 수 한도)
+func (h *AdminHandler) SetQuotaService(q *service.QuotaService) {
+	h.quotaService = q
+}
+
+// boardService returns the admin service bound to the request's tenant boards
+func (h *AdminHandler) boardService(c *gin.Context) *v2svc.AdminService {
+	if db := middleware.GetTenantDB(c); db != nil {
+		return h.adminService.WithBoardRepository(v2repo.NewBoardRepository(db))
+	}
+	return h.adminService
+}
+
 // ListBoards handles GET /api/v2/admin/boards
 func (h *AdminHandler) ListBoards(c *gin.Context) {
-	boards, err := h.adminService.ListAllBoards()
+	boards, err := h.boardService(c).ListAllBoards()
 	if err != nil {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "게시판 목록 조회 실패", err)
 		return
@@ -61,7 +78,14 @@ func (h *AdminHandler) CreateBoard(c *gin.Context) {
 		board.OrderNum = *req.OrderNum
 	}
 
-	if err := h.adminService.CreateBoard(board); err != nil {
+	if err := h.quotaService.Reserve(c.Request.Context(), common.QuotaBoards, 1); err != nil {
+		if !common.RespondQuotaExceeded(c, err) {
+			common.V2ErrorResponse(c, http.StatusInternalServerError, "게시판 생성 실패", err)
+		}
+		return
+	}
+	if err := h.boardService(c).CreateBoard(board); err != nil {
+		h.quotaService.Release(c.Request.Context(), common.QuotaBoards, 1)
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "게시판 생성 실패", err)
 		return
 	}
@@ -107,7 +131,7 @@ func (h *AdminHandler) UpdateBoard(c *gin.Context) {
 		board.OrderNum = *req.OrderNum
 	}
 
-	if err := h.adminService.UpdateBoard(board); err != nil {
+	if err := h.boardService(c).UpdateBoard(board); err != nil {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "게시판 수정 실패", err)
 		return
 	}
@@ -121,10 +145,11 @@ func (h *AdminHandler) DeleteBoard(c *gin.Context) {
 		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 게시판 ID", err)
 		return
 	}
-	if err := h.adminService.DeleteBoard(id); err != nil {
+	if err := h.boardService(c).DeleteBoard(id); err != nil {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "게시판 삭제 실패", err)
 		return
 	}
+	h.quotaService.Release(c.Request.Context(), common.QuotaBoards, 1)
 	common.V2Success(c, gin.H{"message": "삭제 완료"})
 }
 
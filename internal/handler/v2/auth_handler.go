@@ -1,6 +1,7 @@
 package v2
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -55,12 +56,35 @@ func (h *V2AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.Login(req.Username, req.Password)
+	resp, err := h.authService.Login(req.Username, req.Password, sessionClient(c))
 	if err != nil {
-		common.V2ErrorResponse(c, http.StatusUnauthorized, "로그인에 실패했습니다", err)
+		switch {
+		case common.RespondLoginThrottled(c, err):
+		case errors.Is(err, v2svc.ErrEmailNotVerified):
+			c.JSON(http.StatusForbidden, common.V2Response{
+				Success: false,
+				Error:   &common.V2Error{Code: "EMAIL_NOT_VERIFIED", Message: "이메일 인증 후 로그인할 수 있습니다"},
+			})
+		default:
+			common.V2ErrorResponse(c, http.StatusUnauthorized, "로그인에 실패했습니다", err)
+		}
+		return
+	}
+	if resp.TwoFactorRequired {
+		// 토큰은 POST /api/v2/auth/2fa/verify에서 OTP 확인 후 발급
+		common.V2Success(c, gin.H{
+			"two_factor_required": true,
+			"challenge_token":     resp.ChallengeToken,
+			"expires_in":          int(v2svc.TwoFactorChallengeTTL.Seconds()),
+		})
 		return
 	}
 
+	h.respondLogin(c, resp)
+}
+
+// respondLogin sets the refresh token cookie and writes the access token
+func (h *V2AuthHandler) respondLogin(c *gin.Context, resp *v2svc.V2LoginResponse) {
 	// Set refresh token as httpOnly cookie (domain shared across subdomains)
 	c.SetCookie("refresh_token", resp.RefreshToken, 7*24*3600, "/", cookieDomain(), isSecureCookie(), true)
 
@@ -70,6 +94,27 @@ func (h *V2AuthHandler) Login(c *gin.Context) {
 	})
 }
 
+type v2TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // OTP 6자리 또는 복구 코드
+}
+
+// VerifyTwoFactor handles POST /api/v2/auth/2fa/verify (로그인 2단계)
+func (h *V2AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req v2TwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 요청입니다", err)
+		return
+	}
+
+	resp, err := h.authService.VerifyTwoFactor(req.ChallengeToken, req.Code, sessionClient(c))
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+	h.respondLogin(c, resp)
+}
+
 // RefreshToken handles POST /api/v2/auth/refresh
 func (h *V2AuthHandler) RefreshToken(c *gin.Context) {
 	// Try cookie first, then JSON body
@@ -83,8 +128,9 @@ func (h *V2AuthHandler) RefreshToken(c *gin.Context) {
 		refreshToken = req.RefreshToken
 	}
 
-	resp, err := h.authService.RefreshToken(refreshToken)
+	resp, err := h.authService.RefreshToken(refreshToken, sessionClient(c))
 	if err != nil {
+		clearRefreshCookie(c)
 		common.V2ErrorResponse(c, http.StatusUnauthorized, "토큰 갱신에 실패했습니다", err)
 		return
 	}
@@ -98,11 +144,36 @@ func (h *V2AuthHandler) RefreshToken(c *gin.Context) {
 }
 
 // Logout handles POST /api/v2/auth/logout
+// 서버 세션을 폐기하므로 로그아웃 후에는 같은 리프레시 토큰으로 갱신할 수 없다.
 func (h *V2AuthHandler) Logout(c *gin.Context) {
-	c.SetCookie("refresh_token", "", -1, "/", cookieDomain(), isSecureCookie(), true)
+	if err := h.authService.Logout(h.refreshTokenFromRequest(c)); err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "로그아웃 처리에 실패했습니다", err)
+		return
+	}
+	clearRefreshCookie(c)
 	common.V2Success(c, gin.H{"message": "로그아웃되었습니다"})
 }
 
+// refreshTokenFromRequest returns the refresh token from the cookie or JSON body ("" if none)
+func (h *V2AuthHandler) refreshTokenFromRequest(c *gin.Context) string {
+	if token, err := c.Cookie("refresh_token"); err == nil && token != "" {
+		return token
+	}
+	var req v2RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return ""
+	}
+	return req.RefreshToken
+}
+
+func sessionClient(c *gin.Context) v2svc.SessionClient {
+	return v2svc.SessionClient{UserAgent: c.Request.UserAgent(), IPAddress: c.ClientIP()}
+}
+
+func clearRefreshCookie(c *gin.Context) {
+	c.SetCookie("refresh_token", "", -1, "/", cookieDomain(), isSecureCookie(), true)
+}
+
 // ExchangeToken handles POST /api/v1/auth/exchange
 // Exchanges a refresh_token cookie for a new access token (legacy damoang.net SSO flow)
 // TODO: v2 마이그레이션 - DB 재설계 후 세션 기반 인증으로 전환
@@ -113,8 +184,9 @@ func (h *V2AuthHandler) ExchangeToken(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.RefreshToken(refreshToken)
+	resp, err := h.authService.RefreshToken(refreshToken, sessionClient(c))
 	if err != nil {
+		clearRefreshCookie(c)
 		common.V2ErrorResponse(c, http.StatusUnauthorized, "토큰 교환에 실패했습니다", err)
 		return
 	}
@@ -144,3 +216,196 @@ func (h *V2AuthHandler) GetMe(c *gin.Context) {
 
 	common.V2Success(c, user)
 }
+
+// ListSessions handles GET /api/v2/me/sessions
+func (h *V2AuthHandler) ListSessions(c *gin.Context) {
+	userID, err := strconv.ParseUint(middleware.GetUserID(c), 10, 64)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증 정보가 올바르지 않습니다", err)
+		return
+	}
+
+	current := h.authService.SessionIDFromToken(h.refreshTokenFromRequest(c))
+	sessions, err := h.authService.ListSessions(userID, current)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "세션 목록 조회에 실패했습니다", err)
+		return
+	}
+
+	common.V2Success(c, sessions)
+}
+
+// RevokeSession handles DELETE /api/v2/me/sessions/:id
+func (h *V2AuthHandler) RevokeSession(c *gin.Context) {
+	userID, err := strconv.ParseUint(middleware.GetUserID(c), 10, 64)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증 정보가 올바르지 않습니다", err)
+		return
+	}
+
+	sessionID := c.Param("id")
+	if err := h.authService.RevokeSession(userID, sessionID); err != nil {
+		if errors.Is(err, v2svc.ErrSessionNotFound) {
+			common.V2ErrorResponse(c, http.StatusNotFound, "세션을 찾을 수 없습니다", err)
+			return
+		}
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "세션 종료에 실패했습니다", err)
+		return
+	}
+	if sessionID == h.authService.SessionIDFromToken(h.refreshTokenFromRequest(c)) {
+		clearRefreshCookie(c)
+	}
+
+	common.V2Success(c, gin.H{"message": "세션이 종료되었습니다"})
+}
+
+// RevokeOtherSessions handles DELETE /api/v2/me/sessions (현재 기기를 제외한 모든 세션 종료)
+func (h *V2AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, err := strconv.ParseUint(middleware.GetUserID(c), 10, 64)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증 정보가 올바르지 않습니다", err)
+		return
+	}
+
+	current := h.authService.SessionIDFromToken(h.refreshTokenFromRequest(c))
+	revoked, err := h.authService.RevokeOtherSessions(userID, current)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "세션 종료에 실패했습니다", err)
+		return
+	}
+
+	common.V2Success(c, gin.H{"revoked": revoked})
+}
+
+// ListLockouts handles GET /api/v2/admin/auth/lockouts
+func (h *V2AuthHandler) ListLockouts(c *gin.Context) {
+	lockouts, err := h.authService.ListLockouts(c.Request.Context())
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "잠금 목록 조회에 실패했습니다", err)
+		return
+	}
+	common.V2Success(c, lockouts)
+}
+
+// ClearLockout handles DELETE /api/v2/admin/auth/lockouts?username=&ip=
+func (h *V2AuthHandler) ClearLockout(c *gin.Context) {
+	username, ip := c.Query("username"), c.Query("ip")
+	if username == "" && ip == "" {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "username 또는 ip가 필요합니다", nil)
+		return
+	}
+	if err := h.authService.ClearLockout(c.Request.Context(), username, ip); err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "잠금 해제에 실패했습니다", err)
+		return
+	}
+	common.V2Success(c, gin.H{"message": "잠금이 해제되었습니다"})
+}
+
+// ListLoginAttempts handles GET /api/v2/admin/auth/login-attempts?username=&ip=&page=&limit=
+func (h *V2AuthHandler) ListLoginAttempts(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	attempts, total, err := h.authService.ListLoginAttempts(c.Query("username"), c.Query("ip"), page, limit)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "로그인 기록 조회에 실패했습니다", err)
+		return
+	}
+	common.V2SuccessWithMeta(c, attempts, common.NewV2Meta(page, limit, total))
+}
+
+type v2TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// GetTwoFactor handles GET /api/v2/me/2fa
+func (h *V2AuthHandler) GetTwoFactor(c *gin.Context) {
+	status, err := h.authService.TwoFactorStatus(middleware.GetUserID(c))
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+	common.V2Success(c, status)
+}
+
+// SetupTwoFactor handles POST /api/v2/me/2fa/setup (비밀 키와 QR용 otpauth:// URI 발급)
+func (h *V2AuthHandler) SetupTwoFactor(c *gin.Context) {
+	setup, err := h.authService.SetupTwoFactor(middleware.GetUserID(c))
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+	common.V2Success(c, setup)
+}
+
+// EnableTwoFactor handles POST /api/v2/me/2fa/enable (첫 OTP 확인 후 복구 코드 발급)
+func (h *V2AuthHandler) EnableTwoFactor(c *gin.Context) {
+	var req v2TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "인증 코드가 필요합니다", err)
+		return
+	}
+	codes, err := h.authService.EnableTwoFactor(middleware.GetUserID(c), req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"recovery_codes": codes})
+}
+
+// RegenerateRecoveryCodes handles POST /api/v2/me/2fa/recovery-codes
+func (h *V2AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req v2TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "인증 코드가 필요합니다", err)
+		return
+	}
+	codes, err := h.authService.RegenerateRecoveryCodes(middleware.GetUserID(c), req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"recovery_codes": codes})
+}
+
+// DisableTwoFactor handles DELETE /api/v2/me/2fa (현재 OTP 또는 복구 코드 필요)
+func (h *V2AuthHandler) DisableTwoFactor(c *gin.Context) {
+	var req v2TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "인증 코드가 필요합니다", err)
+		return
+	}
+	if err := h.authService.DisableTwoFactor(middleware.GetUserID(c), req.Code); err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"message": "2단계 인증이 해제되었습니다"})
+}
+
+func respondTwoFactorError(c *gin.Context, err error) {
+	if common.RespondLoginThrottled(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, v2svc.ErrInvalidOTP):
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증 코드가 올바르지 않습니다", err)
+	case errors.Is(err, v2svc.ErrInvalidChallenge), errors.Is(err, common.ErrUnauthorized):
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증 시간이 만료되었습니다. 다시 로그인해주세요", err)
+	case errors.Is(err, v2svc.ErrTwoFactorEnabled):
+		common.V2ErrorResponse(c, http.StatusConflict, "이미 2단계 인증을 사용 중입니다", err)
+	case errors.Is(err, v2svc.ErrTwoFactorNotPending):
+		common.V2ErrorResponse(c, http.StatusConflict, "2단계 인증 등록을 먼저 시작해주세요", err)
+	case errors.Is(err, v2svc.ErrTwoFactorNotEnabled):
+		common.V2ErrorResponse(c, http.StatusConflict, "2단계 인증을 사용하고 있지 않습니다", err)
+	case errors.Is(err, v2svc.ErrTwoFactorUnavailable):
+		common.V2ErrorResponse(c, http.StatusNotImplemented, "2단계 인증을 사용할 수 없습니다", err)
+	default:
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "2단계 인증 처리에 실패했습니다", err)
+	}
+}
@@ -11,8 +11,11 @@ import (
 	"github.com/damoang/angple-backend/internal/common"
 	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
 	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/damoang/angple-backend/internal/repository"
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/internal/service"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -33,6 +36,8 @@ type V2Handler struct {
 	gnuPointWriteRepo v2repo.GnuboardPointWriteRepository
 	pointConfigRepo   v2repo.PointConfigRepository
 	blockRepo         v2repo.BlockRepository
+	hookManager       *plugin.HookManager
+	quotaService      *service.QuotaService
 }
 
 // NewV2Handler creates a new V2Handler
@@ -97,6 +102,16 @@ func (h *V2Handler) SetBlockRepository(repo v2repo.BlockRepository) {
 	h.blockRepo = repo
 }
 
+// SetHookManager sets the plugin hook manager for firing write/content hooks
+func (h *V2Handler) SetHookManager(hm *plugin.HookManager) {
+	h.hookManager = hm
+}
+
+// SetQuotaService sets the tenant plan quota service for write-time limits
+func (h *V2Handler) SetQuotaService(q *service.QuotaService) {
+	h.quotaService = q
+}
+
 // getBlockedUserIDs returns blocked user IDs (as uint64) for the given mb_id
 func (h *V2Handler) getBlockedUserIDs(mbID string) []uint64 {
 	if h.blockRepo == nil || mbID == "" || h.gnuDB == nil {
@@ -197,7 +212,7 @@ func isOwnerOrAdmin(c *gin.Context, resourceUserID uint64) bool {
 	if userID == resourceUserID {
 		return true
 	}
-	return middleware.GetUserLevel(c) >= 10
+	return middleware.IsAdmin(c)
 }
 
 // === Users ===
@@ -341,6 +356,12 @@ func (h *V2Handler) GetPost(c *gin.Context) {
 		return
 	}
 
+	// 플러그인 콘텐츠 필터 (렌더링 시점에만 적용, DB 원본은 유지)
+	post.Content = h.filterContent(plugin.HookPostContent, post.Content, map[string]interface{}{
+		"post_id":  post.ID,
+		"board_id": post.BoardID,
+	})
+
 	// 조회수는 프론트엔드 /api/viewcount 에서 쿠키 기반 중복방지로 처리
 	// 백엔드에서 매 요청마다 증가시키면 새로고침할 때마다 무한 증가 (버그)
 	common.V2Success(c, post)
@@ -418,19 +439,51 @@ func (h *V2Handler) CreatePost(c *gin.Context) {
 		}
 	}
 
+	// 플러그인 Before Hook (거부 또는 제목/본문 변경 가능)
+	hookData, err := h.runBeforeHook(plugin.HookPostBeforeCreate, map[string]interface{}{
+		"board_id":   board.ID,
+		"board_slug": slug,
+		"user_id":    userID,
+		"title":      req.Title,
+		"content":    req.Content,
+	})
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
+
+	// 테넌트 플랜 게시글 수 한도
+	if err := h.quotaService.Reserve(c.Request.Context(), common.QuotaPosts, 1); err != nil {
+		if !common.RespondQuotaExceeded(c, err) {
+			common.V2ErrorResponse(c, http.StatusInternalServerError, "게시글 작성 실패", err)
+		}
+		return
+	}
+
 	post := &v2domain.V2Post{
 		BoardID:  board.ID,
 		UserID:   userID,
-		Title:    req.Title,
-		Content:  req.Content,
+		Title:    hookString(hookData, "title", req.Title),
+		Content:  hookString(hookData, "content", req.Content),
 		Status:   "published",
 		IsSecret: req.IsSecret != nil && *req.IsSecret,
 	}
 	if err := h.postRepo.Create(post); err != nil {
+		h.quotaService.Release(c.Request.Context(), common.QuotaPosts, 1)
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "게시글 작성 실패", err)
 		return
 	}
 
+	h.runAfterHook(plugin.HookPostAfterCreate, map[string]interface{}{
+		"site_id":    repository.SiteIDFromContext(c.Request.Context()),
+		"post_id":    post.ID,
+		"board_id":   post.BoardID,
+		"board_slug": slug,
+		"user_id":    post.UserID,
+		"title":      post.Title,
+		"content":    post.Content,
+	})
+
 	// 포인트 처리 (지급 또는 차감) — g5_point 기반 FIFO 소비
 	if board.WritePoint != 0 {
 		mbID := middleware.GetUserID(c)
@@ -503,6 +556,27 @@ func (h *V2Handler) UpdatePost(c *gin.Context) {
 		return
 	}
 
+	title, content := post.Title, post.Content
+	if req.Title != "" {
+		title = req.Title
+	}
+	if req.Content != "" {
+		content = req.Content
+	}
+
+	// 플러그인 Before Hook (거부 또는 제목/본문 변경 가능)
+	hookData, err := h.runBeforeHook(plugin.HookPostBeforeUpdate, map[string]interface{}{
+		"post_id":  post.ID,
+		"board_id": post.BoardID,
+		"user_id":  post.UserID,
+		"title":    title,
+		"content":  content,
+	})
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
+
 	// 리비전 저장 (수정 전 상태)
 	if h.revisionRepo != nil && (req.Title != "" || req.Content != "") {
 		userID, _ := strconv.ParseUint(middleware.GetUserID(c), 10, 64)
@@ -520,16 +594,20 @@ func (h *V2Handler) UpdatePost(c *gin.Context) {
 		_ = h.revisionRepo.Create(revision) //nolint:errcheck
 	}
 
-	if req.Title != "" {
-		post.Title = req.Title
-	}
-	if req.Content != "" {
-		post.Content = req.Content
-	}
+	post.Title = hookString(hookData, "title", title)
+	post.Content = hookString(hookData, "content", content)
 	if err := h.postRepo.Update(post); err != nil {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "게시글 수정 실패", err)
 		return
 	}
+
+	h.runAfterHook(plugin.HookPostAfterUpdate, map[string]interface{}{
+		"post_id":  post.ID,
+		"board_id": post.BoardID,
+		"user_id":  post.UserID,
+		"title":    post.Title,
+		"content":  post.Content,
+	})
 	common.V2Success(c, post)
 }
 
@@ -555,6 +633,17 @@ func (h *V2Handler) DeletePost(c *gin.Context) {
 
 	userID, _ := strconv.ParseUint(middleware.GetUserID(c), 10, 64)
 
+	// 플러그인 Before Hook (거부 가능)
+	if _, err := h.runBeforeHook(plugin.HookPostBeforeDelete, map[string]interface{}{
+		"post_id":    post.ID,
+		"board_id":   post.BoardID,
+		"user_id":    post.UserID,
+		"deleted_by": userID,
+	}); err != nil {
+		common.V2ErrorResponse(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
+
 	// 리비전 저장 (삭제 전 상태)
 	if h.revisionRepo != nil {
 		nickname := middleware.GetNickname(c)
@@ -575,6 +664,14 @@ func (h *V2Handler) DeletePost(c *gin.Context) {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "게시글 삭제 실패", err)
 		return
 	}
+	h.quotaService.Release(c.Request.Context(), common.QuotaPosts, 1)
+
+	h.runAfterHook(plugin.HookPostAfterDelete, map[string]interface{}{
+		"post_id":    post.ID,
+		"board_id":   post.BoardID,
+		"user_id":    post.UserID,
+		"deleted_by": userID,
+	})
 	common.V2Success(c, gin.H{"message": "삭제 완료"})
 }
 
@@ -602,6 +699,14 @@ func (h *V2Handler) RestorePost(c *gin.Context) {
 		return
 	}
 
+	// 삭제 시 반환한 게시글 수 한도를 다시 차지 (삭제·복구로 한도를 넘지 않도록)
+	if err := h.quotaService.Reserve(c.Request.Context(), common.QuotaPosts, 1); err != nil {
+		if !common.RespondQuotaExceeded(c, err) {
+			common.V2ErrorResponse(c, http.StatusInternalServerError, "게시글 복구 실패", err)
+		}
+		return
+	}
+
 	// 리비전 저장 (복구 기록)
 	if h.revisionRepo != nil {
 		userID, _ := strconv.ParseUint(middleware.GetUserID(c), 10, 64)
@@ -620,6 +725,7 @@ func (h *V2Handler) RestorePost(c *gin.Context) {
 	}
 
 	if err := h.postRepo.Restore(id); err != nil {
+		h.quotaService.Release(c.Request.Context(), common.QuotaPosts, 1)
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "게시글 복구 실패", err)
 		return
 	}
@@ -634,10 +740,22 @@ func (h *V2Handler) PermanentDeletePost(c *gin.Context) {
 		return
 	}
 
+	post, err := h.postRepo.FindByIDIncludeDeleted(id)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusNotFound, "게시글을 찾을 수 없습니다", err)
+		return
+	}
 	if err := h.postRepo.PermanentDelete(id); err != nil {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "영구 삭제 실패", err)
 		return
 	}
+	h.runAfterHook(plugin.HookPostAfterDelete, map[string]interface{}{
+		"post_id":    post.ID,
+		"board_id":   post.BoardID,
+		"user_id":    post.UserID,
+		"deleted_by": middleware.GetUserID(c),
+		"permanent":  true,
+	})
 	common.V2Success(c, gin.H{"message": "영구 삭제 완료"})
 }
 
@@ -750,6 +868,12 @@ func (h *V2Handler) ListComments(c *gin.Context) {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "댓글 목록 조회 실패", err)
 		return
 	}
+	for _, comment := range comments {
+		comment.Content = h.filterContent(plugin.HookCommentContent, comment.Content, map[string]interface{}{
+			"comment_id": comment.ID,
+			"post_id":    comment.PostID,
+		})
+	}
 	common.V2SuccessWithMeta(c, comments, common.NewV2Meta(page, perPage, total))
 }
 
@@ -831,11 +955,25 @@ func (h *V2Handler) CreateComment(c *gin.Context) {
 		}
 	}
 
+	// 플러그인 Before Hook (거부 또는 본문 변경 가능)
+	hookData, err := h.runBeforeHook(plugin.HookCommentBeforeCreate, map[string]interface{}{
+		"board_id":   board.ID,
+		"board_slug": slug,
+		"post_id":    postID,
+		"parent_id":  req.ParentID,
+		"user_id":    userID,
+		"content":    req.Content,
+	})
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
+
 	comment := &v2domain.V2Comment{
 		PostID:   postID,
 		UserID:   userID,
 		ParentID: req.ParentID,
-		Content:  req.Content,
+		Content:  hookString(hookData, "content", req.Content),
 		Status:   "active",
 	}
 	if req.ParentID != nil {
@@ -847,6 +985,17 @@ func (h *V2Handler) CreateComment(c *gin.Context) {
 		return
 	}
 
+	h.runAfterHook(plugin.HookCommentAfterCreate, map[string]interface{}{
+		"site_id":    repository.SiteIDFromContext(c.Request.Context()),
+		"comment_id": comment.ID,
+		"board_id":   board.ID,
+		"board_slug": slug,
+		"post_id":    comment.PostID,
+		"parent_id":  comment.ParentID,
+		"user_id":    comment.UserID,
+		"content":    comment.Content,
+	})
+
 	// 30일 이전 글 체크 (포인트 + XP 모두 적용)
 	// postRepo.FindByID는 PK 조회이므로 빠름 (1회만 조회)
 	isOldPost := false
@@ -955,11 +1104,30 @@ func (h *V2Handler) UpdateComment(c *gin.Context) {
 		return
 	}
 
-	comment.Content = req.Content
+	// 플러그인 Before Hook (거부 또는 본문 변경 가능)
+	hookData, err := h.runBeforeHook(plugin.HookCommentBeforeUpdate, map[string]interface{}{
+		"comment_id": comment.ID,
+		"post_id":    comment.PostID,
+		"user_id":    comment.UserID,
+		"content":    req.Content,
+	})
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
+
+	comment.Content = hookString(hookData, "content", req.Content)
 	if err := h.commentRepo.Update(comment); err != nil {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "댓글 수정 실패", err)
 		return
 	}
+
+	h.runAfterHook(plugin.HookCommentAfterUpdate, map[string]interface{}{
+		"comment_id": comment.ID,
+		"post_id":    comment.PostID,
+		"user_id":    comment.UserID,
+		"content":    comment.Content,
+	})
 	common.V2Success(c, comment)
 }
 
@@ -984,10 +1152,29 @@ func (h *V2Handler) DeleteComment(c *gin.Context) {
 	}
 
 	userID, _ := strconv.ParseUint(middleware.GetUserID(c), 10, 64)
+
+	// 플러그인 Before Hook (거부 가능)
+	if _, err := h.runBeforeHook(plugin.HookCommentBeforeDelete, map[string]interface{}{
+		"comment_id": comment.ID,
+		"post_id":    comment.PostID,
+		"user_id":    comment.UserID,
+		"deleted_by": userID,
+	}); err != nil {
+		common.V2ErrorResponse(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
+
 	if err := h.commentRepo.SoftDelete(id, userID); err != nil {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "댓글 삭제 실패", err)
 		return
 	}
+
+	h.runAfterHook(plugin.HookCommentAfterDelete, map[string]interface{}{
+		"comment_id": comment.ID,
+		"post_id":    comment.PostID,
+		"user_id":    comment.UserID,
+		"deleted_by": userID,
+	})
 	common.V2Success(c, gin.H{"message": "삭제 완료"})
 }
 
@@ -1005,6 +1192,39 @@ func parsePagination(c *gin.Context) (int, int) {
 	return page, perPage
 }
 
+// runBeforeHook runs a plugin before-hook; returns the (possibly rewritten) data or a rejection error
+func (h *V2Handler) runBeforeHook(event string, data map[string]interface{}) (map[string]interface{}, error) {
+	if h.hookManager == nil {
+		return data, nil
+	}
+	return h.hookManager.ApplyBefore(event, data)
+}
+
+// runAfterHook runs a plugin after-hook (errors are logged by the hook manager)
+func (h *V2Handler) runAfterHook(event string, data map[string]interface{}) {
+	if h.hookManager == nil {
+		return
+	}
+	h.hookManager.Do(event, data)
+}
+
+// filterContent applies a plugin content filter and returns the rendered content
+func (h *V2Handler) filterContent(event, content string, data map[string]interface{}) string {
+	if h.hookManager == nil {
+		return content
+	}
+	data["content"] = content
+	return hookString(h.hookManager.Apply(event, data), "content", content)
+}
+
+// hookString reads a string value from hook output, falling back when missing or mistyped
+func hookString(data map[string]interface{}, key, fallback string) string {
+	if v, ok := data[key].(string); ok {
+		return v
+	}
+	return fallback
+}
+
 // safeUint64ToInt converts uint64 to int with overflow protection
 func safeUint64ToInt(v uint64) int {
 	if v > uint64(math.MaxInt) {
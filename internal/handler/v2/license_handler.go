@@ -1,18 +1,38 @@
 package v2
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/pluginstore/domain"
+	pluginstoreSvc "github.com/damoang/angple-backend/internal/pluginstore/service"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-// LicenseHandler handles license verification API endpoints
-type LicenseHandler struct{}
+// LicenseHandler handles license verification and admin license management
+type LicenseHandler struct {
+	svc *pluginstoreSvc.LicenseService
+}
 
-// NewLicenseHandler creates a new LicenseHandler
-func NewLicenseHandler() *LicenseHandler {
-	return &LicenseHandler{}
+// NewLicenseHandler creates a new LicenseHandler (svc가 nil이면 서명 키 미설정 — 모든 요청에 503)
+func NewLicenseHandler(svc *pluginstoreSvc.LicenseService) *LicenseHandler {
+	return &LicenseHandler{svc: svc}
+}
+
+// available responds 503 when no signing key is configured (404 대신 명확한 응답)
+func (h *LicenseHandler) available(c *gin.Context) bool {
+	if h.svc != nil {
+		return true
+	}
+	c.JSON(http.StatusServiceUnavailable, common.V2Response{
+		Success: false,
+		Error:   &common.V2Error{Code: "LICENSE_SERVICE_UNAVAILABLE", Message: "라이선스 서비스를 사용할 수 없습니다"},
+	})
+	return false
 }
 
 type licenseVerifyRequest struct {
@@ -21,21 +41,135 @@ type licenseVerifyRequest struct {
 	Domain     string `json:"domain"`
 }
 
+// licenseInvalidMessages maps verification failure reasons to user-facing messages
+var licenseInvalidMessages = map[string]string{
+	"malformed":       "라이선스 키 형식이 올바르지 않습니다",
+	"bad_signature":   "라이선스 서명이 올바르지 않습니다",
+	"expired":         "라이선스가 만료되었습니다",
+	"plugin_mismatch": "다른 플러그인의 라이선스입니다",
+	"domain_mismatch": "이 도메인에서 사용할 수 없는 라이선스입니다",
+	"domain_required": "도메인이 지정된 라이선스입니다. domain을 함께 보내주세요",
+	"not_found":       "등록되지 않은 라이선스입니다",
+	"revoked":         "폐기된 라이선스입니다",
+}
+
 // Verify handles POST /api/v1/licenses/verify
-// Validates a plugin/theme license key
-// TODO: v2 마이그레이션 - DB 재설계 후 라이선스 테이블 연동
+// Validates a plugin/theme license key (서명, 만료, 플러그인, 도메인, 폐기 여부)
 func (h *LicenseHandler) Verify(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
 	var req licenseVerifyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 요청입니다", err)
 		return
 	}
 
-	// TODO: 라이선스 테이블 조회 및 검증 로직 구현
-	// 현재는 모든 라이선스를 유효로 처리 (오픈소스 단계)
+	result, err := h.svc.Verify(req.LicenseKey, req.PluginID, req.Domain)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "라이선스 검증 실패", err)
+		return
+	}
+	if !result.Valid {
+		common.V2Success(c, gin.H{
+			"valid":     false,
+			"plugin_id": req.PluginID,
+			"reason":    result.Reason,
+			"message":   licenseInvalidMessages[result.Reason],
+		})
+		return
+	}
+
+	lic := result.License
 	common.V2Success(c, gin.H{
-		"valid":     true,
-		"plugin_id": req.PluginID,
-		"message":   "라이선스가 유효합니다",
+		"valid":      true,
+		"plugin_id":  req.PluginID,
+		"domain":     lic.Domain,
+		"seats":      lic.Seats,
+		"expires_at": lic.ExpiresAt,
+		"message":    "라이선스가 유효합니다",
 	})
 }
+
+// PublicKey handles GET /api/v1/licenses/public-key
+// 셀프 호스팅 사이트가 오프라인 검증(pkg/license.Verify)에 쓰는 Ed25519 공개 키
+func (h *LicenseHandler) PublicKey(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	common.V2Success(c, gin.H{
+		"algorithm":  "Ed25519",
+		"public_key": h.svc.PublicKey(),
+	})
+}
+
+// ListLicenses handles GET /api/v2/admin/licenses
+func (h *LicenseHandler) ListLicenses(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	page, perPage := parsePagination(c)
+	filter := domain.LicenseListFilter{
+		PluginName: c.Query("plugin"),
+		Status:     c.Query("status"),
+	}
+	if devID, err := strconv.ParseUint(c.Query("developer_id"), 10, 64); err == nil {
+		filter.DeveloperID = devID
+	}
+
+	lics, total, err := h.svc.List(filter, page, perPage)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "라이선스 목록 조회 실패", err)
+		return
+	}
+	common.V2SuccessWithMeta(c, lics, common.NewV2Meta(page, perPage, total))
+}
+
+// IssueLicense handles POST /api/v2/admin/licenses
+func (h *LicenseHandler) IssueLicense(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req domain.LicenseIssueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 요청입니다", err)
+		return
+	}
+
+	issuedBy, _ := strconv.ParseUint(middleware.GetUserID(c), 10, 64) //nolint:errcheck // 0 if not numeric
+	lic, err := h.svc.Issue(&req, issuedBy)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		common.V2ErrorResponse(c, http.StatusNotFound, "플러그인 제출을 찾을 수 없습니다", err)
+		return
+	}
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	common.V2Created(c, lic)
+}
+
+// RevokeLicense handles POST /api/v2/admin/licenses/:id/revoke
+func (h *LicenseHandler) RevokeLicense(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 ID", err)
+		return
+	}
+	var req domain.LicenseRevokeRequest
+	_ = c.ShouldBindJSON(&req) //nolint:errcheck // reason is optional
+
+	lic, err := h.svc.Revoke(id, req.Reason)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		common.V2ErrorResponse(c, http.StatusNotFound, "라이선스를 찾을 수 없습니다", err)
+		return
+	}
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	common.V2Success(c, lic)
+}
@@ -0,0 +1,150 @@
+package v2
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/plugin"
+	v2svc "github.com/damoang/angple-backend/internal/service/v2"
+	"github.com/gin-gonic/gin"
+)
+
+type v2RegisterRequest struct {
+	Username string `json:"username" binding:"required"`
+	Nickname string `json:"nickname" binding:"required"`
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type v2EmailRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type v2TokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type v2ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// Register handles POST /api/v2/auth/register
+func (h *V2AuthHandler) Register(c *gin.Context) {
+	var req v2RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "아이디, 닉네임, 이메일, 비밀번호를 입력해주세요", err)
+		return
+	}
+
+	result, err := h.authService.Register(&v2svc.RegisterRequest{
+		Username:  req.Username,
+		Nickname:  req.Nickname,
+		Email:     req.Email,
+		Password:  req.Password,
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		respondRegistrationError(c, err)
+		return
+	}
+	common.V2Created(c, result)
+}
+
+// CheckRegistration handles GET /api/v2/auth/register/check?username=&nickname=&email= (가입 폼 중복/형식 확인)
+func (h *V2AuthHandler) CheckRegistration(c *gin.Context) {
+	result, err := h.authService.CheckRegistration(&v2svc.RegisterRequest{
+		Username: c.Query("username"),
+		Nickname: c.Query("nickname"),
+		Email:    c.Query("email"),
+	})
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "가입 정보 확인에 실패했습니다", err)
+		return
+	}
+	common.V2Success(c, result)
+}
+
+// VerifyEmail handles POST /api/v2/auth/verify-email
+func (h *V2AuthHandler) VerifyEmail(c *gin.Context) {
+	var req v2TokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "인증 토큰이 필요합니다", err)
+		return
+	}
+	user, err := h.authService.VerifyEmail(req.Token)
+	if err != nil {
+		respondRegistrationError(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"message": "이메일 인증이 완료되었습니다", "user": user})
+}
+
+// ResendVerification handles POST /api/v2/auth/verify-email/resend (가입 여부와 관계없이 같은 응답)
+func (h *V2AuthHandler) ResendVerification(c *gin.Context) {
+	var req v2EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "이메일 주소가 필요합니다", err)
+		return
+	}
+	if err := h.authService.ResendVerification(req.Email); err != nil {
+		respondRegistrationError(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"message": "인증이 필요한 계정이면 인증 메일을 보냈습니다"})
+}
+
+// ForgotPassword handles POST /api/v2/auth/password/forgot (가입 여부와 관계없이 같은 응답)
+func (h *V2AuthHandler) ForgotPassword(c *gin.Context) {
+	var req v2EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "이메일 주소가 필요합니다", err)
+		return
+	}
+	if err := h.authService.RequestPasswordReset(req.Email); err != nil {
+		respondRegistrationError(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"message": "가입된 이메일이면 비밀번호 재설정 메일을 보냈습니다"})
+}
+
+// ResetPassword handles POST /api/v2/auth/password/reset (모든 로그인 기기에서 로그아웃됨)
+func (h *V2AuthHandler) ResetPassword(c *gin.Context) {
+	var req v2ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "토큰과 새 비밀번호가 필요합니다", err)
+		return
+	}
+	if err := h.authService.ResetPassword(req.Token, req.Password); err != nil {
+		respondRegistrationError(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"message": "비밀번호가 변경되었습니다. 다시 로그인해주세요"})
+}
+
+func respondRegistrationError(c *gin.Context, err error) {
+	var regErr *v2svc.RegistrationError
+	var reject *plugin.HookRejectError
+	switch {
+	case errors.As(err, &regErr):
+		status, code := http.StatusBadRequest, "VALIDATION_ERROR"
+		if regErr.Conflict {
+			status, code = http.StatusConflict, "DUPLICATE"
+		}
+		c.JSON(status, common.V2Response{
+			Success: false,
+			Error:   &common.V2Error{Code: code, Message: regErr.Reason, Details: gin.H{"field": regErr.Field}},
+		})
+	case errors.As(err, &reject):
+		common.V2ErrorResponse(c, http.StatusForbidden, reject.Reason, err)
+	case errors.Is(err, v2svc.ErrRegistrationClosed):
+		common.V2ErrorResponse(c, http.StatusForbidden, "현재 회원가입을 받지 않습니다", err)
+	case errors.Is(err, v2svc.ErrInvalidUserToken):
+		common.V2ErrorResponse(c, http.StatusBadRequest, "링크가 만료되었거나 이미 사용되었습니다", err)
+	case errors.Is(err, v2svc.ErrRegistrationUnavailable):
+		common.V2ErrorResponse(c, http.StatusNotImplemented, "회원가입을 사용할 수 없습니다", err)
+	default:
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "요청 처리에 실패했습니다", err)
+	}
+}
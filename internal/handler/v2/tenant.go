@@ -0,0 +1,35 @@
+package v2
+
+import (
+	"github.com/damoang/angple-backend/internal/middleware"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// Tenant wraps a V2Handler method so that it runs against the request's tenant DB.
+// 게시판/게시글/댓글/리비전 리포지토리만 테넌트 DB로 바꾸고, 회원·포인트·알림 등은 공용 DB를 그대로 쓴다.
+//
+//	boards.GET("", h.Tenant((*V2Handler).ListBoards))
+func (h *V2Handler) Tenant(fn func(*V2Handler, *gin.Context)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fn(h.forTenant(c), c)
+	}
+}
+
+// forTenant returns a request-scoped copy bound to the tenant DB (메인 사이트는 h 그대로)
+func (h *V2Handler) forTenant(c *gin.Context) *V2Handler {
+	db := middleware.GetTenantDB(c)
+	if db == nil {
+		return h
+	}
+
+	th := *h
+	th.postRepo = v2repo.NewPostRepository(db)
+	th.commentRepo = v2repo.NewCommentRepository(db)
+	th.boardRepo = v2repo.NewBoardRepository(db)
+	th.permChecker = middleware.TenantPermissionChecker(c, h.permChecker)
+	if h.revisionRepo != nil {
+		th.revisionRepo = v2repo.NewRevisionRepository(db)
+	}
+	return &th
+}
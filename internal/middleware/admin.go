@@ -2,12 +2,27 @@ package middleware
 
 import (
 	"net/http"
+	"sync/atomic"
 
 	"github.com/damoang/angple-backend/internal/common"
 	"github.com/gin-gonic/gin"
 )
 
-// RequireAdmin checks that the authenticated user has admin level (>= 10)
+// adminMFARequired 관리자 라우트에 2단계 인증(TOTP)을 거친 로그인을 요구할지 여부
+var adminMFARequired atomic.Bool
+
+// SetAdminMFARequired turns on the 2FA requirement for RequireAdmin (config two_factor.require_for_admin)
+func SetAdminMFARequired(required bool) {
+	adminMFARequired.Store(required)
+}
+
+// AdminMFARequired reports whether admin routes require a 2FA login
+func AdminMFARequired() bool {
+	return adminMFARequired.Load()
+}
+
+// RequireAdmin checks that the authenticated user has admin level (>= 10).
+// 2FA 요구가 켜져 있으면 2단계 인증 없이 발급된 토큰과 API 키는 거부한다.
 func RequireAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		level := GetUserLevel(c)
@@ -16,6 +31,37 @@ func RequireAdmin() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if !adminMFASatisfied(c) {
+			c.JSON(http.StatusForbidden, common.V2Response{
+				Success: false,
+				Error:   &common.V2Error{Code: "TWO_FACTOR_REQUIRED", Message: "관리자 기능은 2단계 인증 로그인이 필요합니다"},
+			})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
+
+// IsAdmin reports whether the request may use admin privileges (RequireAdmin과 같은 등급·2단계 인증 기준)
+// 작성자 또는 관리자처럼 라우트 안에서 관리자 권한을 판단할 때 사용한다.
+func IsAdmin(c *gin.Context) bool {
+	return GetUserLevel(c) >= 10 && adminMFASatisfied(c)
+}
+
+// adminMFASatisfied API 키는 2단계 인증 로그인에서 발급·재발급된 키만 인정한다
+// (스위치를 켜기 전에 발급된 키는 2단계 인증 후 재발급해야 관리자 기능을 쓸 수 있다).
+func adminMFASatisfied(c *gin.Context) bool {
+	if !AdminMFARequired() {
+		return true
+	}
+	if apiKey := getAPIKey(c); apiKey != nil {
+		return apiKey.MFA
+	}
+	return IsMFAAuthenticated(c)
+}
+
+// IsMFAAuthenticated reports whether the request's credentials came from a 2FA login
+func IsMFAAuthenticated(c *gin.Context) bool {
+	return c.GetBool("mfa")
+}
@@ -1,10 +1,14 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/pkg/jwt"
 	"github.com/gin-gonic/gin"
 )
 
@@ -69,3 +73,105 @@ func TestRequireAdmin_NoLevel(t *testing.T) {
 		t.Errorf("expected 403, got %d", w.Code)
 	}
 }
+
+func TestRequireAdminTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtManager := jwt.NewManager("test-secret", 3600, 7200)
+	keys := NewAPIKeyAuth(&fakeAPIKeyValidator{level: 10, keys: map[string]*domain.APIKey{
+		"ak_plain": {ID: 1, UserID: "1", Scopes: "admin:*"},
+		"ak_mfa":   {ID: 2, UserID: "1", Scopes: "admin:*", MFA: true},
+	}}, nil)
+	r := gin.New()
+	r.GET("/api/v2/admin/stats", keys.Require(domain.ScopeAdminAll), JWTAuth(jwtManager), RequireAdmin(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	// 작성자 또는 관리자 검사도 같은 기준
+	r.GET("/api/v1/boards/free/posts/1", keys.Require(domain.ScopeAdminAll), JWTAuth(jwtManager), func(c *gin.Context) {
+		if !IsAdmin(c) {
+			c.Status(http.StatusForbidden)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	plain, _ := jwtManager.GenerateAccessToken("1", "admin", "관리자", 10)
+	mfa, _ := jwtManager.GenerateMFAAccessToken("1", "admin", "관리자", 10)
+	member, _ := jwtManager.GenerateMFAAccessToken("2", "member", "회원", 2)
+
+	get := func(path, token string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		if strings.HasPrefix(token, "ak_") {
+			req.Header.Set("X-API-Key", token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	defer SetAdminMFARequired(false)
+	for _, tc := range []struct {
+		name     string
+		required bool
+		token    string
+		want     int
+	}{
+		{"switch off, password login", false, plain, http.StatusOK},
+		{"switch on, password login", true, plain, http.StatusForbidden},
+		{"switch on, 2fa login", true, mfa, http.StatusOK},
+		{"switch on, non-admin", true, member, http.StatusForbidden},
+		{"switch off, key without 2fa", false, "ak_plain", http.StatusOK},
+		{"switch on, key issued without 2fa", true, "ak_plain", http.StatusForbidden},
+		{"switch on, key issued after 2fa", true, "ak_mfa", http.StatusOK},
+	} {
+		SetAdminMFARequired(tc.required)
+		for _, path := range []string{"/api/v2/admin/stats", "/api/v1/boards/free/posts/1"} {
+			if got := get(path, tc.token); got != tc.want {
+				t.Errorf("%s %s: expected %d, got %d", tc.name, path, tc.want, got)
+			}
+		}
+	}
+}
+
+type fakeSiteRoles map[string]string
+
+func (f fakeSiteRoles) CheckUserPermission(_ context.Context, siteID, userID, _ string) (bool, error) {
+	return f[siteID+"/"+userID] == "owner", nil
+}
+
+func TestRequireSiteOwner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	roles := fakeSiteRoles{"site-1/alice": "owner"}
+
+	cases := []struct {
+		name  string
+		user  string
+		level int
+		site  string
+		want  int
+	}{
+		{"owner", "alice", 2, "site-1", http.StatusOK},
+		{"owner of another site", "alice", 2, "site-2", http.StatusForbidden},
+		{"not owner", "bob", 2, "site-1", http.StatusForbidden},
+		{"anonymous", "", 0, "site-1", http.StatusUnauthorized},
+		{"admin", "root", 10, "site-2", http.StatusOK},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.Use(func(c *gin.Context) {
+			if tc.user != "" {
+				c.Set("userID", tc.user)
+				c.Set("level", tc.level)
+			}
+			c.Next()
+		})
+		r.GET("/communities/:id/events", RequireSiteOwner(roles), func(c *gin.Context) { c.Status(http.StatusOK) })
+		req, _ := http.NewRequest("GET", "/communities/"+tc.site+"/events", nil)
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.want, w.Code)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// APIKeyValidator validates API keys (implemented by service.OAuthService)
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (*domain.APIKey, error)
+	APIKeyOwnerLevel(ctx context.Context, userID string) (int, error)
+}
+
+// APIKeyAuth authenticates external integrations by API key
+// X-API-Key: <key> 또는 Authorization: ApiKey <key> 헤더를 사용한다.
+type APIKeyAuth struct {
+	validator APIKeyValidator
+	redis     *redis.Client
+}
+
+// NewAPIKeyAuth creates a new APIKeyAuth (redisClient가 nil이면 키별 rate limit 미적용)
+func NewAPIKeyAuth(validator APIKeyValidator, redisClient *redis.Client) *APIKeyAuth {
+	return &APIKeyAuth{validator: validator, redis: redisClient}
+}
+
+// extractAPIKey reads the key from X-API-Key or "Authorization: ApiKey <key>"
+func extractAPIKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2); len(parts) == 2 && strings.EqualFold(parts[0], "ApiKey") {
+		return strings.TrimSpace(parts[1])
+	}
+	return ""
+}
+
+// Require returns middleware that authenticates an API key holding any of the given scopes.
+// 키가 없는 요청은 그대로 다음 인증(JWTAuth 등)으로 넘기므로 JWTAuth 앞에 둔다.
+// 키로 인증되면 JWTAuth / OptionalJWTAuth는 건너뛴다.
+// 그룹에는 하위 라우트가 허용하는 scope를 모두 넘기고, 라우트별 Require로 다시 좁힌다.
+func (a *APIKeyAuth) Require(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := extractAPIKey(c)
+		if a == nil || key == "" {
+			c.Next()
+			return
+		}
+
+		// 같은 요청에서 이미 인증된 키는 scope만 다시 확인
+		apiKey := getAPIKey(c)
+		if apiKey == nil {
+			validated, err := a.validator.ValidateAPIKey(c.Request.Context(), key)
+			if err != nil {
+				common.ErrorResponse(c, http.StatusUnauthorized, "유효하지 않은 API 키입니다", nil)
+				c.Abort()
+				return
+			}
+			level, err := a.validator.APIKeyOwnerLevel(c.Request.Context(), validated.UserID)
+			if err != nil {
+				common.ErrorResponse(c, http.StatusUnauthorized, "유효하지 않은 API 키입니다", nil)
+				c.Abort()
+				return
+			}
+			if !a.allow(c, validated) {
+				return
+			}
+
+			c.Set("apiKey", validated)
+			c.Set("apiKeyID", validated.ID)
+			c.Set("userID", validated.UserID)
+			c.Set("nickname", "")
+			c.Set("level", level)
+			c.Set("v2_user_id", validated.UserID)
+			apiKey = validated
+		}
+
+		for _, scope := range scopes {
+			if apiKey.HasScope(scope) {
+				c.Next()
+				return
+			}
+		}
+		common.ErrorResponse(c, http.StatusForbidden, fmt.Sprintf("API 키에 %s 권한이 없습니다", strings.Join(scopes, " 또는 ")), nil)
+		c.Abort()
+	}
+}
+
+// allow applies the per-key sliding window rate limit (Redis 오류 시 허용)
+func (a *APIKeyAuth) allow(c *gin.Context, apiKey *domain.APIKey) bool {
+	if a.redis == nil {
+		return true
+	}
+
+	limit := apiKey.EffectiveRateLimit()
+	now := time.Now().UnixMilli()
+	windowMs := int64(60 * 1000)
+	key := "api:ratelimit:apikey:" + strconv.FormatInt(apiKey.ID, 10)
+
+	result, err := rateLimitScript.Run(context.Background(), a.redis, []string{key}, limit, windowMs, now).Int64Slice()
+	if err != nil {
+		return true
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+	c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", result[1]))
+
+	if result[0] != 1 {
+		retryAfter := (result[2] - now) / 1000
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"success": false,
+			"error":   gin.H{"code": "RATE_LIMITED", "message": "API 키 요청 한도를 초과했습니다. 잠시 후 다시 시도해주세요."},
+		})
+		return false
+	}
+	return true
+}
+
+func getAPIKey(c *gin.Context) *domain.APIKey {
+	if v, ok := c.Get("apiKey"); ok {
+		if apiKey, ok := v.(*domain.APIKey); ok {
+			return apiKey
+		}
+	}
+	return nil
+}
+
+// IsAPIKeyAuthenticated reports whether the request was authenticated by an API key
+func IsAPIKeyAuthenticated(c *gin.Context) bool {
+	return getAPIKey(c) != nil
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeAPIKeyValidator struct {
+	keys  map[string]*domain.APIKey
+	level int // 키 소유자 등급 (0이면 2)
+}
+
+func (v *fakeAPIKeyValidator) ValidateAPIKey(_ context.Context, key string) (*domain.APIKey, error) {
+	if k, ok := v.keys[key]; ok {
+		return k, nil
+	}
+	return nil, errors.New("invalid API key")
+}
+
+func (v *fakeAPIKeyValidator) APIKeyOwnerLevel(_ context.Context, _ string) (int, error) {
+	if v.level == 0 {
+		return 2, nil
+	}
+	return v.level, nil
+}
+
+func newAPIKeyTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	auth := NewAPIKeyAuth(&fakeAPIKeyValidator{keys: map[string]*domain.APIKey{
+		"ak_read":  {ID: 1, UserID: "reader", Scopes: "read:posts"},
+		"ak_write": {ID: 2, UserID: "writer", Scopes: "read:posts,write:posts"},
+		"ak_wonly": {ID: 3, UserID: "writer", Scopes: "write:posts"},
+	}}, nil)
+	jwtManager := jwt.NewManager("test-secret", 3600, 7200)
+
+	r := gin.New()
+	r.POST("/posts", auth.Require(domain.ScopeWritePosts), JWTAuth(jwtManager), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user": GetUserID(c)})
+	})
+
+	// 그룹은 모든 게시판 scope로 인증하고 라우트별로 좁힘 (routes.Setup과 같은 구성)
+	boards := r.Group("/boards", auth.Require(domain.ScopeReadPosts, domain.ScopeWritePosts))
+	boards.GET("", auth.Require(domain.ScopeReadPosts), func(c *gin.Context) { c.Status(http.StatusOK) })
+	boards.POST("", auth.Require(domain.ScopeWritePosts), JWTAuth(jwtManager), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func TestAPIKeyAuth_Scopes(t *testing.T) {
+	r := newAPIKeyTestRouter()
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		header string
+		value  string
+		want   int
+	}{
+		{"write scope via X-API-Key", "POST", "/posts", "X-API-Key", "ak_write", http.StatusOK},
+		{"write scope via Authorization", "POST", "/posts", "Authorization", "ApiKey ak_write", http.StatusOK},
+		{"missing scope", "POST", "/posts", "X-API-Key", "ak_read", http.StatusForbidden},
+		{"unknown key", "POST", "/posts", "X-API-Key", "ak_nope", http.StatusUnauthorized},
+		{"no key falls through to JWT", "POST", "/posts", "", "", http.StatusUnauthorized},
+		{"write-only key writes under read group", "POST", "/boards", "X-API-Key", "ak_wonly", http.StatusOK},
+		{"write-only key cannot read", "GET", "/boards", "X-API-Key", "ak_wonly", http.StatusForbidden},
+		{"read key reads", "GET", "/boards", "X-API-Key", "ak_read", http.StatusOK},
+		{"read key cannot write", "POST", "/boards", "X-API-Key", "ak_read", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tc.method, tc.path, nil)
+		if tc.header != "" {
+			req.Header.Set(tc.header, tc.value)
+		}
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.want, w.Code)
+		}
+	}
+}
+
+func TestAPIKey_HasScope(t *testing.T) {
+	admin := &domain.APIKey{Scopes: "admin:*"}
+	legacy := &domain.APIKey{Scopes: "read, write"}
+
+	if !admin.HasScope("admin:*") || !admin.HasScope("admin:members") {
+		t.Error("admin:* should grant admin scopes")
+	}
+	if admin.HasScope(domain.ScopeWritePosts) {
+		t.Error("admin:* should not grant write:posts")
+	}
+	if !legacy.HasScope(domain.ScopeReadPosts) || !legacy.HasScope(domain.ScopeWritePosts) {
+		t.Error("legacy read/write scopes should map to read:*/write:*")
+	}
+	if legacy.HasScope(domain.ScopeAdminAll) {
+		t.Error("legacy read/write should not grant admin")
+	}
+}
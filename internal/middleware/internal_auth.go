@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// InternalAssertionHeader carries a signed user assertion from trusted services
+// (SvelteKit SSR, CloudFront edge) to the backend.
+//
+// 형식: v1.<key id>.<base64url(JSON payload)>.<base64url(HMAC-SHA256)>
+// 서명 대상은 "v1.<key id>.<payload>" 문자열이며, payload는 InternalAssertion이다.
+const InternalAssertionHeader = "X-Internal-Assertion"
+
+const (
+	internalAssertionVersion = "v1"
+	// DefaultInternalAuthSkew 서명 시각 허용 오차 (재전송 캐시는 이 기간의 2배 동안 nonce 보관)
+	DefaultInternalAuthSkew = 30 * time.Second
+	internalNonceKeyPrefix  = "internal_auth:nonce:"
+	maxInternalNonceLen     = 64
+)
+
+// 내부 인증 에러
+var (
+	ErrInternalAssertionMalformed = errors.New("malformed internal assertion")
+	ErrInternalAssertionKey       = errors.New("unknown internal assertion key")
+	ErrInternalAssertionSignature = errors.New("invalid internal assertion signature")
+	ErrInternalAssertionExpired   = errors.New("internal assertion outside validity window")
+	ErrInternalAssertionRequest   = errors.New("internal assertion does not match request")
+	ErrInternalAssertionReplayed  = errors.New("internal assertion nonce already used")
+)
+
+// InternalAssertion is the payload a trusted service signs for each request
+type InternalAssertion struct {
+	UserID    string `json:"uid"`
+	Level     int    `json:"lvl"`
+	Timestamp int64  `json:"ts"` // Unix 초
+	Nonce     string `json:"nonce"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`          // 쿼리 문자열 제외
+	MFA       bool   `json:"mfa,omitempty"` // 사용자 토큰의 2단계 인증 여부를 그대로 전달
+}
+
+// InternalAuth verifies signed internal assertions.
+// 키 교체 중에는 이전/새 key ID를 모두 등록해 두고, 모든 서명 측이 새 키로 바뀐 뒤 이전 키를 제거한다.
+type InternalAuth struct {
+	keys  map[string][]byte
+	skew  time.Duration
+	redis *redis.Client
+	now   func() time.Time
+
+	// Redis가 없을 때(로컬 개발) 사용하는 프로세스 내 재전송 캐시
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewInternalAuth creates an InternalAuth (키가 없으면 nil — 내부 인증 비활성)
+func NewInternalAuth(keys map[string]string, maxSkew time.Duration, redisClient *redis.Client) *InternalAuth {
+	if len(keys) == 0 {
+		return nil
+	}
+	if maxSkew <= 0 {
+		maxSkew = DefaultInternalAuthSkew
+	}
+	a := &InternalAuth{
+		keys:  make(map[string][]byte, len(keys)),
+		skew:  maxSkew,
+		redis: redisClient,
+		now:   time.Now,
+		seen:  make(map[string]time.Time),
+	}
+	for id, secret := range keys {
+		if id != "" && secret != "" {
+			a.keys[id] = []byte(secret)
+		}
+	}
+	return a
+}
+
+// SignInternalAssertion builds the X-Internal-Assertion header value
+func SignInternalAssertion(keyID, secret string, assertion InternalAssertion) (string, error) {
+	payload, err := json.Marshal(assertion)
+	if err != nil {
+		return "", err
+	}
+	signed := internalAssertionVersion + "." + keyID + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(internalSignature(secret, signed)), nil
+}
+
+func internalSignature(secret, signed string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return mac.Sum(nil)
+}
+
+// Middleware authenticates requests carrying X-Internal-Assertion.
+// 헤더가 없는 요청은 그대로 넘기고, 헤더가 있는데 검증에 실패하면 401로 거부한다.
+// 인증되면 JWTAuth / OptionalJWTAuth는 건너뛴다.
+func (a *InternalAuth) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(InternalAssertionHeader)
+		if a == nil || header == "" {
+			c.Next()
+			return
+		}
+
+		assertion, err := a.Verify(c.Request.Context(), header, c.Request.Method, c.Request.URL.Path)
+		if err != nil {
+			log.Printf("[InternalAuth] rejected %s %s from %s: %v", c.Request.Method, c.Request.URL.Path, getRemoteIP(c), err)
+			common.ErrorResponse(c, http.StatusUnauthorized, "내부 인증에 실패했습니다", nil)
+			c.Abort()
+			return
+		}
+
+		c.Set("internalAuth", true)
+		c.Set("userID", assertion.UserID)
+		c.Set("nickname", "")
+		c.Set("level", assertion.Level)
+		c.Set("v2_user_id", assertion.UserID)
+		c.Set("mfa", assertion.MFA)
+		c.Next()
+	}
+}
+
+// Verify checks the signature, validity window, request binding and nonce of an assertion
+func (a *InternalAuth) Verify(ctx context.Context, header, method, path string) (*InternalAssertion, error) {
+	parts := strings.Split(header, ".")
+	if len(parts) != 4 || parts[0] != internalAssertionVersion {
+		return nil, ErrInternalAssertionMalformed
+	}
+	keyID := parts[1]
+	secret, ok := a.keys[keyID]
+	if !ok {
+		return nil, ErrInternalAssertionKey
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, ErrInternalAssertionMalformed
+	}
+	if !hmac.Equal(sig, internalSignature(string(secret), strings.Join(parts[:3], "."))) {
+		return nil, ErrInternalAssertionSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInternalAssertionMalformed
+	}
+	var assertion InternalAssertion
+	if err := json.Unmarshal(payload, &assertion); err != nil {
+		return nil, ErrInternalAssertionMalformed
+	}
+	if assertion.UserID == "" || assertion.Nonce == "" || len(assertion.Nonce) > maxInternalNonceLen {
+		return nil, ErrInternalAssertionMalformed
+	}
+
+	issued := time.Unix(assertion.Timestamp, 0)
+	if d := a.now().Sub(issued); d > a.skew || d < -a.skew {
+		return nil, ErrInternalAssertionExpired
+	}
+	if !strings.EqualFold(assertion.Method, method) || assertion.Path != path {
+		return nil, ErrInternalAssertionRequest
+	}
+
+	if err := a.useNonce(ctx, keyID+":"+assertion.Nonce); err != nil {
+		return nil, err
+	}
+	return &assertion, nil
+}
+
+// useNonce records a nonce, failing if it was already used within the replay window
+func (a *InternalAuth) useNonce(ctx context.Context, nonce string) error {
+	ttl := 2 * a.skew
+	if a.redis != nil {
+		ok, err := a.redis.SetNX(ctx, internalNonceKeyPrefix+nonce, 1, ttl).Result()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInternalAssertionReplayed
+		}
+		return nil
+	}
+
+	now := a.now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for n, expires := range a.seen {
+		if now.After(expires) {
+			delete(a.seen, n)
+		}
+	}
+	if _, used := a.seen[nonce]; used {
+		return ErrInternalAssertionReplayed
+	}
+	a.seen[nonce] = now.Add(ttl)
+	return nil
+}
+
+// IsInternalAuthenticated reports whether the request was authenticated by an internal assertion
+func IsInternalAuthenticated(c *gin.Context) bool {
+	return c.GetBool("internalAuth")
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+func TestInternalAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// 키 교체 중: old/new 모두 활성
+	auth := NewInternalAuth(map[string]string{"old": "old-secret", "new": "new-secret"}, 30*time.Second, nil)
+	jwtManager := jwt.NewManager("test-secret", 3600, 7200)
+
+	r := gin.New()
+	r.Use(auth.Middleware())
+	r.GET("/api/v2/admin/stats", JWTAuth(jwtManager), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user": GetUserID(c), "level": GetUserLevel(c)})
+	})
+
+	now := time.Now()
+	sign := func(keyID, secret string, a InternalAssertion) string {
+		t.Helper()
+		header, err := SignInternalAssertion(keyID, secret, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return header
+	}
+	valid := InternalAssertion{UserID: "admin", Level: 10, Timestamp: now.Unix(), Nonce: "n1", Method: "GET", Path: "/api/v2/admin/stats"}
+	replayed := sign("new", "new-secret", valid)
+
+	withNonce := func(a InternalAssertion, nonce string) InternalAssertion {
+		a.Nonce = nonce
+		return a
+	}
+	stale := withNonce(valid, "n4")
+	stale.Timestamp = now.Add(-time.Minute).Unix()
+	otherPath := withNonce(valid, "n5")
+	otherPath.Path = "/api/v2/admin/other"
+
+	cases := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"new key", map[string]string{InternalAssertionHeader: replayed}, http.StatusOK},
+		{"replayed nonce", map[string]string{InternalAssertionHeader: replayed}, http.StatusUnauthorized},
+		{"old key during rotation", map[string]string{InternalAssertionHeader: sign("old", "old-secret", withNonce(valid, "n2"))}, http.StatusOK},
+		{"unknown key", map[string]string{InternalAssertionHeader: sign("gone", "old-secret", withNonce(valid, "n3"))}, http.StatusUnauthorized},
+		{"wrong secret", map[string]string{InternalAssertionHeader: sign("new", "guess", withNonce(valid, "n6"))}, http.StatusUnauthorized},
+		{"expired", map[string]string{InternalAssertionHeader: sign("new", "new-secret", stale)}, http.StatusUnauthorized},
+		{"signed for another path", map[string]string{InternalAssertionHeader: sign("new", "new-secret", otherPath)}, http.StatusUnauthorized},
+		{"legacy headers are no longer trusted", map[string]string{
+			"X-Internal-Auth": "sveltekit-session", "X-Internal-Secret": "angple-internal-dev-2026", "X-Internal-User-ID": "admin",
+		}, http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v2/admin/stats", nil)
+		req.RemoteAddr = "127.0.0.1:5173"
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d (%s)", tc.name, tc.want, w.Code, w.Body.String())
+		}
+	}
+}
@@ -48,8 +48,10 @@ type BoardPermissionChecker interface {
 
 // BoardPermission returns a middleware that checks user's permission level for a board
 // It requires JWTAuth middleware to be applied first
-func BoardPermission(checker BoardPermissionChecker, action PermissionAction) gin.HandlerFunc {
+func BoardPermission(baseChecker BoardPermissionChecker, action PermissionAction) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		checker := TenantPermissionChecker(c, baseChecker)
+
 		// slug 또는 board_id 파라미터 모두 지원
 		boardID := c.Param("slug")
 		if boardID == "" {
@@ -6,6 +6,8 @@ import (
 
 	v2 "github.com/damoang/angple-backend/internal/domain/v2"
 	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // boardCacheEntry holds a cached board with expiry
@@ -20,6 +22,7 @@ type DBBoardPermissionChecker struct {
 	boardRepo v2repo.BoardRepository
 	cache     map[string]boardCacheEntry
 	mu        sync.RWMutex
+	tenants   sync.Map // map[string]*DBBoardPermissionChecker — 테넌트별 checker
 }
 
 const boardCacheTTL = 60 * time.Second
@@ -32,6 +35,29 @@ func NewDBBoardPermissionChecker(boardRepo v2repo.BoardRepository) *DBBoardPermi
 	}
 }
 
+// ForTenant returns a checker (with its own cache) reading boards from the tenant DB
+// key는 사이트 ID + DB 전략 (플랜 변경으로 DB가 바뀌면 새 checker 사용)
+func (c *DBBoardPermissionChecker) ForTenant(key string, db *gorm.DB) *DBBoardPermissionChecker {
+	if v, ok := c.tenants.Load(key); ok {
+		if checker, ok := v.(*DBBoardPermissionChecker); ok {
+			return checker
+		}
+	}
+	v, _ := c.tenants.LoadOrStore(key, NewDBBoardPermissionChecker(v2repo.NewBoardRepository(db)))
+	return v.(*DBBoardPermissionChecker) //nolint:forcetypeassert // only checkers are stored
+}
+
+// TenantPermissionChecker returns the checker for the request's tenant (메인 사이트는 checker 그대로)
+func TenantPermissionChecker(c *gin.Context, checker BoardPermissionChecker) BoardPermissionChecker {
+	db := GetTenantDB(c)
+	tenantID := GetTenantID(c)
+	dbChecker, ok := checker.(*DBBoardPermissionChecker)
+	if db == nil || tenantID == "" || !ok {
+		return checker
+	}
+	return dbChecker.ForTenant(tenantID+"|"+GetTenantDBStrategy(c), db)
+}
+
 // getBoard returns a board from cache or DB
 func (c *DBBoardPermissionChecker) getBoard(slug string) (*v2.V2Board, error) {
 	now := time.Now()
@@ -28,6 +28,15 @@ func DefaultRateLimitConfig() RateLimitConfig {
 	}
 }
 
+// VariantRateLimitConfig limits the unauthenticated image variant route (변형 생성이 동기로 실행됨)
+func VariantRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		RequestsPerMinute: 120,
+		KeyPrefix:         "api:ratelimit:variants:",
+		Message:           "이미지 요청이 너무 많습니다. 잠시 후 다시 시도해주세요.",
+	}
+}
+
 // rateLimitScript is an atomic Lua script for sliding window rate limiting
 var rateLimitScript = redis.NewScript(`
 local key = KEYS[1]
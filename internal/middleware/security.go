@@ -82,7 +82,7 @@ func CSRFProtection() gin.HandlerFunc {
 		}
 
 		// Skip if using API key
-		if c.GetHeader("X-API-Key") != "" || c.Query("api_key") != "" {
+		if extractAPIKey(c) != "" || c.Query("api_key") != "" {
 			c.Next()
 			return
 		}
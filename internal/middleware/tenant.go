@@ -1,12 +1,16 @@
 package middleware
 
 import (
+	"context"
+	"net/http"
 	"regexp"
 	"strings"
 
 	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/domain"
 	"github.com/damoang/angple-backend/internal/repository"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 const planFree = "free"
@@ -20,8 +24,10 @@ type TenantContext struct {
 }
 
 // TenantMiddleware extracts tenant information from the request
-// and stores it in the Gin context for downstream handlers
-func TenantMiddleware(siteRepo *repository.SiteRepository, baseDomain string) gin.HandlerFunc {
+// and stores it in the Gin context for downstream handlers.
+// resolver가 있으면 테넌트 DB(shared/schema/dedicated)를 "tenant_db"에 담는다 (GetTenantDB).
+// 메인 도메인, 예약 서브도메인 요청은 테넌트 없이 통과한다.
+func TenantMiddleware(siteRepo *repository.SiteRepository, baseDomain string, resolver *TenantDBResolver) gin.HandlerFunc {
 	// Compile regex once for performance
 	subdomainRegex := regexp.MustCompile(`^([a-z0-9-]+)\.` + regexp.QuoteMeta(baseDomain) + `$`)
 
@@ -43,12 +49,16 @@ func TenantMiddleware(siteRepo *repository.SiteRepository, baseDomain string) gi
 		// Check for custom domain header (set by Caddy)
 		if customDomain := c.GetHeader("X-Custom-Domain"); customDomain != "" {
 			isCustomDomain = true
-			subdomain = customDomain
+			subdomain = strings.ToLower(customDomain)
 		} else if subdomainHeader := c.GetHeader("X-Tenant-Subdomain"); subdomainHeader != "" {
 			// Caddy passes subdomain in header
-			subdomain = subdomainHeader
+			subdomain = strings.ToLower(subdomainHeader)
 		} else {
 			// Extract from Host header
+			if baseDomain == "" {
+				c.Next()
+				return
+			}
 			matches := subdomainRegex.FindStringSubmatch(host)
 			if len(matches) < 2 {
 				// Not a subdomain, might be the main domain
@@ -59,13 +69,13 @@ func TenantMiddleware(siteRepo *repository.SiteRepository, baseDomain string) gi
 		}
 
 		// Skip reserved subdomains
-		if isReservedSubdomain(subdomain) {
+		if !isCustomDomain && isReservedSubdomain(subdomain) {
 			c.Next()
 			return
 		}
 
 		// 2. Look up site by subdomain or custom domain
-		var site interface{}
+		var site *domain.Site
 		var err error
 
 		if isCustomDomain {
@@ -75,26 +85,37 @@ func TenantMiddleware(siteRepo *repository.SiteRepository, baseDomain string) gi
 		}
 
 		if err != nil {
+			common.ErrorResponse(c, 500, "Failed to resolve site", err)
+			c.Abort()
+			return
+		}
+		if site == nil || !site.Active {
 			common.ErrorResponse(c, 404, "Site not found", nil)
 			c.Abort()
 			return
 		}
-
-		// 3. Extract site information
-		// Note: Actual implementation would use proper type assertion
-		// This is a placeholder structure
-		siteMap, ok := site.(map[string]interface{})
-		if !ok {
-			common.ErrorResponse(c, 500, "Invalid site data", nil)
+		if site.Suspended {
+			common.ErrorResponse(c, 403, "Site is suspended", nil)
 			c.Abort()
 			return
 		}
 
 		tenant := TenantContext{
-			SiteID:     getString(siteMap, "id"),
-			Subdomain:  getString(siteMap, "subdomain"),
-			DBStrategy: getString(siteMap, "db_strategy"),
-			Plan:       getString(siteMap, "plan"),
+			SiteID:     site.ID,
+			Subdomain:  site.Subdomain,
+			DBStrategy: site.DBStrategy,
+			Plan:       site.Plan,
+		}
+
+		// 3. Resolve tenant DB (사이트 ID가 바인딩된 세션)
+		if resolver != nil {
+			db, err := resolver.Resolve(site)
+			if err != nil {
+				common.ErrorResponse(c, 503, "Site database unavailable", err)
+				c.Abort()
+				return
+			}
+			c.Set("tenant_db", db)
 		}
 
 		// 4. Store tenant info in context
@@ -103,11 +124,22 @@ func TenantMiddleware(siteRepo *repository.SiteRepository, baseDomain string) gi
 		c.Set("tenant_subdomain", tenant.Subdomain)
 		c.Set("tenant_db_strategy", tenant.DBStrategy)
 		c.Set("tenant_plan", tenant.Plan)
+		c.Request = c.Request.WithContext(repository.ContextWithSiteID(c.Request.Context(), tenant.SiteID))
 
 		c.Next()
 	}
 }
 
+// GetTenantDB returns the tenant's *gorm.DB set by TenantMiddleware (메인 사이트 요청은 nil)
+func GetTenantDB(c *gin.Context) *gorm.DB {
+	if v, ok := c.Get("tenant_db"); ok {
+		if db, ok := v.(*gorm.DB); ok {
+			return db
+		}
+	}
+	return nil
+}
+
 // GetTenant extracts tenant context from Gin context
 func GetTenant(c *gin.Context) *TenantContext {
 	tenant, exists := c.Get("tenant")
@@ -202,16 +234,6 @@ func isReservedSubdomain(subdomain string) bool {
 	return reserved[subdomain]
 }
 
-// getString safely extracts a string from a map
-func getString(m map[string]interface{}, key string) string {
-	if val, ok := m[key]; ok {
-		if str, ok := val.(string); ok {
-			return str
-		}
-	}
-	return ""
-}
-
 // RequireTenant middleware ensures a tenant context exists
 func RequireTenant() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -254,3 +276,38 @@ func RequirePlan(minPlan string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// SiteRoleChecker reports whether a user holds at least the given role on a site (service.SiteService)
+type SiteRoleChecker interface {
+	CheckUserPermission(ctx context.Context, siteID, userID, requiredRole string) (bool, error)
+}
+
+// RequireSiteOwner allows only the owner of the :id site or a platform admin (관리자는 RequireAdmin과 같은 2FA 검사).
+// JWTAuth 뒤에 둔다.
+func RequireSiteOwner(checker SiteRoleChecker) gin.HandlerFunc {
+	requireAdmin := RequireAdmin()
+	return func(c *gin.Context) {
+		if GetUserLevel(c) >= 10 {
+			requireAdmin(c)
+			return
+		}
+		userID := GetUserID(c)
+		if userID == "" {
+			common.V2ErrorResponse(c, http.StatusUnauthorized, "로그인이 필요합니다", nil)
+			c.Abort()
+			return
+		}
+		owner, err := checker.CheckUserPermission(c.Request.Context(), c.Param("id"), userID, "owner")
+		if err != nil {
+			common.V2ErrorResponse(c, http.StatusInternalServerError, "권한 확인에 실패했습니다", err)
+			c.Abort()
+			return
+		}
+		if !owner {
+			common.V2ErrorResponse(c, http.StatusForbidden, "사이트 소유자만 접근할 수 있습니다", nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,17 +1,26 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/repository"
 	"gorm.io/gorm"
 )
 
+// TenantDBConnector opens a connection pool to a tenant database.
+// host가 비어 있으면 기본 DB 서버, port가 0이면 기본 포트를 사용한다.
+type TenantDBConnector func(host string, port int, database string) (*gorm.DB, error)
+
 // TenantDBResolver resolves the correct database connection for a tenant
 // based on the site's DB strategy (shared, schema, dedicated)
 type TenantDBResolver struct {
 	defaultDB *gorm.DB
-	schemaDbs sync.Map // map[string]*gorm.DB — per-schema connections
+	connector TenantDBConnector
+	conns     sync.Map // map[string]*gorm.DB — schema/dedicated 연결 풀 (host:port/database)
+	tenantDBs sync.Map // map[string]*gorm.DB — 사이트별 컨텍스트 바인딩 세션
 }
 
 // NewTenantDBResolver creates a new resolver
@@ -21,39 +30,110 @@ func NewTenantDBResolver(defaultDB *gorm.DB) *TenantDBResolver {
 	}
 }
 
-// ResolveDB returns the appropriate *gorm.DB for a tenant
-func (r *TenantDBResolver) ResolveDB(_, dbStrategy, schemaName string) *gorm.DB {
-	switch dbStrategy {
+// SetConnector sets the connector used for schema / dedicated tenants.
+// 설정하지 않으면 모든 테넌트가 기본 DB를 사용한다 (site_id로 격리).
+func (r *TenantDBResolver) SetConnector(connector TenantDBConnector) {
+	r.connector = connector
+}
+
+// Resolve returns the *gorm.DB for a site, bound to a context carrying its site ID.
+// 반환된 DB로 만든 리포지토리는 repository.RegisterTenantScope에 의해 해당 사이트 데이터만 본다.
+func (r *TenantDBResolver) Resolve(site *domain.Site) (*gorm.DB, error) {
+	schemaName := ""
+	if site.DBSchemaName != nil {
+		schemaName = *site.DBSchemaName
+	}
+	cacheKey := site.ID + "|" + site.DBStrategy + "|" + schemaName
+	if db, ok := loadDB(&r.tenantDBs, cacheKey); ok {
+		return db, nil
+	}
+
+	base, err := r.baseDB(site, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	db := base.WithContext(repository.ContextWithSiteID(context.Background(), site.ID))
+	r.tenantDBs.Store(cacheKey, db)
+	return db, nil
+}
+
+// ResolveDB returns the appropriate *gorm.DB for a tenant (연결 실패 시 기본 DB)
+func (r *TenantDBResolver) ResolveDB(siteID, dbStrategy, schemaName string) *gorm.DB {
+	site := &domain.Site{ID: siteID, DBStrategy: dbStrategy}
+	if schemaName != "" {
+		site.DBSchemaName = &schemaName
+	}
+	db, err := r.baseDB(site, schemaName)
+	if err != nil {
+		return r.defaultDB
+	}
+	return db
+}
+
+// baseDB returns the connection pool for the site's strategy
+func (r *TenantDBResolver) baseDB(site *domain.Site, schemaName string) (*gorm.DB, error) {
+	switch site.DBStrategy {
 	case "schema":
-		return r.resolveSchema(schemaName)
+		if schemaName == "" {
+			return r.defaultDB, nil
+		}
+		return r.connect("", 0, schemaName)
 	case "dedicated":
-		// dedicated DB 연결은 별도 설정 필요 (추후)
-		return r.defaultDB
+		if site.DBHost == nil || *site.DBHost == "" {
+			return nil, fmt.Errorf("dedicated DB host not configured for site %s", site.ID)
+		}
+		if schemaName == "" {
+			schemaName = "tenant_" + site.ID
+		}
+		return r.connect(*site.DBHost, site.DBPort, schemaName)
 	default: // "shared"
-		return r.defaultDB
+		return r.defaultDB, nil
 	}
 }
 
-// resolveSchema returns a session scoped to the given schema
-func (r *TenantDBResolver) resolveSchema(schemaName string) *gorm.DB {
-	if schemaName == "" {
-		return r.defaultDB
+// connect returns a cached connection pool, opening one via the connector if needed
+func (r *TenantDBResolver) connect(host string, port int, database string) (*gorm.DB, error) {
+	if r.connector == nil {
+		return r.defaultDB, nil
 	}
 
-	// 캐시된 세션 확인
-	if db, ok := r.schemaDbs.Load(schemaName); ok {
-		if gormDB, assertOK := db.(*gorm.DB); assertOK {
-			return gormDB
-		}
+	key := connKey(host, port, database)
+	if db, ok := loadDB(&r.conns, key); ok {
+		return db, nil
 	}
 
-	// 새 세션 생성 (USE schema_name)
-	db := r.defaultDB.Session(&gorm.Session{})
-	if err := db.Exec(fmt.Sprintf("USE `%s`", schemaName)).Error; err != nil {
-		return r.defaultDB
+	db, err := r.connector(host, port, database)
+	if err != nil {
+		return nil, fmt.Errorf("tenant DB %s: %w", key, err)
+	}
+	if err := repository.RegisterTenantScope(db); err != nil {
+		return nil, fmt.Errorf("tenant DB %s: %w", key, err)
+	}
+	if actual, loaded := r.conns.LoadOrStore(key, db); loaded {
+		// 동시에 열린 중복 연결은 닫는다
+		closeDB(db)
+		return actual.(*gorm.DB), nil //nolint:forcetypeassert // only *gorm.DB is stored
+	}
+	return db, nil
+}
+
+func connKey(host string, port int, database string) string {
+	return fmt.Sprintf("%s:%d/%s", host, port, database)
+}
+
+func loadDB(m *sync.Map, key string) (*gorm.DB, bool) {
+	v, ok := m.Load(key)
+	if !ok {
+		return nil, false
+	}
+	db, ok := v.(*gorm.DB)
+	return db, ok
+}
+
+func closeDB(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		_ = sqlDB.Close() //nolint:errcheck // best-effort close
 	}
-	r.schemaDbs.Store(schemaName, db)
-	return db
 }
 
 // CreateSchema creates a new database schema for a tenant
@@ -65,7 +145,10 @@ func (r *TenantDBResolver) CreateSchema(schemaName string) error {
 // DropSchema drops a tenant's database schema
 func (r *TenantDBResolver) DropSchema(schemaName string) error {
 	sql := fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", schemaName)
-	r.schemaDbs.Delete(schemaName)
+	if db, ok := loadDB(&r.conns, connKey("", 0, schemaName)); ok {
+		r.conns.Delete(connKey("", 0, schemaName))
+		closeDB(db)
+	}
 	return r.defaultDB.Exec(sql).Error
 }
 
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageRecorder records per-tenant request usage (implemented by service.UsageMeter)
+type UsageRecorder interface {
+	RecordRequest(ctx context.Context, siteID, visitor string, bytes int64, pageView bool)
+}
+
+// UsageMetering counts API calls, bytes served, page views and unique visitors per tenant.
+// TenantMiddleware 뒤에 등록해야 하며, 테넌트가 없는 요청(메인 사이트)은 집계하지 않는다.
+// 페이지뷰는 게시판/게시글 조회(GET /api/v2/boards/...) 성공 응답만 센다.
+func UsageMetering(recorder UsageRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		siteID := GetTenantID(c)
+		if recorder == nil || siteID == "" {
+			return
+		}
+
+		pageView := c.Request.Method == http.MethodGet &&
+			c.Writer.Status() < http.StatusBadRequest &&
+			strings.HasPrefix(c.Request.URL.Path, "/api/v2/boards/")
+
+		recorder.RecordRequest(c.Request.Context(), siteID, usageVisitor(c), int64(c.Writer.Size()), pageView)
+	}
+}
+
+// usageVisitor identifies a visitor for unique counting (회원 ID, 없으면 IP + User-Agent)
+func usageVisitor(c *gin.Context) string {
+	if userID := GetUserID(c); userID != "" {
+		return "u:" + userID
+	}
+	return "a:" + c.ClientIP() + "|" + c.Request.UserAgent()
+}
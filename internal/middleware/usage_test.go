@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type usageCall struct {
+	siteID   string
+	visitor  string
+	bytes    int64
+	pageView bool
+}
+
+type fakeUsageRecorder struct {
+	calls []usageCall
+}
+
+func (r *fakeUsageRecorder) RecordRequest(_ context.Context, siteID, visitor string, bytes int64, pageView bool) {
+	r.calls = append(r.calls, usageCall{siteID, visitor, bytes, pageView})
+}
+
+func TestUsageMetering(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := &fakeUsageRecorder{}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if site := c.GetHeader("X-Test-Site"); site != "" {
+			c.Set("tenant_id", site)
+		}
+	}, UsageMetering(recorder))
+	r.GET("/api/v2/boards/free/posts", func(c *gin.Context) { c.String(http.StatusOK, "hello") })
+	r.POST("/api/v2/boards/free/posts", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	send := func(method, site string) {
+		req, _ := http.NewRequest(method, "/api/v2/boards/free/posts", nil)
+		if site != "" {
+			req.Header.Set("X-Test-Site", site)
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	send(http.MethodGet, "site-a")
+	send(http.MethodPost, "site-a")
+	send(http.MethodGet, "")
+
+	if len(recorder.calls) != 2 {
+		t.Fatalf("expected 2 recorded calls (main site skipped), got %d", len(recorder.calls))
+	}
+	get, post := recorder.calls[0], recorder.calls[1]
+	if get.siteID != "site-a" || !get.pageView || get.bytes != 5 || get.visitor == "" {
+		t.Errorf("unexpected GET usage: %+v", get)
+	}
+	if post.pageView {
+		t.Errorf("POST should not count as a page view: %+v", post)
+	}
+}
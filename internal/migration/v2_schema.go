@@ -21,6 +21,10 @@ func RunV2Schema(db *gorm.DB) error {
 		&v2.V2File{},
 		&v2.V2Notification{},
 		&v2.V2Session{},
+		&v2.V2LoginAttempt{},
+		&v2.V2TwoFactor{},
+		&v2.V2RecoveryCode{},
+		&v2.V2UserToken{},
 
 		// Scrap, Memo, Message
 		&v2.V2Scrap{},
@@ -0,0 +1,126 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fake webhook headers (서명 방식은 Toss와 동일)
+const (
+	FakeSignatureHeader = "X-Fake-Signature"
+	FakeTimeHeader      = "X-Fake-Timestamp"
+)
+
+// FakeProvider is an in-memory Provider for tests and local development.
+// Decline을 설정하면 다음 Charge가 거절되며, Charges에 모든 청구 요청이 기록된다.
+type FakeProvider struct {
+	WebhookSecret string
+
+	mu      sync.Mutex
+	seq     int
+	decline bool
+	Charges []ChargeRequest
+}
+
+// NewFakeProvider creates a new FakeProvider
+func NewFakeProvider(webhookSecret string) *FakeProvider {
+	return &FakeProvider{WebhookSecret: webhookSecret}
+}
+
+// Name returns "fake"
+func (p *FakeProvider) Name() string { return ProviderFake }
+
+// SetDecline makes subsequent charges fail (true) or succeed (false)
+func (p *FakeProvider) SetDecline(decline bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.decline = decline
+}
+
+// CreateCheckout returns a session pointing at a fake checkout page
+func (p *FakeProvider) CreateCheckout(_ context.Context, req *CheckoutRequest) (*CheckoutSession, error) {
+	return &CheckoutSession{
+		Provider:    ProviderFake,
+		CustomerKey: req.CustomerKey,
+		OrderID:     req.OrderID,
+		OrderName:   req.OrderName,
+		AmountKRW:   req.AmountKRW,
+		CheckoutURL: "https://fake-pay.local/checkout/" + req.OrderID,
+		SuccessURL:  req.SuccessURL,
+		FailURL:     req.FailURL,
+	}, nil
+}
+
+// ConfirmCheckout returns a deterministic billing key for the customer
+func (p *FakeProvider) ConfirmCheckout(_ context.Context, req *ConfirmRequest) (string, error) {
+	if req.AuthKey == "" {
+		return "", &ChargeError{Code: "INVALID_AUTH_KEY", Message: "auth key is required"}
+	}
+	return "fake_bk_" + req.CustomerKey, nil
+}
+
+// Charge records the request and approves it unless declining
+func (p *FakeProvider) Charge(_ context.Context, req *ChargeRequest) (*ChargeResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Charges = append(p.Charges, *req)
+	if p.decline {
+		return nil, &ChargeError{Code: "REJECT_CARD_PAYMENT", Message: "declined by fake provider"}
+	}
+	p.seq++
+	return &ChargeResult{PaymentKey: "fake_pay_" + strconv.Itoa(p.seq), OrderID: req.OrderID, ApprovedAt: time.Now()}, nil
+}
+
+// fakeWebhook is the JSON body of a fake webhook
+type fakeWebhook struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	OrderID     string `json:"order_id"`
+	PaymentKey  string `json:"payment_key"`
+	CustomerKey string `json:"customer_key"`
+}
+
+// ParseWebhook verifies the HMAC signature of a fake webhook
+func (p *FakeProvider) ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	if !verifyHMAC(p.WebhookSecret, header.Get(FakeSignatureHeader), header.Get(FakeTimeHeader), body) {
+		return nil, ErrInvalidSignature
+	}
+	var payload fakeWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("fake webhook: %w", err)
+	}
+	switch payload.Type {
+	case EventPaymentSucceeded, EventPaymentFailed, EventPaymentRefunded, EventSubscriptionCanceled:
+	default:
+		return nil, ErrUnknownEvent
+	}
+	return &WebhookEvent{
+		ID:          payload.ID,
+		Type:        payload.Type,
+		OrderID:     payload.OrderID,
+		PaymentKey:  payload.PaymentKey,
+		CustomerKey: payload.CustomerKey,
+		OccurredAt:  time.Now(),
+	}, nil
+}
+
+// BuildWebhook returns a signed webhook body and headers for event (테스트용)
+func (p *FakeProvider) BuildWebhook(event *WebhookEvent) ([]byte, http.Header) {
+	body, _ := json.Marshal(fakeWebhook{ //nolint:errcheck // plain struct always marshals
+		ID:          event.ID,
+		Type:        event.Type,
+		OrderID:     event.OrderID,
+		PaymentKey:  event.PaymentKey,
+		CustomerKey: event.CustomerKey,
+	})
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	header := http.Header{}
+	header.Set(FakeTimeHeader, ts)
+	header.Set(FakeSignatureHeader, SignWebhook(p.WebhookSecret, ts, body))
+	return body, header
+}
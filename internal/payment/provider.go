@@ -0,0 +1,123 @@
+// Package payment abstracts payment gateways used for SaaS subscription billing.
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Provider names (Subscription.PaymentProvider / Invoice.PaymentProvider)
+const (
+	ProviderToss = "toss"
+	ProviderFake = "fake"
+)
+
+// Normalized webhook event types
+const (
+	EventPaymentSucceeded     = "payment.succeeded"
+	EventPaymentFailed        = "payment.failed"
+	EventPaymentRefunded      = "payment.refunded"
+	EventSubscriptionCanceled = "subscription.canceled"
+)
+
+// Errors returned by providers
+var (
+	ErrInvalidSignature = errors.New("payment: invalid webhook signature")
+	ErrUnknownEvent     = errors.New("payment: unsupported webhook event")
+)
+
+// Provider is a payment gateway used for recurring subscription billing.
+// 정기 결제는 "결제 수단 등록(checkout) → 빌링키 발급(confirm) → 빌링키로 청구(charge)" 순서로 진행한다.
+type Provider interface {
+	Name() string
+	// CreateCheckout prepares a checkout session for registering a payment method
+	CreateCheckout(ctx context.Context, req *CheckoutRequest) (*CheckoutSession, error)
+	// ConfirmCheckout exchanges the gateway's auth result for a reusable billing key
+	ConfirmCheckout(ctx context.Context, req *ConfirmRequest) (string, error)
+	// Charge bills an amount against a billing key
+	Charge(ctx context.Context, req *ChargeRequest) (*ChargeResult, error)
+	// ParseWebhook verifies the webhook signature and normalizes the event
+	ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error)
+}
+
+// CheckoutRequest describes a payment method registration for a site
+type CheckoutRequest struct {
+	CustomerKey string // 사이트 ID
+	OrderID     string // 첫 청구서 주문 ID
+	OrderName   string
+	AmountKRW   int
+	Email       string
+	SuccessURL  string
+	FailURL     string
+}
+
+// CheckoutSession is what the frontend needs to open the gateway's payment window
+type CheckoutSession struct {
+	Provider    string `json:"provider"`
+	ClientKey   string `json:"client_key,omitempty"`
+	CustomerKey string `json:"customer_key"`
+	OrderID     string `json:"order_id"`
+	OrderName   string `json:"order_name"`
+	AmountKRW   int    `json:"amount_krw"`
+	CheckoutURL string `json:"checkout_url,omitempty"`
+	SuccessURL  string `json:"success_url"`
+	FailURL     string `json:"fail_url"`
+}
+
+// ConfirmRequest carries the gateway's auth result back from the success redirect
+type ConfirmRequest struct {
+	CustomerKey string
+	AuthKey     string
+}
+
+// ChargeRequest bills AmountKRW against a billing key
+type ChargeRequest struct {
+	BillingKey  string
+	CustomerKey string
+	OrderID     string
+	OrderName   string
+	AmountKRW   int
+	Email       string
+}
+
+// ChargeResult is the outcome of a successful charge
+type ChargeResult struct {
+	PaymentKey string
+	OrderID    string
+	ApprovedAt time.Time
+}
+
+// ChargeError is returned when the gateway declines a charge (카드 한도 초과 등)
+type ChargeError struct {
+	Code    string
+	Message string
+}
+
+func (e *ChargeError) Error() string {
+	return "payment declined: " + e.Code + " " + e.Message
+}
+
+// GatewayError is a non-decline gateway failure (5xx, 인증/요청 오류 등).
+// 결제 거절이 아니므로 청구서를 실패 처리하지 않고 나중에 다시 시도한다.
+type GatewayError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *GatewayError) Error() string {
+	return fmt.Sprintf("payment gateway error: %d %s %s", e.StatusCode, e.Code, e.Message)
+}
+
+// WebhookEvent is a verified, provider-independent webhook event
+type WebhookEvent struct {
+	ID          string
+	Type        string
+	OrderID     string // Invoice.ExternalInvID
+	PaymentKey  string
+	CustomerKey string // 사이트 ID (구독 이벤트)
+	OccurredAt  time.Time
+}
@@ -0,0 +1,248 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const tossAPIBase = "https://api.tosspayments.com"
+
+// Toss webhook headers (HMAC-SHA256(secret, body + ":" + transmission-time), "v1:" 접두사 + base64)
+const (
+	tossSignatureHeader = "tosspayments-webhook-signature"
+	tossTimeHeader      = "tosspayments-webhook-transmission-time"
+
+	// tossWebhookTolerance 전송 시각과 현재 시각의 허용 차이 (재전송 공격 방지)
+	tossWebhookTolerance = 5 * time.Minute
+)
+
+// tossDeclineCodes Toss 결제 거절 에러 코드 (카드/계좌 사유) — 이 코드만 ChargeError로 취급한다
+var tossDeclineCodes = map[string]bool{
+	"REJECT_CARD_PAYMENT":                             true,
+	"REJECT_CARD_COMPANY":                             true,
+	"REJECT_ACCOUNT_PAYMENT":                          true,
+	"INVALID_REJECT_CARD":                             true,
+	"INVALID_CARD_EXPIRATION":                         true,
+	"INVALID_STOPPED_CARD":                            true,
+	"INVALID_CARD_LOST_OR_STOLEN":                     true,
+	"INVALID_CARD_NUMBER":                             true,
+	"INVALID_CARD_INFO_RE_REGISTER":                   true,
+	"INVALID_ACCOUNT_INFO_RE_REGISTER":                true,
+	"EXCEED_MAX_DAILY_PAYMENT_COUNT":                  true,
+	"EXCEED_MAX_PAYMENT_AMOUNT":                       true,
+	"EXCEED_MAX_AMOUNT":                               true,
+	"EXCEED_MAX_MONTHLY_PAYMENT_AMOUNT":               true,
+	"NOT_SUPPORTED_INSTALLMENT_PLAN_CARD_OR_MERCHANT": true,
+	"NOT_REGISTERED_CARD_COMPANY":                     true,
+	"NOT_AVAILABLE_PAYMENT":                           true,
+	"BELOW_MINIMUM_AMOUNT":                            true,
+	"RESTRICTED_TRANSFER_ACCOUNT":                     true,
+	"INVALID_AUTHORIZE_AUTH":                          true,
+	"NOT_MATCHES_CUSTOMER_KEY":                        true,
+}
+
+// TossConfig Toss Payments 자격 증명
+type TossConfig struct {
+	SecretKey     string
+	ClientKey     string
+	WebhookSecret string
+	APIBase       string // 비어 있으면 운영 API
+}
+
+// TossProvider implements Provider with Toss Payments automatic billing (자동결제/빌링)
+type TossProvider struct {
+	cfg    TossConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewTossProvider creates a new TossProvider
+func NewTossProvider(cfg TossConfig) *TossProvider {
+	if cfg.APIBase == "" {
+		cfg.APIBase = tossAPIBase
+	}
+	return &TossProvider{cfg: cfg, client: &http.Client{Timeout: 15 * time.Second}, now: time.Now}
+}
+
+// Name returns "toss"
+func (p *TossProvider) Name() string { return ProviderToss }
+
+// CreateCheckout returns the parameters for the Toss billing auth window (requestBillingAuth).
+// Toss는 서버 측 결제 세션이 없으므로 프론트엔드 SDK에 넘길 값만 만든다.
+func (p *TossProvider) CreateCheckout(_ context.Context, req *CheckoutRequest) (*CheckoutSession, error) {
+	return &CheckoutSession{
+		Provider:    ProviderToss,
+		ClientKey:   p.cfg.ClientKey,
+		CustomerKey: req.CustomerKey,
+		OrderID:     req.OrderID,
+		OrderName:   req.OrderName,
+		AmountKRW:   req.AmountKRW,
+		SuccessURL:  req.SuccessURL,
+		FailURL:     req.FailURL,
+	}, nil
+}
+
+// ConfirmCheckout issues a billing key from the authKey (POST /v1/billing/authorizations/issue)
+func (p *TossProvider) ConfirmCheckout(ctx context.Context, req *ConfirmRequest) (string, error) {
+	var resp struct {
+		BillingKey string `json:"billingKey"`
+	}
+	body := map[string]string{"authKey": req.AuthKey, "customerKey": req.CustomerKey}
+	if err := p.post(ctx, "/v1/billing/authorizations/issue", body, &resp); err != nil {
+		return "", err
+	}
+	if resp.BillingKey == "" {
+		return "", fmt.Errorf("toss: empty billing key")
+	}
+	return resp.BillingKey, nil
+}
+
+// Charge bills the billing key (POST /v1/billing/{billingKey})
+func (p *TossProvider) Charge(ctx context.Context, req *ChargeRequest) (*ChargeResult, error) {
+	var resp struct {
+		PaymentKey string `json:"paymentKey"`
+		OrderID    string `json:"orderId"`
+		Status     string `json:"status"`
+		ApprovedAt string `json:"approvedAt"`
+	}
+	body := map[string]interface{}{
+		"customerKey":   req.CustomerKey,
+		"amount":        req.AmountKRW,
+		"orderId":       req.OrderID,
+		"orderName":     req.OrderName,
+		"customerEmail": req.Email,
+	}
+	if err := p.post(ctx, "/v1/billing/"+url.PathEscape(req.BillingKey), body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != "DONE" {
+		return nil, &ChargeError{Code: resp.Status, Message: "payment not completed"}
+	}
+	approvedAt, err := time.Parse(time.RFC3339, resp.ApprovedAt)
+	if err != nil {
+		approvedAt = time.Now()
+	}
+	return &ChargeResult{PaymentKey: resp.PaymentKey, OrderID: resp.OrderID, ApprovedAt: approvedAt}, nil
+}
+
+// ParseWebhook verifies the signature and maps PAYMENT_STATUS_CHANGED events.
+// 서명에 포함된 전송 시각이 허용 범위를 벗어나면 (재전송) 서명 오류로 거부한다.
+func (p *TossProvider) ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	transmissionTime := header.Get(tossTimeHeader)
+	if !verifyHMAC(p.cfg.WebhookSecret, header.Get(tossSignatureHeader), transmissionTime, body) {
+		return nil, ErrInvalidSignature
+	}
+	sentAt, err := time.Parse(time.RFC3339, transmissionTime)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	if d := p.now().Sub(sentAt); d > tossWebhookTolerance || d < -tossWebhookTolerance {
+		return nil, ErrInvalidSignature
+	}
+
+	var payload struct {
+		EventType string `json:"eventType"`
+		CreatedAt string `json:"createdAt"`
+		Data      struct {
+			PaymentKey string `json:"paymentKey"`
+			OrderID    string `json:"orderId"`
+			Status     string `json:"status"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("toss webhook: %w", err)
+	}
+	if payload.EventType != "PAYMENT_STATUS_CHANGED" {
+		return nil, ErrUnknownEvent
+	}
+
+	event := &WebhookEvent{
+		ID:         payload.Data.PaymentKey + ":" + payload.Data.Status,
+		OrderID:    payload.Data.OrderID,
+		PaymentKey: payload.Data.PaymentKey,
+		OccurredAt: time.Now(),
+	}
+	if t, err := time.Parse(time.RFC3339, payload.CreatedAt); err == nil {
+		event.OccurredAt = t
+	}
+	switch payload.Data.Status {
+	case "DONE":
+		event.Type = EventPaymentSucceeded
+	case "ABORTED", "EXPIRED":
+		event.Type = EventPaymentFailed
+	case "CANCELED", "PARTIAL_CANCELED":
+		event.Type = EventPaymentRefunded
+	default:
+		return nil, ErrUnknownEvent
+	}
+	return event, nil
+}
+
+func (p *TossProvider) post(ctx context.Context, path string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.APIBase+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(p.cfg.SecretKey+":")))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("toss request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("toss response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var tossErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(raw, &tossErr) //nolint:errcheck // best-effort error decoding
+		// 문서화된 거절 코드(4xx)만 결제 거절, 나머지(5xx, 인증 오류, 알 수 없는 코드)는 재시도 대상
+		if resp.StatusCode < http.StatusInternalServerError && tossDeclineCodes[tossErr.Code] {
+			return &ChargeError{Code: tossErr.Code, Message: tossErr.Message}
+		}
+		return &GatewayError{StatusCode: resp.StatusCode, Code: tossErr.Code, Message: tossErr.Message}
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// SignWebhook computes the v1 signature header value for body (Toss 형식, FakeProvider/테스트에서도 사용)
+func SignWebhook(secret, transmissionTime string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	mac.Write([]byte(":" + transmissionTime))
+	return "v1:" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyHMAC checks any of the comma-separated v1 signatures
+func verifyHMAC(secret, signatures, transmissionTime string, body []byte) bool {
+	if secret == "" || signatures == "" || transmissionTime == "" {
+		return false
+	}
+	expected := SignWebhook(secret, transmissionTime, body)
+	for _, sig := range strings.Split(signatures, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(sig)), []byte(expected)) {
+			return true
+		}
+	}
+	return false
+}
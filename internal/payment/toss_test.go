@@ -0,0 +1,86 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTossProvider_ParseWebhook(t *testing.T) {
+	p := NewTossProvider(TossConfig{WebhookSecret: "secret"})
+	sentAt, _ := time.Parse(time.RFC3339, "2026-01-02T03:04:05+09:00")
+	p.now = func() time.Time { return sentAt.Add(time.Minute) }
+	body := []byte(`{"eventType":"PAYMENT_STATUS_CHANGED","createdAt":"2026-01-02T03:04:05+09:00","data":{"paymentKey":"pk_1","orderId":"angple-inv-7","status":"DONE"}}`)
+
+	header := http.Header{}
+	header.Set(tossTimeHeader, "2026-01-02T03:04:05+09:00")
+	header.Set(tossSignatureHeader, "v1:old,"+SignWebhook("secret", "2026-01-02T03:04:05+09:00", body))
+
+	event, err := p.ParseWebhook(header, body)
+	if err != nil {
+		t.Fatalf("ParseWebhook: %v", err)
+	}
+	if event.Type != EventPaymentSucceeded || event.OrderID != "angple-inv-7" || event.PaymentKey != "pk_1" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	header.Set(tossSignatureHeader, SignWebhook("wrong", "2026-01-02T03:04:05+09:00", body))
+	if _, err := p.ParseWebhook(header, body); err != ErrInvalidSignature {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestTossProvider_ParseWebhookRejectsReplay(t *testing.T) {
+	p := NewTossProvider(TossConfig{WebhookSecret: "secret"})
+	body := []byte(`{"eventType":"PAYMENT_STATUS_CHANGED","data":{"paymentKey":"pk_1","orderId":"angple-inv-7","status":"DONE"}}`)
+	sent := "2026-01-02T03:04:05+09:00"
+	sentAt, _ := time.Parse(time.RFC3339, sent)
+
+	header := http.Header{}
+	header.Set(tossTimeHeader, sent)
+	header.Set(tossSignatureHeader, SignWebhook("secret", sent, body))
+
+	p.now = func() time.Time { return sentAt.Add(time.Hour) }
+	if _, err := p.ParseWebhook(header, body); err != ErrInvalidSignature {
+		t.Errorf("stale webhook: expected ErrInvalidSignature, got %v", err)
+	}
+	p.now = func() time.Time { return sentAt.Add(-time.Hour) }
+	if _, err := p.ParseWebhook(header, body); err != ErrInvalidSignature {
+		t.Errorf("future webhook: expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestTossProvider_ChargeErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   int
+		body     string
+		declined bool
+	}{
+		{"documented decline", http.StatusBadRequest, `{"code":"REJECT_CARD_PAYMENT","message":"한도 초과"}`, true},
+		{"auth failure", http.StatusUnauthorized, `{"code":"UNAUTHORIZED_KEY","message":"bad key"}`, false},
+		{"unknown 4xx code", http.StatusBadRequest, `{"code":"INVALID_REQUEST","message":"bad"}`, false},
+		{"server error", http.StatusInternalServerError, `{"code":"FAILED_INTERNAL_SYSTEM_PROCESSING"}`, false},
+	}
+	for _, tc := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tc.status)
+			_, _ = w.Write([]byte(tc.body)) //nolint:errcheck // test server
+		}))
+		p := NewTossProvider(TossConfig{SecretKey: "sk", APIBase: srv.URL})
+		_, err := p.Charge(context.Background(), &ChargeRequest{BillingKey: "bk", OrderID: "o1", AmountKRW: 1000})
+		srv.Close()
+
+		var declined *ChargeError
+		var gateway *GatewayError
+		if tc.declined && !errors.As(err, &declined) {
+			t.Errorf("%s: expected ChargeError, got %v", tc.name, err)
+		}
+		if !tc.declined && !errors.As(err, &gateway) {
+			t.Errorf("%s: expected GatewayError, got %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCapabilityDenied 플러그인이 승인되지 않은 리소스에 접근
+var ErrCapabilityDenied = errors.New("plugin capability denied")
+
+// 위반 종류
+const (
+	CapabilityDB   = "db"
+	CapabilityHTTP = "http"
+	CapabilityHook = "hook"
+)
+
+// capabilityViolationInterval 같은 위반을 다시 기록하기까지의 최소 간격
+const capabilityViolationInterval = time.Minute
+
+// Capabilities 플러그인이 사용할 리소스 선언 (plugin.yaml capabilities, 설치 시 관리자 승인)
+// 테이블/호스트/Hook 이름에는 "shop_*", "*.example.com" 같은 와일드카드를 쓸 수 있다.
+type Capabilities struct {
+	Tables      TableCapabilities `yaml:"tables" json:"tables"`
+	RedisPrefix string            `yaml:"redis_prefix" json:"redis_prefix"` // 비어 있으면 "plugin:<name>:"
+	HTTPHosts   []string          `yaml:"http_hosts" json:"http_hosts"`
+	Hooks       []string          `yaml:"hooks" json:"hooks"`
+}
+
+// TableCapabilities 읽기/쓰기 허용 테이블 (쓰기 허용은 읽기도 포함)
+type TableCapabilities struct {
+	Read  []string `yaml:"read" json:"read"`
+	Write []string `yaml:"write" json:"write"`
+}
+
+// CanRead 테이블 읽기 허용 여부
+func (c *Capabilities) CanRead(table string) bool {
+	return matchAny(c.Tables.Read, normalizeTable(table)) || c.CanWrite(table)
+}
+
+// CanWrite 테이블 쓰기 허용 여부
+func (c *Capabilities) CanWrite(table string) bool {
+	return matchAny(c.Tables.Write, normalizeTable(table))
+}
+
+// AllowsHost 외부 HTTP 호스트 허용 여부
+func (c *Capabilities) AllowsHost(host string) bool {
+	return matchAny(c.HTTPHosts, strings.ToLower(host))
+}
+
+// AllowsHook Hook 이벤트 등록 허용 여부
+func (c *Capabilities) AllowsHook(event string) bool {
+	return matchAny(c.Hooks, event)
+}
+
+// KeyPrefix Redis 키 접두사
+func (c *Capabilities) KeyPrefix(pluginName string) string {
+	if c.RedisPrefix != "" {
+		return c.RedisPrefix
+	}
+	return "plugin:" + pluginName + ":"
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, err := path.Match(strings.ToLower(p), strings.ToLower(name)); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTable 따옴표/스키마 접두사 제거 후 소문자
+func normalizeTable(table string) string {
+	table = strings.Trim(table, "`\"[] ")
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		table = strings.Trim(table[i+1:], "`\"[]")
+	}
+	return strings.ToLower(table)
+}
+
+// CapabilityViolation 승인되지 않은 리소스 접근 시도
+type CapabilityViolation struct {
+	Plugin string `json:"plugin"`
+	Kind   string `json:"kind"`   // db, http, hook
+	Target string `json:"target"` // 테이블, 호스트, Hook 이벤트
+	Detail string `json:"detail"`
+}
+
+func (v CapabilityViolation) Error() string {
+	return fmt.Sprintf("%s: plugin %s may not access %s %s", ErrCapabilityDenied, v.Plugin, v.Kind, v.Target)
+}
+
+// Unwrap errors.Is(err, ErrCapabilityDenied) 지원
+func (v CapabilityViolation) Unwrap() error {
+	return ErrCapabilityDenied
+}
+
+// CapabilityProvider 관리자가 승인한 capabilities 조회 (순환 의존 방지)
+type CapabilityProvider interface {
+	ApprovedCapabilities(pluginName string) (*Capabilities, bool)
+}
+
+// ViolationRecorder capability 위반 기록 (plugin_events)
+type ViolationRecorder interface {
+	RecordViolation(v CapabilityViolation)
+}
+
+// violationLog 위반을 로그에 남기고 같은 위반은 capabilityViolationInterval마다 한 번만 기록
+type violationLog struct {
+	logger   Logger
+	recorder ViolationRecorder
+	mu       sync.Mutex
+	last     map[string]time.Time
+}
+
+func newViolationLog(logger Logger) *violationLog {
+	return &violationLog{logger: logger, last: make(map[string]time.Time)}
+}
+
+func (l *violationLog) setRecorder(r ViolationRecorder) {
+	l.mu.Lock()
+	l.recorder = r
+	l.mu.Unlock()
+}
+
+func (l *violationLog) report(v CapabilityViolation) {
+	key := v.Plugin + "\x00" + v.Kind + "\x00" + v.Target
+	l.mu.Lock()
+	if t, ok := l.last[key]; ok && time.Since(t) < capabilityViolationInterval {
+		l.mu.Unlock()
+		return
+	}
+	l.last[key] = time.Now()
+	recorder := l.recorder
+	l.mu.Unlock()
+
+	l.logger.Warn("Capability violation: plugin %s %s %s (%s)", v.Plugin, v.Kind, v.Target, v.Detail)
+	if recorder != nil {
+		recorder.RecordViolation(v)
+	}
+}
+
+// scopedTransport capabilities.http_hosts에 없는 호스트로의 요청을 차단
+type scopedTransport struct {
+	plugin string
+	caps   *Capabilities
+	base   http.RoundTripper
+	report func(CapabilityViolation)
+}
+
+func (t *scopedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if !t.caps.AllowsHost(host) {
+		v := CapabilityViolation{Plugin: t.plugin, Kind: CapabilityHTTP, Target: host, Detail: req.Method + " " + req.URL.Redacted()}
+		t.report(v)
+		return nil, v
+	}
+	return t.base.RoundTrip(req)
+}
+
+// newScopedHTTPClient 허용 호스트로만 요청하는 HTTP 클라이언트 (리다이렉트도 검사됨)
+func newScopedHTTPClient(pluginName string, caps *Capabilities, report func(CapabilityViolation)) *http.Client {
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &scopedTransport{plugin: pluginName, caps: caps, base: http.DefaultTransport, report: report},
+	}
+}
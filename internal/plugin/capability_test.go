@@ -0,0 +1,182 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSQLTables(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []tableAccess
+	}{
+		{"SELECT * FROM g5_member WHERE mb_id = 'x'", []tableAccess{{"g5_member", false}}},
+		{"select a.* from `shop_items` a join g5_member m on m.id = a.mb_id", []tableAccess{{"shop_items", false}, {"g5_member", false}}},
+		{"SELECT * FROM shop_items i, shop_orders AS o", []tableAccess{{"shop_items", false}, {"shop_orders", false}}},
+		{"INSERT INTO shop_log (msg) SELECT name FROM shop_items", []tableAccess{{"shop_log", true}, {"shop_items", false}}},
+		{"UPDATE g5_member SET mb_level = 10", []tableAccess{{"g5_member", true}}},
+		{"DELETE FROM angple.g5_member", []tableAccess{{"g5_member", true}}},
+		{"SELECT * FROM shop_items FOR UPDATE", []tableAccess{{"shop_items", false}}},
+		{"DROP TABLE IF EXISTS g5_config", []tableAccess{{"g5_config", true}}},
+		{"SELECT 'from g5_member' -- from g5_member\nFROM shop_items", []tableAccess{{"shop_items", false}}},
+	}
+	for _, tt := range tests {
+		if got := sqlTables(tt.sql); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sqlTables(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+type shopItem struct {
+	ID   int64
+	Name string
+}
+
+type member struct {
+	ID       int64
+	Password string
+}
+
+func (member) TableName() string { return "g5_member" }
+
+func TestScopedDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&shopItem{}, &member{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var violations []CapabilityViolation
+	caps := &Capabilities{Tables: TableCapabilities{Write: []string{"shop_*"}}}
+	scoped, err := newScopedDB(db, &dbScope{plugin: "shop", caps: caps, report: func(v CapabilityViolation) {
+		violations = append(violations, v)
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scoped.Create(&shopItem{Name: "a"}).Error; err != nil {
+		t.Fatalf("expected write to allowed table, got %v", err)
+	}
+	var items []shopItem
+	if err := scoped.WithContext(context.Background()).Where("name = ?", "a").Find(&items).Error; err != nil || len(items) != 1 {
+		t.Fatalf("expected read from allowed table, got %v (%d rows)", err, len(items))
+	}
+
+	var members []member
+	if err := scoped.Find(&members).Error; !errors.Is(err, ErrCapabilityDenied) {
+		t.Errorf("expected model read to be denied, got %v", err)
+	}
+	if err := scoped.Exec("DELETE FROM g5_member").Error; !errors.Is(err, ErrCapabilityDenied) {
+		t.Errorf("expected raw delete to be denied, got %v", err)
+	}
+	err = scoped.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		return tx.Table("g5_member").Count(&count).Error
+	})
+	if !errors.Is(err, ErrCapabilityDenied) {
+		t.Errorf("expected scope to carry into transactions, got %v", err)
+	}
+	if len(violations) != 3 || violations[0].Target != "g5_member" || violations[1].Detail != "write" {
+		t.Errorf("unexpected violations: %+v", violations)
+	}
+
+	// 빌더 절 안의 서브쿼리도 실행되는 SQL 기준으로 검사
+	var leaked []string
+	if err := scoped.Table("shop_items").Select("(SELECT password FROM g5_member LIMIT 1)").Scan(&leaked).Error; !errors.Is(err, ErrCapabilityDenied) {
+		t.Errorf("expected subquery in Select to be denied, got %v (%v)", err, leaked)
+	}
+	if err := scoped.Where("id IN (SELECT id FROM g5_member)").Find(&items).Error; !errors.Is(err, ErrCapabilityDenied) {
+		t.Errorf("expected subquery in Where to be denied, got %v", err)
+	}
+	if err := scoped.Order("(SELECT password FROM g5_member LIMIT 1)").Find(&items).Error; !errors.Is(err, ErrCapabilityDenied) {
+		t.Errorf("expected subquery in Order to be denied, got %v", err)
+	}
+	if err := scoped.Model(&shopItem{}).Where("1 = 1").Update("name", gorm.Expr("(SELECT password FROM g5_member LIMIT 1)")).Error; !errors.Is(err, ErrCapabilityDenied) {
+		t.Errorf("expected subquery in Update to be denied, got %v", err)
+	}
+
+	if err := db.Find(&members).Error; err != nil {
+		t.Errorf("unscoped DB must not be restricted, got %v", err)
+	}
+}
+
+func TestScopedHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	var denied []string
+	report := func(v CapabilityViolation) { denied = append(denied, v.Target) }
+	client := newScopedHTTPClient("shop", &Capabilities{HTTPHosts: []string{"127.0.0.1"}}, report)
+
+	resp, err := client.Get(srv.URL) //nolint:noctx // test
+	if err != nil {
+		t.Fatalf("expected allowed host, got %v", err)
+	}
+	resp.Body.Close()
+
+	_, err = client.Get("http://169.254.169.254/latest/meta-data") //nolint:noctx,bodyclose // denied before sending
+	if !errors.Is(err, ErrCapabilityDenied) || len(denied) != 1 || denied[0] != "169.254.169.254" {
+		t.Errorf("expected metadata host to be denied, got %v %v", err, denied)
+	}
+}
+
+func TestManager_CapabilityScopedContext(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager("", db, nil, NewDefaultLogger("test"), nil, nil)
+	p := &capabilityPlugin{}
+	manifest := &PluginManifest{
+		Name: "shop", Version: "1.0.0", Title: "Shop",
+		Capabilities: &Capabilities{Tables: TableCapabilities{Read: []string{"shop_items"}}, Hooks: []string{"post.created"}},
+	}
+	if err := m.RegisterBuiltIn("shop", p, manifest); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Enable("shop"); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.ctx.Capabilities == nil || p.ctx.DB == db {
+		t.Fatal("expected a scoped DB for a plugin with declared capabilities")
+	}
+	m.GetHookManager().Do("post.created", nil)
+	m.GetHookManager().Do("member.login", nil)
+	if !p.fired["post.created"] {
+		t.Error("expected allowed hook to be registered")
+	}
+	if p.fired["member.login"] {
+		t.Error("expected hook outside capabilities to be denied")
+	}
+}
+
+type capabilityPlugin struct {
+	ctx   *PluginContext
+	fired map[string]bool
+}
+
+func (p *capabilityPlugin) Name() string                        { return "shop" }
+func (p *capabilityPlugin) Migrate(_ *gorm.DB) error            { return nil }
+func (p *capabilityPlugin) Initialize(ctx *PluginContext) error { p.ctx = ctx; return nil }
+func (p *capabilityPlugin) RegisterRoutes(_ gin.IRouter)        {}
+func (p *capabilityPlugin) Shutdown() error                     { return nil }
+func (p *capabilityPlugin) RegisterHooks(hm *HookManager) {
+	p.fired = make(map[string]bool)
+	mark := func(ctx *HookContext) error { p.fired[ctx.Event] = true; return nil }
+	hm.Register("post.created", "shop", mark, 10)
+	hm.Register("member.login", "shop", mark, 10)
+}
@@ -0,0 +1,210 @@
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultScheduleTimezone 스케줄 기본 타임존
+const DefaultScheduleTimezone = "Asia/Seoul"
+
+// kst Asia/Seoul (tzdata가 없는 컨테이너용 폴백)
+var kst = time.FixedZone("KST", 9*60*60)
+
+// LoadScheduleLocation 타임존 이름을 Location으로 변환 (빈 값이면 Asia/Seoul)
+func LoadScheduleLocation(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultScheduleTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		if name == DefaultScheduleTimezone {
+			return kst, nil
+		}
+		return nil, fmt.Errorf("unknown timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// cronMacros 축약 표현식
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField 필드별 허용 범위
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0과 7 모두 일요일
+}
+
+// CronSchedule 5필드 cron 표현식 (분 시 일 월 요일)
+// *, 목록(1,15), 범위(1-5), 간격(*/10, 0-30/5)과 @daily 같은 축약형을 지원한다.
+type CronSchedule struct {
+	expr     string
+	minute   uint64
+	hour     uint64
+	dom      uint64
+	month    uint64
+	dow      uint64
+	domStar  bool
+	dowStar  bool
+	location *time.Location
+}
+
+// ParseCron cron 표현식 파싱. loc가 nil이면 Asia/Seoul 기준으로 계산한다
+func ParseCron(expr string, loc *time.Location) (*CronSchedule, error) {
+	if loc == nil {
+		var err error
+		if loc, err = LoadScheduleLocation(""); err != nil {
+			return nil, err
+		}
+	}
+
+	spec := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[spec]; ok {
+		spec = macro
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", expr, len(parts))
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		b, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", expr, err)
+		}
+		bits[i] = b
+	}
+
+	// 요일 7 → 0 (일요일)
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = (bits[4] | 1) &^ (1 << 7)
+	}
+
+	sched := &CronSchedule{
+		expr:     strings.TrimSpace(expr),
+		minute:   bits[0],
+		hour:     bits[1],
+		dom:      bits[2],
+		month:    bits[3],
+		dow:      bits[4],
+		domStar:  parts[2] == "*" || parts[2] == "?",
+		dowStar:  parts[4] == "*" || parts[4] == "?",
+		location: loc,
+	}
+	// 2월 31일처럼 검색 범위(5년) 안에 실행 시각이 없는 표현식은 거부 (Next가 zero time을 반환해 매 틱 실행됨)
+	if sched.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron %q: schedule never runs", expr)
+	}
+	return sched, nil
+}
+
+// parseCronField 한 필드를 비트셋으로 변환
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field: %q", f.name, item)
+			}
+			rangePart, step = item[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range in %s field: %q", f.name, item)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value in %s field: %q", f.name, item)
+			}
+			lo, hi = n, n
+			if strings.Contains(item, "/") {
+				hi = f.max
+			}
+		}
+
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("%s field out of range [%d-%d]: %q", f.name, f.min, f.max, item)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// String 원본 표현식
+func (c *CronSchedule) String() string {
+	return c.expr
+}
+
+// Location 계산 기준 타임존
+func (c *CronSchedule) Location() *time.Location {
+	return c.location
+}
+
+// Next after 이후 첫 실행 시각 (분 단위, 5년 내 일치가 없으면 zero time)
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	t := after.In(c.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.location)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.location)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.location)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 일/요일 일치 여부 (둘 다 지정되면 표준 cron처럼 OR)
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
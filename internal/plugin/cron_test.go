@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	loc := time.FixedZone("KST", 9*60*60)
+	base := time.Date(2026, 3, 10, 10, 7, 30, 0, loc) // 화요일
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 10, 10, 8, 0, 0, loc)},
+		{"*/15 * * * *", time.Date(2026, 3, 10, 10, 15, 0, 0, loc)},
+		{"0 4 * * *", time.Date(2026, 3, 11, 4, 0, 0, 0, loc)},
+		{"30 9 * * 1-5", time.Date(2026, 3, 11, 9, 30, 0, 0, loc)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, loc)},
+		{"0 12 * * 7", time.Date(2026, 3, 15, 12, 0, 0, 0, loc)},
+		{"@hourly", time.Date(2026, 3, 10, 11, 0, 0, 0, loc)},
+		// 일/요일을 모두 지정하면 OR
+		{"0 0 13 * 5", time.Date(2026, 3, 13, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr, loc)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := s.Next(base); !got.Equal(tt.want) {
+			t.Errorf("%q: expected %s, got %s", tt.expr, tt.want, got)
+		}
+	}
+}
+
+func TestParseCron_DefaultTimezone(t *testing.T) {
+	s, err := ParseCron("0 4 * * *", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := s.Next(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)) // KST 09:00
+	if next.UTC().Hour() != 19 {
+		t.Errorf("expected 04:00 KST (19:00 UTC), got %s", next.UTC())
+	}
+}
+
+func TestParseCron_LeapDay(t *testing.T) {
+	// 2월 29일은 4년에 한 번이지만 유효한 일정
+	if _, err := ParseCron("0 0 29 2 *", nil); err != nil {
+		t.Errorf("expected leap day schedule to parse, got %v", err)
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "0 0 31 2 *", "0 0 30 2 *", "0 0 31 4,6,9,11 *"} {
+		if _, err := ParseCron(expr, nil); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
@@ -1,12 +1,20 @@
 package plugin
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+// eventPublishTimeout 백엔드 발행 타임아웃
+const eventPublishTimeout = 5 * time.Second
+
 // Event 플러그인 간 이벤트
 type Event struct {
+	ID        string                 `json:"id"` // 멱등성 키 (같은 ID는 한 번만 발행/처리)
 	Topic     string                 `json:"topic"`
 	Source    string                 `json:"source"` // 발행 플러그인
 	Payload   map[string]interface{} `json:"payload"`
@@ -16,14 +24,45 @@ type Event struct {
 // EventHandler 이벤트 핸들러 함수
 type EventHandler func(event Event)
 
+// ReliableEventHandler 에러를 반환하면 백엔드가 재시도하는 핸들러
+type ReliableEventHandler func(event Event) error
+
+// DeliverFunc 백엔드가 수신한 이벤트를 구독 플러그인 핸들러에 전달
+type DeliverFunc func(pluginName string, event Event) error
+
+// EventBackend 이벤트 전달 백엔드 (미설정 시 프로세스 내 동기 전달)
+type EventBackend interface {
+	// Name 백엔드 이름 (memory, redis)
+	Name() string
+	// Start 수신 이벤트를 deliver로 전달하기 시작
+	Start(deliver DeliverFunc)
+	// Stop 소비 중지
+	Stop()
+	Publish(ctx context.Context, event Event) error
+	Subscribe(pluginName, topic string)
+	Unsubscribe(pluginName string)
+	Stats(ctx context.Context) ([]SubscriberStats, error)
+}
+
+// SubscriberStats 구독자(플러그인/토픽)별 전달 현황
+type SubscriberStats struct {
+	Plugin      string `json:"plugin"`
+	Topic       string `json:"topic"`
+	Backend     string `json:"backend"`
+	Pending     int64  `json:"pending"`      // 전달됐지만 아직 ACK되지 않은 이벤트
+	Lag         int64  `json:"lag"`          // 아직 전달되지 않은 이벤트
+	DeadLetters int64  `json:"dead_letters"` // 재시도 초과로 dead-letter로 이동한 이벤트
+}
+
 type subscription struct {
 	pluginName string
-	handler    EventHandler
+	handler    ReliableEventHandler
 }
 
 // EventBus 플러그인 간 이벤트 발행/구독 시스템
 type EventBus struct {
 	subscribers map[string][]subscription // topic -> handlers
+	backend     EventBackend
 	mu          sync.RWMutex
 	logger      Logger
 }
@@ -36,14 +75,61 @@ func NewEventBus(logger Logger) *EventBus {
 	}
 }
 
+// SetBackend 이벤트 백엔드 설정 (기존 구독도 백엔드에 등록)
+func (eb *EventBus) SetBackend(backend EventBackend) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	if eb.backend != nil {
+		eb.backend.Stop()
+	}
+	eb.backend = backend
+	backend.Start(eb.deliver)
+	for topic, subs := range eb.subscribers {
+		for _, s := range subs {
+			backend.Subscribe(s.pluginName, topic)
+		}
+	}
+}
+
+// BackendName 현재 백엔드 이름
+func (eb *EventBus) BackendName() string {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+	if eb.backend == nil {
+		return "memory"
+	}
+	return eb.backend.Name()
+}
+
+// Close 백엔드 소비 중지
+func (eb *EventBus) Close() {
+	eb.mu.RLock()
+	backend := eb.backend
+	eb.mu.RUnlock()
+	if backend != nil {
+		backend.Stop()
+	}
+}
+
 // Subscribe 토픽 구독
 func (eb *EventBus) Subscribe(pluginName, topic string, handler EventHandler) {
+	eb.SubscribeReliable(pluginName, topic, func(event Event) error {
+		handler(event)
+		return nil
+	})
+}
+
+// SubscribeReliable 토픽 구독 (에러 반환 시 백엔드가 재시도)
+func (eb *EventBus) SubscribeReliable(pluginName, topic string, handler ReliableEventHandler) {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 	eb.subscribers[topic] = append(eb.subscribers[topic], subscription{
 		pluginName: pluginName,
 		handler:    handler,
 	})
+	if eb.backend != nil {
+		eb.backend.Subscribe(pluginName, topic)
+	}
 	eb.logger.Debug("Plugin %s subscribed to topic: %s", pluginName, topic)
 }
 
@@ -64,36 +150,45 @@ func (eb *EventBus) Unsubscribe(pluginName string) {
 			eb.subscribers[topic] = remaining
 		}
 	}
+	if eb.backend != nil {
+		eb.backend.Unsubscribe(pluginName)
+	}
 }
 
-// Publish 이벤트 발행 (동기: 모든 핸들러 순차 실행)
+// Publish 이벤트 발행
+// 백엔드가 없으면 모든 핸들러를 동기로 순차 실행하고, 있으면 백엔드에 적재한다.
 func (eb *EventBus) Publish(source, topic string, payload map[string]interface{}) {
-	eb.mu.RLock()
-	subs := make([]subscription, len(eb.subscribers[topic]))
-	copy(subs, eb.subscribers[topic])
-	eb.mu.RUnlock()
+	if err := eb.PublishEvent(Event{Topic: topic, Source: source, Payload: payload}); err != nil {
+		eb.logger.Error("Event publish failed [%s/%s]: %v", source, topic, err)
+	}
+}
 
-	if len(subs) == 0 {
-		return
+// PublishEvent ID(멱등성 키)를 지정해 이벤트 발행. ID가 비어 있으면 새로 생성한다.
+// 백엔드 적재에 실패하면 이 인스턴스의 구독자에게만 동기로 전달하고 에러를 반환한다.
+func (eb *EventBus) PublishEvent(event Event) error {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
 	}
 
-	event := Event{
-		Topic:     topic,
-		Source:    source,
-		Payload:   payload,
-		Timestamp: time.Now(),
+	eb.mu.RLock()
+	backend := eb.backend
+	eb.mu.RUnlock()
+
+	if backend == nil {
+		eb.dispatchLocal(event)
+		return nil
 	}
 
-	for _, s := range subs {
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					eb.logger.Error("Event handler panicked [%s/%s → %s]: %v", source, topic, s.pluginName, r)
-				}
-			}()
-			s.handler(event)
-		}()
+	ctx, cancel := context.WithTimeout(context.Background(), eventPublishTimeout)
+	defer cancel()
+	if err := backend.Publish(ctx, event); err != nil {
+		eb.dispatchLocal(event)
+		return fmt.Errorf("event backend %s: %w", backend.Name(), err)
 	}
+	return nil
 }
 
 // PublishAsync 이벤트 비동기 발행
@@ -101,6 +196,49 @@ func (eb *EventBus) PublishAsync(source, topic string, payload map[string]interf
 	go eb.Publish(source, topic, payload)
 }
 
+// dispatchLocal 이 인스턴스의 모든 구독자에게 동기 전달 (에러는 로그만)
+func (eb *EventBus) dispatchLocal(event Event) {
+	for _, s := range eb.handlers(event.Topic, "") {
+		if err := eb.invoke(s, event); err != nil {
+			eb.logger.Error("Event handler failed [%s/%s → %s]: %v", event.Source, event.Topic, s.pluginName, err)
+		}
+	}
+}
+
+// deliver 백엔드에서 받은 이벤트를 한 플러그인의 핸들러에 전달 (DeliverFunc)
+func (eb *EventBus) deliver(pluginName string, event Event) error {
+	var firstErr error
+	for _, s := range eb.handlers(event.Topic, pluginName) {
+		if err := eb.invoke(s, event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// handlers 토픽 구독 목록 복사본 (pluginName이 있으면 해당 플러그인만)
+func (eb *EventBus) handlers(topic, pluginName string) []subscription {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+	var subs []subscription
+	for _, s := range eb.subscribers[topic] {
+		if pluginName == "" || s.pluginName == pluginName {
+			subs = append(subs, s)
+		}
+	}
+	return subs
+}
+
+// invoke 핸들러 실행 (panic은 에러로 변환)
+func (eb *EventBus) invoke(s subscription, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("event handler panicked: %v", r)
+		}
+	}()
+	return s.handler(event)
+}
+
 // GetSubscriptions 구독 현황 조회
 func (eb *EventBus) GetSubscriptions() map[string][]string {
 	eb.mu.RLock()
@@ -113,3 +251,26 @@ func (eb *EventBus) GetSubscriptions() map[string][]string {
 	}
 	return result
 }
+
+// GetStats 구독자별 lag/pending/dead-letter 현황 (인메모리는 항상 0)
+func (eb *EventBus) GetStats(ctx context.Context) ([]SubscriberStats, error) {
+	eb.mu.RLock()
+	backend := eb.backend
+	eb.mu.RUnlock()
+	if backend != nil {
+		return backend.Stats(ctx)
+	}
+
+	var stats []SubscriberStats
+	seen := make(map[string]bool)
+	for topic, plugins := range eb.GetSubscriptions() {
+		for _, name := range plugins {
+			if seen[name+"\x00"+topic] {
+				continue
+			}
+			seen[name+"\x00"+topic] = true
+			stats = append(stats, SubscriberStats{Plugin: name, Topic: topic, Backend: "memory"})
+		}
+	}
+	return stats, nil
+}
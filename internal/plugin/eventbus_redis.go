@@ -0,0 +1,426 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis Streams 이벤트 백엔드 키
+//
+//	plugin:events:stream:<topic>            토픽 스트림 (플러그인마다 consumer group)
+//	plugin:events:dead:<plugin>:<topic>     dead-letter 스트림
+//	plugin:events:published:<id>            발행 멱등성 키
+//	plugin:events:done:<plugin>:<id>        처리 완료 멱등성 키
+//	plugin:events:errors:<plugin>:<topic>   미처리 메시지의 마지막 에러 (hash)
+const (
+	eventStreamPrefix    = "plugin:events:stream:"
+	eventDeadPrefix      = "plugin:events:dead:"
+	eventPublishedPrefix = "plugin:events:published:"
+	eventDonePrefix      = "plugin:events:done:"
+	eventErrorsPrefix    = "plugin:events:errors:"
+)
+
+// Redis 이벤트 백엔드 기본값
+const (
+	DefaultEventMaxAttempts    = 5
+	DefaultEventRetryBackoff   = 30 * time.Second
+	DefaultEventMaxBackoff     = 10 * time.Minute
+	DefaultEventStreamMaxLen   = 10000
+	DefaultEventIdempotencyTTL = 24 * time.Hour
+
+	eventReadBlock       = 2 * time.Second
+	eventReadCount       = 10
+	eventReclaimInterval = 5 * time.Second
+	eventReclaimBatch    = 100
+)
+
+type streamSub struct {
+	plugin string
+	topic  string
+}
+
+// RedisEventBackend Redis Streams + consumer group 기반 이벤트 백엔드
+// 구독(플러그인/토픽)마다 consumer group을 두어 클러스터 전체에서 at-least-once 전달을 보장한다.
+// 실패한 이벤트는 pending으로 남아 지수 백오프 후 (다른 인스턴스에서도) 재시도되고,
+// MaxAttempts를 넘으면 dead-letter 스트림으로 옮겨진다.
+type RedisEventBackend struct {
+	redis  *redis.Client
+	nodeID string
+	logger Logger
+
+	MaxAttempts    int64
+	RetryBackoff   time.Duration
+	MaxBackoff     time.Duration
+	StreamMaxLen   int64
+	IdempotencyTTL time.Duration
+
+	mu        sync.Mutex
+	deliver   DeliverFunc
+	consumers map[streamSub]context.CancelFunc
+	wg        sync.WaitGroup
+	started   bool
+}
+
+// NewRedisEventBackend 생성자 (nodeID는 consumer 이름으로 사용)
+func NewRedisEventBackend(redisClient *redis.Client, nodeID string, logger Logger) *RedisEventBackend {
+	return &RedisEventBackend{
+		redis:          redisClient,
+		nodeID:         nodeID,
+		logger:         logger,
+		MaxAttempts:    DefaultEventMaxAttempts,
+		RetryBackoff:   DefaultEventRetryBackoff,
+		MaxBackoff:     DefaultEventMaxBackoff,
+		StreamMaxLen:   DefaultEventStreamMaxLen,
+		IdempotencyTTL: DefaultEventIdempotencyTTL,
+		consumers:      make(map[streamSub]context.CancelFunc),
+	}
+}
+
+// Name 백엔드 이름
+func (b *RedisEventBackend) Name() string {
+	return "redis"
+}
+
+// Start 등록된 구독의 소비 시작
+func (b *RedisEventBackend) Start(deliver DeliverFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deliver = deliver
+	b.started = true
+	for sub, cancel := range b.consumers {
+		if cancel == nil {
+			b.consumers[sub] = b.spawn(sub)
+		}
+	}
+}
+
+// Stop 모든 consumer 중지 (진행 중인 전달이 끝날 때까지 대기)
+func (b *RedisEventBackend) Stop() {
+	b.mu.Lock()
+	b.started = false
+	for sub, cancel := range b.consumers {
+		if cancel != nil {
+			cancel()
+			b.consumers[sub] = nil
+		}
+	}
+	b.mu.Unlock()
+	b.wg.Wait()
+}
+
+// Subscribe 구독의 consumer group 소비 시작 (이미 있으면 무시)
+func (b *RedisEventBackend) Subscribe(pluginName, topic string) {
+	sub := streamSub{plugin: pluginName, topic: topic}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.consumers[sub]; ok {
+		return
+	}
+	b.consumers[sub] = nil
+	if b.started {
+		b.consumers[sub] = b.spawn(sub)
+	}
+}
+
+// Unsubscribe 플러그인의 consumer 중지 (consumer group과 pending 이벤트는 유지)
+func (b *RedisEventBackend) Unsubscribe(pluginName string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub, cancel := range b.consumers {
+		if sub.plugin != pluginName {
+			continue
+		}
+		if cancel != nil {
+			cancel()
+		}
+		delete(b.consumers, sub)
+	}
+}
+
+// Publish 토픽 스트림에 이벤트 추가 (같은 ID는 IdempotencyTTL 동안 한 번만 추가)
+func (b *RedisEventBackend) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	pubKey := eventPublishedPrefix + event.ID
+	ok, err := b.redis.SetNX(ctx, pubKey, 1, b.IdempotencyTTL).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		b.logger.Debug("Duplicate event %s on %s ignored", event.ID, event.Topic)
+		return nil
+	}
+	err = b.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: eventStreamPrefix + event.Topic,
+		MaxLen: b.StreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": string(data)},
+	}).Err()
+	if err != nil {
+		b.redis.Del(context.Background(), pubKey) //nolint:errcheck // 재발행 허용
+	}
+	return err
+}
+
+// Stats 구독자별 lag/pending/dead-letter 수
+func (b *RedisEventBackend) Stats(ctx context.Context) ([]SubscriberStats, error) {
+	b.mu.Lock()
+	subs := make([]streamSub, 0, len(b.consumers))
+	for sub := range b.consumers {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].topic != subs[j].topic {
+			return subs[i].topic < subs[j].topic
+		}
+		return subs[i].plugin < subs[j].plugin
+	})
+
+	stats := make([]SubscriberStats, 0, len(subs))
+	for _, sub := range subs {
+		s := SubscriberStats{Plugin: sub.plugin, Topic: sub.topic, Backend: b.Name()}
+		groups, err := b.redis.XInfoGroups(ctx, eventStreamPrefix+sub.topic).Result()
+		if err != nil && !isNoSuchKey(err) {
+			return nil, err
+		}
+		for _, g := range groups {
+			if g.Name == sub.plugin {
+				s.Pending = g.Pending
+				s.Lag = g.Lag
+			}
+		}
+		dead, err := b.redis.XLen(ctx, deadLetterKey(sub)).Result()
+		if err != nil {
+			return nil, err
+		}
+		s.DeadLetters = dead
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// spawn 구독 consumer goroutine 시작 (b.mu 보유 상태에서 호출)
+func (b *RedisEventBackend) spawn(sub streamSub) context.CancelFunc {
+	ctx, cancel := context.WithCancel(context.Background())
+	b.wg.Add(1)
+	go b.consume(ctx, sub, b.deliver)
+	return cancel
+}
+
+// consume XREADGROUP으로 새 이벤트를 받고, 주기적으로 실패한 이벤트를 재시도
+func (b *RedisEventBackend) consume(ctx context.Context, sub streamSub, deliver DeliverFunc) {
+	defer b.wg.Done()
+	stream := eventStreamPrefix + sub.topic
+
+	for ctx.Err() == nil {
+		if err := b.ensureGroup(ctx, sub); err == nil {
+			break
+		} else if ctx.Err() == nil {
+			b.logger.Warn("Event consumer group %s/%s: %v", sub.plugin, sub.topic, err)
+			sleepCtx(ctx, time.Second)
+		}
+	}
+
+	var lastReclaim time.Time
+	for ctx.Err() == nil {
+		if time.Since(lastReclaim) >= eventReclaimInterval {
+			b.reclaim(ctx, sub, deliver)
+			lastReclaim = time.Now()
+		}
+
+		res, err := b.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    sub.plugin,
+			Consumer: b.nodeID,
+			Streams:  []string{stream, ">"},
+			Count:    eventReadCount,
+			Block:    eventReadBlock,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				_ = b.ensureGroup(ctx, sub) //nolint:errcheck // 다음 루프에서 재시도
+				continue
+			}
+			b.logger.Warn("Event consumer %s/%s read failed: %v", sub.plugin, sub.topic, err)
+			sleepCtx(ctx, time.Second)
+			continue
+		}
+		for _, s := range res {
+			for _, msg := range s.Messages {
+				b.handle(ctx, sub, msg, deliver)
+			}
+		}
+	}
+}
+
+// ensureGroup 토픽 스트림에 플러그인 consumer group 생성 (구독 이후 이벤트부터 수신)
+func (b *RedisEventBackend) ensureGroup(ctx context.Context, sub streamSub) error {
+	err := b.redis.XGroupCreateMkStream(ctx, eventStreamPrefix+sub.topic, sub.plugin, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// handle 메시지 하나 전달. 성공하면 ACK, 실패하면 pending으로 남겨 재시도 대상이 된다.
+func (b *RedisEventBackend) handle(ctx context.Context, sub streamSub, msg redis.XMessage, deliver DeliverFunc) {
+	event, err := decodeStreamEvent(msg)
+	if err != nil {
+		b.deadLetter(ctx, sub, msg, 1, err.Error())
+		return
+	}
+
+	doneKey := eventDonePrefix + sub.plugin + ":" + event.ID
+	if n, err := b.redis.Exists(ctx, doneKey).Result(); err == nil && n > 0 {
+		b.ack(ctx, sub, msg.ID)
+		return
+	}
+
+	if err := deliver(sub.plugin, event); err != nil {
+		b.logger.Warn("Event %s delivery to %s failed: %v", event.ID, sub.plugin, err)
+		b.redis.HSet(ctx, errorsKey(sub), msg.ID, err.Error()) //nolint:errcheck // dead-letter 사유 기록용
+		return
+	}
+	b.redis.Set(ctx, doneKey, 1, b.IdempotencyTTL) //nolint:errcheck // 재전달 시 핸들러가 한 번 더 실행될 뿐
+	b.ack(ctx, sub, msg.ID)
+}
+
+// reclaim 백오프가 지난 pending 이벤트를 가져와 재시도하고, 시도 횟수를 넘으면 dead-letter로 이동
+// 다른 인스턴스(종료된 노드 포함)의 pending 이벤트도 가져온다.
+func (b *RedisEventBackend) reclaim(ctx context.Context, sub streamSub, deliver DeliverFunc) {
+	stream := eventStreamPrefix + sub.topic
+	pending, err := b.redis.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  sub.plugin,
+		Start:  "-",
+		End:    "+",
+		Count:  eventReclaimBatch,
+	}).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) && ctx.Err() == nil {
+			b.logger.Warn("Event consumer %s/%s pending scan failed: %v", sub.plugin, sub.topic, err)
+		}
+		return
+	}
+
+	for _, p := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		minIdle := b.backoff(p.RetryCount)
+		if p.Idle < minIdle {
+			continue
+		}
+		// XCLAIM은 idle 조건을 원자적으로 검사하므로 여러 인스턴스가 동시에 가져가지 않는다
+		msgs, err := b.redis.XClaim(ctx, &redis.XClaimArgs{
+			Stream:   stream,
+			Group:    sub.plugin,
+			Consumer: b.nodeID,
+			MinIdle:  minIdle,
+			Messages: []string{p.ID},
+		}).Result()
+		if err != nil || len(msgs) == 0 {
+			continue
+		}
+		if p.RetryCount >= b.MaxAttempts {
+			reason, _ := b.redis.HGet(ctx, errorsKey(sub), p.ID).Result() //nolint:errcheck // 사유 없음 허용
+			b.deadLetter(ctx, sub, msgs[0], p.RetryCount, reason)
+			continue
+		}
+		b.handle(ctx, sub, msgs[0], deliver)
+	}
+}
+
+// deadLetter 메시지를 dead-letter 스트림으로 옮기고 ACK
+func (b *RedisEventBackend) deadLetter(ctx context.Context, sub streamSub, msg redis.XMessage, attempts int64, reason string) {
+	raw, _ := msg.Values["event"].(string) //nolint:errcheck // 손상된 메시지도 그대로 보관
+	err := b.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: deadLetterKey(sub),
+		MaxLen: b.StreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"message_id": msg.ID,
+			"event":      raw,
+			"error":      reason,
+			"attempts":   strconv.FormatInt(attempts, 10),
+			"failed_at":  time.Now().UTC().Format(time.RFC3339),
+		},
+	}).Err()
+	if err != nil {
+		b.logger.Error("Event %s dead-letter for %s failed: %v", msg.ID, sub.plugin, err)
+		return
+	}
+	b.logger.Error("Event %s on %s moved to dead-letter for %s after %d attempts: %s", msg.ID, sub.topic, sub.plugin, attempts, reason)
+	b.ack(ctx, sub, msg.ID)
+}
+
+func (b *RedisEventBackend) ack(ctx context.Context, sub streamSub, id string) {
+	if err := b.redis.XAck(ctx, eventStreamPrefix+sub.topic, sub.plugin, id).Err(); err != nil {
+		b.logger.Warn("Event %s ack for %s failed: %v", id, sub.plugin, err)
+		return
+	}
+	b.redis.HDel(ctx, errorsKey(sub), id) //nolint:errcheck // 정리 실패는 무해
+}
+
+// backoff attempts번 전달된 이벤트를 다시 시도하기 전 대기 시간 (RetryBackoff * 2^(attempts-1), 최대 MaxBackoff)
+func (b *RedisEventBackend) backoff(attempts int64) time.Duration {
+	d := b.RetryBackoff
+	for i := int64(1); i < attempts; i++ {
+		d *= 2
+		if d >= b.MaxBackoff {
+			return b.MaxBackoff
+		}
+	}
+	return d
+}
+
+func decodeStreamEvent(msg redis.XMessage) (Event, error) {
+	var event Event
+	raw, ok := msg.Values["event"].(string)
+	if !ok {
+		return event, fmt.Errorf("message %s has no event field", msg.ID)
+	}
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		return event, fmt.Errorf("message %s: %w", msg.ID, err)
+	}
+	if event.ID == "" {
+		event.ID = msg.ID
+	}
+	return event, nil
+}
+
+func deadLetterKey(sub streamSub) string {
+	return eventDeadPrefix + sub.plugin + ":" + sub.topic
+}
+
+func errorsKey(sub streamSub) string {
+	return eventErrorsPrefix + sub.plugin + ":" + sub.topic
+}
+
+func isNoSuchKey(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "no such key")
+}
+
+// sleepCtx ctx가 취소되면 즉시 반환하는 sleep
+func sleepCtx(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
@@ -1,6 +1,8 @@
 package plugin
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -137,3 +139,97 @@ func TestEventBus_PublishAsync(t *testing.T) {
 		t.Fatal("expected async handler to be called")
 	}
 }
+
+// fakeEventBackend 발행 이벤트를 보관하고 테스트가 직접 deliver를 호출하는 백엔드
+type fakeEventBackend struct {
+	deliver   DeliverFunc
+	published []Event
+	subs      map[string][]string
+}
+
+func (f *fakeEventBackend) Name() string              { return "fake" }
+func (f *fakeEventBackend) Start(deliver DeliverFunc) { f.deliver = deliver }
+func (f *fakeEventBackend) Stop()                     {}
+func (f *fakeEventBackend) Publish(_ context.Context, e Event) error {
+	f.published = append(f.published, e)
+	return nil
+}
+func (f *fakeEventBackend) Subscribe(pluginName, topic string) {
+	f.subs[pluginName] = append(f.subs[pluginName], topic)
+}
+func (f *fakeEventBackend) Unsubscribe(pluginName string) { delete(f.subs, pluginName) }
+func (f *fakeEventBackend) Stats(context.Context) ([]SubscriberStats, error) {
+	return nil, nil
+}
+
+func TestEventBus_Backend(t *testing.T) {
+	eb := NewEventBus(NewDefaultLogger("test"))
+	var calls []string
+	eb.Subscribe("plugin-a", "order.paid", func(_ Event) { calls = append(calls, "a") })
+
+	backend := &fakeEventBackend{subs: make(map[string][]string)}
+	eb.SetBackend(backend)
+	if len(backend.subs["plugin-a"]) != 1 {
+		t.Fatalf("expected existing subscription to be registered, got %v", backend.subs)
+	}
+	eb.SubscribeReliable("plugin-b", "order.paid", func(_ Event) error { return errors.New("downstream unavailable") })
+	eb.Subscribe("plugin-c", "order.paid", func(_ Event) { panic("boom") })
+
+	if err := eb.PublishEvent(Event{ID: "order-1", Topic: "order.paid", Source: "commerce"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 0 {
+		t.Fatal("expected handlers to run only when the backend delivers")
+	}
+	if len(backend.published) != 1 || backend.published[0].ID != "order-1" || backend.published[0].Timestamp.IsZero() {
+		t.Fatalf("unexpected published events: %+v", backend.published)
+	}
+
+	event := backend.published[0]
+	if err := backend.deliver("plugin-a", event); err != nil || len(calls) != 1 {
+		t.Fatalf("expected plugin-a to handle event, err=%v calls=%v", err, calls)
+	}
+	if err := backend.deliver("plugin-b", event); err == nil {
+		t.Error("expected handler error to be returned for retry")
+	}
+	if err := backend.deliver("plugin-c", event); err == nil {
+		t.Error("expected panic to be returned as an error")
+	}
+	if len(calls) != 1 {
+		t.Errorf("expected delivery to target only the named plugin, calls=%v", calls)
+	}
+
+	eb.Unsubscribe("plugin-b")
+	if _, ok := backend.subs["plugin-b"]; ok {
+		t.Error("expected unsubscribe to reach the backend")
+	}
+}
+
+func TestEventBus_PublishAssignsID(t *testing.T) {
+	eb := NewEventBus(NewDefaultLogger("test"))
+	var received Event
+	eb.Subscribe("plugin-a", "topic", func(e Event) { received = e })
+
+	eb.Publish("source", "topic", nil)
+	if received.ID == "" {
+		t.Error("expected generated idempotency key")
+	}
+
+	stats, err := eb.GetStats(context.Background())
+	if err != nil || len(stats) != 1 || stats[0].Backend != "memory" || stats[0].Plugin != "plugin-a" {
+		t.Errorf("unexpected stats: %+v, %v", stats, err)
+	}
+}
+
+func TestRedisEventBackend_Backoff(t *testing.T) {
+	b := NewRedisEventBackend(nil, "node", NewDefaultLogger("test"))
+	b.RetryBackoff = time.Second
+	b.MaxBackoff = 10 * time.Second
+
+	want := []time.Duration{time.Second, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for attempts, d := range want {
+		if got := b.backoff(int64(attempts)); got != d {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, d)
+		}
+	}
+}
@@ -0,0 +1,488 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/damoang/angple-backend/pkg/pluginsdk"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultExternalTimeout = 10 * time.Second
+	externalShutdownWait   = 5 * time.Second
+	restartBackoffMin      = time.Second
+	restartBackoffMax      = time.Minute
+	// 이 시간 이상 정상 동작 후 종료되면 backoff를 초기화한다
+	restartStableAfter = time.Minute
+	maxProxyBodySize   = 10 << 20
+)
+
+// 외부 플러그인에 전달하지 않는 요청 헤더 (인증 정보는 user 필드로만 전달)
+var proxyDroppedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Connection":    true,
+}
+
+// ExternalPlugin plugin.yaml의 runtime.command를 서브프로세스로 실행하는 외부 플러그인
+// Plugin, HookAware, EventAware, RateLimitable, HealthCheckable을 구현하므로
+// 내장 플러그인과 같은 Manager 라이프사이클(Enable/Disable/헬스/메트릭)을 따른다.
+type ExternalPlugin struct {
+	manifest *PluginManifest
+	dir      string
+	command  string
+	args     []string
+	env      []string
+	timeout  time.Duration
+	logger   Logger
+	metrics  *Metrics
+	limiter  *RateLimiter
+	sandbox  SandboxConfig
+
+	mu       sync.RWMutex
+	config   map[string]interface{}
+	conn     *rpcConn
+	cmd      *exec.Cmd
+	exited   chan struct{}
+	stop     chan struct{}
+	running  bool
+	restarts int
+	failures int
+}
+
+// NewExternalPlugin 외부 플러그인 생성. 실행 파일은 플러그인 디렉토리 안에 있어야 한다
+func NewExternalPlugin(manifest *PluginManifest, dir string, logger Logger, metrics *Metrics, limiter *RateLimiter) (*ExternalPlugin, error) {
+	rt := manifest.Runtime
+	if rt == nil || rt.Command == "" {
+		return nil, fmt.Errorf("plugin %s has no runtime.command", manifest.Name)
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	command := filepath.Clean(filepath.Join(absDir, rt.Command))
+	if !strings.HasPrefix(command, absDir+string(filepath.Separator)) {
+		return nil, fmt.Errorf("plugin %s: runtime.command must be inside the plugin directory", manifest.Name)
+	}
+
+	timeout := defaultExternalTimeout
+	if rt.Timeout != "" {
+		if timeout, err = time.ParseDuration(rt.Timeout); err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("plugin %s: invalid runtime.timeout %q", manifest.Name, rt.Timeout)
+		}
+	}
+
+	// 호스트 환경변수(DB 비밀번호 등)는 상속하지 않는다
+	env := []string{
+		"PATH=" + os.Getenv("PATH"),
+		"ANGPLE_PLUGIN_NAME=" + manifest.Name,
+		"ANGPLE_PLUGIN_PROTOCOL=" + pluginsdk.ProtocolVersion,
+	}
+	for k, v := range rt.Env {
+		env = append(env, k+"="+v)
+	}
+
+	return &ExternalPlugin{
+		manifest: manifest,
+		dir:      absDir,
+		command:  command,
+		args:     rt.Args,
+		env:      env,
+		timeout:  timeout,
+		logger:   logger,
+		metrics:  metrics,
+		limiter:  limiter,
+		sandbox:  DefaultSandboxConfig(),
+	}, nil
+}
+
+// Name 플러그인 이름
+func (p *ExternalPlugin) Name() string {
+	return p.manifest.Name
+}
+
+// Migrate 파일 기반 마이그레이션은 Manager.RunMigrations가 처리
+func (p *ExternalPlugin) Migrate(_ *gorm.DB) error {
+	return nil
+}
+
+// Initialize 서브프로세스 시작 후 initialize 호출 (이후 비정상 종료 시 backoff로 재시작)
+func (p *ExternalPlugin) Initialize(ctx *PluginContext) error {
+	p.mu.Lock()
+	if p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.config = ctx.Config
+	p.stop = make(chan struct{})
+	p.running = true
+	p.failures = 0
+	p.mu.Unlock()
+
+	if err := p.spawn(); err != nil {
+		p.mu.Lock()
+		p.running = false
+		p.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// spawn 프로세스 실행 + initialize 핸드셰이크
+func (p *ExternalPlugin) spawn() error {
+	cmd := exec.Command(p.command, p.args...) // #nosec G204 -- command from plugin manifest, confined to plugin dir
+	cmd.Dir = p.dir
+	cmd.Env = p.env
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start plugin %s: %w", p.Name(), err)
+	}
+
+	conn := newRPCConn(stdout, stdin, p.logger, p.Name())
+	go p.pipeLogs(stderr)
+	exited := make(chan struct{})
+	go p.wait(cmd, conn, exited, time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	p.mu.RLock()
+	config := p.config
+	p.mu.RUnlock()
+	params := &pluginsdk.InitializeParams{
+		ProtocolVersion: pluginsdk.ProtocolVersion,
+		Name:            p.Name(),
+		Version:         p.manifest.Version,
+		Config:          config,
+	}
+	var result pluginsdk.InitializeResult
+	if err := conn.Call(ctx, pluginsdk.MethodInitialize, params, &result); err != nil {
+		_ = cmd.Process.Kill() //nolint:errcheck // best-effort cleanup of a failed start
+		return fmt.Errorf("plugin %s initialize failed: %w", p.Name(), err)
+	}
+	if result.ProtocolVersion != pluginsdk.ProtocolVersion {
+		p.logger.Warn("Plugin %s speaks protocol %q (host %q)", p.Name(), result.ProtocolVersion, pluginsdk.ProtocolVersion)
+	}
+
+	// 핸드셰이크가 끝난 프로세스만 호출 대상으로 공개 (실패한 시작은 wait가 재시작하지 않음)
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		_ = cmd.Process.Kill() //nolint:errcheck // stopped while starting
+		return ErrPluginNotRunning
+	}
+	select {
+	case <-exited:
+		// 핸드셰이크 직후 종료됨 (wait는 이미 재시작 없이 반환)
+		p.mu.Unlock()
+		return fmt.Errorf("plugin %s exited during startup", p.Name())
+	default:
+	}
+	p.cmd, p.conn, p.exited = cmd, conn, exited
+	p.mu.Unlock()
+
+	p.logger.Info("External plugin started: %s (pid %d)", p.Name(), cmd.Process.Pid)
+	return nil
+}
+
+// wait 프로세스 종료 감시. 실행 중이던 프로세스가 중지 요청 없이 종료되면 backoff 후 재시작
+func (p *ExternalPlugin) wait(cmd *exec.Cmd, conn *rpcConn, exited chan struct{}, startedAt time.Time) {
+	err := cmd.Wait()
+	conn.close()
+	close(exited)
+
+	p.mu.Lock()
+	if p.conn != conn {
+		p.mu.Unlock()
+		return // 시작 실패 또는 정상 종료
+	}
+	p.conn = nil
+	running := p.running
+	if time.Since(startedAt) >= restartStableAfter {
+		p.failures = 0
+	}
+	stop := p.stop
+	p.mu.Unlock()
+
+	if !running {
+		return
+	}
+	p.logger.Error("External plugin %s exited unexpectedly: %v", p.Name(), err)
+	p.restartLoop(stop)
+}
+
+// restartLoop 지수 backoff(1s~1m)로 재시작 시도
+func (p *ExternalPlugin) restartLoop(stop chan struct{}) {
+	for {
+		p.mu.Lock()
+		delay := restartBackoffMin << p.failures
+		if delay > restartBackoffMax || delay <= 0 {
+			delay = restartBackoffMax
+		}
+		p.failures++
+		p.mu.Unlock()
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(delay):
+		}
+
+		err := p.spawn()
+		if err == nil {
+			p.mu.Lock()
+			p.restarts++
+			p.mu.Unlock()
+			return
+		}
+		p.logger.Error("External plugin %s restart failed: %v", p.Name(), err)
+	}
+}
+
+// pipeLogs 플러그인 stderr를 호스트 로그로 전달
+func (p *ExternalPlugin) pipeLogs(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		p.logger.Info("[%s] %s", p.Name(), scanner.Text())
+	}
+}
+
+// call 실행 중인 프로세스에 RPC 호출 (ctx에 기한이 없으면 기본 타임아웃 적용)
+func (p *ExternalPlugin) call(ctx context.Context, method string, params, result interface{}) error {
+	p.mu.RLock()
+	conn := p.conn
+	p.mu.RUnlock()
+	if conn == nil {
+		return ErrPluginNotRunning
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	return conn.Call(ctx, method, params, result)
+}
+
+// Running 프로세스 실행 여부
+func (p *ExternalPlugin) Running() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.conn != nil
+}
+
+// Restarts 비정상 종료 후 재시작 횟수
+func (p *ExternalPlugin) Restarts() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.restarts
+}
+
+// RegisterRoutes 매니페스트 라우트를 프로세스로 프록시 (샌드박스 → 메트릭 → 레이트 리밋 → 프록시)
+func (p *ExternalPlugin) RegisterRoutes(router gin.IRouter) {
+	for _, route := range p.manifest.Routes {
+		handlers := []gin.HandlerFunc{SandboxMiddleware(p.Name(), p.sandbox, p.logger)}
+		if p.metrics != nil {
+			handlers = append(handlers, p.metrics.Middleware(p.Name()))
+		}
+		if p.limiter != nil {
+			handlers = append(handlers, p.limiter.Middleware(p.Name()))
+		}
+		handlers = append(handlers, p.proxy(route))
+		router.Handle(strings.ToUpper(route.Method), route.Path, handlers...)
+	}
+}
+
+// proxy HTTP 요청을 http.handle RPC로 전달
+func (p *ExternalPlugin) proxy(route RouteConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxProxyBodySize+1))
+		if err != nil {
+			abortPlugin(c, http.StatusBadRequest, "PLUGIN_BAD_REQUEST", "요청 본문을 읽을 수 없습니다")
+			return
+		}
+		if len(body) > maxProxyBodySize {
+			abortPlugin(c, http.StatusRequestEntityTooLarge, "PLUGIN_BODY_TOO_LARGE", "요청 본문이 너무 큽니다")
+			return
+		}
+
+		req := &pluginsdk.HTTPRequest{
+			Handler: route.Handler,
+			Method:  c.Request.Method,
+			Path:    c.Request.URL.Path,
+			Params:  make(map[string]string, len(c.Params)),
+			Query:   c.Request.URL.Query(),
+			Headers: make(map[string]string),
+			Body:    body,
+		}
+		for _, param := range c.Params {
+			req.Params[param.Key] = param.Value
+		}
+		for key, values := range c.Request.Header {
+			if !proxyDroppedHeaders[key] && len(values) > 0 {
+				req.Headers[key] = values[0]
+			}
+		}
+		if userID := c.GetString("userID"); userID != "" {
+			req.User = &pluginsdk.User{ID: userID, Nickname: c.GetString("nickname"), Level: c.GetInt("level")}
+		}
+
+		var resp pluginsdk.HTTPResponse
+		err = p.call(c.Request.Context(), pluginsdk.MethodHTTPHandle, req, &resp)
+		var rpcErr *pluginsdk.Error
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			abortPlugin(c, http.StatusGatewayTimeout, "PLUGIN_TIMEOUT", fmt.Sprintf("플러그인 %s 요청 시간이 초과되었습니다", p.Name()))
+			return
+		case errors.Is(err, ErrPluginNotRunning):
+			abortPlugin(c, http.StatusServiceUnavailable, "PLUGIN_UNAVAILABLE", fmt.Sprintf("플러그인 %s가 실행 중이 아닙니다", p.Name()))
+			return
+		case errors.As(err, &rpcErr):
+			p.logger.Error("Plugin %s handler %s failed: %v", p.Name(), route.Handler, rpcErr)
+			abortPlugin(c, http.StatusBadGateway, "PLUGIN_ERROR", fmt.Sprintf("플러그인 %s에서 오류가 발생했습니다", p.Name()))
+			return
+		case err != nil:
+			abortPlugin(c, http.StatusBadGateway, "PLUGIN_ERROR", err.Error())
+			return
+		}
+
+		status := resp.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		contentType := "application/json; charset=utf-8"
+		for key, value := range resp.Headers {
+			if strings.EqualFold(key, "Content-Type") {
+				contentType = value
+				continue
+			}
+			c.Header(key, value)
+		}
+		c.Data(status, contentType, resp.Body)
+	}
+}
+
+// abortPlugin 플러그인 라우트 공통 에러 응답 (SandboxMiddleware와 같은 형식)
+func abortPlugin(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"error": gin.H{"code": code, "message": message},
+	})
+}
+
+// RegisterHooks 매니페스트 hooks를 hook.call RPC로 연결
+func (p *ExternalPlugin) RegisterHooks(hm *HookManager) {
+	for _, hook := range p.manifest.Hooks {
+		hook := hook
+		handler := func(ctx *HookContext) error {
+			var result pluginsdk.HookResult
+			call := &pluginsdk.HookCall{Event: ctx.Event, Handler: hook.Handler, Input: ctx.Input}
+			if err := p.call(context.Background(), pluginsdk.MethodHookCall, call, &result); err != nil {
+				return err
+			}
+			if result.Reject != "" {
+				return Reject(result.Reject)
+			}
+			if result.Output != nil {
+				ctx.SetOutput(result.Output)
+			}
+			return nil
+		}
+		if hook.Type == "filter" {
+			hm.RegisterFilter(hook.Event, p.Name(), handler, hook.Priority)
+		} else {
+			hm.Register(hook.Event, p.Name(), handler, hook.Priority)
+		}
+	}
+}
+
+// RegisterEvents 매니페스트 events 토픽을 event.deliver RPC로 전달 (실패 시 백엔드가 재시도)
+func (p *ExternalPlugin) RegisterEvents(bus *EventBus) {
+	for _, topic := range p.manifest.Events {
+		bus.SubscribeReliable(p.Name(), topic, func(event Event) error {
+			msg := &pluginsdk.Event{ID: event.ID, Topic: event.Topic, Source: event.Source, Payload: event.Payload, Timestamp: event.Timestamp}
+			return p.call(context.Background(), pluginsdk.MethodEventDeliver, msg, nil)
+		})
+	}
+}
+
+// ConfigureRateLimit runtime.rate_limit 적용
+func (p *ExternalPlugin) ConfigureRateLimit(limiter *RateLimiter) {
+	rl := p.manifest.Runtime.RateLimit
+	if rl == nil || rl.Requests <= 0 {
+		return
+	}
+	window, err := time.ParseDuration(rl.Window)
+	if err != nil || window <= 0 {
+		p.logger.Warn("Plugin %s: invalid rate_limit.window %q", p.Name(), rl.Window)
+		return
+	}
+	limiter.Configure(p.Name(), rl.Requests, window)
+}
+
+// HealthCheck 프로세스 실행 여부 + health RPC
+func (p *ExternalPlugin) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.call(ctx, pluginsdk.MethodHealth, struct{}{}, nil); err != nil {
+		if errors.Is(err, ErrPluginNotRunning) {
+			return fmt.Errorf("%w (restarts: %d)", err, p.Restarts())
+		}
+		return err
+	}
+	return nil
+}
+
+// Shutdown shutdown RPC 후 종료 대기, 시간 초과 시 강제 종료 (재시작하지 않음)
+func (p *ExternalPlugin) Shutdown() error {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.running = false
+	close(p.stop)
+	cmd, conn, exited := p.cmd, p.conn, p.exited
+	p.mu.Unlock()
+
+	if conn == nil || cmd == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), externalShutdownWait)
+	defer cancel()
+	if err := conn.Call(ctx, pluginsdk.MethodShutdown, struct{}{}, nil); err != nil && !errors.Is(err, ErrPluginNotRunning) {
+		p.logger.Warn("Plugin %s shutdown request failed: %v", p.Name(), err)
+	}
+
+	select {
+	case <-exited:
+	case <-time.After(externalShutdownWait):
+		p.logger.Warn("Plugin %s did not exit, killing", p.Name())
+		_ = cmd.Process.Kill() //nolint:errcheck // process may already be gone
+		<-exited
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/pkg/pluginsdk"
+	"github.com/gin-gonic/gin"
+)
+
+// 테스트 바이너리를 외부 플러그인 프로세스로 재사용
+const helperEnv = "ANGPLE_TEST_PLUGIN_HELPER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperEnv) == "1" {
+		runHelperPlugin()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func runHelperPlugin() {
+	err := pluginsdk.Serve(&pluginsdk.Plugin{
+		HTTP: map[string]pluginsdk.HTTPHandler{
+			"echo": func(req *pluginsdk.HTTPRequest) (*pluginsdk.HTTPResponse, error) {
+				body, _ := json.Marshal(map[string]interface{}{ //nolint:errcheck // test helper
+					"id":     req.Params["id"],
+					"body":   string(req.Body),
+					"authed": req.Headers["Authorization"] != "",
+				})
+				return &pluginsdk.HTTPResponse{Status: http.StatusCreated, Headers: map[string]string{"X-Plugin": "ext"}, Body: body}, nil
+			},
+			"crash": func(*pluginsdk.HTTPRequest) (*pluginsdk.HTTPResponse, error) {
+				os.Exit(3)
+				return nil, nil
+			},
+		},
+		Hooks: map[string]pluginsdk.HookHandler{
+			"upper": func(call *pluginsdk.HookCall) (*pluginsdk.HookResult, error) {
+				title, _ := call.Input["title"].(string) //nolint:errcheck // test helper
+				return &pluginsdk.HookResult{Output: map[string]interface{}{"title": strings.ToUpper(title)}}, nil
+			},
+			"block": func(*pluginsdk.HookCall) (*pluginsdk.HookResult, error) {
+				return &pluginsdk.HookResult{Reject: "blocked by ext"}, nil
+			},
+		},
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// setupExternalPlugin 테스트 바이너리를 가리키는 외부 플러그인 디렉토리 생성
+func setupExternalPlugin(t *testing.T) (*Manager, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pluginsDir := t.TempDir()
+	dir := filepath.Join(pluginsDir, "ext")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(exe, filepath.Join(dir, "plugin-bin")); err != nil {
+		t.Skipf("symlink not supported: %v", err)
+	}
+	manifest := `name: ext
+version: 1.0.0
+title: External
+requires:
+  angple: ">=0.0.0"
+runtime:
+  command: ./plugin-bin
+  timeout: 5s
+  env:
+    ` + helperEnv + `: "1"
+routes:
+  - path: /echo/:id
+    method: POST
+    handler: echo
+  - path: /crash
+    method: GET
+    handler: crash
+hooks:
+  - event: post.title
+    handler: upper
+    type: filter
+  - event: post.before_create
+    handler: block
+capabilities:
+  hooks: ["post.*"]
+`
+	if err := os.WriteFile(filepath.Join(dir, "plugin.yaml"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	m := NewManager(pluginsDir, nil, nil, NewDefaultLogger("test"), nil, nil)
+	m.GetRegistry().SetRouter(router)
+	if err := m.LoadAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Enable("ext"); err != nil {
+		t.Fatalf("Enable failed: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Disable("ext") }) //nolint:errcheck // cleanup
+	return m, router
+}
+
+func TestExternalPlugin_RoutesHooksAndHealth(t *testing.T) {
+	m, router := setupExternalPlugin(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/plugins/ext/echo/42", strings.NewReader("hello"))
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("X-Plugin") != "ext" {
+		t.Error("expected plugin response header")
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["id"] != "42" || body["body"] != "hello" {
+		t.Errorf("unexpected proxied request: %v", body)
+	}
+	if body["authed"] != false {
+		t.Error("Authorization header must not be forwarded to the plugin")
+	}
+
+	if summary := m.GetPluginMetrics("ext"); summary == nil || summary.TotalRequests != 1 {
+		t.Errorf("expected metrics to record the proxied request, got %+v", summary)
+	}
+
+	out := m.GetHookManager().Apply("post.title", map[string]interface{}{"title": "hi"})
+	if out["title"] != "HI" {
+		t.Errorf("expected filter hook output HI, got %v", out["title"])
+	}
+	_, err := m.GetHookManager().ApplyBefore("post.before_create", map[string]interface{}{})
+	var reject *HookRejectError
+	if !errors.As(err, &reject) || reject.Reason != "blocked by ext" {
+		t.Errorf("expected reject from external hook, got %v", err)
+	}
+
+	if health := m.CheckHealth("ext"); health.Status != "healthy" {
+		t.Errorf("expected healthy, got %+v", health)
+	}
+}
+
+func TestExternalPlugin_RestartsAfterCrash(t *testing.T) {
+	m, router := setupExternalPlugin(t)
+	info, _ := m.GetPlugin("ext")
+	ext := info.Instance.(*ExternalPlugin)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/plugins/ext/crash", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while process is down, got %d", w.Code)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for ext.Restarts() == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if ext.Restarts() != 1 || !ext.Running() {
+		t.Fatalf("expected one restart, got restarts=%d running=%v", ext.Restarts(), ext.Running())
+	}
+	if health := m.CheckHealth("ext"); health.Status != "healthy" {
+		t.Errorf("expected healthy after restart, got %+v", health)
+	}
+
+	if err := m.Disable("ext"); err != nil {
+		t.Fatal(err)
+	}
+	if ext.Running() {
+		t.Error("expected process to stop on disable")
+	}
+}
+
+func TestNewExternalPlugin_CommandOutsideDir(t *testing.T) {
+	manifest := &PluginManifest{Name: "bad", Runtime: &RuntimeConfig{Command: "../../bin/sh"}}
+	if _, err := NewExternalPlugin(manifest, t.TempDir(), NewDefaultLogger("test"), nil, nil); err == nil {
+		t.Error("expected error for command outside plugin directory")
+	}
+}
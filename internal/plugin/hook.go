@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"errors"
 	"sort"
 	"sync"
 )
@@ -36,6 +37,22 @@ func (c *HookContext) GetOutput() map[string]interface{} {
 // HookHandler Hook 핸들러 함수
 type HookHandler func(ctx *HookContext) error
 
+// HookRejectError Before Hook이 쓰기 작업을 거부할 때 반환되는 에러
+type HookRejectError struct {
+	Event  string
+	Plugin string
+	Reason string
+}
+
+func (e *HookRejectError) Error() string {
+	return e.Reason
+}
+
+// Reject Before Hook 핸들러에서 쓰기 작업을 거부할 때 반환
+func Reject(reason string) error {
+	return &HookRejectError{Reason: reason}
+}
+
 // hookEntry 등록된 Hook 정보
 type hookEntry struct {
 	pluginName string
@@ -47,22 +64,51 @@ type hookEntry struct {
 // HookManager Hook 등록/실행 관리자 (thread-safe)
 type HookManager struct {
 	hooks  map[string][]hookEntry
+	guards map[string]HookGuard // pluginName -> 등록 허용 검사
 	mu     sync.RWMutex
 	logger Logger
 }
 
+// HookGuard 플러그인의 Hook 등록 허용 여부 (capabilities.hooks)
+type HookGuard func(event string) bool
+
 // NewHookManager 새 HookManager 생성
 func NewHookManager(logger Logger) *HookManager {
 	return &HookManager{
 		hooks:  make(map[string][]hookEntry),
+		guards: make(map[string]HookGuard),
 		logger: logger,
 	}
 }
 
+// SetGuard 플러그인의 Hook 등록 제한 설정 (nil이면 제한 해제)
+func (hm *HookManager) SetGuard(pluginName string, guard HookGuard) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	if guard == nil {
+		delete(hm.guards, pluginName)
+		return
+	}
+	hm.guards[pluginName] = guard
+}
+
+// allowed 가드가 등록을 거부하면 false (hm.mu 보유 상태에서 호출)
+func (hm *HookManager) allowed(event, pluginName string) bool {
+	guard, ok := hm.guards[pluginName]
+	if !ok || guard(event) {
+		return true
+	}
+	hm.logger.Warn("Hook registration denied: plugin %s may not hook %s", pluginName, event)
+	return false
+}
+
 // Register Action Hook 등록
 func (hm *HookManager) Register(event string, pluginName string, handler HookHandler, priority int) {
 	hm.mu.Lock()
 	defer hm.mu.Unlock()
+	if !hm.allowed(event, pluginName) {
+		return
+	}
 
 	hm.hooks[event] = append(hm.hooks[event], hookEntry{
 		pluginName: pluginName,
@@ -77,6 +123,9 @@ func (hm *HookManager) Register(event string, pluginName string, handler HookHan
 func (hm *HookManager) RegisterFilter(event string, pluginName string, handler HookHandler, priority int) {
 	hm.mu.Lock()
 	defer hm.mu.Unlock()
+	if !hm.allowed(event, pluginName) {
+		return
+	}
 
 	hm.hooks[event] = append(hm.hooks[event], hookEntry{
 		pluginName: pluginName,
@@ -99,7 +148,7 @@ func (hm *HookManager) Do(event string, data map[string]interface{}) {
 			Event: event,
 			Input: data,
 		}
-		if err := entry.handler(ctx); err != nil {
+		if err := hm.call(entry, ctx); err != nil {
 			hm.logger.Error("Hook error [%s] plugin=%s: %v", event, entry.pluginName, err)
 		}
 	}
@@ -118,7 +167,7 @@ func (hm *HookManager) Apply(event string, data map[string]interface{}) map[stri
 			Event: event,
 			Input: current,
 		}
-		if err := entry.handler(ctx); err != nil {
+		if err := hm.call(entry, ctx); err != nil {
 			hm.logger.Error("Filter error [%s] plugin=%s: %v", event, entry.pluginName, err)
 			continue
 		}
@@ -127,6 +176,44 @@ func (hm *HookManager) Apply(event string, data map[string]interface{}) map[stri
 	return current
 }
 
+// ApplyBefore Before Hook 실행 (Apply와 같이 체이닝하되 거부 가능)
+// 핸들러가 Reject()를 반환하면 즉시 중단하고 *HookRejectError를 반환한다.
+// 그 외 에러는 로깅 후 다음 핸들러로 진행한다.
+func (hm *HookManager) ApplyBefore(event string, data map[string]interface{}) (map[string]interface{}, error) {
+	hm.mu.RLock()
+	entries := make([]hookEntry, len(hm.hooks[event]))
+	copy(entries, hm.hooks[event])
+	hm.mu.RUnlock()
+
+	current := data
+	for _, entry := range entries {
+		ctx := &HookContext{
+			Event: event,
+			Input: current,
+		}
+		if err := hm.call(entry, ctx); err != nil {
+			var reject *HookRejectError
+			if errors.As(err, &reject) {
+				reject.Event = event
+				reject.Plugin = entry.pluginName
+				hm.logger.Info("Hook rejected [%s] plugin=%s: %s", event, entry.pluginName, reject.Reason)
+				return current, reject
+			}
+			hm.logger.Error("Before hook error [%s] plugin=%s: %v", event, entry.pluginName, err)
+			continue
+		}
+		current = ctx.GetOutput()
+	}
+	return current, nil
+}
+
+// call 핸들러를 패닉 복구와 함께 실행
+func (hm *HookManager) call(entry hookEntry, ctx *HookContext) error {
+	return SafeCall(entry.pluginName, hm.logger, func() error {
+		return entry.handler(ctx)
+	})
+}
+
 // Unregister 특정 플러그인의 모든 Hook 해제
 func (hm *HookManager) Unregister(pluginName string) {
 	hm.mu.Lock()
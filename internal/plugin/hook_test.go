@@ -171,3 +171,78 @@ func TestHookApplyNoHandlers(t *testing.T) {
 		t.Error("Apply with no handlers should return original data")
 	}
 }
+
+func TestHookApplyBeforeRewrite(t *testing.T) {
+	hm := newTestHookManager()
+
+	hm.RegisterFilter(HookPostBeforeCreate, "censor", func(ctx *HookContext) error {
+		out := map[string]interface{}{}
+		for k, v := range ctx.Input {
+			out[k] = v
+		}
+		out["title"] = "[검토] " + ctx.Input["title"].(string)
+		ctx.SetOutput(out)
+		return nil
+	}, 10)
+
+	result, err := hm.ApplyBefore(HookPostBeforeCreate, map[string]interface{}{"title": "Hello", "content": "body"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result["title"] != "[검토] Hello" {
+		t.Errorf("title = %v, want %q", result["title"], "[검토] Hello")
+	}
+	if result["content"] != "body" {
+		t.Errorf("content = %v, want %q", result["content"], "body")
+	}
+}
+
+func TestHookApplyBeforeReject(t *testing.T) {
+	hm := newTestHookManager()
+
+	laterCalled := false
+	hm.Register(HookCommentBeforeCreate, "spam-guard", func(_ *HookContext) error {
+		return Reject("스팸으로 판단되어 등록할 수 없습니다")
+	}, 10)
+	hm.Register(HookCommentBeforeCreate, "later", func(_ *HookContext) error {
+		laterCalled = true
+		return nil
+	}, 20)
+
+	_, err := hm.ApplyBefore(HookCommentBeforeCreate, map[string]interface{}{"content": "buy now"})
+	if err == nil {
+		t.Fatal("expected rejection error")
+	}
+	var reject *HookRejectError
+	if !errors.As(err, &reject) {
+		t.Fatalf("expected *HookRejectError, got %T", err)
+	}
+	if reject.Plugin != "spam-guard" || reject.Event != HookCommentBeforeCreate {
+		t.Errorf("reject = %+v, want plugin spam-guard / event %s", reject, HookCommentBeforeCreate)
+	}
+	if err.Error() != "스팸으로 판단되어 등록할 수 없습니다" {
+		t.Errorf("unexpected message: %q", err.Error())
+	}
+	if laterCalled {
+		t.Error("hooks after a rejection should not run")
+	}
+}
+
+func TestHookApplyBeforeIgnoresPlainErrors(t *testing.T) {
+	hm := newTestHookManager()
+
+	hm.Register(HookPostBeforeUpdate, "flaky", func(_ *HookContext) error {
+		return errors.New("temporary failure")
+	}, 10)
+	hm.Register(HookPostBeforeUpdate, "panicky", func(_ *HookContext) error {
+		panic("boom")
+	}, 20)
+
+	result, err := hm.ApplyBefore(HookPostBeforeUpdate, map[string]interface{}{"title": "t"})
+	if err != nil {
+		t.Fatalf("plain errors and panics must not reject the write: %v", err)
+	}
+	if result["title"] != "t" {
+		t.Errorf("expected original data, got %v", result)
+	}
+}
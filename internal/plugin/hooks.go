@@ -24,5 +24,7 @@ const (
 
 // User hooks
 const (
-	HookUserAfterLogin = "user.after_login"
+	HookUserAfterLogin     = "user.after_login"
+	HookUserBeforeRegister = "user.before_register"
+	HookUserAfterRegister  = "user.after_register"
 )
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -28,7 +29,11 @@ func (l *Loader) LoadManifest(pluginPath string) (*PluginManifest, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read plugin.yaml: %w", err)
 	}
+	return l.ParseManifest(data)
+}
 
+// ParseManifest plugin.yaml 내용 파싱 + 필수 필드 검증
+func (l *Loader) ParseManifest(data []byte) (*PluginManifest, error) {
 	var manifest PluginManifest
 	if err := yaml.Unmarshal(data, &manifest); err != nil {
 		return nil, fmt.Errorf("failed to parse plugin.yaml: %w", err)
@@ -47,6 +52,10 @@ func (l *Loader) validateManifest(m *PluginManifest) error {
 	if m.Name == "" {
 		return fmt.Errorf("plugin name is required")
 	}
+	// 이름은 설치 디렉토리/테이블 접두사로 쓰이므로 경로 문자(../, /)를 허용하지 않는다
+	if !pluginNamePattern.MatchString(m.Name) {
+		return fmt.Errorf("plugin name %q must be lowercase letters, digits and hyphens", m.Name)
+	}
 	if m.Version == "" {
 		return fmt.Errorf("plugin version is required")
 	}
@@ -65,6 +74,13 @@ func (l *Loader) validateManifest(m *PluginManifest) error {
 		return fmt.Errorf("invalid requires.angple %q: %w", m.Requires.Angple, err)
 	}
 
+	if m.Runtime != nil && m.Runtime.Command == "" {
+		return fmt.Errorf("runtime.command is required")
+	}
+	if m.Capabilities != nil && strings.ContainsAny(m.Capabilities.RedisPrefix, "*?[]\\") {
+		return fmt.Errorf("capabilities.redis_prefix %q must not contain glob characters", m.Capabilities.RedisPrefix)
+	}
+
 	return nil
 }
 
@@ -83,7 +99,8 @@ func (l *Loader) DiscoverPlugins() ([]*PluginInfo, error) {
 	plugins := make([]*PluginInfo, 0, len(entries))
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		// 패키지 설치 중인 임시 디렉토리(.install-*)는 제외
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
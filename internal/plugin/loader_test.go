@@ -85,6 +85,16 @@ requires:
 	}
 }
 
+func TestLoader_ParseManifest_InvalidName(t *testing.T) {
+	loader := NewLoader(t.TempDir())
+	for _, name := range []string{"../evil", "Evil", "a/b", "-lead", "under_score"} {
+		manifest := "name: \"" + name + "\"\nversion: 1.0.0\ntitle: T\nrequires:\n  angple: \">=1.0.0\"\n"
+		if _, err := loader.ParseManifest([]byte(manifest)); err == nil {
+			t.Errorf("expected error for plugin name %q", name)
+		}
+	}
+}
+
 func TestLoader_DiscoverPlugins(t *testing.T) {
 	tempDir := t.TempDir()
 
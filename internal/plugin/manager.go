@@ -1,10 +1,9 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
-	"sort"
+	"net/http"
 	"sync"
 	"time"
 
@@ -29,10 +28,29 @@ type Manager struct {
 	metrics     *Metrics
 	eventBus    *EventBus
 	jwtManager  interface{} // JWT 매니저 (순환 의존 방지)
+
+	capabilities CapabilityProvider
+	violations   *violationLog
+
+	// upgradeCaps 업그레이드 중 새 버전 활성화에만 적용할 capabilities (UpgradeWithCapabilities)
+	upgradeMu   sync.Mutex
+	upgradeCaps map[string]*Capabilities
 }
 
 // NewManager 새 매니저 생성
 func NewManager(pluginsDir string, db *gorm.DB, redisClient *redis.Client, logger Logger, settings SettingGetter, permissions PermissionSyncer) *Manager {
+	scheduler := NewScheduler(logger)
+	if redisClient != nil {
+		scheduler.SetLocker(NewRedisRunLocker(redisClient, scheduler.NodeID()))
+	}
+	if db != nil {
+		scheduler.SetHistoryDB(db)
+	}
+	eventBus := NewEventBus(logger)
+	if redisClient != nil {
+		eventBus.SetBackend(NewRedisEventBackend(redisClient, scheduler.NodeID(), logger))
+	}
+
 	return &Manager{
 		loader:      NewLoader(pluginsDir),
 		registry:    NewRegistry(),
@@ -43,10 +61,11 @@ func NewManager(pluginsDir string, db *gorm.DB, redisClient *redis.Client, logge
 		logger:      logger,
 		settings:    settings,
 		permissions: permissions,
-		scheduler:   NewScheduler(logger),
+		scheduler:   scheduler,
 		rateLimiter: NewRateLimiter(redisClient),
 		metrics:     NewMetrics(),
-		eventBus:    NewEventBus(logger),
+		eventBus:    eventBus,
+		violations:  newViolationLog(logger),
 	}
 }
 
@@ -68,9 +87,10 @@ func (m *Manager) LoadAll() error {
 			continue
 		}
 
-		m.mu.Lock()
-		m.plugins[info.Manifest.Name] = info
-		m.mu.Unlock()
+		if err := m.addDiscovered(info); err != nil {
+			m.logger.Warn("Plugin %s skipped: %v", info.Path, err)
+			continue
+		}
 
 		m.logger.Info("Discovered plugin: %s v%s", info.Manifest.Name, info.Manifest.Version)
 	}
@@ -78,6 +98,26 @@ func (m *Manager) LoadAll() error {
 	return nil
 }
 
+// addDiscovered 디렉토리에서 찾은 플러그인 등록 (runtime이 선언된 플러그인은 서브프로세스로 실행)
+func (m *Manager) addDiscovered(info *PluginInfo) error {
+	if info.Manifest.Runtime != nil {
+		ext, err := NewExternalPlugin(info.Manifest, info.Path, m.logger, m.metrics, m.rateLimiter)
+		if err != nil {
+			return err
+		}
+		info.Instance = ext
+		info.LoadedAt = time.Now().Unix()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.plugins[info.Manifest.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrPluginExists, info.Manifest.Name)
+	}
+	m.plugins[info.Manifest.Name] = info
+	return nil
+}
+
 // RegisterBuiltIn 내장 플러그인 등록
 func (m *Manager) RegisterBuiltIn(name string, p Plugin, manifest *PluginManifest) error {
 	m.mu.Lock()
@@ -153,6 +193,15 @@ func (m *Manager) Enable(name string) error {
 		}
 	}
 
+	// 파일 기반 마이그레이션 (적용된 파일이 변경되었으면 활성화 거부)
+	if !info.IsBuiltIn {
+		if err := m.RunMigrations(name); err != nil {
+			info.Status = StatusError
+			info.Error = err
+			return fmt.Errorf("failed to migrate plugin %s: %w", name, err)
+		}
+	}
+
 	// 플러그인 인스턴스가 있으면 마이그레이션 → 초기화
 	if info.Instance != nil {
 		// 1) 마이그레이션 먼저
@@ -173,14 +222,13 @@ func (m *Manager) Enable(name string) error {
 			}
 		}
 
-		ctx := &PluginContext{
-			DB:         m.db,
-			Redis:      m.redis,
-			Config:     pluginConfig,
-			Logger:     m.logger,
-			BasePath:   info.Path,
-			JWTManager: m.jwtManager,
+		ctx, err := m.newPluginContext(name, info)
+		if err != nil {
+			info.Status = StatusError
+			info.Error = err
+			return fmt.Errorf("failed to scope plugin %s: %w", name, err)
 		}
+		ctx.Config = pluginConfig
 
 		if err := info.Instance.Initialize(ctx); err != nil {
 			info.Status = StatusError
@@ -259,6 +307,18 @@ func (m *Manager) Disable(name string) error {
 		return nil // 이미 비활성화됨
 	}
 
+	m.deactivate(name, info)
+
+	m.mu.Lock()
+	info.Status = StatusDisabled
+	m.mu.Unlock()
+
+	m.logger.Info("Disabled plugin: %s", name)
+	return nil
+}
+
+// deactivate 플러그인 종료 후 Hook, 스케줄, 라우트, 메뉴 등록 해제 (상태는 바꾸지 않음)
+func (m *Manager) deactivate(name string, info *PluginInfo) {
 	// 라이프사이클 훅: OnDisable
 	if info.Instance != nil {
 		if lc, ok := info.Instance.(LifecycleAware); ok {
@@ -295,12 +355,6 @@ func (m *Manager) Disable(name string) error {
 		m.logger.Warn("Failed to disable menus for plugin %s: %v", name, err)
 	}
 
-	m.mu.Lock()
-	info.Status = StatusDisabled
-	m.mu.Unlock()
-
-	m.logger.Info("Disabled plugin: %s", name)
-	return nil
 }
 
 // ReloadPlugin 플러그인 재초기화 (설정 변경 후 호출)
@@ -333,6 +387,9 @@ func (m *Manager) ReloadPlugin(name string) error {
 
 // StartScheduler 스케줄러 시작
 func (m *Manager) StartScheduler() {
+	if err := m.scheduler.MigrateHistory(); err != nil {
+		m.logger.Warn("Failed to migrate schedule run history: %v", err)
+	}
 	m.scheduler.Start()
 }
 
@@ -346,6 +403,16 @@ func (m *Manager) GetScheduledTasks() []ScheduledTaskInfo {
 	return m.scheduler.GetTasks()
 }
 
+// RunScheduledTask 스케줄 작업 즉시 실행
+func (m *Manager) RunScheduledTask(pluginName, taskName string) error {
+	return m.scheduler.RunNow(pluginName, taskName)
+}
+
+// GetScheduleRuns 스케줄 작업 실행 이력
+func (m *Manager) GetScheduleRuns(pluginName, taskName string, limit int) ([]ScheduleRun, error) {
+	return m.scheduler.ListRuns(pluginName, taskName, limit)
+}
+
 // GetRateLimiter 레이트 리미터 반환
 func (m *Manager) GetRateLimiter() *RateLimiter {
 	return m.rateLimiter
@@ -381,6 +448,11 @@ func (m *Manager) GetEventSubscriptions() map[string][]string {
 	return m.eventBus.GetSubscriptions()
 }
 
+// GetEventSubscriberStats 구독자별 lag/dead-letter 현황
+func (m *Manager) GetEventSubscriberStats(ctx context.Context) ([]SubscriberStats, error) {
+	return m.eventBus.GetStats(ctx)
+}
+
 // NotifyInstall 설치 시 라이프사이클 훅 호출
 func (m *Manager) NotifyInstall(name string) {
 	m.mu.RLock()
@@ -498,95 +570,12 @@ func (m *Manager) Shutdown() error {
 		}
 		info.Status = StatusDisabled
 	}
+	m.eventBus.Close()
 
 	m.logger.Info("All plugins shutdown complete")
 	return nil
 }
 
-// PluginMigrationRecord 마이그레이션 실행 이력 (GORM 모델)
-type PluginMigrationRecord struct {
-	ID         int64     `gorm:"primaryKey"`
-	PluginName string    `gorm:"size:100;uniqueIndex:uk_plugin_migration"`
-	Filename   string    `gorm:"size:255;uniqueIndex:uk_plugin_migration"`
-	ExecutedAt time.Time `gorm:"autoCreateTime"`
-}
-
-func (PluginMigrationRecord) TableName() string {
-	return "plugin_migrations"
-}
-
-// RunMigrations 플러그인 마이그레이션 실행
-func (m *Manager) RunMigrations(name string) error {
-	m.mu.RLock()
-	info, exists := m.plugins[name]
-	m.mu.RUnlock()
-
-	if !exists {
-		return fmt.Errorf("plugin %s not found", name)
-	}
-
-	if info.IsBuiltIn {
-		return m.runBuiltInMigrations(name)
-	}
-
-	if m.db == nil {
-		return fmt.Errorf("database not initialized")
-	}
-
-	files, err := m.loader.GetMigrationFiles(info.Path)
-	if err != nil {
-		return err
-	}
-
-	if len(files) == 0 {
-		return nil
-	}
-
-	// 파일명 순서 정렬 (001_init.up.sql, 002_add_column.up.sql, ...)
-	sort.Strings(files)
-
-	// 이미 실행된 마이그레이션 조회
-	var executed []PluginMigrationRecord
-	m.db.Where("plugin_name = ?", name).Find(&executed)
-	executedMap := make(map[string]bool, len(executed))
-	for _, rec := range executed {
-		executedMap[rec.Filename] = true
-	}
-
-	// 미실행 마이그레이션 순서대로 실행
-	for _, filePath := range files {
-		filename := filepath.Base(filePath)
-
-		if executedMap[filename] {
-			continue // 이미 실행됨
-		}
-
-		cleanPath := filepath.Clean(filePath)
-		sql, err := os.ReadFile(cleanPath) // #nosec G304 -- path from plugin dir + glob, not user input
-		if err != nil {
-			return fmt.Errorf("마이그레이션 파일 읽기 실패 %s: %w", filename, err)
-		}
-
-		// SQL 실행
-		if err := m.db.Exec(string(sql)).Error; err != nil {
-			return fmt.Errorf("마이그레이션 실행 실패 %s: %w", filename, err)
-		}
-
-		// 실행 이력 기록
-		record := &PluginMigrationRecord{
-			PluginName: name,
-			Filename:   filename,
-		}
-		if err := m.db.Create(record).Error; err != nil {
-			return fmt.Errorf("마이그레이션 이력 기록 실패 %s: %w", filename, err)
-		}
-
-		m.logger.Info("마이그레이션 실행 완료: %s/%s", name, filename)
-	}
-
-	return nil
-}
-
 // runBuiltInMigrations 내장 플러그인 마이그레이션 실행
 func (m *Manager) runBuiltInMigrations(name string) error {
 	m.mu.RLock()
@@ -762,11 +751,105 @@ func (m *Manager) disablePluginMenus(pluginName string) error {
 	return nil
 }
 
+// deletePluginMenus 플러그인 메뉴 삭제 (업그레이드로 메뉴 구성이 바뀌었을 때 다시 등록하기 위해)
+func (m *Manager) deletePluginMenus(pluginName string) {
+	if m.db == nil {
+		return
+	}
+	if err := m.db.Where("plugin_name = ?", pluginName).Delete(&PluginMenu{}).Error; err != nil {
+		m.logger.Warn("Failed to delete menus for plugin %s: %v", pluginName, err)
+	}
+}
+
 // GetHookManager HookManager 반환
 func (m *Manager) GetHookManager() *HookManager {
 	return m.hookManager
 }
 
+// SetCapabilityProvider 관리자 승인 capabilities 조회기 설정
+func (m *Manager) SetCapabilityProvider(p CapabilityProvider) {
+	m.capabilities = p
+}
+
+// SetViolationRecorder capability 위반 기록기 설정
+func (m *Manager) SetViolationRecorder(r ViolationRecorder) {
+	m.violations.setRecorder(r)
+}
+
+// resolveCapabilities 플러그인에 적용할 capabilities (nil이면 제한 없음)
+// 승인 기록이 있으면 그것을, 없으면 내장 플러그인은 매니페스트 선언을 따른다.
+// 승인되지 않은 외부 플러그인은 승인 절차가 없는 환경(스토어 미설정)에서만 선언을 그대로 쓴다.
+func (m *Manager) resolveCapabilities(name string, info *PluginInfo) *Capabilities {
+	m.upgradeMu.Lock()
+	caps, upgrading := m.upgradeCaps[name]
+	m.upgradeMu.Unlock()
+	switch {
+	case upgrading && caps != nil:
+		return caps
+	case upgrading:
+		// 새 버전 승인이 없음 — 이전 버전의 승인 기록을 쓰지 않는다
+	case m.capabilities != nil:
+		if caps, ok := m.capabilities.ApprovedCapabilities(name); ok {
+			return caps
+		}
+	}
+	declared := info.Manifest != nil && info.Manifest.Capabilities != nil
+	switch {
+	case info.IsBuiltIn && !declared:
+		return nil
+	case declared && (info.IsBuiltIn || m.capabilities == nil):
+		return info.Manifest.Capabilities
+	default:
+		return &Capabilities{}
+	}
+}
+
+// newPluginContext capabilities 범위로 제한된 DB/Redis/HTTP를 담은 컨텍스트 생성
+func (m *Manager) newPluginContext(name string, info *PluginInfo) (*PluginContext, error) {
+	ctx := &PluginContext{
+		DB:         m.db,
+		Logger:     m.logger,
+		BasePath:   info.Path,
+		JWTManager: m.jwtManager,
+		HTTP:       &http.Client{Timeout: 30 * time.Second},
+	}
+	caps := m.resolveCapabilities(name, info)
+	if caps == nil {
+		m.hookManager.SetGuard(name, nil)
+		if m.redis != nil {
+			ctx.Redis = NewScopedRedis(m.redis, "")
+		}
+		return ctx, nil
+	}
+
+	ctx.Capabilities = caps
+	ctx.HTTP = newScopedHTTPClient(name, caps, m.violations.report)
+	if m.redis != nil {
+		ctx.Redis = NewScopedRedis(m.redis, caps.KeyPrefix(name))
+	}
+	if m.db != nil {
+		db, err := newScopedDB(m.db, &dbScope{plugin: name, caps: caps, report: m.violations.report})
+		if err != nil {
+			return nil, err
+		}
+		ctx.DB = db
+	}
+	m.hookManager.SetGuard(name, func(event string) bool {
+		if caps.AllowsHook(event) {
+			return true
+		}
+		m.violations.report(CapabilityViolation{Plugin: name, Kind: CapabilityHook, Target: event})
+		return false
+	})
+	return ctx, nil
+}
+
+// SetHookManager 외부에서 생성한 HookManager 주입 (코어 쓰기 경로와 공유)
+// 플러그인 활성화 전에 호출해야 한다.
+func (m *Manager) SetHookManager(hm *HookManager) {
+	m.hookManager = hm
+}
+
 // GetPermissionSyncer PermissionSyncer 반환
 func (m *Manager) GetPermissionSyncer() PermissionSyncer {
 	return m.permissions
@@ -0,0 +1,326 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrMigrationChecksumMismatch 이미 적용된 마이그레이션 파일이 변경됨
+var ErrMigrationChecksumMismatch = errors.New("applied plugin migration was modified")
+
+// 마이그레이션 방향
+const (
+	MigrationUp   = "up"
+	MigrationDown = "down"
+)
+
+// PluginMigrationRecord 마이그레이션 실행 이력 (GORM 모델)
+type PluginMigrationRecord struct {
+	ID         int64     `gorm:"primaryKey"`
+	PluginName string    `gorm:"size:100;uniqueIndex:uk_plugin_migration"`
+	Filename   string    `gorm:"size:255;uniqueIndex:uk_plugin_migration"`
+	Checksum   string    `gorm:"size:64"`
+	ExecutedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (PluginMigrationRecord) TableName() string {
+	return "plugin_migrations"
+}
+
+// MigrationFile 버전별 up/down 파일 쌍 (001_init.up.sql + 001_init.down.sql)
+type MigrationFile struct {
+	Version  string
+	UpPath   string
+	DownPath string
+}
+
+// UpFilename 이력에 기록되는 파일명
+func (f MigrationFile) UpFilename() string {
+	return filepath.Base(f.UpPath)
+}
+
+// MigrationStep 실행(또는 드라이런) 대상 마이그레이션
+type MigrationStep struct {
+	Filename  string `json:"filename"`
+	Direction string `json:"direction"`
+	Checksum  string `json:"checksum,omitempty"`
+	SQL       string `json:"sql"`
+}
+
+// collectMigrations .sql 파일 목록을 버전별 up/down 쌍으로 정리 (버전 오름차순)
+// 접미사가 없는 001_init.sql은 down 없는 up 마이그레이션으로 취급한다.
+func collectMigrations(files []string) []MigrationFile {
+	byVersion := make(map[string]*MigrationFile)
+	for _, path := range files {
+		base := filepath.Base(path)
+		var version string
+		down := false
+		switch {
+		case strings.HasSuffix(base, ".down.sql"):
+			version, down = strings.TrimSuffix(base, ".down.sql"), true
+		case strings.HasSuffix(base, ".up.sql"):
+			version = strings.TrimSuffix(base, ".up.sql")
+		default:
+			version = strings.TrimSuffix(base, ".sql")
+		}
+
+		mf, ok := byVersion[version]
+		if !ok {
+			mf = &MigrationFile{Version: version}
+			byVersion[version] = mf
+		}
+		if down {
+			mf.DownPath = path
+		} else {
+			mf.UpPath = path
+		}
+	}
+
+	result := make([]MigrationFile, 0, len(byVersion))
+	for _, mf := range byVersion {
+		if mf.UpPath != "" {
+			result = append(result, *mf)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result
+}
+
+// readMigration 파일 내용과 SHA-256 체크섬
+func readMigration(path string) (sql, checksum string, err error) {
+	data, err := os.ReadFile(filepath.Clean(path)) // #nosec G304 -- path from plugin dir + glob, not user input
+	if err != nil {
+		return "", "", fmt.Errorf("마이그레이션 파일 읽기 실패 %s: %w", filepath.Base(path), err)
+	}
+	sum := sha256.Sum256(data)
+	return string(data), hex.EncodeToString(sum[:]), nil
+}
+
+// supportsTransactionalDDL DDL을 트랜잭션으로 롤백할 수 있는 dialect 여부
+// MySQL은 DDL 실행 시 암묵적으로 커밋하므로 트랜잭션 없이 실행한다.
+func supportsTransactionalDDL(db *gorm.DB) bool {
+	switch db.Name() {
+	case "sqlite", "postgres":
+		return true
+	default:
+		return false
+	}
+}
+
+// inMigrationTx 가능하면 트랜잭션 안에서 fn 실행
+func (m *Manager) inMigrationTx(fn func(tx *gorm.DB) error) error {
+	if supportsTransactionalDDL(m.db) {
+		return m.db.Transaction(fn)
+	}
+	return fn(m.db)
+}
+
+// loadMigrationState 마이그레이션 파일과 적용 이력을 읽고 체크섬을 검증
+func (m *Manager) loadMigrationState(name string) ([]MigrationFile, map[string]*PluginMigrationRecord, error) {
+	m.mu.RLock()
+	info, exists := m.plugins[name]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, nil, fmt.Errorf("plugin %s not found", name)
+	}
+	if info.Path == "" {
+		return nil, nil, nil
+	}
+
+	files, err := m.loader.GetMigrationFiles(info.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	migrations := collectMigrations(files)
+	if len(migrations) == 0 {
+		return nil, nil, nil
+	}
+
+	if m.db == nil {
+		return nil, nil, fmt.Errorf("database not initialized")
+	}
+	if err := m.db.AutoMigrate(&PluginMigrationRecord{}); err != nil {
+		return nil, nil, fmt.Errorf("마이그레이션 이력 테이블 생성 실패: %w", err)
+	}
+
+	var executed []PluginMigrationRecord
+	if err := m.db.Where("plugin_name = ?", name).Find(&executed).Error; err != nil {
+		return nil, nil, fmt.Errorf("마이그레이션 이력 조회 실패: %w", err)
+	}
+	applied := make(map[string]*PluginMigrationRecord, len(executed))
+	for i := range executed {
+		applied[executed[i].Filename] = &executed[i]
+	}
+
+	// 적용된 파일이 변경되었으면 실행 거부 (체크섬이 없는 이전 이력은 현재 값으로 채운다)
+	for _, mf := range migrations {
+		rec, ok := applied[mf.UpFilename()]
+		if !ok {
+			continue
+		}
+		_, checksum, err := readMigration(mf.UpPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if rec.Checksum == "" {
+			rec.Checksum = checksum
+			if err := m.db.Model(rec).Update("checksum", checksum).Error; err != nil {
+				return nil, nil, fmt.Errorf("마이그레이션 체크섬 기록 실패 %s: %w", rec.Filename, err)
+			}
+			continue
+		}
+		if rec.Checksum != checksum {
+			return nil, nil, fmt.Errorf("%w: %s/%s", ErrMigrationChecksumMismatch, name, rec.Filename)
+		}
+	}
+
+	return migrations, applied, nil
+}
+
+// RunMigrations 플러그인 마이그레이션 실행
+func (m *Manager) RunMigrations(name string) error {
+	m.mu.RLock()
+	info, exists := m.plugins[name]
+	m.mu.RUnlock()
+
+	if exists && info.IsBuiltIn {
+		return m.runBuiltInMigrations(name)
+	}
+
+	steps, err := m.PlanMigrations(name)
+	if err != nil {
+		return err
+	}
+
+	// 미실행 마이그레이션 순서대로 실행 (파일 + 이력을 한 트랜잭션으로)
+	for _, step := range steps {
+		err := m.inMigrationTx(func(tx *gorm.DB) error {
+			if err := tx.Exec(step.SQL).Error; err != nil {
+				return fmt.Errorf("마이그레이션 실행 실패 %s: %w", step.Filename, err)
+			}
+			record := &PluginMigrationRecord{
+				PluginName: name,
+				Filename:   step.Filename,
+				Checksum:   step.Checksum,
+			}
+			if err := tx.Create(record).Error; err != nil {
+				return fmt.Errorf("마이그레이션 이력 기록 실패 %s: %w", step.Filename, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		m.logger.Info("마이그레이션 실행 완료: %s/%s", name, step.Filename)
+	}
+
+	return nil
+}
+
+// PlanMigrations 미실행 up 마이그레이션 목록 (드라이런, 실행하지 않음)
+func (m *Manager) PlanMigrations(name string) ([]MigrationStep, error) {
+	migrations, applied, err := m.loadMigrationState(name)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]MigrationStep, 0)
+	for _, mf := range migrations {
+		if _, ok := applied[mf.UpFilename()]; ok {
+			continue // 이미 실행됨
+		}
+		sql, checksum, err := readMigration(mf.UpPath)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, MigrationStep{
+			Filename:  mf.UpFilename(),
+			Direction: MigrationUp,
+			Checksum:  checksum,
+			SQL:       sql,
+		})
+	}
+	return steps, nil
+}
+
+// RollbackMigrations 적용된 마이그레이션을 역순으로 down 실행
+// dryRun이면 실행하지 않고 실행될 SQL만 반환한다. down 파일이 없는 버전이 있으면 아무것도 실행하지 않는다.
+func (m *Manager) RollbackMigrations(name string, dryRun bool) ([]MigrationStep, error) {
+	return m.rollbackMigrations(name, nil, dryRun)
+}
+
+// rollbackMigrations only에 있는 up 파일만 롤백 (nil이면 적용된 전체)
+func (m *Manager) rollbackMigrations(name string, only map[string]bool, dryRun bool) ([]MigrationStep, error) {
+	m.mu.RLock()
+	info, exists := m.plugins[name]
+	m.mu.RUnlock()
+
+	if exists && info.IsBuiltIn {
+		return []MigrationStep{}, nil // 내장 플러그인은 Migrate()로 관리
+	}
+
+	migrations, applied, err := m.loadMigrationState(name)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]MigrationStep, 0)
+	upFilenames := make([]string, 0)
+	for i := len(migrations) - 1; i >= 0; i-- {
+		mf := migrations[i]
+		if _, ok := applied[mf.UpFilename()]; !ok {
+			continue
+		}
+		if only != nil && !only[mf.UpFilename()] {
+			continue
+		}
+		if mf.DownPath == "" {
+			return nil, fmt.Errorf("마이그레이션 %s/%s에 down 파일이 없습니다", name, mf.UpFilename())
+		}
+		sql, _, err := readMigration(mf.DownPath)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, MigrationStep{
+			Filename:  filepath.Base(mf.DownPath),
+			Direction: MigrationDown,
+			SQL:       sql,
+		})
+		upFilenames = append(upFilenames, mf.UpFilename())
+	}
+
+	if dryRun {
+		for _, step := range steps {
+			m.logger.Info("[dry-run] %s/%s:\n%s", name, step.Filename, step.SQL)
+		}
+		return steps, nil
+	}
+
+	for i, step := range steps {
+		err := m.inMigrationTx(func(tx *gorm.DB) error {
+			if err := tx.Exec(step.SQL).Error; err != nil {
+				return fmt.Errorf("마이그레이션 롤백 실패 %s: %w", step.Filename, err)
+			}
+			return tx.Where("plugin_name = ? AND filename = ?", name, upFilenames[i]).
+				Delete(&PluginMigrationRecord{}).Error
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		m.logger.Info("마이그레이션 롤백 완료: %s/%s", name, step.Filename)
+	}
+
+	return steps, nil
+}
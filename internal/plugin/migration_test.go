@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -148,3 +149,153 @@ func TestRunMigrations_PluginNotFound(t *testing.T) {
 		t.Error("존재하지 않는 플러그인에 대해 에러 예상")
 	}
 }
+
+func newMigrationTestManager(t *testing.T, db *gorm.DB, files map[string]string) (*Manager, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	pluginDir := filepath.Join(tmpDir, "test-plugin")
+	os.MkdirAll(pluginDir, 0o755)
+	setupMigrationFiles(t, pluginDir, files)
+
+	manager := NewManager(tmpDir, db, nil, NewDefaultLogger("test"), nil, nil)
+	manager.plugins["test-plugin"] = &PluginInfo{
+		Manifest: &PluginManifest{Name: "test-plugin", Version: "1.0.0"},
+		Path:     pluginDir,
+		Status:   StatusDisabled,
+	}
+	return manager, pluginDir
+}
+
+func TestRunMigrations_SkipsDownFilesAndStoresChecksum(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	manager, _ := newMigrationTestManager(t, db, map[string]string{
+		"001_init.up.sql":   "CREATE TABLE down_items (id INTEGER PRIMARY KEY);",
+		"001_init.down.sql": "DROP TABLE down_items;",
+	})
+
+	if err := manager.RunMigrations("test-plugin"); err != nil {
+		t.Fatalf("RunMigrations 실패: %v", err)
+	}
+
+	var records []PluginMigrationRecord
+	db.Where("plugin_name = ?", "test-plugin").Find(&records)
+	if len(records) != 1 || records[0].Filename != "001_init.up.sql" {
+		t.Fatalf("up 파일 1건만 기록 예상, %+v", records)
+	}
+	if len(records[0].Checksum) != 64 {
+		t.Errorf("SHA-256 체크섬 기록 예상, %q", records[0].Checksum)
+	}
+	if !db.Migrator().HasTable("down_items") {
+		t.Error("down 파일이 실행되어 테이블이 삭제됨")
+	}
+}
+
+func TestRunMigrations_ChecksumMismatch(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	manager, pluginDir := newMigrationTestManager(t, db, map[string]string{
+		"001_init.up.sql": "CREATE TABLE sum_items (id INTEGER PRIMARY KEY);",
+	})
+	if err := manager.RunMigrations("test-plugin"); err != nil {
+		t.Fatalf("RunMigrations 실패: %v", err)
+	}
+
+	// 적용된 파일 수정 + 새 파일 추가
+	setupMigrationFiles(t, pluginDir, map[string]string{
+		"001_init.up.sql": "CREATE TABLE sum_items (id INTEGER PRIMARY KEY, name TEXT);",
+		"002_next.up.sql": "CREATE TABLE next_items (id INTEGER PRIMARY KEY);",
+	})
+
+	err := manager.RunMigrations("test-plugin")
+	if !errors.Is(err, ErrMigrationChecksumMismatch) {
+		t.Fatalf("체크섬 불일치 에러 예상, %v", err)
+	}
+	if db.Migrator().HasTable("next_items") {
+		t.Error("체크섬 불일치 시 새 마이그레이션이 실행되면 안 됨")
+	}
+	if err := manager.Enable("test-plugin"); err == nil {
+		t.Error("체크섬 불일치 시 활성화 거부 예상")
+	}
+}
+
+func TestRunMigrations_FailedMigrationRollsBack(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	manager, _ := newMigrationTestManager(t, db, map[string]string{
+		"001_broken.up.sql": "CREATE TABLE tx_items (id INTEGER PRIMARY KEY); INSERT INTO missing_table VALUES (1);",
+	})
+
+	if err := manager.RunMigrations("test-plugin"); err == nil {
+		t.Fatal("실패한 마이그레이션에 대해 에러 예상")
+	}
+	if db.Migrator().HasTable("tx_items") {
+		t.Error("트랜잭션 롤백으로 테이블이 남지 않아야 함")
+	}
+	var count int64
+	db.Model(&PluginMigrationRecord{}).Where("plugin_name = ?", "test-plugin").Count(&count)
+	if count != 0 {
+		t.Errorf("실패한 마이그레이션 이력이 기록됨: %d건", count)
+	}
+}
+
+func TestPlanAndRollbackMigrations(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	manager, _ := newMigrationTestManager(t, db, map[string]string{
+		"001_init.up.sql":    "CREATE TABLE rb_items (id INTEGER PRIMARY KEY);",
+		"001_init.down.sql":  "DROP TABLE rb_items;",
+		"002_extra.up.sql":   "CREATE TABLE rb_extra (id INTEGER PRIMARY KEY);",
+		"002_extra.down.sql": "DROP TABLE rb_extra;",
+	})
+
+	plan, err := manager.PlanMigrations("test-plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 2 || plan[0].Filename != "001_init.up.sql" || plan[0].SQL == "" {
+		t.Fatalf("미실행 마이그레이션 2건 예상, %+v", plan)
+	}
+	if db.Migrator().HasTable("rb_items") {
+		t.Fatal("드라이런에서 SQL이 실행됨")
+	}
+
+	if err := manager.RunMigrations("test-plugin"); err != nil {
+		t.Fatal(err)
+	}
+
+	steps, err := manager.RollbackMigrations("test-plugin", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || steps[0].Filename != "002_extra.down.sql" {
+		t.Fatalf("역순 down 2건 예상, %+v", steps)
+	}
+	if !db.Migrator().HasTable("rb_extra") {
+		t.Fatal("드라이런 롤백에서 SQL이 실행됨")
+	}
+
+	if _, err := manager.RollbackMigrations("test-plugin", false); err != nil {
+		t.Fatal(err)
+	}
+	if db.Migrator().HasTable("rb_items") || db.Migrator().HasTable("rb_extra") {
+		t.Error("롤백 후 테이블이 남아 있음")
+	}
+	var count int64
+	db.Model(&PluginMigrationRecord{}).Where("plugin_name = ?", "test-plugin").Count(&count)
+	if count != 0 {
+		t.Errorf("롤백 후 이력 0건 예상, %d건", count)
+	}
+}
+
+func TestRollbackMigrations_MissingDownFile(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	manager, _ := newMigrationTestManager(t, db, map[string]string{
+		"001_init.up.sql": "CREATE TABLE nodown_items (id INTEGER PRIMARY KEY);",
+	})
+	if err := manager.RunMigrations("test-plugin"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.RollbackMigrations("test-plugin", false); err == nil {
+		t.Error("down 파일 누락 시 에러 예상")
+	}
+	if !db.Migrator().HasTable("nodown_items") {
+		t.Error("에러 시 아무것도 실행되지 않아야 함")
+	}
+}
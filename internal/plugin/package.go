@@ -0,0 +1,299 @@
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// 플러그인 패키지 (.tar.gz) 형식
+//
+//	plugin.yaml          매니페스트 (필수, 아카이브 루트)
+//	migrations/*.sql     마이그레이션 (선택)
+//	assets/...           정적 파일 (선택)
+//	<runtime.command>    외부 플러그인 실행 파일 (runtime 선언 시 필수)
+//
+// 서명은 개발자 Ed25519 키로 패키지 SHA-256 다이제스트(32바이트)에 서명한 값의 base64이다.
+// 일반 파일과 디렉토리만 허용되며 심볼릭 링크, 절대 경로, ".."는 거부된다.
+const (
+	MaxPackageSize     = 50 << 20  // 압축된 패키지 최대 크기
+	maxPackageUnpacked = 200 << 20 // 압축 해제 후 최대 크기
+	maxPackageFiles    = 2000
+	maxManifestSize    = 1 << 20
+)
+
+// 패키지 에러
+var (
+	ErrInvalidPackage   = errors.New("invalid plugin package")
+	ErrPackageSignature = errors.New("invalid plugin package signature")
+	ErrPluginExists     = errors.New("plugin already exists")
+)
+
+// pluginNamePattern 디렉토리 이름으로 쓰이는 플러그인 이름 (소문자, 숫자, 하이픈)
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,99}$`)
+
+// PackageInfo 검사를 통과한 패키지 정보
+type PackageInfo struct {
+	Manifest *PluginManifest
+	Checksum string // SHA-256 hex
+	Size     int64
+	Files    []string
+}
+
+// PackageChecksum 패키지 SHA-256 hex
+func PackageChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SignPackage 패키지 서명 (개발자 도구/테스트용)
+func SignPackage(priv ed25519.PrivateKey, data []byte) string {
+	sum := sha256.Sum256(data)
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(priv, sum[:]))
+}
+
+// VerifyPackageSignature 개발자 공개 키로 패키지 서명 검증
+func VerifyPackageSignature(pub ed25519.PublicKey, data []byte, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed signature", ErrPackageSignature)
+	}
+	sum := sha256.Sum256(data)
+	if !ed25519.Verify(pub, sum[:], sig) {
+		return ErrPackageSignature
+	}
+	return nil
+}
+
+// InspectPackage 패키지 구조, 크기, 매니페스트 검증
+func (l *Loader) InspectPackage(data []byte) (*PackageInfo, error) {
+	if len(data) > MaxPackageSize {
+		return nil, fmt.Errorf("%w: package exceeds %d bytes", ErrInvalidPackage, MaxPackageSize)
+	}
+
+	info := &PackageInfo{Checksum: PackageChecksum(data), Size: int64(len(data))}
+	var manifestData []byte
+	err := walkPackage(data, func(name string, hdr *tar.Header, r io.Reader) error {
+		if hdr.Typeflag == tar.TypeDir {
+			return nil
+		}
+		info.Files = append(info.Files, name)
+		if name != "plugin.yaml" {
+			return nil
+		}
+		b, err := io.ReadAll(io.LimitReader(r, maxManifestSize+1))
+		if err != nil {
+			return err
+		}
+		if len(b) > maxManifestSize {
+			return fmt.Errorf("%w: plugin.yaml too large", ErrInvalidPackage)
+		}
+		manifestData = b
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if manifestData == nil {
+		return nil, fmt.Errorf("%w: plugin.yaml not found at package root", ErrInvalidPackage)
+	}
+	manifest, err := l.ParseManifest(manifestData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPackage, err)
+	}
+	if manifest.Runtime != nil {
+		command := path.Clean(strings.TrimPrefix(filepath.ToSlash(manifest.Runtime.Command), "./"))
+		if !containsString(info.Files, command) {
+			return nil, fmt.Errorf("%w: runtime.command %s is not in the package", ErrInvalidPackage, manifest.Runtime.Command)
+		}
+	}
+	info.Manifest = manifest
+	return info, nil
+}
+
+// extractPackage 패키지를 dest 디렉토리에 풀기 (InspectPackage를 통과한 패키지만)
+func extractPackage(data []byte, dest string) error {
+	return walkPackage(data, func(name string, hdr *tar.Header, r io.Reader) error {
+		target := filepath.Join(dest, filepath.FromSlash(name))
+		if hdr.Typeflag == tar.TypeDir {
+			return os.MkdirAll(target, 0o755)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		mode := os.FileMode(0o644)
+		if hdr.Mode&0o111 != 0 {
+			mode = 0o755
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	})
+}
+
+// walkPackage 아카이브 항목을 경로/종류/크기 검사 후 fn에 전달
+func walkPackage(data []byte, fn func(name string, hdr *tar.Header, r io.Reader) error) error {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: not a gzip archive", ErrInvalidPackage)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	var (
+		files int
+		total int64
+	)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidPackage, err)
+		}
+
+		name, err := packagePath(hdr)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			continue
+		}
+		if files++; files > maxPackageFiles {
+			return fmt.Errorf("%w: more than %d entries", ErrInvalidPackage, maxPackageFiles)
+		}
+		if total += hdr.Size; total > maxPackageUnpacked {
+			return fmt.Errorf("%w: unpacked size exceeds %d bytes", ErrInvalidPackage, maxPackageUnpacked)
+		}
+		if err := fn(name, hdr, io.LimitReader(tr, hdr.Size)); err != nil {
+			return err
+		}
+	}
+}
+
+// packagePath 항목의 정규화된 상대 경로 (루트 자체는 "")
+func packagePath(hdr *tar.Header) (string, error) {
+	switch hdr.Typeflag {
+	case tar.TypeReg, tar.TypeDir:
+	default:
+		return "", fmt.Errorf("%w: %s is not a regular file or directory", ErrInvalidPackage, hdr.Name)
+	}
+	name := strings.TrimPrefix(hdr.Name, "./")
+	if name == "" || name == "." {
+		return "", nil
+	}
+	if strings.Contains(name, "\\") || path.IsAbs(name) {
+		return "", fmt.Errorf("%w: invalid path %s", ErrInvalidPackage, hdr.Name)
+	}
+	clean := path.Clean(name)
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("%w: path escapes package root: %s", ErrInvalidPackage, hdr.Name)
+	}
+	return clean, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// InspectPackage 패키지 검사 (설치 전 매니페스트 확인용)
+func (m *Manager) InspectPackage(data []byte) (*PackageInfo, error) {
+	return m.loader.InspectPackage(data)
+}
+
+// InstallPackage 패키지를 플러그인 디렉토리에 풀고 비활성 상태로 등록
+// 임시 디렉토리에 먼저 푼 뒤 rename하므로 실패해도 반쯤 풀린 플러그인이 남지 않는다.
+func (m *Manager) InstallPackage(data []byte) (*PluginInfo, error) {
+	pkg, err := m.loader.InspectPackage(data)
+	if err != nil {
+		return nil, err
+	}
+	name := pkg.Manifest.Name
+
+	if _, exists := m.GetPlugin(name); exists {
+		return nil, fmt.Errorf("%w: %s", ErrPluginExists, name)
+	}
+	dest := filepath.Join(m.loader.pluginsDir, name)
+	if _, err := os.Stat(dest); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPluginExists, dest)
+	}
+
+	staging, err := m.stagePackage(name, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(staging, dest); err != nil {
+		_ = os.RemoveAll(staging) //nolint:errcheck // 정리 실패는 원래 에러가 우선
+		return nil, err
+	}
+
+	info := &PluginInfo{Manifest: pkg.Manifest, Path: dest, Status: StatusDisabled}
+	if err := m.addDiscovered(info); err != nil {
+		_ = os.RemoveAll(dest) //nolint:errcheck // 정리 실패는 원래 에러가 우선
+		return nil, err
+	}
+	m.logger.Info("Installed plugin package: %s v%s (%s)", name, pkg.Manifest.Version, pkg.Checksum)
+	return info, nil
+}
+
+// stagePackage 플러그인 디렉토리 안의 임시 디렉토리(.install-*)에 패키지 풀기
+func (m *Manager) stagePackage(name string, data []byte) (string, error) {
+	if err := os.MkdirAll(m.loader.pluginsDir, 0o755); err != nil {
+		return "", err
+	}
+	staging, err := os.MkdirTemp(m.loader.pluginsDir, ".install-"+name+"-")
+	if err != nil {
+		return "", err
+	}
+	if err = os.Chmod(staging, 0o755); err == nil {
+		err = extractPackage(data, staging)
+	}
+	if err != nil {
+		_ = os.RemoveAll(staging) //nolint:errcheck // 정리 실패는 원래 에러가 우선
+		return "", err
+	}
+	return staging, nil
+}
+
+// RemovePackage InstallPackage로 설치한 비활성 플러그인 제거 (설치 실패 시 롤백용)
+func (m *Manager) RemovePackage(name string) error {
+	m.mu.Lock()
+	info, ok := m.plugins[name]
+	if !ok || info.IsBuiltIn || info.Path == "" {
+		m.mu.Unlock()
+		return fmt.Errorf("plugin %s is not an installed package", name)
+	}
+	if info.Status == StatusEnabled {
+		m.mu.Unlock()
+		return fmt.Errorf("plugin %s is enabled", name)
+	}
+	delete(m.plugins, name)
+	m.mu.Unlock()
+
+	return os.RemoveAll(info.Path)
+}
@@ -0,0 +1,143 @@
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPackageManifest = `name: hello
+version: 1.2.0
+title: Hello
+requires:
+  angple: ">=1.0.0"
+`
+
+type testEntry struct {
+	name string
+	body string
+	typ  byte
+}
+
+func buildTestPackage(t *testing.T, entries ...testEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.body)), Typeflag: e.typ}
+		if e.typ == 0 {
+			hdr.Typeflag = tar.TypeReg
+		}
+		if e.typ == tar.TypeSymlink {
+			hdr.Linkname, hdr.Size = e.body, 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(e.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestInspectPackage(t *testing.T) {
+	l := NewLoader("")
+	data := buildTestPackage(t,
+		testEntry{name: "./plugin.yaml", body: testPackageManifest},
+		testEntry{name: "migrations/001_init.sql", body: "CREATE TABLE hello_items (id INTEGER);"},
+	)
+	pkg, err := l.InspectPackage(data)
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	if pkg.Manifest.Name != "hello" || pkg.Checksum != PackageChecksum(data) || len(pkg.Files) != 2 {
+		t.Errorf("unexpected package info: %+v", pkg)
+	}
+
+	invalid := map[string][]byte{
+		"no manifest":  buildTestPackage(t, testEntry{name: "assets/a.css", body: "a{}"}),
+		"traversal":    buildTestPackage(t, testEntry{name: "plugin.yaml", body: testPackageManifest}, testEntry{name: "../evil.sh", body: "x"}),
+		"absolute":     buildTestPackage(t, testEntry{name: "plugin.yaml", body: testPackageManifest}, testEntry{name: "/etc/cron.d/x", body: "x"}),
+		"symlink":      buildTestPackage(t, testEntry{name: "plugin.yaml", body: testPackageManifest}, testEntry{name: "link", body: "/etc/passwd", typ: tar.TypeSymlink}),
+		"runtime":      buildTestPackage(t, testEntry{name: "plugin.yaml", body: testPackageManifest + "runtime:\n  command: ./bin/hello\n"}),
+		"invalid name": buildTestPackage(t, testEntry{name: "plugin.yaml", body: "name: ../hello\nversion: 1.0.0\ntitle: x\nrequires:\n  angple: \">=1.0.0\"\n"}),
+		"not gzip":     []byte("plugin.yaml"),
+	}
+	for name, data := range invalid {
+		if _, err := l.InspectPackage(data); !errors.Is(err, ErrInvalidPackage) {
+			t.Errorf("%s: expected ErrInvalidPackage, got %v", name, err)
+		}
+	}
+}
+
+func TestPackageSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buildTestPackage(t, testEntry{name: "plugin.yaml", body: testPackageManifest})
+	sig := SignPackage(priv, data)
+
+	if err := VerifyPackageSignature(pub, data, sig); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+	tampered := append([]byte{}, data...)
+	tampered[len(tampered)-1] ^= 0xff
+	if err := VerifyPackageSignature(pub, tampered, sig); !errors.Is(err, ErrPackageSignature) {
+		t.Errorf("expected tampered package to fail, got %v", err)
+	}
+	if err := VerifyPackageSignature(pub, data, "not-base64"); !errors.Is(err, ErrPackageSignature) {
+		t.Errorf("expected malformed signature to fail, got %v", err)
+	}
+}
+
+func TestManager_InstallPackage(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(dir, nil, nil, NewDefaultLogger("test"), nil, nil)
+	data := buildTestPackage(t,
+		testEntry{name: "plugin.yaml", body: testPackageManifest},
+		testEntry{name: "assets/", typ: tar.TypeDir},
+		testEntry{name: "assets/app.js", body: "console.log(1)"},
+	)
+
+	info, err := m.InstallPackage(data)
+	if err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if info.Path != filepath.Join(dir, "hello") || info.Status != StatusDisabled {
+		t.Errorf("unexpected plugin info: %+v", info)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "hello", "assets", "app.js")); err != nil {
+		t.Errorf("expected assets to be extracted: %v", err)
+	}
+	if _, err := m.InstallPackage(data); !errors.Is(err, ErrPluginExists) {
+		t.Errorf("expected ErrPluginExists on reinstall, got %v", err)
+	}
+
+	if err := m.RemovePackage("hello"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, ok := m.GetPlugin("hello"); ok {
+		t.Error("expected plugin to be unregistered")
+	}
+	entries, _ := os.ReadDir(dir) //nolint:errcheck // empty on error
+	if len(entries) != 0 {
+		t.Errorf("expected plugins dir to be empty, got %d entries", len(entries))
+	}
+}
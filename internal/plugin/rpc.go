@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/damoang/angple-backend/pkg/pluginsdk"
+)
+
+// ErrPluginNotRunning 외부 플러그인 프로세스가 실행 중이 아님
+var ErrPluginNotRunning = errors.New("plugin process is not running")
+
+// rpcConn 외부 플러그인과의 줄 단위 JSON-RPC 연결 (호스트 측 클라이언트)
+type rpcConn struct {
+	w   io.Writer
+	wmu sync.Mutex
+
+	mu      sync.Mutex
+	seq     int64
+	pending map[int64]chan *pluginsdk.Message
+	closed  bool
+}
+
+// newRPCConn r에서 응답을 읽는 goroutine을 시작하고 연결 반환
+func newRPCConn(r io.Reader, w io.Writer, logger Logger, pluginName string) *rpcConn {
+	c := &rpcConn{w: w, pending: make(map[int64]chan *pluginsdk.Message)}
+	go c.readLoop(r, logger, pluginName)
+	return c
+}
+
+// readLoop 응답을 대기 중인 호출에 전달 (EOF 시 연결 종료)
+func (c *rpcConn) readLoop(r io.Reader, logger Logger, pluginName string) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var msg pluginsdk.Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			logger.Warn("Plugin %s sent invalid message: %v", pluginName, err)
+			continue
+		}
+		if msg.ID == nil {
+			continue
+		}
+		c.mu.Lock()
+		ch, ok := c.pending[*msg.ID]
+		delete(c.pending, *msg.ID)
+		c.mu.Unlock()
+		if ok {
+			ch <- &msg
+		}
+	}
+	c.close()
+}
+
+// close 대기 중인 모든 호출을 ErrPluginNotRunning으로 종료
+func (c *rpcConn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+}
+
+// Call 요청을 보내고 응답을 result에 디코딩 (result가 nil이면 무시)
+func (c *rpcConn) Call(ctx context.Context, method string, params, result interface{}) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("plugin rpc %s: %w", method, err)
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrPluginNotRunning
+	}
+	c.seq++
+	id := c.seq
+	ch := make(chan *pluginsdk.Message, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	data, err := json.Marshal(&pluginsdk.Message{JSONRPC: "2.0", ID: &id, Method: method, Params: raw})
+	if err != nil {
+		c.forget(id)
+		return fmt.Errorf("plugin rpc %s: %w", method, err)
+	}
+	c.wmu.Lock()
+	_, err = c.w.Write(append(data, '\n'))
+	c.wmu.Unlock()
+	if err != nil {
+		c.forget(id)
+		return fmt.Errorf("plugin rpc %s: %w: %w", method, ErrPluginNotRunning, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		c.forget(id)
+		return fmt.Errorf("plugin rpc %s: %w", method, ctx.Err())
+	case msg, ok := <-ch:
+		if !ok {
+			return ErrPluginNotRunning
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result == nil || len(msg.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(msg.Result, result)
+	}
+}
+
+func (c *rpcConn) forget(id int64) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
@@ -1,54 +1,182 @@
 package plugin
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
 	"sync"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultTaskTimeout 작업 기본 타임아웃
+	DefaultTaskTimeout = 5 * time.Minute
+
+	schedulerTickInterval = 10 * time.Second
+	schedulerLeaderTTL    = 3 * schedulerTickInterval
+)
+
+// 실행 트리거
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
 )
 
+// 실행 결과 상태
+const (
+	RunStatusRunning = "running"
+	RunStatusSuccess = "success"
+	RunStatusFailed  = "failed"
+	RunStatusTimeout = "timeout"
+)
+
+var (
+	// ErrTaskNotFound 등록되지 않은 작업
+	ErrTaskNotFound = errors.New("scheduled task not found")
+	// ErrTaskRunning 이미 실행 중인 작업
+	ErrTaskRunning = errors.New("scheduled task is already running")
+	// ErrTaskTimeout 작업 타임아웃
+	ErrTaskTimeout = errors.New("scheduled task timed out")
+)
+
+// TaskOptions 작업 등록 옵션 (Interval 또는 Cron 중 하나)
+type TaskOptions struct {
+	Interval time.Duration
+	Cron     string        // 5필드 cron 표현식 (예: "0 4 * * *")
+	Timezone string        // cron 기준 타임존 (기본 Asia/Seoul)
+	Timeout  time.Duration // 실행 제한 시간 (기본 5분)
+}
+
 // ScheduledTask 등록된 주기적 작업
 type ScheduledTask struct {
 	Name       string
 	PluginName string
 	Interval   time.Duration
-	Handler    func() error
+	Cron       *CronSchedule
+	Timeout    time.Duration
+	Handler    func(ctx context.Context) error
 	LastRun    time.Time
 	NextRun    time.Time
 	RunCount   int64
 	LastError  error
+	running    bool
 }
 
-// Scheduler 플러그인 스케줄러 (in-process)
+// next now 이후 다음 실행 시각
+func (t *ScheduledTask) next(now time.Time) time.Time {
+	if t.Cron != nil {
+		return t.Cron.Next(now)
+	}
+	return now.Add(t.Interval)
+}
+
+// Scheduler 플러그인 스케줄러
+// locker가 설정되면 리더 노드만 예약 작업을 실행하고, history DB가 설정되면 실행 이력을 저장한다.
 type Scheduler struct {
-	tasks  []*ScheduledTask
-	mu     sync.RWMutex
-	logger Logger
-	stop   chan struct{}
-	wg     sync.WaitGroup
+	tasks   []*ScheduledTask
+	mu      sync.RWMutex
+	logger  Logger
+	locker  RunLocker
+	db      *gorm.DB
+	nodeID  string
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	running sync.WaitGroup
 }
 
 // NewScheduler 스케줄러 생성
 func NewScheduler(logger Logger) *Scheduler {
+	nodeID, err := os.Hostname()
+	if err != nil || nodeID == "" {
+		nodeID = "node"
+	}
+	nodeID += ":" + strconv.Itoa(os.Getpid())
+
 	return &Scheduler{
 		tasks:  make([]*ScheduledTask, 0),
 		logger: logger,
+		nodeID: nodeID,
 		stop:   make(chan struct{}),
 	}
 }
 
+// SetLocker 클러스터 잠금 설정 (없으면 모든 노드가 실행)
+func (s *Scheduler) SetLocker(locker RunLocker) {
+	s.locker = locker
+}
+
+// SetHistoryDB 실행 이력 저장소 설정
+func (s *Scheduler) SetHistoryDB(db *gorm.DB) {
+	s.db = db
+}
+
+// NodeID 이 노드의 식별자 (리더 리스 값)
+func (s *Scheduler) NodeID() string {
+	return s.nodeID
+}
+
+// MigrateHistory 실행 이력 테이블 생성
+func (s *Scheduler) MigrateHistory() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.AutoMigrate(&ScheduleRun{})
+}
+
 // Register 주기적 작업 등록
 func (s *Scheduler) Register(pluginName, taskName string, interval time.Duration, handler func() error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	//nolint:errcheck // 고정 주기는 항상 유효
+	_ = s.RegisterTask(pluginName, taskName, TaskOptions{Interval: interval}, func(context.Context) error {
+		return handler()
+	})
+}
 
-	s.tasks = append(s.tasks, &ScheduledTask{
+// RegisterCron cron 표현식 작업 등록 (Asia/Seoul, 기본 타임아웃)
+func (s *Scheduler) RegisterCron(pluginName, taskName, expr string, handler func(ctx context.Context) error) error {
+	return s.RegisterTask(pluginName, taskName, TaskOptions{Cron: expr}, handler)
+}
+
+// RegisterTask 옵션을 지정해 작업 등록
+func (s *Scheduler) RegisterTask(pluginName, taskName string, opts TaskOptions, handler func(ctx context.Context) error) error {
+	task := &ScheduledTask{
 		Name:       taskName,
 		PluginName: pluginName,
-		Interval:   interval,
+		Interval:   opts.Interval,
+		Timeout:    opts.Timeout,
 		Handler:    handler,
-		NextRun:    time.Now().Add(interval),
-	})
+	}
+	if task.Timeout <= 0 {
+		task.Timeout = DefaultTaskTimeout
+	}
+
+	switch {
+	case opts.Cron != "":
+		loc, err := LoadScheduleLocation(opts.Timezone)
+		if err != nil {
+			return err
+		}
+		if task.Cron, err = ParseCron(opts.Cron, loc); err != nil {
+			return err
+		}
+	case opts.Interval <= 0:
+		return fmt.Errorf("task %s/%s: interval or cron is required", pluginName, taskName)
+	}
+	task.NextRun = task.next(time.Now())
 
-	s.logger.Info("Scheduled task registered: %s/%s (every %s)", pluginName, taskName, interval)
+	s.mu.Lock()
+	s.tasks = append(s.tasks, task)
+	s.mu.Unlock()
+
+	if task.Cron != nil {
+		s.logger.Info("Scheduled task registered: %s/%s (cron %q %s)", pluginName, taskName, task.Cron, task.Cron.Location())
+	} else {
+		s.logger.Info("Scheduled task registered: %s/%s (every %s)", pluginName, taskName, opts.Interval)
+	}
+	return nil
 }
 
 // Unregister 플러그인의 모든 작업 해제
@@ -70,7 +198,7 @@ func (s *Scheduler) Start() {
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
-		ticker := time.NewTicker(30 * time.Second)
+		ticker := time.NewTicker(schedulerTickInterval)
 		defer ticker.Stop()
 
 		for {
@@ -78,45 +206,183 @@ func (s *Scheduler) Start() {
 			case <-s.stop:
 				return
 			case now := <-ticker.C:
-				s.tick(now)
+				// 오래 걸리는 작업이 다음 틱을 막지 않도록 비동기 실행 (중복 실행은 running 플래그로 방지)
+				s.wg.Add(1)
+				go func() {
+					defer s.wg.Done()
+					s.tick(now)
+				}()
 			}
 		}
 	}()
-	s.logger.Info("Plugin scheduler started")
+
+	if s.locker == nil {
+		s.logger.Warn("Plugin scheduler started without cluster lock: tasks run on every replica")
+	} else {
+		s.logger.Info("Plugin scheduler started (node %s)", s.nodeID)
+	}
 }
 
-// Stop 스케줄러 중지
+// Stop 스케줄러 중지 (실행 중인 작업 완료 대기)
 func (s *Scheduler) Stop() {
 	close(s.stop)
 	s.wg.Wait()
+	s.running.Wait()
 	s.logger.Info("Plugin scheduler stopped")
 }
 
-// tick 실행 대상 작업 체크 및 실행
+// tick 실행 대상 작업 체크 및 실행 (due 작업은 병렬 실행 후 완료 대기)
 func (s *Scheduler) tick(now time.Time) {
-	s.mu.RLock()
-	tasks := make([]*ScheduledTask, len(s.tasks))
-	copy(tasks, s.tasks)
-	s.mu.RUnlock()
+	leader := s.isLeader()
+
+	type dueRun struct {
+		task        *ScheduledTask
+		scheduledAt time.Time
+	}
+	var due []dueRun
 
-	for _, task := range tasks {
+	s.mu.Lock()
+	for _, task := range s.tasks {
 		if now.Before(task.NextRun) {
 			continue
 		}
+		scheduledAt := task.NextRun
+		// 리더가 아니어도 NextRun은 진행시켜 리더 교체 시 지난 실행이 재발하지 않게 한다
+		task.NextRun = task.next(now)
+		if !leader || task.running {
+			continue
+		}
+		due = append(due, dueRun{task: task, scheduledAt: scheduledAt})
+	}
+	s.mu.Unlock()
 
-		s.logger.Info("Running scheduled task: %s/%s", task.PluginName, task.Name)
+	var wg sync.WaitGroup
+	for _, d := range due {
+		if !s.claim(d.task, d.scheduledAt) {
+			continue
+		}
+		if !s.markRunning(d.task) {
+			continue
+		}
+		wg.Add(1)
+		go func(task *ScheduledTask, scheduledAt time.Time) {
+			defer wg.Done()
+			s.execute(task, TriggerSchedule, scheduledAt)
+		}(d.task, d.scheduledAt)
+	}
+	wg.Wait()
+}
 
-		if err := task.Handler(); err != nil {
-			s.logger.Error("Scheduled task error [%s/%s]: %v", task.PluginName, task.Name, err)
-			task.LastError = err
-		} else {
-			task.LastError = nil
+// isLeader 리더 리스 확인 (잠금이 없으면 항상 리더, Redis 오류 시 실행 보류)
+func (s *Scheduler) isLeader() bool {
+	if s.locker == nil {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	ok, err := s.locker.AcquireLeader(ctx, schedulerLeaderTTL)
+	if err != nil {
+		s.logger.Warn("Scheduler leader lease failed: %v", err)
+		return false
+	}
+	return ok
+}
+
+// claim 예약 실행 선점 (리더 교체 직후 두 노드가 같은 실행을 집는 경우 방지)
+func (s *Scheduler) claim(task *ScheduledTask, scheduledAt time.Time) bool {
+	if s.locker == nil {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	key := fmt.Sprintf("%s:%s:%d", task.PluginName, task.Name, scheduledAt.Unix())
+	ok, err := s.locker.ClaimRun(ctx, key, task.Timeout+schedulerLeaderTTL)
+	if err != nil {
+		s.logger.Warn("Scheduled task claim failed [%s/%s]: %v", task.PluginName, task.Name, err)
+		return false
+	}
+	return ok
+}
+
+// markRunning 실행 중 플래그 설정 (이미 실행 중이면 false)
+func (s *Scheduler) markRunning(task *ScheduledTask) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if task.running {
+		return false
+	}
+	task.running = true
+	return true
+}
+
+// RunNow 작업 즉시 실행 (비동기, 리더 여부와 무관하게 요청받은 노드에서 실행)
+func (s *Scheduler) RunNow(pluginName, taskName string) error {
+	s.mu.RLock()
+	var task *ScheduledTask
+	for _, t := range s.tasks {
+		if t.PluginName == pluginName && t.Name == taskName {
+			task = t
+			break
 		}
+	}
+	s.mu.RUnlock()
 
-		task.LastRun = now
-		task.NextRun = now.Add(task.Interval)
-		task.RunCount++
+	if task == nil {
+		return ErrTaskNotFound
+	}
+	if !s.markRunning(task) {
+		return ErrTaskRunning
 	}
+
+	go s.execute(task, TriggerManual, time.Now())
+	return nil
+}
+
+// execute SafeCall + 타임아웃으로 작업 실행 후 상태/이력 기록 (호출 전 running 설정 필요)
+func (s *Scheduler) execute(task *ScheduledTask, trigger string, scheduledAt time.Time) {
+	s.running.Add(1)
+	defer s.running.Done()
+
+	s.logger.Info("Running scheduled task: %s/%s (%s)", task.PluginName, task.Name, trigger)
+	run := s.recordStart(task, trigger, scheduledAt)
+	started := time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), task.Timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		err := SafeCall(task.PluginName, s.logger, func() error { return task.Handler(ctx) })
+		// 타임아웃 이후에도 핸들러가 실제로 끝날 때까지 재실행하지 않는다
+		s.mu.Lock()
+		task.running = false
+		s.mu.Unlock()
+		done <- err
+	}()
+
+	var err error
+	status := RunStatusSuccess
+	select {
+	case err = <-done:
+		if err != nil {
+			status = RunStatusFailed
+		}
+	case <-ctx.Done():
+		err = fmt.Errorf("%w after %s", ErrTaskTimeout, task.Timeout)
+		status = RunStatusTimeout
+	}
+
+	if err != nil {
+		s.logger.Error("Scheduled task error [%s/%s]: %v", task.PluginName, task.Name, err)
+	}
+
+	s.mu.Lock()
+	task.LastError = err
+	task.LastRun = started
+	task.RunCount++
+	s.mu.Unlock()
+
+	s.recordFinish(run, status, err, time.Since(started))
 }
 
 // GetTasks 등록된 작업 목록 조회 (모니터링용)
@@ -129,10 +395,17 @@ func (s *Scheduler) GetTasks() []ScheduledTaskInfo {
 		info := ScheduledTaskInfo{
 			Name:       t.Name,
 			PluginName: t.PluginName,
-			Interval:   t.Interval.String(),
+			Timeout:    t.Timeout.String(),
 			LastRun:    t.LastRun,
 			NextRun:    t.NextRun,
 			RunCount:   t.RunCount,
+			Running:    t.running,
+		}
+		if t.Cron != nil {
+			info.Cron = t.Cron.String()
+			info.Timezone = t.Cron.Location().String()
+		} else {
+			info.Interval = t.Interval.String()
 		}
 		if t.LastError != nil {
 			errMsg := t.LastError.Error()
@@ -147,9 +420,13 @@ func (s *Scheduler) GetTasks() []ScheduledTaskInfo {
 type ScheduledTaskInfo struct {
 	Name       string    `json:"name"`
 	PluginName string    `json:"plugin_name"`
-	Interval   string    `json:"interval"`
+	Interval   string    `json:"interval,omitempty"`
+	Cron       string    `json:"cron,omitempty"`
+	Timezone   string    `json:"timezone,omitempty"`
+	Timeout    string    `json:"timeout"`
 	LastRun    time.Time `json:"last_run"`
 	NextRun    time.Time `json:"next_run"`
 	RunCount   int64     `json:"run_count"`
+	Running    bool      `json:"running"`
 	LastError  *string   `json:"last_error,omitempty"`
 }
@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"time"
+)
+
+// ScheduleRun 스케줄 작업 실행 이력 (GORM 모델)
+type ScheduleRun struct {
+	ID          int64      `gorm:"primaryKey" json:"id"`
+	PluginName  string     `gorm:"size:100;index:idx_schedule_run_task" json:"plugin_name"`
+	TaskName    string     `gorm:"size:100;index:idx_schedule_run_task" json:"task_name"`
+	Trigger     string     `gorm:"size:20" json:"trigger"`
+	Status      string     `gorm:"size:20;index" json:"status"`
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+	Node        string     `gorm:"size:150" json:"node"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	StartedAt   time.Time  `gorm:"index" json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+}
+
+func (ScheduleRun) TableName() string {
+	return "plugin_schedule_runs"
+}
+
+// recordStart 실행 시작 기록 (저장소가 없거나 실패하면 nil)
+func (s *Scheduler) recordStart(task *ScheduledTask, trigger string, scheduledAt time.Time) *ScheduleRun {
+	if s.db == nil {
+		return nil
+	}
+	run := &ScheduleRun{
+		PluginName:  task.PluginName,
+		TaskName:    task.Name,
+		Trigger:     trigger,
+		Status:      RunStatusRunning,
+		Node:        s.nodeID,
+		ScheduledAt: scheduledAt,
+		StartedAt:   time.Now(),
+	}
+	if err := s.db.Create(run).Error; err != nil {
+		s.logger.Warn("Failed to record schedule run [%s/%s]: %v", task.PluginName, task.Name, err)
+		return nil
+	}
+	return run
+}
+
+// recordFinish 실행 결과 기록
+func (s *Scheduler) recordFinish(run *ScheduleRun, status string, runErr error, elapsed time.Duration) {
+	if run == nil {
+		return
+	}
+	finished := run.StartedAt.Add(elapsed)
+	updates := map[string]interface{}{
+		"status":      status,
+		"finished_at": finished,
+		"duration_ms": elapsed.Milliseconds(),
+	}
+	if runErr != nil {
+		updates["error"] = runErr.Error()
+	}
+	if err := s.db.Model(run).Updates(updates).Error; err != nil {
+		s.logger.Warn("Failed to update schedule run %d: %v", run.ID, err)
+	}
+}
+
+// ListRuns 실행 이력 조회 (최신순, pluginName/taskName이 비어 있으면 전체)
+func (s *Scheduler) ListRuns(pluginName, taskName string, limit int) ([]ScheduleRun, error) {
+	runs := []ScheduleRun{}
+	if s.db == nil {
+		return runs, nil
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	q := s.db.Model(&ScheduleRun{})
+	if pluginName != "" {
+		q = q.Where("plugin_name = ?", pluginName)
+	}
+	if taskName != "" {
+		q = q.Where("task_name = ?", taskName)
+	}
+	err := q.Order("id DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
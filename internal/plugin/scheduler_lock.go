@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RunLocker 클러스터 전역 스케줄 잠금
+// 리더 리스를 가진 노드만 예약 작업을 실행하고, 각 예약 실행(작업+예정 시각)은 한 번만 선점된다.
+type RunLocker interface {
+	// AcquireLeader 리더 리스 획득 또는 갱신 (이미 리더면 TTL 연장)
+	AcquireLeader(ctx context.Context, ttl time.Duration) (bool, error)
+	// ClaimRun 예약 실행 선점. 다른 노드가 먼저 선점했으면 false
+	ClaimRun(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+const (
+	schedulerLeaderKey = "plugin:scheduler:leader"
+	schedulerRunPrefix = "plugin:scheduler:run:"
+)
+
+// acquireLeaderScript 자신이 리더면 TTL 연장, 아니면 빈 키일 때만 획득
+var acquireLeaderScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if current == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+if current then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return 1
+`)
+
+// RedisRunLocker Redis 기반 RunLocker
+type RedisRunLocker struct {
+	redis  *redis.Client
+	nodeID string
+}
+
+// NewRedisRunLocker 생성자. nodeID는 노드(파드)를 식별하는 고유 값
+func NewRedisRunLocker(redisClient *redis.Client, nodeID string) *RedisRunLocker {
+	return &RedisRunLocker{redis: redisClient, nodeID: nodeID}
+}
+
+// AcquireLeader 리더 리스 획득/갱신
+func (l *RedisRunLocker) AcquireLeader(ctx context.Context, ttl time.Duration) (bool, error) {
+	ok, err := acquireLeaderScript.Run(ctx, l.redis, []string{schedulerLeaderKey}, l.nodeID, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return ok == 1, nil
+}
+
+// ClaimRun 예약 실행 선점 (SET NX)
+func (l *RedisRunLocker) ClaimRun(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return l.redis.SetNX(ctx, schedulerRunPrefix+key, l.nodeID, ttl).Result()
+}
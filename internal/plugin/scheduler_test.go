@@ -1,9 +1,13 @@
 package plugin
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestScheduler_RegisterAndGetTasks(t *testing.T) {
@@ -100,3 +104,135 @@ func TestScheduler_SkipNotReady(t *testing.T) {
 		t.Error("expected handler NOT called for future task")
 	}
 }
+
+// fakeLocker 리더 여부와 선점 키를 메모리로 흉내
+type fakeLocker struct {
+	leader bool
+	claims map[string]bool
+}
+
+func (l *fakeLocker) AcquireLeader(context.Context, time.Duration) (bool, error) {
+	return l.leader, nil
+}
+
+func (l *fakeLocker) ClaimRun(_ context.Context, key string, _ time.Duration) (bool, error) {
+	if l.claims[key] {
+		return false, nil
+	}
+	l.claims[key] = true
+	return true, nil
+}
+
+func TestScheduler_LeaderLease(t *testing.T) {
+	locker := &fakeLocker{claims: map[string]bool{}}
+	s := NewScheduler(NewDefaultLogger("test"))
+	s.SetLocker(locker)
+
+	var count int
+	s.Register("test-plugin", "counter", time.Hour, func() error {
+		count++
+		return nil
+	})
+	scheduledAt := time.Now().Add(-time.Second)
+
+	// 리더가 아니면 실행하지 않고 NextRun만 진행
+	s.tasks[0].NextRun = scheduledAt
+	s.tick(time.Now())
+	if count != 0 {
+		t.Fatalf("expected follower not to run task, got %d", count)
+	}
+	if !s.tasks[0].NextRun.After(time.Now()) {
+		t.Error("expected follower to advance NextRun")
+	}
+
+	// 리더는 실행, 같은 예약 시각은 다시 선점되지 않음
+	locker.leader = true
+	s.tasks[0].NextRun = scheduledAt
+	s.tick(time.Now())
+	s.tasks[0].NextRun = scheduledAt
+	s.tick(time.Now())
+	if count != 1 {
+		t.Errorf("expected exactly one run per scheduled time, got %d", count)
+	}
+}
+
+func TestScheduler_TimeoutAndHistory(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewScheduler(NewDefaultLogger("test"))
+	s.SetHistoryDB(db)
+	if err := s.MigrateHistory(); err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	err = s.RegisterTask("test-plugin", "slow", TaskOptions{Cron: "* * * * *", Timeout: 20 * time.Millisecond}, func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.tasks[0].NextRun = time.Now().Add(-time.Second)
+	s.tick(time.Now())
+
+	if !errors.Is(s.tasks[0].LastError, ErrTaskTimeout) {
+		t.Errorf("expected timeout error, got %v", s.tasks[0].LastError)
+	}
+	// 핸들러가 끝나기 전에는 즉시 실행도 거부
+	if err := s.RunNow("test-plugin", "slow"); !errors.Is(err, ErrTaskRunning) {
+		t.Errorf("expected ErrTaskRunning, got %v", err)
+	}
+	close(release)
+
+	runs, err := s.ListRuns("test-plugin", "slow", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].Status != RunStatusTimeout || runs[0].Trigger != TriggerSchedule {
+		t.Fatalf("unexpected run history: %+v", runs)
+	}
+	if runs[0].FinishedAt == nil || runs[0].Error == "" {
+		t.Errorf("expected finished_at and error to be recorded, got %+v", runs[0])
+	}
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	s := NewScheduler(NewDefaultLogger("test"))
+	done := make(chan struct{})
+	s.Register("test-plugin", "manual", time.Hour, func() error {
+		close(done)
+		return nil
+	})
+
+	if err := s.RunNow("test-plugin", "missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+	if err := s.RunNow("test-plugin", "manual"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected manual run to execute")
+	}
+	s.running.Wait()
+	if s.GetTasks()[0].RunCount != 1 {
+		t.Errorf("expected RunCount 1, got %d", s.GetTasks()[0].RunCount)
+	}
+}
+
+func TestScheduler_RegisterTaskValidation(t *testing.T) {
+	s := NewScheduler(NewDefaultLogger("test"))
+	if err := s.RegisterTask("p", "t", TaskOptions{}, func(context.Context) error { return nil }); err == nil {
+		t.Error("expected error without interval or cron")
+	}
+	if err := s.RegisterCron("p", "t", "bad cron", func(context.Context) error { return nil }); err == nil {
+		t.Error("expected error for invalid cron")
+	}
+	if err := s.RegisterTask("p", "t", TaskOptions{Cron: "0 * * * *", Timezone: "Nowhere/City"}, func(context.Context) error { return nil }); err == nil {
+		t.Error("expected error for unknown timezone")
+	}
+}
@@ -0,0 +1,313 @@
+package plugin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// dbScopeKey 스코프 DB의 Statement 설정 키
+const dbScopeKey = "angple:plugin_scope"
+
+// dbScope 플러그인 DB 접근 범위
+type dbScope struct {
+	plugin string
+	caps   *Capabilities
+	report func(CapabilityViolation)
+}
+
+// tableAccess SQL이 접근하는 테이블과 쓰기 여부
+type tableAccess struct {
+	table string
+	write bool
+}
+
+// newScopedDB capabilities.tables 밖의 테이블 접근을 거부하는 DB 세션
+// 검사는 GORM 콜백이 설치한 연결 래퍼에서 드라이버로 보내는 최종 SQL을 대상으로 하므로
+// 빌더의 Select/Where/Order에 넣은 서브쿼리도 걸러진다.
+// NewDB 세션이나 db.DB()로 얻은 원시 연결은 범위를 벗어난다.
+// 완전한 격리가 필요한 플러그인은 외부 프로세스(runtime)로 실행한다.
+func newScopedDB(db *gorm.DB, scope *dbScope) (*gorm.DB, error) {
+	if err := registerScopeCallbacks(db); err != nil {
+		return nil, err
+	}
+	return db.Set(dbScopeKey, scope).Session(&gorm.Session{}), nil
+}
+
+// registerScopeCallbacks 스코프 검사 콜백 등록 (DB당 한 번)
+// 실행 콜백 직전에 연결을 감싸고 뒤에서 되돌린다. 쓰기 체인은 기본 트랜잭션 커밋이
+// 원래 연결(*sql.Tx)을 봐야 하므로 커밋 콜백 직전에 되돌린다.
+func registerScopeCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	if cb.Query().Get("angple:scope_query") != nil {
+		return nil
+	}
+	return errors.Join(
+		cb.Query().Before("gorm:query").Register("angple:scope_query", wrapScope),
+		cb.Query().After("gorm:query").Register("angple:unscope_query", unwrapScope),
+		cb.Row().Before("gorm:row").Register("angple:scope_row", wrapScope),
+		cb.Row().After("gorm:row").Register("angple:unscope_row", unwrapScope),
+		cb.Create().Before("gorm:create").Register("angple:scope_create", wrapScope),
+		cb.Create().Before("gorm:commit_or_rollback_transaction").Register("angple:unscope_create", unwrapScope),
+		cb.Update().Before("gorm:update").Register("angple:scope_update", wrapScope),
+		cb.Update().Before("gorm:commit_or_rollback_transaction").Register("angple:unscope_update", unwrapScope),
+		cb.Delete().Before("gorm:delete").Register("angple:scope_delete", wrapScope),
+		cb.Delete().Before("gorm:commit_or_rollback_transaction").Register("angple:unscope_delete", unwrapScope),
+		cb.Raw().Before("gorm:raw").Register("angple:scope_raw", wrapScope),
+		cb.Raw().After("gorm:raw").Register("angple:unscope_raw", unwrapScope),
+	)
+}
+
+// wrapScope 스코프 DB의 문장이면 연결을 scopedConnPool로 감싼다
+func wrapScope(db *gorm.DB) {
+	v, ok := db.Get(dbScopeKey)
+	if !ok {
+		return
+	}
+	scope, ok := v.(*dbScope)
+	if !ok {
+		return
+	}
+	if _, wrapped := db.Statement.ConnPool.(*scopedConnPool); !wrapped {
+		db.Statement.ConnPool = &scopedConnPool{ConnPool: db.Statement.ConnPool, scope: scope}
+	}
+}
+
+// unwrapScope wrapScope로 감싼 연결을 되돌린다
+func unwrapScope(db *gorm.DB) {
+	if pool, ok := db.Statement.ConnPool.(*scopedConnPool); ok {
+		db.Statement.ConnPool = pool.ConnPool
+	}
+}
+
+// scopedConnPool 드라이버로 보내기 직전의 SQL이 허용되지 않은 테이블에 접근하면 실행하지 않는다
+type scopedConnPool struct {
+	gorm.ConnPool
+	scope *dbScope
+}
+
+func (p *scopedConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if err := p.scope.check(query); err != nil {
+		return nil, err
+	}
+	return p.ConnPool.PrepareContext(ctx, query)
+}
+
+func (p *scopedConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := p.scope.check(query); err != nil {
+		return nil, err
+	}
+	return p.ConnPool.ExecContext(ctx, query, args...)
+}
+
+func (p *scopedConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := p.scope.check(query); err != nil {
+		return nil, err
+	}
+	return p.ConnPool.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext *sql.Row에는 에러를 넣을 수 없으므로 취소된 컨텍스트로 실행해 Scan이 실패하게 한다
+func (p *scopedConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if err := p.scope.check(query); err != nil {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		ctx = canceled
+	}
+	return p.ConnPool.QueryRowContext(ctx, query, args...)
+}
+
+// check SQL이 허용되지 않은 테이블에 접근하면 위반을 보고하고 에러 반환
+func (s *dbScope) check(query string) error {
+	for _, a := range sqlTables(query) {
+		allowed := s.caps.CanRead(a.table)
+		mode := "read"
+		if a.write {
+			allowed = s.caps.CanWrite(a.table)
+			mode = "write"
+		}
+		if allowed {
+			continue
+		}
+		violation := CapabilityViolation{Plugin: s.plugin, Kind: CapabilityDB, Target: a.table, Detail: mode}
+		s.report(violation)
+		return violation
+	}
+	return nil
+}
+
+// sqlTables 원시 SQL에서 FROM/JOIN(읽기), INTO/UPDATE/TABLE(쓰기) 뒤의 테이블 이름 추출
+func sqlTables(sql string) []tableAccess {
+	tokens := sqlTokens(sql)
+	var tables []tableAccess
+	verb := ""
+	for i := 0; i < len(tokens); i++ {
+		if i == 0 || tokens[i-1] == ";" {
+			verb = tokens[i]
+		}
+		write, ok := tableKeyword(tokens, i, verb)
+		if !ok {
+			continue
+		}
+		var names []string
+		names, i = tableNames(tokens, i, tokens[i] == "from")
+		for _, name := range names {
+			tables = append(tables, tableAccess{table: normalizeTable(name), write: write})
+		}
+	}
+	return tables
+}
+
+// tableKeyword tokens[i]가 테이블 이름 앞 키워드인지와 쓰기 여부
+func tableKeyword(tokens []string, i int, verb string) (write, ok bool) {
+	switch tokens[i] {
+	case "from":
+		return verb == "delete", true
+	case "join":
+		return false, true
+	case "into", "table", "truncate":
+		return true, true
+	case "update":
+		// FOR UPDATE, ON DUPLICATE KEY UPDATE는 제외
+		return true, verb == "update" && (i == 0 || tokens[i-1] == ";")
+	case "on":
+		// CREATE INDEX ... ON t
+		return true, verb == "create" || verb == "drop" || verb == "alter"
+	}
+	return false, false
+}
+
+// tableNames 키워드 tokens[i] 뒤의 테이블 이름 (list이면 "a, b AS x" 목록) 과 마지막 위치
+func tableNames(tokens []string, i int, list bool) ([]string, int) {
+	var names []string
+	for {
+		j := i + 1
+		for j < len(tokens) && isSQLModifier(tokens[j]) {
+			j++
+		}
+		if j >= len(tokens) || !isTableName(tokens[j]) {
+			return names, i
+		}
+		names = append(names, tokens[j])
+		i = j
+		if !list {
+			return names, i
+		}
+		k := j + 1
+		if k < len(tokens) && tokens[k] == "as" {
+			k++
+		}
+		if k < len(tokens) && isTableName(tokens[k]) {
+			k++
+		}
+		if k >= len(tokens) || tokens[k] != "," {
+			return names, i
+		}
+		i = k
+	}
+}
+
+// sqlTokens 소문자 토큰 목록 (문자열 리터럴과 주석은 제거, 따옴표 식별자는 하나의 토큰)
+func sqlTokens(sql string) []string {
+	var tokens []string
+	runes := []rune(sql)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '#' || hasPrefixAt(runes, i, "--"):
+			i = skipUntil(runes, i, "\n")
+		case hasPrefixAt(runes, i, "/*"):
+			i = skipUntil(runes, i+2, "*/")
+		case r == '\'':
+			i = skipString(runes, i)
+			tokens = append(tokens, "?")
+		case isIdentRune(r) || r == '`' || r == '"':
+			start := i
+			i = skipIdentifier(runes, i)
+			tokens = append(tokens, strings.ToLower(string(runes[start:i])))
+		default:
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+	return tokens
+}
+
+func hasPrefixAt(runes []rune, i int, prefix string) bool {
+	return strings.HasPrefix(string(runes[i:min(i+len(prefix), len(runes))]), prefix)
+}
+
+// skipUntil end 다음 위치 (없으면 끝)
+func skipUntil(runes []rune, i int, end string) int {
+	for ; i < len(runes); i++ {
+		if hasPrefixAt(runes, i, end) {
+			return i + len([]rune(end))
+		}
+	}
+	return len(runes)
+}
+
+// skipString 작은따옴표 문자열 리터럴 다음 위치
+func skipString(runes []rune, i int) int {
+	for i++; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			i++
+		case '\'':
+			return i + 1
+		}
+	}
+	return len(runes)
+}
+
+// skipIdentifier (따옴표/스키마 포함) 식별자 다음 위치
+func skipIdentifier(runes []rune, i int) int {
+	for i < len(runes) {
+		switch r := runes[i]; {
+		case r == '`' || r == '"':
+			i = skipUntil(runes, i+1, string(r))
+		case isIdentRune(r) || r == '.':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isTableName 테이블 이름(또는 별칭)이 될 수 있는 토큰
+func isTableName(tok string) bool {
+	if tok == "" || isSQLKeyword(tok) {
+		return false
+	}
+	r := []rune(tok)[0]
+	return r == '`' || r == '"' || r == '_' || unicode.IsLetter(r)
+}
+
+// isSQLModifier 테이블 이름 앞에 올 수 있는 키워드
+func isSQLModifier(tok string) bool {
+	switch tok {
+	case "if", "not", "exists", "only", "ignore", "low_priority", "temporary":
+		return true
+	}
+	return false
+}
+
+func isSQLKeyword(tok string) bool {
+	switch tok {
+	case "where", "join", "left", "right", "inner", "outer", "cross", "natural", "on", "using",
+		"group", "order", "limit", "having", "union", "set", "values", "select", "for", "as":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ScopedRedis 키 접두사로 제한된 Redis 클라이언트
+// 모든 키에 capabilities.redis_prefix가 붙으므로 플러그인은 다른 키를 읽거나 지울 수 없다.
+type ScopedRedis struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewScopedRedis 생성자
+func NewScopedRedis(client *redis.Client, prefix string) *ScopedRedis {
+	return &ScopedRedis{client: client, prefix: prefix}
+}
+
+// Prefix 키 접두사
+func (r *ScopedRedis) Prefix() string {
+	return r.prefix
+}
+
+// Key 접두사가 붙은 실제 키
+func (r *ScopedRedis) Key(key string) string {
+	return r.prefix + key
+}
+
+func (r *ScopedRedis) keys(keys []string) []string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = r.prefix + k
+	}
+	return out
+}
+
+// Get GET
+func (r *ScopedRedis) Get(ctx context.Context, key string) *redis.StringCmd {
+	return r.client.Get(ctx, r.Key(key))
+}
+
+// Set SET
+func (r *ScopedRedis) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	return r.client.Set(ctx, r.Key(key), value, expiration)
+}
+
+// SetNX SET NX
+func (r *ScopedRedis) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	return r.client.SetNX(ctx, r.Key(key), value, expiration)
+}
+
+// Del DEL
+func (r *ScopedRedis) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	return r.client.Del(ctx, r.keys(keys)...)
+}
+
+// Exists EXISTS
+func (r *ScopedRedis) Exists(ctx context.Context, keys ...string) *redis.IntCmd {
+	return r.client.Exists(ctx, r.keys(keys)...)
+}
+
+// Expire EXPIRE
+func (r *ScopedRedis) Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	return r.client.Expire(ctx, r.Key(key), expiration)
+}
+
+// TTL TTL
+func (r *ScopedRedis) TTL(ctx context.Context, key string) *redis.DurationCmd {
+	return r.client.TTL(ctx, r.Key(key))
+}
+
+// Incr INCR
+func (r *ScopedRedis) Incr(ctx context.Context, key string) *redis.IntCmd {
+	return r.client.Incr(ctx, r.Key(key))
+}
+
+// IncrBy INCRBY
+func (r *ScopedRedis) IncrBy(ctx context.Context, key string, value int64) *redis.IntCmd {
+	return r.client.IncrBy(ctx, r.Key(key), value)
+}
+
+// HGet HGET
+func (r *ScopedRedis) HGet(ctx context.Context, key, field string) *redis.StringCmd {
+	return r.client.HGet(ctx, r.Key(key), field)
+}
+
+// HSet HSET
+func (r *ScopedRedis) HSet(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	return r.client.HSet(ctx, r.Key(key), values...)
+}
+
+// HGetAll HGETALL
+func (r *ScopedRedis) HGetAll(ctx context.Context, key string) *redis.MapStringStringCmd {
+	return r.client.HGetAll(ctx, r.Key(key))
+}
+
+// HDel HDEL
+func (r *ScopedRedis) HDel(ctx context.Context, key string, fields ...string) *redis.IntCmd {
+	return r.client.HDel(ctx, r.Key(key), fields...)
+}
+
+// HIncrBy HINCRBY
+func (r *ScopedRedis) HIncrBy(ctx context.Context, key, field string, incr int64) *redis.IntCmd {
+	return r.client.HIncrBy(ctx, r.Key(key), field, incr)
+}
+
+// LPush LPUSH
+func (r *ScopedRedis) LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	return r.client.LPush(ctx, r.Key(key), values...)
+}
+
+// RPush RPUSH
+func (r *ScopedRedis) RPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	return r.client.RPush(ctx, r.Key(key), values...)
+}
+
+// LRange LRANGE
+func (r *ScopedRedis) LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd {
+	return r.client.LRange(ctx, r.Key(key), start, stop)
+}
+
+// LTrim LTRIM
+func (r *ScopedRedis) LTrim(ctx context.Context, key string, start, stop int64) *redis.StatusCmd {
+	return r.client.LTrim(ctx, r.Key(key), start, stop)
+}
+
+// SAdd SADD
+func (r *ScopedRedis) SAdd(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	return r.client.SAdd(ctx, r.Key(key), members...)
+}
+
+// SRem SREM
+func (r *ScopedRedis) SRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	return r.client.SRem(ctx, r.Key(key), members...)
+}
+
+// SMembers SMEMBERS
+func (r *ScopedRedis) SMembers(ctx context.Context, key string) *redis.StringSliceCmd {
+	return r.client.SMembers(ctx, r.Key(key))
+}
+
+// SIsMember SISMEMBER
+func (r *ScopedRedis) SIsMember(ctx context.Context, key string, member interface{}) *redis.BoolCmd {
+	return r.client.SIsMember(ctx, r.Key(key), member)
+}
+
+// ZAdd ZADD
+func (r *ScopedRedis) ZAdd(ctx context.Context, key string, members ...redis.Z) *redis.IntCmd {
+	return r.client.ZAdd(ctx, r.Key(key), members...)
+}
+
+// ZRangeWithScores ZRANGE WITHSCORES
+func (r *ScopedRedis) ZRangeWithScores(ctx context.Context, key string, start, stop int64) *redis.ZSliceCmd {
+	return r.client.ZRangeWithScores(ctx, r.Key(key), start, stop)
+}
+
+// ZRem ZREM
+func (r *ScopedRedis) ZRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	return r.client.ZRem(ctx, r.Key(key), members...)
+}
+
+// Scan 접두사 안의 키를 pattern으로 조회 (반환 키에는 접두사가 없음)
+func (r *ScopedRedis) Scan(ctx context.Context, pattern string) ([]string, error) {
+	var (
+		result []string
+		cursor uint64
+	)
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, r.Key(pattern), 100).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			result = append(result, strings.TrimPrefix(k, r.prefix))
+		}
+		if next == 0 {
+			return result, nil
+		}
+		cursor = next
+	}
+}
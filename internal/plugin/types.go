@@ -1,8 +1,9 @@
 package plugin
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
@@ -40,6 +41,30 @@ type PluginManifest struct {
 
 	// 메뉴 정의 (선택) - 플러그인이 Admin UI에 메뉴 등록
 	Menus []MenuConfig `yaml:"menus"`
+
+	// 이벤트 버스 구독 토픽 (선택, 외부 플러그인용)
+	Events []string `yaml:"events"`
+
+	// 외부 프로세스 실행 설정 (선택) - 있으면 서브프로세스로 실행되는 외부 플러그인
+	Runtime *RuntimeConfig `yaml:"runtime"`
+
+	// 사용할 리소스 선언 (선택) - 설치 시 관리자가 승인한 범위만 PluginContext로 전달
+	Capabilities *Capabilities `yaml:"capabilities"`
+}
+
+// RuntimeConfig 외부 플러그인 실행 설정 (stdio JSON-RPC, pkg/pluginsdk 참고)
+type RuntimeConfig struct {
+	Command   string            `yaml:"command"` // 플러그인 디렉토리 기준 실행 파일 경로
+	Args      []string          `yaml:"args"`
+	Env       map[string]string `yaml:"env"`
+	Timeout   string            `yaml:"timeout"` // RPC 호출 타임아웃 (기본 10s)
+	RateLimit *RuntimeRateLimit `yaml:"rate_limit"`
+}
+
+// RuntimeRateLimit 외부 플러그인 API 레이트 리밋
+type RuntimeRateLimit struct {
+	Requests int    `yaml:"requests"`
+	Window   string `yaml:"window"` // 예: 1m
 }
 
 // MenuConfig 플러그인 메뉴 설정
@@ -78,6 +103,7 @@ type HookRegistration struct {
 	Event    string `yaml:"event"`
 	Handler  string `yaml:"handler"`
 	Priority int    `yaml:"priority"`
+	Type     string `yaml:"type"` // action(기본) | filter
 }
 
 // RouteConfig API 라우트 설정
@@ -152,12 +178,14 @@ type Plugin interface {
 
 // PluginContext 플러그인에 전달되는 컨텍스트
 type PluginContext struct {
-	DB         *gorm.DB
-	Redis      *redis.Client
-	Config     map[string]interface{}
-	Logger     Logger
-	BasePath   string
-	JWTManager interface{} // JWT 매니저 (순환 의존 방지를 위해 interface{} 사용)
+	DB           *gorm.DB      // capabilities.tables로 제한된 DB
+	Redis        *ScopedRedis  // capabilities.redis_prefix로 제한된 Redis (미설정 시 nil)
+	HTTP         *http.Client  // capabilities.http_hosts로만 요청하는 클라이언트
+	Capabilities *Capabilities // 승인된 capabilities (nil이면 제한 없음)
+	Config       map[string]interface{}
+	Logger       Logger
+	BasePath     string
+	JWTManager   interface{} // JWT 매니저 (순환 의존 방지를 위해 interface{} 사용)
 }
 
 // Logger 플러그인용 로거 인터페이스
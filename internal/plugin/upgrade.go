@@ -0,0 +1,306 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+// 업그레이드 에러
+var (
+	ErrUpgradeNotNewer   = errors.New("plugin upgrade version must be newer than the installed version")
+	ErrUpgradeRolledBack = errors.New("plugin upgrade failed and was rolled back")
+)
+
+// UpgradeResult 업그레이드 결과
+type UpgradeResult struct {
+	Plugin      string   `json:"plugin"`
+	FromVersion string   `json:"from_version"`
+	ToVersion   string   `json:"to_version"`
+	Migrations  []string `json:"migrations"` // 이번 업그레이드에서 적용된 up 마이그레이션
+	RolledBack  bool     `json:"rolled_back"`
+}
+
+// upgradeSnapshot 롤백용 이전 버전 상태
+type upgradeSnapshot struct {
+	manifest *PluginManifest
+	path     string
+	instance Plugin
+	enabled  bool
+}
+
+// UpgradeTarget 업그레이드 대상 매니페스트 검증
+// 디렉토리 플러그인은 새 패키지가 필요하고, 내장 플러그인은 현재 바이너리의 매니페스트가 대상이다.
+func (m *Manager) UpgradeTarget(name string, data []byte) (*PluginManifest, error) {
+	info, ok := m.GetPlugin(name)
+	if !ok {
+		return nil, fmt.Errorf("plugin %s not found", name)
+	}
+
+	target := info.Manifest
+	switch {
+	case data != nil && info.IsBuiltIn:
+		return nil, fmt.Errorf("built-in plugin %s is upgraded with the server binary", name)
+	case data != nil:
+		pkg, err := m.loader.InspectPackage(data)
+		if err != nil {
+			return nil, err
+		}
+		if pkg.Manifest.Name != name {
+			return nil, fmt.Errorf("%w: package is for plugin %s, not %s", ErrInvalidPackage, pkg.Manifest.Name, name)
+		}
+		if !versionNewer(pkg.Manifest.Version, info.Manifest.Version) {
+			return nil, fmt.Errorf("%w: %s -> %s", ErrUpgradeNotNewer, info.Manifest.Version, pkg.Manifest.Version)
+		}
+		target = pkg.Manifest
+	case !info.IsBuiltIn:
+		return nil, fmt.Errorf("plugin %s: an upgrade package is required", name)
+	}
+
+	if target.Requires.Angple != "" {
+		if err := CheckVersionRange(CoreVersion, target.Requires.Angple); err != nil {
+			return nil, fmt.Errorf("plugin %s %s is not compatible: %w", name, target.Version, err)
+		}
+	}
+	return target, nil
+}
+
+// Upgrade 플러그인을 새 버전으로 교체
+// 새 마이그레이션만 실행하고 Hook/라우트/메뉴를 다시 등록한다. 활성 플러그인은 Initialize와
+// HealthCheck를 통과해야 하며, 실패하면 이번에 적용한 마이그레이션을 down으로 되돌리고
+// 이전 파일과 매니페스트로 복구한 뒤 다시 활성화한다. 내장 플러그인은 코드를 되돌릴 수 없어
+// 실패 시 에러 상태로 남는다.
+func (m *Manager) Upgrade(name string, data []byte) (*UpgradeResult, error) {
+	return m.upgrade(name, data, nil, false)
+}
+
+// UpgradeWithCapabilities Upgrade와 같지만 새 버전은 승인된 caps로 활성화한다 (nil이면 승인 없음으로 취급).
+// 승인 기록은 호출자가 성공 후 저장하므로, 롤백된 이전 버전은 기존 승인으로 다시 활성화된다.
+func (m *Manager) UpgradeWithCapabilities(name string, data []byte, caps *Capabilities) (*UpgradeResult, error) {
+	return m.upgrade(name, data, caps, true)
+}
+
+func (m *Manager) upgrade(name string, data []byte, caps *Capabilities, overrideCaps bool) (*UpgradeResult, error) {
+	target, err := m.UpgradeTarget(name, data)
+	if err != nil {
+		return nil, err
+	}
+	info, _ := m.GetPlugin(name)
+
+	m.mu.RLock()
+	prev := upgradeSnapshot{manifest: info.Manifest, path: info.Path, instance: info.Instance, enabled: info.Status == StatusEnabled}
+	m.mu.RUnlock()
+	result := &UpgradeResult{Plugin: name, FromVersion: prev.manifest.Version, ToVersion: target.Version}
+
+	before, err := m.appliedMigrations(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Disable(name); err != nil {
+		return nil, err
+	}
+
+	backup := ""
+	if data != nil {
+		if backup, err = m.swapPackage(info, target, data); err != nil {
+			m.restoreSnapshot(name, info, prev)
+			return nil, err
+		}
+	}
+	menusChanged := !reflect.DeepEqual(prev.manifest.Menus, target.Menus)
+	if menusChanged {
+		m.deletePluginMenus(name)
+	}
+
+	if overrideCaps {
+		m.setUpgradeCapabilities(name, caps)
+	}
+	err = m.activateUpgrade(name, prev.enabled)
+	m.clearUpgradeCapabilities(name)
+	result.Migrations = m.newMigrations(name, before)
+	if err == nil {
+		if backup != "" {
+			if rmErr := os.RemoveAll(backup); rmErr != nil {
+				m.logger.Warn("Failed to remove upgrade backup %s: %v", backup, rmErr)
+			}
+		}
+		m.logger.Info("Upgraded plugin %s: %s -> %s", name, result.FromVersion, result.ToVersion)
+		return result, nil
+	}
+
+	if info.IsBuiltIn {
+		m.deactivate(name, info)
+		m.mu.Lock()
+		info.Status, info.Error = StatusError, err
+		m.mu.Unlock()
+		return result, fmt.Errorf("plugin %s upgrade failed: %w", name, err)
+	}
+
+	m.logger.Error("Upgrade of plugin %s to %s failed, rolling back: %v", name, target.Version, err)
+	m.rollbackUpgrade(name, info, prev, backup, result.Migrations, menusChanged)
+	result.RolledBack = true
+	return result, fmt.Errorf("%w: %w", ErrUpgradeRolledBack, err)
+}
+
+// setUpgradeCapabilities 새 버전 활성화에 쓸 capabilities 지정
+func (m *Manager) setUpgradeCapabilities(name string, caps *Capabilities) {
+	m.upgradeMu.Lock()
+	defer m.upgradeMu.Unlock()
+	if m.upgradeCaps == nil {
+		m.upgradeCaps = make(map[string]*Capabilities)
+	}
+	m.upgradeCaps[name] = caps
+}
+
+// clearUpgradeCapabilities 활성화가 끝나면 승인 기록 조회로 되돌림 (롤백은 이전 승인 사용)
+func (m *Manager) clearUpgradeCapabilities(name string) {
+	m.upgradeMu.Lock()
+	defer m.upgradeMu.Unlock()
+	delete(m.upgradeCaps, name)
+}
+
+// activateUpgrade 새 버전 활성화 + 헬스 체크 (비활성 플러그인은 마이그레이션만 실행)
+func (m *Manager) activateUpgrade(name string, enable bool) error {
+	if !enable {
+		return m.RunMigrations(name)
+	}
+	if err := m.Enable(name); err != nil {
+		return err
+	}
+	if health := m.CheckHealth(name); health.Status != "healthy" {
+		return fmt.Errorf("health check failed: %s", health.Message)
+	}
+	return nil
+}
+
+// swapPackage 새 패키지를 풀고 기존 디렉토리를 백업으로 옮긴 뒤 PluginInfo를 새 버전으로 교체
+func (m *Manager) swapPackage(info *PluginInfo, target *PluginManifest, data []byte) (string, error) {
+	staging, err := m.stagePackage(target.Name, data)
+	if err != nil {
+		return "", err
+	}
+	backup := filepath.Join(filepath.Dir(info.Path), ".backup-"+target.Name+"-"+info.Manifest.Version)
+	if err := os.RemoveAll(backup); err != nil {
+		_ = os.RemoveAll(staging) //nolint:errcheck // 정리 실패는 원래 에러가 우선
+		return "", err
+	}
+	if err := os.Rename(info.Path, backup); err != nil {
+		_ = os.RemoveAll(staging) //nolint:errcheck // 정리 실패는 원래 에러가 우선
+		return "", err
+	}
+	if err := os.Rename(staging, info.Path); err != nil {
+		_ = os.Rename(backup, info.Path) //nolint:errcheck // 복구 실패는 원래 에러가 우선
+		_ = os.RemoveAll(staging)        //nolint:errcheck // 정리 실패는 원래 에러가 우선
+		return "", err
+	}
+
+	var instance Plugin
+	if target.Runtime != nil {
+		ext, err := NewExternalPlugin(target, info.Path, m.logger, m.metrics, m.rateLimiter)
+		if err != nil {
+			_ = os.RemoveAll(info.Path)      //nolint:errcheck // 복구 전 새 버전 제거
+			_ = os.Rename(backup, info.Path) //nolint:errcheck // 복구 실패는 원래 에러가 우선
+			return "", err
+		}
+		instance = ext
+	}
+
+	m.mu.Lock()
+	info.Manifest, info.Instance = target, instance
+	m.mu.Unlock()
+	return backup, nil
+}
+
+// rollbackUpgrade 새 버전 해제 → 새 마이그레이션 down → 이전 파일/매니페스트 복구 → 재활성화
+func (m *Manager) rollbackUpgrade(name string, info *PluginInfo, prev upgradeSnapshot, backup string, applied []string, menusChanged bool) {
+	if info.Status != StatusDisabled {
+		m.deactivate(name, info)
+	}
+
+	if len(applied) > 0 {
+		only := make(map[string]bool, len(applied))
+		for _, f := range applied {
+			only[f] = true
+		}
+		if _, err := m.rollbackMigrations(name, only, false); err != nil {
+			m.logger.Error("Failed to roll back migrations of plugin %s: %v", name, err)
+		}
+	}
+
+	if backup != "" {
+		if err := os.RemoveAll(prev.path); err != nil {
+			m.logger.Error("Failed to remove failed upgrade of plugin %s: %v", name, err)
+		}
+		if err := os.Rename(backup, prev.path); err != nil {
+			m.logger.Error("Failed to restore plugin %s from %s: %v", name, backup, err)
+		}
+	}
+	if menusChanged {
+		m.deletePluginMenus(name)
+	}
+
+	m.restoreSnapshot(name, info, prev)
+}
+
+// restoreSnapshot 이전 매니페스트/인스턴스로 되돌리고 활성 상태였으면 다시 활성화
+func (m *Manager) restoreSnapshot(name string, info *PluginInfo, prev upgradeSnapshot) {
+	m.mu.Lock()
+	info.Manifest, info.Path, info.Instance = prev.manifest, prev.path, prev.instance
+	info.Status, info.Error = StatusDisabled, nil
+	m.mu.Unlock()
+
+	if prev.enabled {
+		if err := m.Enable(name); err != nil {
+			m.logger.Error("Failed to re-enable plugin %s after rollback: %v", name, err)
+		}
+	}
+}
+
+// appliedMigrations 적용된 up 마이그레이션 파일명 집합
+func (m *Manager) appliedMigrations(name string) (map[string]bool, error) {
+	applied := make(map[string]bool)
+	if m.db == nil || !m.db.Migrator().HasTable(&PluginMigrationRecord{}) {
+		return applied, nil
+	}
+	var files []string
+	if err := m.db.Model(&PluginMigrationRecord{}).Where("plugin_name = ?", name).Pluck("filename", &files).Error; err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		applied[f] = true
+	}
+	return applied, nil
+}
+
+// newMigrations before 이후 새로 적용된 마이그레이션
+func (m *Manager) newMigrations(name string, before map[string]bool) []string {
+	after, err := m.appliedMigrations(name)
+	if err != nil {
+		m.logger.Warn("Failed to read migration history of plugin %s: %v", name, err)
+		return nil
+	}
+	var added []string
+	for f := range after {
+		if !before[f] {
+			added = append(added, f)
+		}
+	}
+	sort.Strings(added)
+	return added
+}
+
+// versionNewer a가 b보다 높은 버전인지
+func versionNewer(a, b string) bool {
+	va, err := ParseSemVer(a)
+	if err != nil {
+		return false
+	}
+	vb, err := ParseSemVer(b)
+	if err != nil {
+		return true
+	}
+	return va.Compare(vb) > 0
+}
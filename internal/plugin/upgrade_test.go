@@ -0,0 +1,112 @@
+package plugin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func helloPackage(t *testing.T, version string, migrations ...testEntry) []byte {
+	t.Helper()
+	manifest := "name: hello\nversion: " + version + "\ntitle: Hello\nrequires:\n  angple: \">=1.0.0\"\n"
+	entries := append([]testEntry{{name: "plugin.yaml", body: manifest}}, migrations...)
+	return buildTestPackage(t, entries...)
+}
+
+func TestManager_Upgrade(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	m := NewManager(dir, db, nil, NewDefaultLogger("test"), nil, nil)
+
+	v1 := []testEntry{
+		{name: "migrations/001_init.up.sql", body: "CREATE TABLE hello_items (id INTEGER PRIMARY KEY);"},
+		{name: "migrations/001_init.down.sql", body: "DROP TABLE hello_items;"},
+	}
+	if _, err := m.InstallPackage(helloPackage(t, "1.0.0", v1...)); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if err := m.Enable("hello"); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+
+	if _, err := m.Upgrade("hello", helloPackage(t, "1.0.0", v1...)); !errors.Is(err, ErrUpgradeNotNewer) {
+		t.Errorf("expected ErrUpgradeNotNewer, got %v", err)
+	}
+
+	v2 := append(v1,
+		testEntry{name: "migrations/002_name.up.sql", body: "ALTER TABLE hello_items ADD COLUMN name TEXT;"},
+		testEntry{name: "migrations/002_name.down.sql", body: "ALTER TABLE hello_items DROP COLUMN name;"},
+	)
+	result, err := m.Upgrade("hello", helloPackage(t, "2.0.0", v2...))
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if !reflect.DeepEqual(result.Migrations, []string{"002_name.up.sql"}) || result.ToVersion != "2.0.0" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	// 003은 성공, 004는 실패 → 003을 down으로 되돌리고 2.0.0으로 복구
+	v3 := append(v2,
+		testEntry{name: "migrations/003_extra.up.sql", body: "CREATE TABLE hello_extra (id INTEGER);"},
+		testEntry{name: "migrations/003_extra.down.sql", body: "DROP TABLE hello_extra;"},
+		testEntry{name: "migrations/004_broken.up.sql", body: "ALTER TABLE missing_table ADD COLUMN x TEXT;"},
+		testEntry{name: "migrations/004_broken.down.sql", body: "SELECT 1;"},
+	)
+	result, err = m.Upgrade("hello", helloPackage(t, "3.0.0", v3...))
+	if !errors.Is(err, ErrUpgradeRolledBack) || result == nil || !result.RolledBack {
+		t.Fatalf("expected rollback, got %+v %v", result, err)
+	}
+	info, _ := m.GetPlugin("hello")
+	if info.Manifest.Version != "2.0.0" || info.Status != StatusEnabled {
+		t.Errorf("expected 2.0.0 to be restored and enabled, got %s %s", info.Manifest.Version, info.Status)
+	}
+	if db.Migrator().HasTable("hello_extra") {
+		t.Error("expected migrations applied by the failed upgrade to be rolled back")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "hello", "migrations", "003_extra.up.sql")); !os.IsNotExist(err) {
+		t.Errorf("expected previous plugin files to be restored, got %v", err)
+	}
+	entries, _ := os.ReadDir(dir) //nolint:errcheck // empty on error
+	if len(entries) != 1 {
+		t.Errorf("expected staging and backup directories to be cleaned up, got %d entries", len(entries))
+	}
+}
+
+type fixedCapabilities map[string]*Capabilities
+
+func (f fixedCapabilities) ApprovedCapabilities(name string) (*Capabilities, bool) {
+	caps, ok := f[name]
+	return caps, ok
+}
+
+func TestManager_UpgradeCapabilitiesApplyOnlyToNewVersion(t *testing.T) {
+	m := NewManager(t.TempDir(), nil, nil, NewDefaultLogger("test"), nil, nil)
+	approved := &Capabilities{Tables: TableCapabilities{Read: []string{"hello_*"}}}
+	next := &Capabilities{Tables: TableCapabilities{Write: []string{"hello_*"}}}
+	m.SetCapabilityProvider(fixedCapabilities{"hello": approved})
+	info := &PluginInfo{Manifest: &PluginManifest{Name: "hello"}}
+
+	// 새 버전 활성화 중에는 업그레이드 승인값
+	m.setUpgradeCapabilities("hello", next)
+	if got := m.resolveCapabilities("hello", info); got != next {
+		t.Errorf("expected upgrade capabilities during activation, got %+v", got)
+	}
+	// 롤백으로 이전 버전을 다시 켤 때는 기존 승인 기록
+	m.clearUpgradeCapabilities("hello")
+	if got := m.resolveCapabilities("hello", info); got != approved {
+		t.Errorf("expected stored approval after the upgrade, got %+v", got)
+	}
+	// 새 버전이 capabilities를 선언하지 않으면 이전 승인을 물려받지 않음
+	m.setUpgradeCapabilities("hello", nil)
+	if got := m.resolveCapabilities("hello", info); got == nil || got.CanRead("hello_items") {
+		t.Errorf("expected no access without an approval for the new version, got %+v", got)
+	}
+}
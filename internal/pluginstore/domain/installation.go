@@ -4,16 +4,17 @@ import "time"
 
 // PluginInstallation 플러그인 설치 상태 엔티티
 type PluginInstallation struct {
-	ID           int64      `gorm:"primaryKey" json:"id"`
-	PluginName   string     `gorm:"uniqueIndex;size:100" json:"plugin_name"`
-	Version      string     `gorm:"size:50" json:"version"`
-	Status       string     `gorm:"size:20;default:disabled" json:"status"` // enabled, disabled, error
-	InstalledAt  time.Time  `gorm:"autoCreateTime" json:"installed_at"`
-	EnabledAt    *time.Time `json:"enabled_at"`
-	DisabledAt   *time.Time `json:"disabled_at"`
-	Config       *string    `gorm:"type:json" json:"config"`
-	ErrorMessage *string    `gorm:"type:text" json:"error_message"`
-	InstalledBy  *string    `gorm:"size:100" json:"installed_by"`
+	ID                   int64      `gorm:"primaryKey" json:"id"`
+	PluginName           string     `gorm:"uniqueIndex;size:100" json:"plugin_name"`
+	Version              string     `gorm:"size:50" json:"version"`
+	Status               string     `gorm:"size:20;default:disabled" json:"status"` // enabled, disabled, error
+	InstalledAt          time.Time  `gorm:"autoCreateTime" json:"installed_at"`
+	EnabledAt            *time.Time `json:"enabled_at"`
+	DisabledAt           *time.Time `json:"disabled_at"`
+	Config               *string    `gorm:"type:json" json:"config"`
+	ErrorMessage         *string    `gorm:"type:text" json:"error_message"`
+	InstalledBy          *string    `gorm:"size:100" json:"installed_by"`
+	ApprovedCapabilities *string    `gorm:"type:json" json:"approved_capabilities"` // 관리자가 승인한 plugin.Capabilities
 }
 
 // TableName GORM 테이블명
@@ -39,7 +40,7 @@ func (PluginSetting) TableName() string {
 type PluginEvent struct {
 	ID         int64     `gorm:"primaryKey" json:"id"`
 	PluginName string    `gorm:"size:100;index:idx_plugin_event" json:"plugin_name"`
-	EventType  string    `gorm:"size:30" json:"event_type"` // installed, enabled, disabled, uninstalled, config_changed, error
+	EventType  string    `gorm:"size:30" json:"event_type"` // installed, enabled, disabled, uninstalled, config_changed, error, capabilities_approved, capability_violation, upgraded, upgrade_failed
 	Details    *string   `gorm:"type:json" json:"details"`
 	ActorID    *string   `gorm:"size:100" json:"actor_id"`
 	CreatedAt  time.Time `gorm:"autoCreateTime;index:idx_plugin_event" json:"created_at"`
@@ -69,6 +70,7 @@ type PluginMigration struct {
 	ID         int64     `gorm:"primaryKey" json:"id"`
 	PluginName string    `gorm:"size:100;uniqueIndex:uk_plugin_migration" json:"plugin_name"`
 	Filename   string    `gorm:"size:255;uniqueIndex:uk_plugin_migration" json:"filename"`
+	Checksum   string    `gorm:"size:64" json:"checksum"`
 	ExecutedAt time.Time `gorm:"autoCreateTime" json:"executed_at"`
 }
 
@@ -77,6 +79,23 @@ func (PluginMigration) TableName() string {
 	return "plugin_migrations"
 }
 
+// InstallOptions 플러그인 설치 옵션
+type InstallOptions struct {
+	ApproveCapabilities bool // 매니페스트 capabilities 승인 (선언이 있으면 필수)
+}
+
+// UpgradeOptions 플러그인 업그레이드 옵션
+type UpgradeOptions struct {
+	Package             []byte // 새 버전 패키지 (내장 플러그인은 nil: 현재 바이너리 버전으로 동기화)
+	ApproveCapabilities bool   // 새 버전 capabilities가 승인된 것과 다르면 필수
+}
+
+// UninstallOptions 플러그인 제거 옵션
+type UninstallOptions struct {
+	PurgeData bool // down 마이그레이션 실행 (플러그인 테이블/데이터 삭제)
+	DryRun    bool // 실행하지 않고 실행될 SQL만 반환
+}
+
 // 이벤트 타입 상수
 const (
 	EventInstalled     = "installed"
@@ -85,6 +104,12 @@ const (
 	EventUninstalled   = "uninstalled"
 	EventConfigChanged = "config_changed"
 	EventError         = "error"
+
+	EventCapabilitiesApproved = "capabilities_approved"
+	EventCapabilityViolation  = "capability_violation"
+
+	EventUpgraded      = "upgraded"
+	EventUpgradeFailed = "upgrade_failed"
 )
 
 // 플러그인 상태 상수
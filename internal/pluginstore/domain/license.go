@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// PluginLicense 마켓플레이스 플러그인 라이선스 (Ed25519 서명 토큰 발급 기록)
+type PluginLicense struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubmissionID uint64     `gorm:"index;not null" json:"submission_id"`
+	DeveloperID  uint64     `gorm:"index;not null" json:"developer_id"`
+	PluginName   string     `gorm:"size:100;index;not null" json:"plugin_name"`
+	LicenseKey   string     `gorm:"type:text" json:"license_key"` // 서명된 토큰 (pkg/license)
+	Licensee     string     `gorm:"size:255" json:"licensee"`
+	Domain       string     `gorm:"size:255" json:"domain"` // 비어 있으면 도메인 제한 없음
+	Seats        int        `gorm:"default:1" json:"seats"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	RevokedAt    *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+	RevokeReason string     `gorm:"size:255" json:"revoke_reason,omitempty"`
+	IssuedBy     uint64     `json:"issued_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (PluginLicense) TableName() string { return "plugin_licenses" }
+
+// Status returns active, expired or revoked
+func (l *PluginLicense) Status(now time.Time) string {
+	switch {
+	case l.RevokedAt != nil:
+		return "revoked"
+	case l.ExpiresAt != nil && !now.Before(*l.ExpiresAt):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+// LicenseIssueRequest 라이선스 발급 요청
+type LicenseIssueRequest struct {
+	SubmissionID uint64     `json:"submission_id" binding:"required"`
+	Licensee     string     `json:"licensee"`
+	Domain       string     `json:"domain"`
+	Seats        int        `json:"seats"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+}
+
+// LicenseRevokeRequest 라이선스 폐기 요청
+type LicenseRevokeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// LicenseListFilter 라이선스 목록 필터
+type LicenseListFilter struct {
+	PluginName  string
+	DeveloperID uint64
+	Status      string // active, revoked
+}
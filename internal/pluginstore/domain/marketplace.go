@@ -12,6 +12,7 @@ type PluginDeveloper struct {
 	Bio         string    `gorm:"type:text" json:"bio"`
 	IsVerified  bool      `gorm:"default:false" json:"is_verified"`
 	Status      string    `gorm:"size:20;default:active" json:"status"` // active, suspended
+	SigningKey  string    `gorm:"size:64" json:"signing_key,omitempty"` // 패키지 서명 Ed25519 공개 키 (base64)
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -36,8 +37,15 @@ type PluginSubmission struct {
 	ReviewedBy    *uint64    `json:"reviewed_by,omitempty"`
 	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
 	DownloadCount int64      `gorm:"default:0" json:"download_count"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// 업로드된 패키지 (메타데이터만 제출한 경우 비어 있음)
+	PackageKey       string `gorm:"size:500" json:"-"`
+	PackageChecksum  string `gorm:"size:64" json:"package_checksum,omitempty"` // SHA-256 hex
+	PackageSize      int64  `json:"package_size,omitempty"`
+	PackageSignature string `gorm:"size:128" json:"package_signature,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 func (PluginSubmission) TableName() string { return "plugin_submissions" }
@@ -89,6 +97,19 @@ type PluginSubmitRequest struct {
 	Readme      string `json:"readme"`
 }
 
+// SigningKeyRequest 패키지 서명 키 등록 요청
+type SigningKeyRequest struct {
+	PublicKey string `json:"public_key" binding:"required"` // Ed25519 공개 키 (base64)
+}
+
+// PluginPackageMeta 패키지 업로드 시 매니페스트에 없는 제출 정보 (multipart 폼 필드)
+type PluginPackageMeta struct {
+	Category  string `form:"category"`
+	Tags      string `form:"tags"`
+	SourceURL string `form:"source_url"`
+	Readme    string `form:"readme"`
+}
+
 // PluginReviewRequest 리뷰 작성 요청
 type PluginReviewRequest struct {
 	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/damoang/angple-backend/internal/common"
 	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/plugin"
 	"github.com/damoang/angple-backend/internal/pluginstore/domain"
 	"github.com/damoang/angple-backend/internal/pluginstore/service"
 	"github.com/gin-gonic/gin"
@@ -224,6 +228,105 @@ func (h *MarketplaceHandler) SubmitPlugin(c *gin.Context) {
 	common.V2Created(c, sub)
 }
 
+// SetSigningKey godoc
+// @Summary 패키지 서명 키 등록
+// @Tags marketplace-developer
+// @Success 200 {object} common.V2Response
+// @Router /api/v2/marketplace/developers/me/signing-key [put]
+func (h *MarketplaceHandler) SetSigningKey(c *gin.Context) {
+	userID := getUserIDUint64(c)
+	if userID == 0 {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "로그인이 필요합니다", nil)
+		return
+	}
+
+	var req domain.SigningKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "요청 형식이 올바르지 않습니다", err)
+		return
+	}
+
+	dev, err := h.svc.SetSigningKey(userID, req.PublicKey)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	common.V2Success(c, dev)
+}
+
+// UploadPackage godoc
+// @Summary 서명된 플러그인 패키지 업로드
+// @Tags marketplace-developer
+// @Accept multipart/form-data
+// @Param package formData file true "플러그인 패키지 (.tar.gz)"
+// @Param signature formData string true "패키지 서명 (base64)"
+// @Success 201 {object} common.V2Response
+// @Router /api/v2/marketplace/developers/submissions/package [post]
+func (h *MarketplaceHandler) UploadPackage(c *gin.Context) {
+	userID := getUserIDUint64(c)
+	if userID == 0 {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "로그인이 필요합니다", nil)
+		return
+	}
+
+	dev, err := h.svc.GetDeveloperProfile(userID)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusForbidden, "개발자 등록이 필요합니다", nil)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, plugin.MaxPackageSize+1<<20)
+	data, err := readPackageFile(c)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	var meta domain.PluginPackageMeta
+	if err := c.ShouldBind(&meta); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "요청 형식이 올바르지 않습니다", err)
+		return
+	}
+
+	sub, err := h.svc.UploadPackage(c.Request.Context(), dev, data, c.PostForm("signature"), meta)
+	if err != nil {
+		common.V2ErrorResponse(c, uploadErrorStatus(err), err.Error(), nil)
+		return
+	}
+	common.V2Created(c, sub)
+}
+
+// readPackageFile multipart "package" 파일 읽기 (크기 제한)
+func readPackageFile(c *gin.Context) ([]byte, error) {
+	fh, err := c.FormFile("package")
+	if err != nil {
+		return nil, errors.New("package 파일이 필요합니다")
+	}
+	if fh.Size > plugin.MaxPackageSize {
+		return nil, fmt.Errorf("패키지는 %dMB를 넘을 수 없습니다", plugin.MaxPackageSize>>20)
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, plugin.MaxPackageSize))
+}
+
+// uploadErrorStatus 패키지 업로드 에러의 HTTP 상태
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrPackageStorageUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, service.ErrPluginNameTaken), errors.Is(err, service.ErrVersionNotNewer):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrSigningKeyRequired), errors.Is(err, plugin.ErrPackageSignature),
+		errors.Is(err, plugin.ErrInvalidPackage):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 // ListMySubmissions godoc
 // @Summary 내 제출 목록
 // @Tags marketplace-developer
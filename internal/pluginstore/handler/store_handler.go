@@ -1,19 +1,25 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
+	"github.com/damoang/angple-backend/internal/common"
 	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/damoang/angple-backend/internal/pluginstore/domain"
 	"github.com/damoang/angple-backend/internal/pluginstore/service"
 	"github.com/gin-gonic/gin"
 )
 
 // StoreHandler 플러그인 스토어 관리 핸들러
 type StoreHandler struct {
-	storeSvc   *service.StoreService
-	catalogSvc *service.CatalogService
-	manager    *plugin.Manager
+	storeSvc    *service.StoreService
+	catalogSvc  *service.CatalogService
+	manager     *plugin.Manager
+	marketplace *service.MarketplaceService
 }
 
 // NewStoreHandler 생성자
@@ -29,6 +35,11 @@ func NewStoreHandler(
 	}
 }
 
+// SetMarketplace 마켓플레이스 서비스 설정 (마켓플레이스 설치용)
+func (h *StoreHandler) SetMarketplace(svc *service.MarketplaceService) {
+	h.marketplace = svc
+}
+
 // ListPlugins 카탈로그 목록 조회
 // GET /api/v2/admin/plugins
 func (h *StoreHandler) ListPlugins(c *gin.Context) {
@@ -66,12 +77,37 @@ func (h *StoreHandler) GetPlugin(c *gin.Context) {
 }
 
 // InstallPlugin 플러그인 설치
-// POST /api/v2/admin/plugins/:name/install
+// POST /api/v2/admin/plugins/:name/install {"approve_capabilities": true}
+// 매니페스트에 capabilities가 있으면 approve_capabilities 없이는 409와 요청 capabilities를 반환
 func (h *StoreHandler) InstallPlugin(c *gin.Context) {
 	name := c.Param("name")
 	actorID := getActorID(c)
 
-	if err := h.storeSvc.Install(name, actorID, h.manager); err != nil {
+	var req struct {
+		ApproveCapabilities bool `json:"approve_capabilities"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{"code": "INVALID_REQUEST", "message": err.Error()},
+		})
+		return
+	}
+
+	opts := domain.InstallOptions{ApproveCapabilities: req.ApproveCapabilities}
+	if err := h.storeSvc.Install(c.Request.Context(), name, actorID, h.manager, opts); err != nil {
+		if common.RespondQuotaExceeded(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrCapabilityApprovalRequired) {
+			var requested *plugin.Capabilities
+			if manifest := h.catalogSvc.GetManifest(name); manifest != nil {
+				requested = manifest.Capabilities
+			}
+			c.JSON(http.StatusConflict, gin.H{
+				"error": gin.H{"code": "CAPABILITY_APPROVAL_REQUIRED", "message": "플러그인 권한(capabilities) 승인이 필요합니다", "capabilities": requested},
+			})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": gin.H{"code": "INSTALL_ERROR", "message": err.Error()},
 		})
@@ -81,6 +117,174 @@ func (h *StoreHandler) InstallPlugin(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "플러그인이 설치되었습니다", "plugin": name}})
 }
 
+// InstallFromMarketplace 승인된 마켓플레이스 제출의 패키지를 내려받아 설치
+// POST /api/v2/admin/plugins/marketplace/:id/install
+func (h *StoreHandler) InstallFromMarketplace(c *gin.Context) {
+	if h.marketplace == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": gin.H{"code": "MARKETPLACE_UNAVAILABLE", "message": "마켓플레이스가 설정되지 않았습니다"},
+		})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{"code": "INVALID_REQUEST", "message": "잘못된 제출 ID"},
+		})
+		return
+	}
+
+	var req struct {
+		ApproveCapabilities bool `json:"approve_capabilities"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{"code": "INVALID_REQUEST", "message": err.Error()},
+		})
+		return
+	}
+
+	sub, data, err := h.marketplace.FetchPackage(c.Request.Context(), id)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrPackageStorageUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{
+			"error": gin.H{"code": "PACKAGE_ERROR", "message": err.Error()},
+		})
+		return
+	}
+
+	opts := domain.InstallOptions{ApproveCapabilities: req.ApproveCapabilities}
+	manifest, err := h.storeSvc.InstallPackage(c.Request.Context(), data, getActorID(c), h.manager, opts)
+	if err != nil {
+		respondPackageInstallError(c, manifest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"message": "플러그인이 설치되었습니다",
+		"plugin":  manifest.Name,
+		"version": manifest.Version,
+		"source":  gin.H{"submission_id": sub.ID, "checksum": sub.PackageChecksum},
+	}})
+}
+
+// respondPackageInstallError 패키지 설치 에러 응답
+func respondPackageInstallError(c *gin.Context, manifest *plugin.PluginManifest, err error) {
+	if common.RespondQuotaExceeded(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrCapabilityApprovalRequired):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": gin.H{"code": "CAPABILITY_APPROVAL_REQUIRED", "message": "플러그인 권한(capabilities) 승인이 필요합니다", "capabilities": manifest.Capabilities},
+		})
+	case errors.Is(err, plugin.ErrPluginExists):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": gin.H{"code": "PLUGIN_EXISTS", "message": err.Error()},
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{"code": "INSTALL_ERROR", "message": err.Error()},
+		})
+	}
+}
+
+// UpgradePlugin 플러그인 업그레이드 (submission_id가 있으면 마켓플레이스 패키지, 없으면 내장 플러그인 버전 동기화)
+// POST /api/v2/admin/plugins/:name/upgrade
+func (h *StoreHandler) UpgradePlugin(c *gin.Context) {
+	name := c.Param("name")
+
+	var req struct {
+		SubmissionID        uint64 `json:"submission_id"`
+		ApproveCapabilities bool   `json:"approve_capabilities"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{"code": "INVALID_REQUEST", "message": err.Error()},
+		})
+		return
+	}
+
+	opts := domain.UpgradeOptions{ApproveCapabilities: req.ApproveCapabilities}
+	if req.SubmissionID != 0 {
+		if h.marketplace == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": gin.H{"code": "MARKETPLACE_UNAVAILABLE", "message": "마켓플레이스가 설정되지 않았습니다"},
+			})
+			return
+		}
+		sub, data, err := h.marketplace.FetchPackage(c.Request.Context(), req.SubmissionID)
+		if err == nil && sub.PluginName != name {
+			err = fmt.Errorf("제출 %d은(는) %s 플러그인이 아닙니다", sub.ID, name)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": gin.H{"code": "PACKAGE_ERROR", "message": err.Error()},
+			})
+			return
+		}
+		opts.Package = data
+	}
+
+	result, err := h.storeSvc.Upgrade(c.Request.Context(), name, getActorID(c), h.manager, opts)
+	if err != nil {
+		respondUpgradeError(c, result, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// respondUpgradeError 업그레이드 에러 응답 (롤백된 경우 결과 포함)
+func respondUpgradeError(c *gin.Context, result *plugin.UpgradeResult, err error) {
+	switch {
+	case errors.Is(err, service.ErrCapabilityApprovalRequired):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": gin.H{"code": "CAPABILITY_APPROVAL_REQUIRED", "message": "새 버전의 플러그인 권한(capabilities) 승인이 필요합니다"},
+		})
+	case errors.Is(err, plugin.ErrUpgradeNotNewer):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": gin.H{"code": "VERSION_NOT_NEWER", "message": err.Error()},
+		})
+	case result != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{"code": "UPGRADE_FAILED", "message": err.Error(), "details": result},
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{"code": "UPGRADE_ERROR", "message": err.Error()},
+		})
+	}
+}
+
+// GetCapabilities 플러그인 capabilities 요청/승인 현황
+// GET /api/v2/admin/plugins/:name/capabilities
+func (h *StoreHandler) GetCapabilities(c *gin.Context) {
+	status, err := h.storeSvc.GetCapabilities(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": gin.H{"code": "PLUGIN_NOT_FOUND", "message": err.Error()},
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// ApproveCapabilities 현재 매니페스트 capabilities 승인 (다음 활성화부터 적용)
+// POST /api/v2/admin/plugins/:name/capabilities/approve
+func (h *StoreHandler) ApproveCapabilities(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.storeSvc.ApproveCapabilities(name, getActorID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{"code": "APPROVE_ERROR", "message": err.Error()},
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "플러그인 권한이 승인되었습니다. 다시 활성화하면 적용됩니다", "plugin": name}})
+}
+
 // EnablePlugin 플러그인 활성화
 // POST /api/v2/admin/plugins/:name/enable
 func (h *StoreHandler) EnablePlugin(c *gin.Context) {
@@ -114,19 +318,48 @@ func (h *StoreHandler) DisablePlugin(c *gin.Context) {
 }
 
 // UninstallPlugin 플러그인 제거
-// DELETE /api/v2/admin/plugins/:name
+// DELETE /api/v2/admin/plugins/:name?purge=true&dry_run=true
+// purge=true이면 down 마이그레이션으로 데이터까지 삭제, dry_run=true이면 실행될 SQL만 반환
 func (h *StoreHandler) UninstallPlugin(c *gin.Context) {
 	name := c.Param("name")
 	actorID := getActorID(c)
+	opts := domain.UninstallOptions{
+		PurgeData: c.Query("purge") == "true",
+		DryRun:    c.Query("dry_run") == "true",
+	}
 
-	if err := h.storeSvc.Uninstall(name, actorID, h.manager); err != nil {
+	steps, err := h.storeSvc.Uninstall(c.Request.Context(), name, actorID, h.manager, opts)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": gin.H{"code": "UNINSTALL_ERROR", "message": err.Error()},
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "플러그인이 제거되었습니다", "plugin": name}})
+	if opts.DryRun {
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"plugin": name, "dry_run": true, "migrations": steps}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "플러그인이 제거되었습니다", "plugin": name, "migrations": steps}})
+}
+
+// PendingMigrations 미실행 마이그레이션 SQL 조회 (드라이런)
+// GET /api/v2/admin/plugins/:name/migrations/pending
+func (h *StoreHandler) PendingMigrations(c *gin.Context) {
+	name := c.Param("name")
+	steps, err := h.manager.PlanMigrations(name)
+	if err != nil {
+		status := http.StatusBadRequest
+		code := "MIGRATION_PLAN_ERROR"
+		if errors.Is(err, plugin.ErrMigrationChecksumMismatch) {
+			status, code = http.StatusConflict, "MIGRATION_CHECKSUM_MISMATCH"
+		}
+		c.JSON(status, gin.H{
+			"error": gin.H{"code": code, "message": err.Error()},
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"plugin": name, "dry_run": true, "migrations": steps}})
 }
 
 // GetEvents 플러그인 이벤트 로그
@@ -186,6 +419,42 @@ func (h *StoreHandler) ScheduledTasks(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": tasks})
 }
 
+// ScheduleRuns 스케줄 작업 실행 이력 조회
+// GET /api/v2/admin/plugins/schedules/runs?plugin=&task=&limit=
+func (h *StoreHandler) ScheduleRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50")) //nolint:errcheck // 잘못된 값은 기본값 사용
+	runs, err := h.manager.GetScheduleRuns(c.Query("plugin"), c.Query("task"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{"code": "SCHEDULE_RUNS_ERROR", "message": "실행 이력 조회 실패", "details": err.Error()},
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": runs})
+}
+
+// RunScheduledTask 스케줄 작업 즉시 실행
+// POST /api/v2/admin/plugins/schedules/:plugin/:task/run
+func (h *StoreHandler) RunScheduledTask(c *gin.Context) {
+	err := h.manager.RunScheduledTask(c.Param("plugin"), c.Param("task"))
+	switch {
+	case errors.Is(err, plugin.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": gin.H{"code": "TASK_NOT_FOUND", "message": "스케줄 작업을 찾을 수 없습니다"},
+		})
+	case errors.Is(err, plugin.ErrTaskRunning):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": gin.H{"code": "TASK_RUNNING", "message": "이미 실행 중인 작업입니다"},
+		})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{"code": "TASK_RUN_ERROR", "message": err.Error()},
+		})
+	default:
+		c.JSON(http.StatusAccepted, gin.H{"data": gin.H{"plugin": c.Param("plugin"), "task": c.Param("task"), "status": "started"}})
+	}
+}
+
 // RateLimitConfigs 레이트 리밋 설정 목록 조회
 // GET /api/v2/admin/plugins/rate-limits
 func (h *StoreHandler) RateLimitConfigs(c *gin.Context) {
@@ -208,11 +477,21 @@ func (h *StoreHandler) PluginMetricsSingle(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": metrics})
 }
 
-// EventSubscriptions 이벤트 구독 현황 조회
+// EventSubscriptions 이벤트 구독 현황 조회 (구독자별 lag/pending/dead-letter 포함)
 // GET /api/v2/admin/plugins/event-subscriptions
 func (h *StoreHandler) EventSubscriptions(c *gin.Context) {
-	subs := h.manager.GetEventSubscriptions()
-	c.JSON(http.StatusOK, gin.H{"data": subs})
+	stats, err := h.manager.GetEventSubscriberStats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{"code": "EVENT_STATS_ERROR", "message": "이벤트 구독 현황 조회 실패", "details": err.Error()},
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"backend":     h.manager.GetEventBus().BackendName(),
+		"topics":      h.manager.GetEventSubscriptions(),
+		"subscribers": stats,
+	}})
 }
 
 // PluginOverview 플러그인 전체 현황 조회
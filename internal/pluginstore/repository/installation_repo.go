@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/damoang/angple-backend/internal/pluginstore/domain"
 	"gorm.io/gorm"
 )
@@ -39,6 +41,13 @@ func (r *InstallationRepository) FindEnabled() ([]domain.PluginInstallation, err
 	return list, err
 }
 
+// Count 설치된 플러그인 수 (상태 무관, 플랜 플러그인 수 한도 기준)
+func (r *InstallationRepository) Count(ctx context.Context) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&domain.PluginInstallation{}).Count(&n).Error
+	return n, err
+}
+
 // Create 새 설치 레코드 생성
 func (r *InstallationRepository) Create(inst *domain.PluginInstallation) error {
 	return r.db.Create(inst).Error
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/damoang/angple-backend/internal/pluginstore/domain"
+	"gorm.io/gorm"
+)
+
+// LicenseRepository 플러그인 라이선스 데이터 접근
+type LicenseRepository struct {
+	db *gorm.DB
+}
+
+// NewLicenseRepository 생성자
+func NewLicenseRepository(db *gorm.DB) *LicenseRepository {
+	return &LicenseRepository{db: db}
+}
+
+// AutoMigrate 라이선스 테이블 생성
+func (r *LicenseRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.PluginLicense{})
+}
+
+func (r *LicenseRepository) Create(lic *domain.PluginLicense) error {
+	return r.db.Create(lic).Error
+}
+
+func (r *LicenseRepository) Update(lic *domain.PluginLicense) error {
+	return r.db.Save(lic).Error
+}
+
+func (r *LicenseRepository) FindByID(id uint64) (*domain.PluginLicense, error) {
+	var lic domain.PluginLicense
+	err := r.db.First(&lic, id).Error
+	return &lic, err
+}
+
+// List 관리자용: 라이선스 목록
+func (r *LicenseRepository) List(filter domain.LicenseListFilter, page, perPage int) ([]domain.PluginLicense, int64, error) {
+	var lics []domain.PluginLicense
+	var total int64
+	query := r.db.Model(&domain.PluginLicense{})
+	if filter.PluginName != "" {
+		query = query.Where("plugin_name = ?", filter.PluginName)
+	}
+	if filter.DeveloperID != 0 {
+		query = query.Where("developer_id = ?", filter.DeveloperID)
+	}
+	switch filter.Status {
+	case "active":
+		query = query.Where("revoked_at IS NULL")
+	case "revoked":
+		query = query.Where("revoked_at IS NOT NULL")
+	}
+	query.Count(&total)
+	err := query.Order("created_at DESC").Offset((page - 1) * perPage).Limit(perPage).Find(&lics).Error
+	return lics, total, err
+}
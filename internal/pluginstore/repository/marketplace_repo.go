@@ -91,6 +91,13 @@ func (r *MarketplaceRepository) FindApprovedByName(pluginName string) (*domain.P
 	return &sub, err
 }
 
+// FindSubmissionsByName 플러그인 이름의 모든 제출 (상태 무관)
+func (r *MarketplaceRepository) FindSubmissionsByName(pluginName string) ([]domain.PluginSubmission, error) {
+	var subs []domain.PluginSubmission
+	err := r.db.Where("plugin_name = ?", pluginName).Order("created_at DESC").Find(&subs).Error
+	return subs, err
+}
+
 func (r *MarketplaceRepository) UpdateSubmission(sub *domain.PluginSubmission) error {
 	return r.db.Save(sub).Error
 }
@@ -41,6 +41,11 @@ func (r *SettingRepository) Set(s *domain.PluginSetting) error {
 	}).Create(s).Error
 }
 
+// Delete 설정 하나 삭제
+func (r *SettingRepository) Delete(pluginName, key string) error {
+	return r.db.Where("plugin_name = ? AND setting_key = ?", pluginName, key).Delete(&domain.PluginSetting{}).Error
+}
+
 // DeleteByPlugin 플러그인의 모든 설정 삭제
 func (r *SettingRepository) DeleteByPlugin(pluginName string) error {
 	return r.db.Where("plugin_name = ?", pluginName).Delete(&domain.PluginSetting{}).Error
@@ -25,6 +25,11 @@ func (s *CatalogService) RegisterManifest(manifest *plugin.PluginManifest) {
 	s.manifests[manifest.Name] = manifest
 }
 
+// UnregisterManifest 매니페스트 제거 (패키지 설치 롤백용)
+func (s *CatalogService) UnregisterManifest(name string) {
+	delete(s.manifests, name)
+}
+
 // ListCatalog 카탈로그 목록 조회 (DB 설치 상태 포함)
 func (s *CatalogService) ListCatalog() ([]*domain.CatalogEntry, error) {
 	installations, err := s.installRepo.FindAll()
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/pluginstore/domain"
+	"github.com/damoang/angple-backend/internal/pluginstore/repository"
+	"github.com/damoang/angple-backend/pkg/license"
+	"gorm.io/gorm"
+)
+
+// LicenseVerification is the result of a server-side license check
+type LicenseVerification struct {
+	Valid   bool                  `json:"valid"`
+	Reason  string                `json:"reason,omitempty"` // malformed, bad_signature, expired, plugin_mismatch, domain_mismatch, not_found, revoked
+	Claims  *license.Claims       `json:"claims,omitempty"`
+	License *domain.PluginLicense `json:"-"`
+}
+
+// LicenseService 플러그인 라이선스 발급/검증/폐기
+type LicenseService struct {
+	repo        *repository.LicenseRepository
+	marketplace *repository.MarketplaceRepository
+	signingKey  ed25519.PrivateKey
+}
+
+// NewLicenseService 생성자 (signingKey로 발급 토큰에 서명)
+func NewLicenseService(repo *repository.LicenseRepository, marketplace *repository.MarketplaceRepository, signingKey ed25519.PrivateKey) *LicenseService {
+	return &LicenseService{repo: repo, marketplace: marketplace, signingKey: signingKey}
+}
+
+// PublicKey returns the base64 public key for offline verification (셀프 호스팅 사이트 배포용)
+func (s *LicenseService) PublicKey() string {
+	pub, _ := s.signingKey.Public().(ed25519.PublicKey) //nolint:errcheck // type assertion, not error
+	return base64.StdEncoding.EncodeToString(pub)
+}
+
+// Issue 관리자: 승인된 제출에 대한 라이선스 발급
+func (s *LicenseService) Issue(req *domain.LicenseIssueRequest, issuedBy uint64) (*domain.PluginLicense, error) {
+	sub, err := s.marketplace.FindSubmissionByID(req.SubmissionID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Status != "approved" {
+		return nil, errors.New("승인된 플러그인에만 라이선스를 발급할 수 있습니다")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("만료일은 현재 시각 이후여야 합니다")
+	}
+	seats := req.Seats
+	if seats <= 0 {
+		seats = 1
+	}
+
+	lic := &domain.PluginLicense{
+		SubmissionID: sub.ID,
+		DeveloperID:  sub.DeveloperID,
+		PluginName:   sub.PluginName,
+		Licensee:     req.Licensee,
+		Domain:       license.NormalizeDomain(req.Domain),
+		Seats:        seats,
+		ExpiresAt:    req.ExpiresAt,
+		IssuedBy:     issuedBy,
+	}
+	// 토큰에 라이선스 ID가 들어가므로 먼저 행을 만든 뒤 서명한다
+	if err := s.repo.Create(lic); err != nil {
+		return nil, err
+	}
+
+	claims := &license.Claims{
+		LicenseID:   lic.ID,
+		PluginID:    lic.PluginName,
+		DeveloperID: lic.DeveloperID,
+		Domain:      lic.Domain,
+		Seats:       lic.Seats,
+		IssuedAt:    lic.CreatedAt.Unix(),
+	}
+	if lic.ExpiresAt != nil {
+		claims.ExpiresAt = lic.ExpiresAt.Unix()
+	}
+	token, err := license.Sign(s.signingKey, claims)
+	if err != nil {
+		return nil, fmt.Errorf("license sign: %w", err)
+	}
+	lic.LicenseKey = token
+	if err := s.repo.Update(lic); err != nil {
+		return nil, err
+	}
+	return lic, nil
+}
+
+// Verify checks signature, expiry, plugin, domain and revocation.
+// 토큰이 유효하지 않으면 Valid=false와 사유를 반환하고, error는 조회 실패에만 쓴다.
+func (s *LicenseService) Verify(token, pluginID, domainName string) (*LicenseVerification, error) {
+	pub, _ := s.signingKey.Public().(ed25519.PublicKey) //nolint:errcheck // type assertion, not error
+	claims, err := license.Verify(pub, token, pluginID, domainName, time.Now())
+	if err != nil {
+		return &LicenseVerification{Reason: licenseReason(err), Claims: claims}, nil
+	}
+
+	lic, err := s.repo.FindByID(claims.LicenseID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && lic.LicenseKey != token) {
+		return &LicenseVerification{Reason: "not_found", Claims: claims}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lic.RevokedAt != nil {
+		return &LicenseVerification{Reason: "revoked", Claims: claims, License: lic}, nil
+	}
+	return &LicenseVerification{Valid: true, Claims: claims, License: lic}, nil
+}
+
+// Revoke 관리자: 라이선스 폐기
+func (s *LicenseService) Revoke(id uint64, reason string) (*domain.PluginLicense, error) {
+	lic, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if lic.RevokedAt != nil {
+		return nil, errors.New("이미 폐기된 라이선스입니다")
+	}
+	now := time.Now()
+	lic.RevokedAt = &now
+	lic.RevokeReason = reason
+	if err := s.repo.Update(lic); err != nil {
+		return nil, err
+	}
+	return lic, nil
+}
+
+// List 관리자: 라이선스 목록
+func (s *LicenseService) List(filter domain.LicenseListFilter, page, perPage int) ([]domain.PluginLicense, int64, error) {
+	return s.repo.List(filter, page, perPage)
+}
+
+func licenseReason(err error) string {
+	switch {
+	case errors.Is(err, license.ErrBadSignature):
+		return "bad_signature"
+	case errors.Is(err, license.ErrExpired):
+		return "expired"
+	case errors.Is(err, license.ErrPluginMismatch):
+		return "plugin_mismatch"
+	case errors.Is(err, license.ErrDomainMismatch):
+		return "domain_mismatch"
+	case errors.Is(err, license.ErrDomainRequired):
+		return "domain_required"
+	default:
+		return "malformed"
+	}
+}
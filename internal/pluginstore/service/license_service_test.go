@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/pluginstore/domain"
+	"github.com/damoang/angple-backend/internal/pluginstore/repository"
+	"github.com/damoang/angple-backend/pkg/license"
+)
+
+func TestLicenseService_IssueVerifyRevoke(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&domain.PluginSubmission{}, &domain.PluginLicense{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	db.Create(&domain.PluginSubmission{ID: 1, DeveloperID: 7, PluginName: "seo-pro", Version: "1.0.0", Title: "SEO", Status: "approved"})
+	db.Create(&domain.PluginSubmission{ID: 2, DeveloperID: 7, PluginName: "draft", Version: "0.1.0", Title: "Draft", Status: "pending"})
+
+	key, err := license.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	svc := NewLicenseService(repository.NewLicenseRepository(db), repository.NewMarketplaceRepository(db), key)
+
+	if _, err := svc.Issue(&domain.LicenseIssueRequest{SubmissionID: 2}, 1); err == nil {
+		t.Error("expected error issuing a license for a pending submission")
+	}
+
+	expires := time.Now().Add(24 * time.Hour)
+	lic, err := svc.Issue(&domain.LicenseIssueRequest{SubmissionID: 1, Domain: "https://www.example.com", Seats: 3, ExpiresAt: &expires}, 1)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if lic.DeveloperID != 7 || lic.Domain != "example.com" || lic.LicenseKey == "" {
+		t.Fatalf("unexpected license: %+v", lic)
+	}
+
+	cases := []struct {
+		plugin, domain, reason string
+	}{
+		{"seo-pro", "example.com", ""},
+		{"seo-pro", "shop.example.com", ""},
+		{"seo-pro", "other.com", "domain_mismatch"},
+		{"seo-pro", "", "domain_required"},
+		{"other-plugin", "example.com", "plugin_mismatch"},
+	}
+	for _, tc := range cases {
+		res, err := svc.Verify(lic.LicenseKey, tc.plugin, tc.domain)
+		if err != nil {
+			t.Fatalf("verify: %v", err)
+		}
+		if res.Valid != (tc.reason == "") || res.Reason != tc.reason {
+			t.Errorf("Verify(%s, %s) = %+v, want reason %q", tc.plugin, tc.domain, res, tc.reason)
+		}
+	}
+
+	// 오프라인 검증: 공개 키만으로 확인
+	pub, err := license.ParsePublicKey(svc.PublicKey())
+	if err != nil {
+		t.Fatalf("public key: %v", err)
+	}
+	claims, err := license.Verify(pub, lic.LicenseKey, "seo-pro", "example.com", time.Now())
+	if err != nil || claims.Seats != 3 || claims.LicenseID != lic.ID {
+		t.Errorf("offline verify = %+v, %v", claims, err)
+	}
+	if _, err := license.Verify(pub, lic.LicenseKey, "seo-pro", "", expires.Add(time.Second)); err != license.ErrExpired {
+		t.Errorf("expected ErrExpired after expiry, got %v", err)
+	}
+
+	if _, err := svc.Revoke(lic.ID, "refund"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	res, err := svc.Verify(lic.LicenseKey, "seo-pro", "example.com")
+	if err != nil || res.Valid || res.Reason != "revoked" {
+		t.Errorf("expected revoked, got %+v, %v", res, err)
+	}
+
+	// 다른 키로 서명된 토큰
+	otherKey, _ := license.GenerateKey()
+	forged, _ := license.Sign(otherKey, &license.Claims{LicenseID: lic.ID, PluginID: "seo-pro"})
+	res, _ = svc.Verify(forged, "seo-pro", "")
+	if res.Valid || res.Reason != "bad_signature" {
+		t.Errorf("expected bad_signature, got %+v", res)
+	}
+}
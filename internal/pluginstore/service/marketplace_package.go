@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/damoang/angple-backend/internal/pluginstore/domain"
+	"github.com/damoang/angple-backend/pkg/license"
+	"github.com/damoang/angple-backend/pkg/storage"
+)
+
+// 패키지 업로드 에러
+var (
+	ErrPackageStorageUnavailable = errors.New("패키지 저장소가 설정되지 않았습니다")
+	ErrSigningKeyRequired        = errors.New("패키지 서명 키를 먼저 등록해야 합니다")
+	ErrPluginNameTaken           = errors.New("다른 개발자가 등록한 플러그인 이름입니다")
+	ErrVersionNotNewer           = errors.New("이전 제출보다 높은 버전이어야 합니다")
+	ErrPackageNotAvailable       = errors.New("설치할 수 있는 패키지가 없습니다")
+)
+
+// PackageStorage 플러그인 패키지 저장소 (pkg/storage.Backend)
+type PackageStorage interface {
+	Upload(ctx context.Context, key string, body io.Reader, contentType string, size int64) (*storage.UploadResult, error)
+	Download(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// SetStorage 패키지 저장소 설정 (없으면 패키지 업로드/설치 불가)
+func (s *MarketplaceService) SetStorage(st PackageStorage) {
+	s.storage = st
+}
+
+// SetSigningKey 개발자 패키지 서명 공개 키 등록/교체
+// 키를 교체하면 이전 키로 서명된 패키지는 더 이상 설치할 수 없다 (유출된 키 폐기).
+func (s *MarketplaceService) SetSigningKey(userID uint64, publicKey string) (*domain.PluginDeveloper, error) {
+	if _, err := license.ParsePublicKey(publicKey); err != nil {
+		return nil, fmt.Errorf("잘못된 서명 키: %w", err)
+	}
+	dev, err := s.repo.FindDeveloperByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	dev.SigningKey = publicKey
+	if err := s.repo.UpdateDeveloper(dev); err != nil {
+		return nil, err
+	}
+	return dev, nil
+}
+
+// UploadPackage 서명된 플러그인 패키지 업로드 → 검증 후 저장하고 대기 중 제출 생성
+func (s *MarketplaceService) UploadPackage(ctx context.Context, dev *domain.PluginDeveloper, data []byte, signature string, meta domain.PluginPackageMeta) (*domain.PluginSubmission, error) {
+	if s.storage == nil {
+		return nil, ErrPackageStorageUnavailable
+	}
+	if dev.SigningKey == "" {
+		return nil, ErrSigningKeyRequired
+	}
+	pub, err := license.ParsePublicKey(dev.SigningKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := plugin.VerifyPackageSignature(pub, data, signature); err != nil {
+		return nil, err
+	}
+	pkg, err := s.loader.InspectPackage(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSubmissionVersion(dev.ID, pkg.Manifest); err != nil {
+		return nil, err
+	}
+
+	name, version := pkg.Manifest.Name, pkg.Manifest.Version
+	key := fmt.Sprintf("plugins/packages/%s/%s-%s.tar.gz", name, name, version)
+	result, err := s.storage.Upload(ctx, key, bytes.NewReader(data), "application/gzip", pkg.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &domain.PluginSubmission{
+		DeveloperID:      dev.ID,
+		PluginName:       name,
+		Version:          version,
+		Title:            pkg.Manifest.Title,
+		Description:      pkg.Manifest.Description,
+		Category:         meta.Category,
+		Tags:             meta.Tags,
+		SourceURL:        meta.SourceURL,
+		Readme:           meta.Readme,
+		Status:           "pending",
+		PackageKey:       result.Key,
+		PackageChecksum:  pkg.Checksum,
+		PackageSize:      pkg.Size,
+		PackageSignature: signature,
+	}
+	if err := s.repo.CreateSubmission(sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// checkSubmissionVersion 이름 소유권과 버전 증가 확인 (거절된 제출은 제외)
+func (s *MarketplaceService) checkSubmissionVersion(developerID uint64, manifest *plugin.PluginManifest) error {
+	subs, err := s.repo.FindSubmissionsByName(manifest.Name)
+	if err != nil {
+		return err
+	}
+	next, err := plugin.ParseSemVer(manifest.Version)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		if sub.DeveloperID != developerID {
+			return ErrPluginNameTaken
+		}
+		if sub.Status == "rejected" {
+			continue
+		}
+		prev, err := plugin.ParseSemVer(sub.Version)
+		if err == nil && next.Compare(prev) <= 0 {
+			return fmt.Errorf("%w (기존 %s)", ErrVersionNotNewer, sub.Version)
+		}
+	}
+	return nil
+}
+
+// FetchPackage 승인된 제출의 패키지 다운로드 + 체크섬/서명 재검증
+func (s *MarketplaceService) FetchPackage(ctx context.Context, submissionID uint64) (*domain.PluginSubmission, []byte, error) {
+	if s.storage == nil {
+		return nil, nil, ErrPackageStorageUnavailable
+	}
+	sub, err := s.repo.FindSubmissionByID(submissionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sub.Status != "approved" || sub.PackageKey == "" {
+		return nil, nil, ErrPackageNotAvailable
+	}
+	dev, err := s.repo.FindDeveloperByID(sub.DeveloperID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if dev.Status != "active" || dev.SigningKey == "" {
+		return nil, nil, fmt.Errorf("%w: 개발자 계정 또는 서명 키가 유효하지 않습니다", ErrPackageNotAvailable)
+	}
+
+	body, err := s.storage.Download(ctx, sub.PackageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(io.LimitReader(body, plugin.MaxPackageSize+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if plugin.PackageChecksum(data) != sub.PackageChecksum {
+		return nil, nil, fmt.Errorf("%w: checksum mismatch", plugin.ErrInvalidPackage)
+	}
+	pub, err := license.ParsePublicKey(dev.SigningKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := plugin.VerifyPackageSignature(pub, data, sub.PackageSignature); err != nil {
+		return nil, nil, err
+	}
+	return sub, data, nil
+}
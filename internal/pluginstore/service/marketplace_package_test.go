@@ -0,0 +1,152 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/damoang/angple-backend/internal/pluginstore/domain"
+	"github.com/damoang/angple-backend/internal/pluginstore/repository"
+	"github.com/damoang/angple-backend/pkg/license"
+	"github.com/damoang/angple-backend/pkg/storage"
+)
+
+type memoryPackageStorage struct {
+	objects map[string][]byte
+}
+
+func (s *memoryPackageStorage) Upload(_ context.Context, key string, body io.Reader, contentType string, size int64) (*storage.UploadResult, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	s.objects[key] = data
+	return &storage.UploadResult{Key: key, ContentType: contentType, Size: size}, nil
+}
+
+func (s *memoryPackageStorage) Download(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func buildPluginPackage(t *testing.T, version string) []byte {
+	t.Helper()
+	files := map[string]string{
+		"plugin.yaml":             "name: hello\nversion: " + version + "\ntitle: Hello\nrequires:\n  angple: \">=1.0.0\"\n",
+		"migrations/001_init.sql": "CREATE TABLE hello_items (id INTEGER PRIMARY KEY);",
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestMarketplace_PackageUploadAndInstall(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewMarketplaceRepository(db)
+	if err := repo.AutoMigrate(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	st := &memoryPackageStorage{objects: make(map[string][]byte)}
+	mp := NewMarketplaceService(repo)
+	mp.SetStorage(st)
+
+	dev, err := mp.RegisterDeveloper(1, domain.DeveloperRegisterRequest{DisplayName: "dev", Email: "dev@example.com"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	priv, err := license.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buildPluginPackage(t, "1.0.0")
+	sig := plugin.SignPackage(priv, data)
+
+	if _, err := mp.UploadPackage(context.Background(), dev, data, sig, domain.PluginPackageMeta{}); !errors.Is(err, ErrSigningKeyRequired) {
+		t.Fatalf("expected ErrSigningKeyRequired, got %v", err)
+	}
+	pubKey := base64.StdEncoding.EncodeToString(priv.Public().(ed25519.PublicKey)) //nolint:errcheck // ed25519 public key
+	if dev, err = mp.SetSigningKey(1, pubKey); err != nil {
+		t.Fatalf("set signing key: %v", err)
+	}
+	if _, err := mp.UploadPackage(context.Background(), dev, data, plugin.SignPackage(priv, []byte("other")), domain.PluginPackageMeta{}); !errors.Is(err, plugin.ErrPackageSignature) {
+		t.Fatalf("expected signature error, got %v", err)
+	}
+
+	sub, err := mp.UploadPackage(context.Background(), dev, data, sig, domain.PluginPackageMeta{Category: "utility"})
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if sub.PluginName != "hello" || sub.Status != "pending" || sub.PackageChecksum != plugin.PackageChecksum(data) {
+		t.Fatalf("unexpected submission: %+v", sub)
+	}
+	if _, err := mp.UploadPackage(context.Background(), dev, data, sig, domain.PluginPackageMeta{}); !errors.Is(err, ErrVersionNotNewer) {
+		t.Errorf("expected ErrVersionNotNewer for the same version, got %v", err)
+	}
+	other, err := mp.RegisterDeveloper(2, domain.DeveloperRegisterRequest{DisplayName: "other", Email: "o@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.SigningKey = pubKey
+	next := buildPluginPackage(t, "2.0.0")
+	if _, err := mp.UploadPackage(context.Background(), other, next, plugin.SignPackage(priv, next), domain.PluginPackageMeta{}); !errors.Is(err, ErrPluginNameTaken) {
+		t.Errorf("expected ErrPluginNameTaken, got %v", err)
+	}
+
+	if _, _, err := mp.FetchPackage(context.Background(), sub.ID); !errors.Is(err, ErrPackageNotAvailable) {
+		t.Fatalf("expected pending submission to be unavailable, got %v", err)
+	}
+	if err := mp.ReviewSubmission(sub.ID, 9, true, ""); err != nil {
+		t.Fatal(err)
+	}
+	_, fetched, err := mp.FetchPackage(context.Background(), sub.ID)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	storeSvc, catalogSvc, _ := setupStoreService(t)
+	manager := plugin.NewManager(t.TempDir(), db, nil, plugin.NewDefaultLogger("test"), nil, nil)
+	manifest, err := storeSvc.InstallPackage(context.Background(), fetched, "admin", manager, domain.InstallOptions{})
+	if err != nil {
+		t.Fatalf("install package: %v", err)
+	}
+	if manifest.Name != "hello" || catalogSvc.GetManifest("hello") == nil {
+		t.Errorf("expected hello to be in the catalog")
+	}
+	if info, ok := manager.GetPlugin("hello"); !ok || info.Status != plugin.StatusEnabled {
+		t.Errorf("expected hello to be enabled, got %+v", info)
+	}
+	if !db.Migrator().HasTable("hello_items") {
+		t.Error("expected package migrations to run")
+	}
+
+	// 저장소의 패키지가 바뀌면 설치 거부
+	st.objects[sub.PackageKey] = next
+	if _, _, err := mp.FetchPackage(context.Background(), sub.ID); !errors.Is(err, plugin.ErrInvalidPackage) {
+		t.Errorf("expected checksum mismatch, got %v", err)
+	}
+}
@@ -4,18 +4,21 @@ import (
 	"errors"
 	"time"
 
+	"github.com/damoang/angple-backend/internal/plugin"
 	"github.com/damoang/angple-backend/internal/pluginstore/domain"
 	"github.com/damoang/angple-backend/internal/pluginstore/repository"
 )
 
 // MarketplaceService 플러그인 마켓플레이스 비즈니스 로직
 type MarketplaceService struct {
-	repo *repository.MarketplaceRepository
+	repo    *repository.MarketplaceRepository
+	loader  *plugin.Loader
+	storage PackageStorage
 }
 
 // NewMarketplaceService 생성자
 func NewMarketplaceService(repo *repository.MarketplaceRepository) *MarketplaceService {
-	return &MarketplaceService{repo: repo}
+	return &MarketplaceService{repo: repo, loader: plugin.NewLoader("")}
 }
 
 // === Public API ===
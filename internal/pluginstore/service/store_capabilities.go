@@ -0,0 +1,105 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/damoang/angple-backend/internal/pluginstore/domain"
+)
+
+// ErrCapabilityApprovalRequired capabilities 승인 없이 설치 시도
+var ErrCapabilityApprovalRequired = errors.New("plugin capabilities must be approved")
+
+// CapabilityStatus 플러그인 capabilities 요청/승인 현황
+type CapabilityStatus struct {
+	Requested *plugin.Capabilities `json:"requested"`
+	Approved  *plugin.Capabilities `json:"approved"`
+	Pending   bool                 `json:"pending"` // 요청이 승인과 달라 재승인이 필요
+}
+
+// ApprovedCapabilities 승인된 capabilities 조회 (plugin.CapabilityProvider)
+func (s *StoreService) ApprovedCapabilities(pluginName string) (*plugin.Capabilities, bool) {
+	inst, err := s.installRepo.FindByName(pluginName)
+	if err != nil || inst == nil || inst.ApprovedCapabilities == nil {
+		return nil, false
+	}
+	var caps plugin.Capabilities
+	if err := json.Unmarshal([]byte(*inst.ApprovedCapabilities), &caps); err != nil {
+		s.logger.Warn("Invalid approved capabilities for plugin %s: %v", pluginName, err)
+		return &plugin.Capabilities{}, true
+	}
+	return &caps, true
+}
+
+// RecordViolation capability 위반을 plugin_events에 기록 (plugin.ViolationRecorder)
+func (s *StoreService) RecordViolation(v plugin.CapabilityViolation) {
+	s.logEvent(v.Plugin, domain.EventCapabilityViolation, map[string]string{
+		"kind":   v.Kind,
+		"target": v.Target,
+		"detail": v.Detail,
+	}, "system")
+}
+
+// GetCapabilities 매니페스트 요청과 승인된 capabilities 비교
+func (s *StoreService) GetCapabilities(name string) (*CapabilityStatus, error) {
+	manifest := s.catalogSvc.GetManifest(name)
+	if manifest == nil {
+		return nil, fmt.Errorf("plugin %s not found in catalog", name)
+	}
+	status := &CapabilityStatus{Requested: manifest.Capabilities}
+	if caps, ok := s.ApprovedCapabilities(name); ok {
+		status.Approved = caps
+	}
+	requested, err := marshalCapabilities(status.Requested)
+	if err != nil {
+		return nil, err
+	}
+	approved, err := marshalCapabilities(status.Approved)
+	if err != nil {
+		return nil, err
+	}
+	status.Pending = requested != nil && (approved == nil || *requested != *approved)
+	return status, nil
+}
+
+// ApproveCapabilities 설치된 플러그인의 현재 매니페스트 capabilities 승인
+// 활성화된 플러그인에는 다음 활성화(재로드) 시 적용된다.
+func (s *StoreService) ApproveCapabilities(name, actorID string) error {
+	manifest := s.catalogSvc.GetManifest(name)
+	if manifest == nil {
+		return fmt.Errorf("plugin %s not found in catalog", name)
+	}
+	inst, err := s.installRepo.FindByName(name)
+	if err != nil {
+		return fmt.Errorf("plugin %s is not installed", name)
+	}
+	approved, err := marshalCapabilities(manifest.Capabilities)
+	if err != nil {
+		return err
+	}
+	if approved == nil {
+		empty := "{}"
+		approved = &empty
+	}
+	inst.ApprovedCapabilities = approved
+	if err := s.installRepo.Update(inst); err != nil {
+		return fmt.Errorf("failed to save approved capabilities: %w", err)
+	}
+	s.logEvent(name, domain.EventCapabilitiesApproved, map[string]string{"capabilities": *approved}, actorID)
+	return nil
+}
+
+// marshalCapabilities capabilities JSON (nil이면 nil)
+func marshalCapabilities(caps *plugin.Capabilities) (*string, error) {
+	if caps == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(caps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode capabilities: %w", err)
+	}
+	str := string(b)
+	return &str, nil
+}
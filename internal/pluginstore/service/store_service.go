@@ -1,10 +1,12 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/damoang/angple-backend/internal/common"
 	"github.com/damoang/angple-backend/internal/plugin"
 	"github.com/damoang/angple-backend/internal/pluginstore/domain"
 	"github.com/damoang/angple-backend/internal/pluginstore/repository"
@@ -17,6 +19,13 @@ type StoreService struct {
 	settingRepo *repository.SettingRepository
 	catalogSvc  *CatalogService
 	logger      plugin.Logger
+	quota       QuotaReserver
+}
+
+// QuotaReserver reserves tenant plan quota (service.QuotaService)
+type QuotaReserver interface {
+	Reserve(ctx context.Context, resource string, amount int64) error
+	Release(ctx context.Context, resource string, amount int64)
 }
 
 // NewStoreService 생성자
@@ -36,13 +45,22 @@ func NewStoreService(
 	}
 }
 
+// SetQuota sets the plan quota checker for installs (플러그인 수 한도)
+func (s *StoreService) SetQuota(q QuotaReserver) {
+	s.quota = q
+}
+
 // Install 플러그인 설치 (DB 레코드 생성 + Enable)
-func (s *StoreService) Install(name, actorID string, manager *plugin.Manager) error {
+// 매니페스트에 capabilities가 있으면 opts.ApproveCapabilities로 관리자 승인이 필요하다.
+func (s *StoreService) Install(ctx context.Context, name, actorID string, manager *plugin.Manager, opts domain.InstallOptions) error {
 	// 카탈로그에 존재하는지 확인
 	manifest := s.catalogSvc.GetManifest(name)
 	if manifest == nil {
 		return fmt.Errorf("plugin %s not found in catalog", name)
 	}
+	if manifest.Capabilities != nil && !opts.ApproveCapabilities {
+		return fmt.Errorf("plugin %s: %w", name, ErrCapabilityApprovalRequired)
+	}
 
 	// 이미 설치되었는지 확인
 	existing, _ := s.installRepo.FindByName(name) //nolint:errcheck // not found is expected
@@ -60,6 +78,13 @@ func (s *StoreService) Install(name, actorID string, manager *plugin.Manager) er
 		return err
 	}
 
+	// 플랜 플러그인 수 한도
+	if s.quota != nil {
+		if err := s.quota.Reserve(ctx, common.QuotaPlugins, 1); err != nil {
+			return err
+		}
+	}
+
 	// DB 레코드 생성
 	now := time.Now()
 	inst := &domain.PluginInstallation{
@@ -70,8 +95,15 @@ func (s *StoreService) Install(name, actorID string, manager *plugin.Manager) er
 		EnabledAt:   &now,
 		InstalledBy: &actorID,
 	}
+	approved, err := marshalCapabilities(manifest.Capabilities)
+	if err != nil {
+		s.releaseQuota(ctx)
+		return err
+	}
+	inst.ApprovedCapabilities = approved
 
 	if err := s.installRepo.Create(inst); err != nil {
+		s.releaseQuota(ctx)
 		return fmt.Errorf("failed to create installation record: %w", err)
 	}
 
@@ -91,11 +123,50 @@ func (s *StoreService) Install(name, actorID string, manager *plugin.Manager) er
 
 	// 이벤트 로그
 	s.logEvent(name, domain.EventInstalled, map[string]string{"version": manifest.Version}, actorID)
+	if approved != nil {
+		s.logEvent(name, domain.EventCapabilitiesApproved, map[string]string{"capabilities": *approved}, actorID)
+	}
 
 	s.logger.Info("Plugin %s installed and enabled by %s", name, actorID)
 	return nil
 }
 
+// InstallPackage 패키지를 플러그인 디렉토리에 풀고 설치 (마켓플레이스 설치)
+// capabilities 승인이 필요하면 풀기 전에 매니페스트와 함께 ErrCapabilityApprovalRequired를 반환하고,
+// 설치 레코드가 생기기 전에 실패하면 풀었던 파일과 카탈로그 항목을 되돌린다.
+func (s *StoreService) InstallPackage(ctx context.Context, data []byte, actorID string, manager *plugin.Manager, opts domain.InstallOptions) (*plugin.PluginManifest, error) {
+	pkg, err := manager.InspectPackage(data)
+	if err != nil {
+		return nil, err
+	}
+	name := pkg.Manifest.Name
+	if pkg.Manifest.Capabilities != nil && !opts.ApproveCapabilities {
+		return pkg.Manifest, fmt.Errorf("plugin %s: %w", name, ErrCapabilityApprovalRequired)
+	}
+	if s.catalogSvc.GetManifest(name) != nil {
+		return pkg.Manifest, fmt.Errorf("%w: %s", plugin.ErrPluginExists, name)
+	}
+
+	info, err := manager.InstallPackage(data)
+	if err != nil {
+		return pkg.Manifest, err
+	}
+	s.catalogSvc.RegisterManifest(info.Manifest)
+
+	if err := s.Install(ctx, name, actorID, manager, opts); err != nil {
+		if inst, _ := s.installRepo.FindByName(name); inst == nil { //nolint:errcheck // not found means nothing was recorded
+			s.catalogSvc.UnregisterManifest(name)
+			if rmErr := manager.RemovePackage(name); rmErr != nil {
+				s.logger.Error("Failed to roll back plugin package %s: %v", name, rmErr)
+			}
+		}
+		return info.Manifest, err
+	}
+
+	s.logger.Info("Plugin %s v%s installed from package %s", name, info.Manifest.Version, pkg.Checksum)
+	return info.Manifest, nil
+}
+
 // Enable 플러그인 활성화
 func (s *StoreService) Enable(name, actorID string, manager *plugin.Manager) error {
 	inst, err := s.installRepo.FindByName(name)
@@ -171,15 +242,28 @@ func (s *StoreService) Disable(name, actorID string, manager *plugin.Manager) er
 }
 
 // Uninstall 플러그인 제거
-func (s *StoreService) Uninstall(name, actorID string, manager *plugin.Manager) error {
+// opts.PurgeData이면 down 마이그레이션을 역순으로 실행해 플러그인 데이터를 삭제한다.
+// opts.DryRun이면 아무것도 변경하지 않고 실행될 down SQL만 반환한다.
+func (s *StoreService) Uninstall(ctx context.Context, name, actorID string, manager *plugin.Manager, opts domain.UninstallOptions) ([]plugin.MigrationStep, error) {
 	inst, err := s.installRepo.FindByName(name)
 	if err != nil {
-		return fmt.Errorf("plugin %s is not installed", name)
+		return nil, fmt.Errorf("plugin %s is not installed", name)
 	}
 
 	// 역방향 의존성 체크: 다른 설치된 플러그인이 이 플러그인에 의존하는지 확인
 	if dependents, err := s.checkReverseDependencies(name, manager); err == nil && len(dependents) > 0 {
-		return fmt.Errorf("cannot uninstall %s: plugins %v depend on it", name, dependents)
+		return nil, fmt.Errorf("cannot uninstall %s: plugins %v depend on it", name, dependents)
+	}
+
+	steps := []plugin.MigrationStep{}
+	if opts.PurgeData {
+		// 실행 전에 down 파일 누락/체크섬 불일치를 먼저 확인
+		if steps, err = manager.RollbackMigrations(name, true); err != nil {
+			return nil, fmt.Errorf("cannot purge %s: %w", name, err)
+		}
+	}
+	if opts.DryRun {
+		return steps, nil
 	}
 
 	// 라이프사이클 훅: OnUninstall
@@ -192,6 +276,13 @@ func (s *StoreService) Uninstall(name, actorID string, manager *plugin.Manager)
 		}
 	}
 
+	// 데이터 삭제 (down 마이그레이션)
+	if opts.PurgeData {
+		if steps, err = manager.RollbackMigrations(name, false); err != nil {
+			return nil, fmt.Errorf("failed to purge plugin data: %w", err)
+		}
+	}
+
 	// 설정 삭제
 	if err := s.settingRepo.DeleteByPlugin(name); err != nil {
 		s.logger.Warn("Failed to delete settings for plugin %s: %v", name, err)
@@ -199,12 +290,17 @@ func (s *StoreService) Uninstall(name, actorID string, manager *plugin.Manager)
 
 	// 설치 레코드 삭제
 	if err := s.installRepo.Delete(name); err != nil {
-		return fmt.Errorf("failed to delete installation: %w", err)
+		return nil, fmt.Errorf("failed to delete installation: %w", err)
 	}
+	s.releaseQuota(ctx)
 
-	s.logEvent(name, domain.EventUninstalled, nil, actorID)
-	s.logger.Info("Plugin %s uninstalled by %s", name, actorID)
-	return nil
+	var details map[string]string
+	if opts.PurgeData {
+		details = map[string]string{"purged": "true"}
+	}
+	s.logEvent(name, domain.EventUninstalled, details, actorID)
+	s.logger.Info("Plugin %s uninstalled by %s (purge=%t)", name, actorID, opts.PurgeData)
+	return steps, nil
 }
 
 // BootEnabledPlugins 서버 부팅 시 DB에서 enabled 플러그인 자동 활성화
@@ -399,6 +495,12 @@ func (s *StoreService) checkReverseDependencies(name string, manager *plugin.Man
 }
 
 // logEvent 이벤트 기록 헬퍼
+func (s *StoreService) releaseQuota(ctx context.Context) {
+	if s.quota != nil {
+		s.quota.Release(ctx, common.QuotaPlugins, 1)
+	}
+}
+
 func (s *StoreService) logEvent(pluginName, eventType string, details map[string]string, actorID string) {
 	var detailsJSON *string
 	if details != nil {
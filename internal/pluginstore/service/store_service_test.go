@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/damoang/angple-backend/internal/plugin"
@@ -11,6 +13,8 @@ import (
 	"gorm.io/gorm"
 )
 
+var ctx = context.Background()
+
 func setupTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
@@ -64,7 +68,7 @@ func (m *mockPlugin) Shutdown() error                          { m.shutdown = tr
 func TestInstallPlugin(t *testing.T) {
 	storeSvc, _, manager := setupStoreService(t)
 
-	err := storeSvc.Install("test-plugin", "admin1", manager)
+	err := storeSvc.Install(ctx, "test-plugin", "admin1", manager, domain.InstallOptions{})
 	if err != nil {
 		t.Fatalf("Install failed: %v", err)
 	}
@@ -81,8 +85,8 @@ func TestInstallPlugin(t *testing.T) {
 func TestInstallDuplicate(t *testing.T) {
 	storeSvc, _, manager := setupStoreService(t)
 
-	_ = storeSvc.Install("test-plugin", "admin1", manager)
-	err := storeSvc.Install("test-plugin", "admin1", manager)
+	_ = storeSvc.Install(ctx, "test-plugin", "admin1", manager, domain.InstallOptions{})
+	err := storeSvc.Install(ctx, "test-plugin", "admin1", manager, domain.InstallOptions{})
 	if err == nil {
 		t.Fatal("expected error for duplicate install")
 	}
@@ -91,7 +95,7 @@ func TestInstallDuplicate(t *testing.T) {
 func TestDisableEnable(t *testing.T) {
 	storeSvc, _, manager := setupStoreService(t)
 
-	_ = storeSvc.Install("test-plugin", "admin1", manager)
+	_ = storeSvc.Install(ctx, "test-plugin", "admin1", manager, domain.InstallOptions{})
 
 	// Disable
 	err := storeSvc.Disable("test-plugin", "admin1", manager)
@@ -117,9 +121,9 @@ func TestDisableEnable(t *testing.T) {
 func TestUninstall(t *testing.T) {
 	storeSvc, _, manager := setupStoreService(t)
 
-	_ = storeSvc.Install("test-plugin", "admin1", manager)
+	_ = storeSvc.Install(ctx, "test-plugin", "admin1", manager, domain.InstallOptions{})
 
-	err := storeSvc.Uninstall("test-plugin", "admin1", manager)
+	_, err := storeSvc.Uninstall(ctx, "test-plugin", "admin1", manager, domain.UninstallOptions{})
 	if err != nil {
 		t.Fatalf("Uninstall failed: %v", err)
 	}
@@ -133,7 +137,7 @@ func TestUninstall(t *testing.T) {
 func TestInstallNotInCatalog(t *testing.T) {
 	storeSvc, _, manager := setupStoreService(t)
 
-	err := storeSvc.Install("nonexistent", "admin1", manager)
+	err := storeSvc.Install(ctx, "nonexistent", "admin1", manager, domain.InstallOptions{})
 	if err == nil {
 		t.Fatal("expected error for unknown plugin")
 	}
@@ -142,7 +146,7 @@ func TestInstallNotInCatalog(t *testing.T) {
 func TestBootEnabledPlugins(t *testing.T) {
 	storeSvc, _, manager := setupStoreService(t)
 
-	_ = storeSvc.Install("test-plugin", "admin1", manager)
+	_ = storeSvc.Install(ctx, "test-plugin", "admin1", manager, domain.InstallOptions{})
 
 	// 새 매니저로 부팅 시뮬레이션
 	logger := plugin.NewDefaultLogger("test")
@@ -181,9 +185,9 @@ func TestConflictCheck(t *testing.T) {
 	manager.RegisterBuiltIn("plugin-a", &mockPlugin{name: "plugin-a"}, pluginA)
 	manager.RegisterBuiltIn("plugin-b", &mockPlugin{name: "plugin-b"}, pluginB)
 
-	_ = storeSvc.Install("plugin-a", "admin", manager)
+	_ = storeSvc.Install(ctx, "plugin-a", "admin", manager, domain.InstallOptions{})
 
-	err := storeSvc.Install("plugin-b", "admin", manager)
+	err := storeSvc.Install(ctx, "plugin-b", "admin", manager, domain.InstallOptions{})
 	if err == nil {
 		t.Fatal("expected conflict error")
 	}
@@ -212,10 +216,10 @@ func TestConflictBidirectional(t *testing.T) {
 	manager.RegisterBuiltIn("plugin-y", &mockPlugin{name: "plugin-y"}, pluginY)
 
 	// plugin-y 먼저 설치 (활성)
-	_ = storeSvc.Install("plugin-y", "admin", manager)
+	_ = storeSvc.Install(ctx, "plugin-y", "admin", manager, domain.InstallOptions{})
 
 	// plugin-x 설치 시도 → plugin-y가 plugin-x를 충돌로 선언했으므로 차단
-	err := storeSvc.Install("plugin-x", "admin", manager)
+	err := storeSvc.Install(ctx, "plugin-x", "admin", manager, domain.InstallOptions{})
 	if err == nil {
 		t.Fatal("expected conflict error from bidirectional check")
 	}
@@ -241,8 +245,8 @@ func TestNoConflict(t *testing.T) {
 	manager.RegisterBuiltIn("plugin-a", &mockPlugin{name: "plugin-a"}, pluginA)
 	manager.RegisterBuiltIn("plugin-c", &mockPlugin{name: "plugin-c"}, pluginC)
 
-	_ = storeSvc.Install("plugin-a", "admin", manager)
-	err := storeSvc.Install("plugin-c", "admin", manager)
+	_ = storeSvc.Install(ctx, "plugin-a", "admin", manager, domain.InstallOptions{})
+	err := storeSvc.Install(ctx, "plugin-c", "admin", manager, domain.InstallOptions{})
 	if err != nil {
 		t.Fatalf("expected no conflict, got: %v", err)
 	}
@@ -274,8 +278,8 @@ func TestDisableBlockedByDependency(t *testing.T) {
 	manager.RegisterBuiltIn("base-plugin", &mockPlugin{name: "base-plugin"}, basePlugin)
 	manager.RegisterBuiltIn("child-plugin", &mockPlugin{name: "child-plugin"}, childPlugin)
 
-	_ = storeSvc.Install("base-plugin", "admin", manager)
-	_ = storeSvc.Install("child-plugin", "admin", manager)
+	_ = storeSvc.Install(ctx, "base-plugin", "admin", manager, domain.InstallOptions{})
+	_ = storeSvc.Install(ctx, "child-plugin", "admin", manager, domain.InstallOptions{})
 
 	// base-plugin 비활성화 시도 → child-plugin이 의존하므로 차단
 	err := storeSvc.Disable("base-plugin", "admin", manager)
@@ -310,18 +314,18 @@ func TestUninstallBlockedByDependency(t *testing.T) {
 	manager.RegisterBuiltIn("base-plugin", &mockPlugin{name: "base-plugin"}, basePlugin)
 	manager.RegisterBuiltIn("child-plugin", &mockPlugin{name: "child-plugin"}, childPlugin)
 
-	_ = storeSvc.Install("base-plugin", "admin", manager)
-	_ = storeSvc.Install("child-plugin", "admin", manager)
+	_ = storeSvc.Install(ctx, "base-plugin", "admin", manager, domain.InstallOptions{})
+	_ = storeSvc.Install(ctx, "child-plugin", "admin", manager, domain.InstallOptions{})
 
 	// base-plugin 삭제 시도 → child-plugin이 의존하므로 차단
-	err := storeSvc.Uninstall("base-plugin", "admin", manager)
+	_, err := storeSvc.Uninstall(ctx, "base-plugin", "admin", manager, domain.UninstallOptions{})
 	if err == nil {
 		t.Fatal("expected error: cannot uninstall base-plugin while child-plugin depends on it")
 	}
 
 	// child-plugin 먼저 삭제 → 그 후 base-plugin 삭제 가능
-	_ = storeSvc.Uninstall("child-plugin", "admin", manager)
-	err = storeSvc.Uninstall("base-plugin", "admin", manager)
+	_, _ = storeSvc.Uninstall(ctx, "child-plugin", "admin", manager, domain.UninstallOptions{})
+	_, err = storeSvc.Uninstall(ctx, "base-plugin", "admin", manager, domain.UninstallOptions{})
 	if err != nil {
 		t.Fatalf("expected base-plugin uninstall to succeed after child removed: %v", err)
 	}
@@ -330,7 +334,7 @@ func TestUninstallBlockedByDependency(t *testing.T) {
 func TestGetEvents(t *testing.T) {
 	storeSvc, _, manager := setupStoreService(t)
 
-	_ = storeSvc.Install("test-plugin", "admin1", manager)
+	_ = storeSvc.Install(ctx, "test-plugin", "admin1", manager, domain.InstallOptions{})
 
 	events, err := storeSvc.GetEvents("test-plugin", 10)
 	if err != nil {
@@ -340,3 +344,93 @@ func TestGetEvents(t *testing.T) {
 		t.Error("expected at least one event after install")
 	}
 }
+
+func TestInstall_RequiresCapabilityApproval(t *testing.T) {
+	storeSvc, catalogSvc, manager := setupStoreService(t)
+	manifest := &plugin.PluginManifest{
+		Name: "shop", Version: "1.0.0", Title: "Shop",
+		Capabilities: &plugin.Capabilities{Tables: plugin.TableCapabilities{Write: []string{"shop_*"}}},
+	}
+	catalogSvc.RegisterManifest(manifest)
+	if err := manager.RegisterBuiltIn("shop", &mockPlugin{name: "shop"}, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	err := storeSvc.Install(ctx, "shop", "admin1", manager, domain.InstallOptions{})
+	if !errors.Is(err, ErrCapabilityApprovalRequired) {
+		t.Fatalf("expected approval error, got %v", err)
+	}
+
+	if err := storeSvc.Install(ctx, "shop", "admin1", manager, domain.InstallOptions{ApproveCapabilities: true}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	caps, ok := storeSvc.ApprovedCapabilities("shop")
+	if !ok || !caps.CanWrite("shop_orders") || caps.CanRead("g5_member") {
+		t.Errorf("unexpected approved capabilities: %+v", caps)
+	}
+	status, err := storeSvc.GetCapabilities("shop")
+	if err != nil || status.Pending {
+		t.Errorf("expected approved capabilities to match manifest, got %+v %v", status, err)
+	}
+}
+
+func TestUpgradeBuiltIn(t *testing.T) {
+	db := setupTestDB(t)
+	installRepo := repository.NewInstallationRepository(db)
+	settingRepo := repository.NewSettingRepository(db)
+	catalogSvc := NewCatalogService(installRepo)
+
+	basePlugin := &plugin.PluginManifest{Name: "base-plugin", Version: "1.0.0", Settings: []plugin.SettingConfig{
+		{Key: "mode", Type: "string"}, {Key: "legacy", Type: "string"},
+	}}
+	childPlugin := &plugin.PluginManifest{
+		Name:     "child-plugin",
+		Version:  "1.0.0",
+		Requires: plugin.Requires{Plugins: []plugin.PluginDependency{{Name: "base-plugin", Version: "^1.0.0"}}},
+	}
+	catalogSvc.RegisterManifest(basePlugin)
+	catalogSvc.RegisterManifest(childPlugin)
+
+	logger := plugin.NewDefaultLogger("test")
+	storeSvc := NewStoreService(installRepo, repository.NewEventRepository(db), settingRepo, catalogSvc, logger)
+	manager := plugin.NewManager("", db, nil, logger, nil, nil)
+	manager.RegisterBuiltIn("base-plugin", &mockPlugin{name: "base-plugin"}, basePlugin)
+	manager.RegisterBuiltIn("child-plugin", &mockPlugin{name: "child-plugin"}, childPlugin)
+	_ = storeSvc.Install(ctx, "base-plugin", "admin", manager, domain.InstallOptions{})
+	_ = storeSvc.Install(ctx, "child-plugin", "admin", manager, domain.InstallOptions{})
+	for key, value := range map[string]string{"mode": "fast", "legacy": "x"} {
+		v := value
+		_ = settingRepo.Set(&domain.PluginSetting{PluginName: "base-plugin", SettingKey: key, SettingValue: &v})
+	}
+
+	if _, err := storeSvc.Upgrade(ctx, "base-plugin", "admin", manager, domain.UpgradeOptions{}); !errors.Is(err, plugin.ErrUpgradeNotNewer) {
+		t.Fatalf("expected ErrUpgradeNotNewer, got %v", err)
+	}
+
+	// 새 바이너리의 매니페스트
+	basePlugin.Version = "2.0.0"
+	if _, err := storeSvc.Upgrade(ctx, "base-plugin", "admin", manager, domain.UpgradeOptions{}); err == nil {
+		t.Fatal("expected child-plugin's ^1.0.0 constraint to block the upgrade")
+	}
+
+	basePlugin.Version = "1.1.0"
+	basePlugin.Settings = []plugin.SettingConfig{{Key: "mode", Type: "select", Options: []plugin.SettingOption{{Value: "fast"}}}}
+	result, err := storeSvc.Upgrade(ctx, "base-plugin", "admin", manager, domain.UpgradeOptions{})
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if result.FromVersion != "1.0.0" || result.ToVersion != "1.1.0" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	inst, _ := storeSvc.GetInstallation("base-plugin")
+	if inst.Version != "1.1.0" || inst.Status != domain.StatusEnabled {
+		t.Errorf("unexpected installation: %+v", inst)
+	}
+	saved, _ := settingRepo.GetAll("base-plugin")
+	if len(saved) != 1 || saved[0].SettingKey != "mode" || *saved[0].SettingValue != "fast" {
+		t.Errorf("expected only the still-valid setting to be kept, got %+v", saved)
+	}
+	if info, _ := manager.GetPlugin("base-plugin"); info.Status != plugin.StatusEnabled {
+		t.Errorf("expected base-plugin to stay enabled, got %s", info.Status)
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/damoang/angple-backend/internal/pluginstore/domain"
+)
+
+// Upgrade 설치된 플러그인을 새 버전으로 업그레이드
+// Core 버전, 의존성, 이 플러그인에 의존하는 플러그인의 버전 범위를 검증하고, 설정 값은 새 스키마에
+// 맞는 것만 유지한다. 새 capabilities는 새 버전 활성화에만 적용하고 성공한 뒤에 승인 기록으로 저장하므로,
+// 매니저가 롤백하면 이전 버전은 기존 승인으로 다시 활성화된다.
+func (s *StoreService) Upgrade(ctx context.Context, name, actorID string, manager *plugin.Manager, opts domain.UpgradeOptions) (*plugin.UpgradeResult, error) {
+	inst, err := s.installRepo.FindByName(name)
+	if err != nil || inst == nil {
+		return nil, fmt.Errorf("plugin %s is not installed", name)
+	}
+
+	target, err := manager.UpgradeTarget(name, opts.Package)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUpgrade(name, inst, target, manager); err != nil {
+		return nil, err
+	}
+
+	approved, err := marshalCapabilities(target.Capabilities)
+	if err != nil {
+		return nil, err
+	}
+	if !sameCapabilities(approved, inst.ApprovedCapabilities) && !opts.ApproveCapabilities {
+		return nil, fmt.Errorf("plugin %s %s: %w", name, target.Version, ErrCapabilityApprovalRequired)
+	}
+
+	dropped, err := s.migrateSettings(name, target)
+	if err != nil {
+		return nil, err
+	}
+	prevCaps := inst.ApprovedCapabilities
+
+	result, err := manager.UpgradeWithCapabilities(name, opts.Package, target.Capabilities)
+	if result != nil {
+		// 내장 플러그인은 매니저의 매니페스트가 이미 새 버전이므로 설치 기록이 이전 버전의 기준
+		result.FromVersion = inst.Version
+	}
+	if err != nil {
+		s.restoreSettings(dropped)
+		if result != nil && !result.RolledBack {
+			// 내장 플러그인은 되돌릴 수 없어 에러 상태로 남는다
+			errMsg := err.Error()
+			inst.Status, inst.ErrorMessage = domain.StatusError, &errMsg
+		}
+		_ = s.installRepo.Update(inst) //nolint:errcheck // best-effort rollback
+		s.logEvent(name, domain.EventUpgradeFailed, map[string]string{
+			"from": inst.Version, "to": target.Version, "error": err.Error(),
+		}, actorID)
+		return result, err
+	}
+
+	from := inst.Version
+	inst.Version = target.Version
+	inst.ApprovedCapabilities = approved
+	if err := s.installRepo.Update(inst); err != nil {
+		return result, fmt.Errorf("plugin %s upgraded but failed to update installation record: %w", name, err)
+	}
+	s.catalogSvc.RegisterManifest(target)
+
+	details := map[string]string{"from": from, "to": target.Version, "migrations": strings.Join(result.Migrations, ",")}
+	if len(dropped) > 0 {
+		keys := make([]string, len(dropped))
+		for i, d := range dropped {
+			keys[i] = d.SettingKey
+		}
+		details["dropped_settings"] = strings.Join(keys, ",")
+	}
+	s.logEvent(name, domain.EventUpgraded, details, actorID)
+	if approved != nil && !sameCapabilities(approved, prevCaps) {
+		s.logEvent(name, domain.EventCapabilitiesApproved, map[string]string{"capabilities": *approved}, actorID)
+	}
+
+	s.logger.Info("Plugin %s upgraded %s -> %s by %s", name, from, target.Version, actorID)
+	return result, nil
+}
+
+// checkUpgrade 버전 증가, 의존성, 역의존 플러그인의 버전 범위 검증
+func (s *StoreService) checkUpgrade(name string, inst *domain.PluginInstallation, target *plugin.PluginManifest, manager *plugin.Manager) error {
+	next, err := plugin.ParseSemVer(target.Version)
+	if err != nil {
+		return err
+	}
+	if cur, err := plugin.ParseSemVer(inst.Version); err == nil && next.Compare(cur) <= 0 {
+		return fmt.Errorf("%w: installed %s, target %s", plugin.ErrUpgradeNotNewer, inst.Version, target.Version)
+	}
+
+	if err := s.checkDependencies(target); err != nil {
+		return err
+	}
+
+	dependents, err := s.checkReverseDependencies(name, manager)
+	if err != nil {
+		return err
+	}
+	for _, depName := range dependents {
+		p, ok := manager.GetPlugin(depName)
+		if !ok || p.Manifest == nil {
+			continue
+		}
+		for _, dep := range p.Manifest.Requires.Plugins {
+			if dep.Name != name || dep.Version == "" {
+				continue
+			}
+			if err := plugin.CheckVersionRange(target.Version, dep.Version); err != nil {
+				return fmt.Errorf("plugin %s requires %s %s, upgrade target is %s", depName, name, dep.Version, target.Version)
+			}
+		}
+	}
+	return nil
+}
+
+// migrateSettings 새 스키마에 없거나 검증을 통과하지 못하는 설정 값을 삭제하고 삭제한 값을 반환
+// 새 키는 저장하지 않아도 매니페스트 기본값이 적용된다.
+func (s *StoreService) migrateSettings(name string, target *plugin.PluginManifest) ([]domain.PluginSetting, error) {
+	saved, err := s.settingRepo.GetAll(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	schema := make(map[string]plugin.SettingConfig, len(target.Settings))
+	for _, cfg := range target.Settings {
+		schema[cfg.Key] = cfg
+	}
+
+	var dropped []domain.PluginSetting
+	for _, setting := range saved {
+		cfg, ok := schema[setting.SettingKey]
+		if ok && (setting.SettingValue == nil || ValidateSetting(cfg, *setting.SettingValue) == nil) {
+			continue
+		}
+		if err := s.settingRepo.Delete(name, setting.SettingKey); err != nil {
+			s.restoreSettings(dropped)
+			return nil, fmt.Errorf("failed to migrate setting %s: %w", setting.SettingKey, err)
+		}
+		dropped = append(dropped, setting)
+	}
+	return dropped, nil
+}
+
+// restoreSettings migrateSettings로 삭제한 값 복구
+func (s *StoreService) restoreSettings(settings []domain.PluginSetting) {
+	for i := range settings {
+		restored := domain.PluginSetting{
+			PluginName:   settings[i].PluginName,
+			SettingKey:   settings[i].SettingKey,
+			SettingValue: settings[i].SettingValue,
+		}
+		if err := s.settingRepo.Set(&restored); err != nil {
+			s.logger.Error("Failed to restore setting %s/%s: %v", restored.PluginName, restored.SettingKey, err)
+		}
+	}
+}
+
+func sameCapabilities(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MediaRepository handles media object and reference persistence
+type MediaRepository struct {
+	db *gorm.DB
+}
+
+// NewMediaRepository creates a new MediaRepository
+func NewMediaRepository(db *gorm.DB) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+// AutoMigrate creates media tables
+func (r *MediaRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.MediaObject{}, &domain.MediaReference{})
+}
+
+// FindByHash retrieves a site's object by content hash (nil if none)
+func (r *MediaRepository) FindByHash(ctx context.Context, siteID, hash string) (*domain.MediaObject, error) {
+	var obj domain.MediaObject
+	err := r.db.WithContext(ctx).Where("site_id = ? AND hash = ?", siteID, hash).First(&obj).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+// FindByKey retrieves an object by storage key (nil if none)
+func (r *MediaRepository) FindByKey(ctx context.Context, key string) (*domain.MediaObject, error) {
+	var obj domain.MediaObject
+	err := r.db.WithContext(ctx).Where("storage_key = ?", key).First(&obj).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+// FindIDsByKeys returns the IDs of registered objects among keys
+func (r *MediaRepository) FindIDsByKeys(ctx context.Context, keys []string) ([]int64, error) {
+	var ids []int64
+	if len(keys) == 0 {
+		return ids, nil
+	}
+	err := r.db.WithContext(ctx).Model(&domain.MediaObject{}).Where("storage_key IN ?", keys).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Create creates a media object
+func (r *MediaRepository) Create(ctx context.Context, obj *domain.MediaObject) error {
+	return r.db.WithContext(ctx).Create(obj).Error
+}
+
+// Touch restarts the GC grace period of an unreferenced object.
+// false면 GC가 이미 삭제한 객체다 (갱신 후 존재 여부로 판단 — 갱신 시각 이후에는 GC 조건을 만족하지 않음).
+func (r *MediaRepository) Touch(ctx context.Context, id int64) (bool, error) {
+	db := r.db.WithContext(ctx)
+	if err := db.Model(&domain.MediaObject{}).
+		Where("id = ? AND unreferenced_at IS NOT NULL", id).
+		Update("unreferenced_at", time.Now()).Error; err != nil {
+		return false, err
+	}
+	var count int64
+	err := db.Model(&domain.MediaObject{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// ReplaceReferences sets the objects referenced by a post (or other ref target)
+// 참조가 생긴 객체는 GC 대상에서 빠지고, 마지막 참조가 사라진 객체는 지금부터 유예 기간이 시작된다.
+func (r *MediaRepository) ReplaceReferences(ctx context.Context, refType, refID string, mediaIDs []int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old []int64
+		if err := tx.Model(&domain.MediaReference{}).
+			Where("ref_type = ? AND ref_id = ?", refType, refID).
+			Pluck("media_id", &old).Error; err != nil {
+			return err
+		}
+
+		keep := make(map[int64]bool, len(mediaIDs))
+		for _, id := range mediaIDs {
+			keep[id] = true
+		}
+		var removed []int64
+		for _, id := range old {
+			if !keep[id] {
+				removed = append(removed, id)
+			}
+		}
+
+		if len(removed) > 0 {
+			if err := tx.Where("ref_type = ? AND ref_id = ? AND media_id IN ?", refType, refID, removed).
+				Delete(&domain.MediaReference{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&domain.MediaObject{}).
+				Where("id IN ? AND NOT EXISTS (SELECT 1 FROM media_references mr WHERE mr.media_id = media_objects.id)", removed).
+				Update("unreferenced_at", time.Now()).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(mediaIDs) == 0 {
+			return nil
+		}
+		refs := make([]domain.MediaReference, len(mediaIDs))
+		for i, id := range mediaIDs {
+			refs[i] = domain.MediaReference{RefType: refType, RefID: refID, MediaID: id}
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&refs).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.MediaObject{}).Where("id IN ?", mediaIDs).Update("unreferenced_at", nil).Error
+	})
+}
+
+// CountReferences counts the references to an object
+func (r *MediaRepository) CountReferences(ctx context.Context, mediaID int64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.MediaReference{}).Where("media_id = ?", mediaID).Count(&count).Error
+	return count, err
+}
+
+// FindReferences lists the references to an object
+func (r *MediaRepository) FindReferences(ctx context.Context, mediaID int64) ([]domain.MediaReference, error) {
+	var refs []domain.MediaReference
+	err := r.db.WithContext(ctx).Where("media_id = ?", mediaID).Order("created_at").Find(&refs).Error
+	return refs, err
+}
+
+// FindUnreferenced lists objects unreferenced since before
+func (r *MediaRepository) FindUnreferenced(ctx context.Context, before time.Time, limit int) ([]domain.MediaObject, error) {
+	var objs []domain.MediaObject
+	err := r.db.WithContext(ctx).
+		Where("unreferenced_at IS NOT NULL AND unreferenced_at < ?", before).
+		Order("unreferenced_at").
+		Limit(limit).
+		Find(&objs).Error
+	return objs, err
+}
+
+// DeleteUnreferenced deletes an object row only if it is still unreferenced since before.
+// false면 그 사이 참조되었거나 다시 업로드되어 삭제하지 않은 것이다.
+func (r *MediaRepository) DeleteUnreferenced(ctx context.Context, id int64, before time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND unreferenced_at IS NOT NULL AND unreferenced_at < ?", id, before).
+		Where("NOT EXISTS (SELECT 1 FROM media_references mr WHERE mr.media_id = media_objects.id)").
+		Delete(&domain.MediaObject{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// Delete deletes an object row and its references
+func (r *MediaRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("media_id = ?", id).Delete(&domain.MediaReference{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.MediaObject{}, id).Error
+	})
+}
+
+// UsageByOwner sums a member's objects
+func (r *MediaRepository) UsageByOwner(ctx context.Context, ownerID string) (*domain.MediaUsage, error) {
+	usage := &domain.MediaUsage{OwnerID: ownerID}
+	err := r.db.WithContext(ctx).Model(&domain.MediaObject{}).
+		Select("COUNT(*) AS objects, COALESCE(SUM(size), 0) AS bytes").
+		Where("owner_id = ?", ownerID).
+		Scan(usage).Error
+	return usage, err
+}
+
+// TopUsage lists members by stored bytes (내림차순)
+func (r *MediaRepository) TopUsage(ctx context.Context, limit int) ([]domain.MediaUsage, error) {
+	var usage []domain.MediaUsage
+	err := r.db.WithContext(ctx).Model(&domain.MediaObject{}).
+		Select("owner_id, COUNT(*) AS objects, COALESCE(SUM(size), 0) AS bytes").
+		Group("owner_id").
+		Order("bytes DESC").
+		Limit(limit).
+		Scan(&usage).Error
+	return usage, err
+}
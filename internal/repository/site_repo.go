@@ -186,7 +186,7 @@ func (r *SiteRepository) RemoveUserPermission(ctx context.Context, siteID, userI
 // ========================================
 
 // FindByCustomDomain retrieves a site by custom domain (from site_settings table)
-func (r *SiteRepository) FindByCustomDomain(ctx context.Context, customDomain string) (interface{}, error) {
+func (r *SiteRepository) FindByCustomDomain(ctx context.Context, customDomain string) (*domain.Site, error) {
 	// First find site_id from settings table
 	var settings domain.SiteSettings
 	err := r.db.WithContext(ctx).
@@ -195,31 +195,12 @@ func (r *SiteRepository) FindByCustomDomain(ctx context.Context, customDomain st
 
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("site not found")
-		}
-		return nil, err
-	}
-
-	// Then get the site
-	var site domain.Site
-	err = r.db.WithContext(ctx).
-		Where("id = ?", settings.SiteID).
-		First(&site).Error
-
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("site not found")
+			return nil, nil
 		}
 		return nil, err
 	}
 
-	// Return as map for compatibility with tenant middleware
-	return map[string]interface{}{
-		"id":          site.ID,
-		"subdomain":   site.Subdomain,
-		"db_strategy": site.DBStrategy,
-		"plan":        site.Plan,
-	}, nil
+	return r.FindByID(ctx, settings.SiteID)
 }
 
 // CheckSubdomainAvailability checks if subdomain is available
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/damoang/angple-backend/internal/domain"
 	"gorm.io/gorm"
@@ -20,7 +21,7 @@ func NewSubscriptionRepository(db *gorm.DB) *SubscriptionRepository {
 
 // AutoMigrate creates subscription tables
 func (r *SubscriptionRepository) AutoMigrate() error {
-	return r.db.AutoMigrate(&domain.Subscription{}, &domain.Invoice{})
+	return r.db.AutoMigrate(&domain.Subscription{}, &domain.Invoice{}, &domain.SubscriptionEvent{})
 }
 
 // ========================================
@@ -88,3 +89,84 @@ func (r *SubscriptionRepository) FindInvoiceByID(ctx context.Context, invoiceID
 func (r *SubscriptionRepository) UpdateInvoice(ctx context.Context, inv *domain.Invoice) error {
 	return r.db.WithContext(ctx).Save(inv).Error
 }
+
+// FindInvoiceByExternalID retrieves an invoice by its payment gateway order ID
+func (r *SubscriptionRepository) FindInvoiceByExternalID(ctx context.Context, externalID string) (*domain.Invoice, error) {
+	var inv domain.Invoice
+	err := r.db.WithContext(ctx).Where("external_inv_id = ?", externalID).First(&inv).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// ListInvoicesByStatus retrieves a site's invoices in a status (oldest first)
+func (r *SubscriptionRepository) ListInvoicesByStatus(ctx context.Context, siteID, status string) ([]domain.Invoice, error) {
+	var invoices []domain.Invoice
+	err := r.db.WithContext(ctx).Where("site_id = ? AND status = ?", siteID, status).Order("created_at ASC").Find(&invoices).Error
+	return invoices, err
+}
+
+// ListByStatusEndingBefore retrieves subscriptions in a status whose current period ends before t
+func (r *SubscriptionRepository) ListByStatusEndingBefore(ctx context.Context, status string, t time.Time) ([]domain.Subscription, error) {
+	var subs []domain.Subscription
+	err := r.db.WithContext(ctx).Where("status = ? AND current_period_end < ?", status, t).Order("current_period_end ASC").Find(&subs).Error
+	return subs, err
+}
+
+// ListRetryDueInvoices retrieves failed invoices whose next retry is due
+func (r *SubscriptionRepository) ListRetryDueInvoices(ctx context.Context, now time.Time) ([]domain.Invoice, error) {
+	var invoices []domain.Invoice
+	err := r.db.WithContext(ctx).Where("status = ? AND next_retry_at IS NOT NULL AND next_retry_at <= ?", "failed", now).
+		Order("next_retry_at ASC").Find(&invoices).Error
+	return invoices, err
+}
+
+// FindOpenInvoiceForPeriod retrieves a pending or failed invoice starting at periodStart
+func (r *SubscriptionRepository) FindOpenInvoiceForPeriod(ctx context.Context, siteID string, periodStart time.Time) (*domain.Invoice, error) {
+	var inv domain.Invoice
+	err := r.db.WithContext(ctx).
+		Where("site_id = ? AND status IN ? AND period_start = ? AND amount_krw > 0", siteID, []string{"pending", "failed"}, periodStart).
+		First(&inv).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// ========================================
+// Subscription audit trail
+// ========================================
+
+// CreateEvent appends an audit trail entry
+func (r *SubscriptionRepository) CreateEvent(ctx context.Context, event *domain.SubscriptionEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// ListEvents retrieves audit trail entries for a site (newest first)
+func (r *SubscriptionRepository) ListEvents(ctx context.Context, siteID string, limit, offset int) ([]domain.SubscriptionEvent, int64, error) {
+	var events []domain.SubscriptionEvent
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.SubscriptionEvent{}).Where("site_id = ?", siteID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&events).Error
+	return events, total, err
+}
+
+// HasEventSince reports whether an event of type was recorded for the site after since
+func (r *SubscriptionRepository) HasEventSince(ctx context.Context, siteID, eventType string, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.SubscriptionEvent{}).
+		Where("site_id = ? AND type = ? AND created_at >= ?", siteID, eventType, since).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type siteIDKey struct{}
+
+// ContextWithSiteID returns a context carrying the tenant site ID
+func ContextWithSiteID(ctx context.Context, siteID string) context.Context {
+	return context.WithValue(ctx, siteIDKey{}, siteID)
+}
+
+// SiteIDFromContext returns the tenant site ID ("" = 메인 사이트)
+func SiteIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	siteID, _ := ctx.Value(siteIDKey{}).(string) //nolint:errcheck // type assertion, not error
+	return siteID
+}
+
+// tenantScopedModels site_id 컬럼으로 테넌트를 구분하는 모델 (shared 전략)
+var tenantScopedModels = []interface{}{&v2.V2Board{}, &v2.V2Post{}, &v2.V2Comment{}}
+
+// tenantScopedTables tenantScopedModels의 테이블 이름
+var tenantScopedTables = map[string]bool{"v2_boards": true, "v2_posts": true, "v2_comments": true}
+
+// MigrateTenantColumns adds the site_id column to tenant-scoped tables if missing
+// 기존 데이터는 site_id 빈 문자열(메인 사이트)로 유지된다.
+func MigrateTenantColumns(db *gorm.DB) error {
+	for _, model := range tenantScopedModels {
+		if !db.Migrator().HasTable(model) || db.Migrator().HasColumn(model, "SiteID") {
+			continue
+		}
+		if err := db.Migrator().AddColumn(model, "SiteID"); err != nil {
+			return fmt.Errorf("add site_id column: %w", err)
+		}
+	}
+
+	// 게시판 slug는 사이트별로 유일: (slug) → (site_id, slug)
+	m := db.Migrator()
+	if m.HasTable(&v2.V2Board{}) && !m.HasIndex(&v2.V2Board{}, "idx_v2_boards_site_slug") {
+		if m.HasIndex(&v2.V2Board{}, "idx_v2_boards_slug") {
+			if err := m.DropIndex(&v2.V2Board{}, "idx_v2_boards_slug"); err != nil {
+				return fmt.Errorf("drop board slug index: %w", err)
+			}
+		}
+		if err := m.CreateIndex(&v2.V2Board{}, "idx_v2_boards_site_slug"); err != nil {
+			return fmt.Errorf("create board site/slug index: %w", err)
+		}
+	}
+	return nil
+}
+
+// RegisterTenantScope installs GORM callbacks that isolate tenant-scoped tables by site_id.
+// 조회/수정/삭제에는 "site_id = <컨텍스트의 사이트>" 조건을, 생성에는 site_id 값을 자동으로 넣는다.
+// 사이트는 Statement.Context(db.WithContext)에서 읽으며, 없으면 메인 사이트(빈 문자열)로 간주한다.
+// Raw/Exec SQL에는 적용되지 않는다.
+func RegisterTenantScope(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Query().Before("gorm:query").Register("tenant:scope_query", tenantWhere); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenant:scope_row", tenantWhere); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:scope_update", tenantWhere); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:scope_delete", tenantWhere); err != nil {
+		return err
+	}
+	return cb.Create().Before("gorm:create").Register("tenant:scope_create", tenantAssign)
+}
+
+func tenantWhere(db *gorm.DB) {
+	if db.Error != nil || !tenantScopedTables[db.Statement.Table] {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "site_id"}, Value: SiteIDFromContext(db.Statement.Context)},
+	}})
+}
+
+func tenantAssign(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || !tenantScopedTables[db.Statement.Table] {
+		return
+	}
+	field := db.Statement.Schema.LookUpField("site_id")
+	if field == nil {
+		return
+	}
+
+	siteID := SiteIDFromContext(db.Statement.Context)
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := field.Set(db.Statement.Context, reflect.Indirect(rv.Index(i)), siteID); err != nil {
+				db.AddError(err) //nolint:errcheck,gosec // AddError returns the same error
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := field.Set(db.Statement.Context, rv, siteID); err != nil {
+			db.AddError(err) //nolint:errcheck,gosec // AddError returns the same error
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package v2
+
+import (
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepository v2 login audit log data access
+type LoginAttemptRepository interface {
+	Create(attempt *v2.V2LoginAttempt) error
+	FindAll(username, ip string, page, limit int) ([]*v2.V2LoginAttempt, int64, error)
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository creates a new v2 LoginAttemptRepository
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Create(attempt *v2.V2LoginAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+func (r *loginAttemptRepository) FindAll(username, ip string, page, limit int) ([]*v2.V2LoginAttempt, int64, error) {
+	var attempts []*v2.V2LoginAttempt
+	var total int64
+
+	query := r.db.Model(&v2.V2LoginAttempt{})
+	if username != "" {
+		query = query.Where("username = ?", username)
+	}
+	if ip != "" {
+		query = query.Where("ip_address = ?", ip)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * limit
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&attempts).Error; err != nil {
+		return nil, 0, err
+	}
+	return attempts, total, nil
+}
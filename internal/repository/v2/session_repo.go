@@ -0,0 +1,78 @@
+package v2
+
+import (
+	"time"
+
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+// SessionRepository v2 session data access
+type SessionRepository interface {
+	Create(session *v2.V2Session) error
+	FindByID(id string) (*v2.V2Session, error)
+	Rotate(id, oldHash, newHash string, expiresAt time.Time) (bool, error)
+	Revoke(id string) error
+	RevokeForUser(userID uint64, id string) (bool, error)
+	RevokeOthers(userID uint64, keepID string) (int64, error)
+	FindActiveByUser(userID uint64) ([]*v2.V2Session, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new v2 SessionRepository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(session *v2.V2Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *sessionRepository) FindByID(id string) (*v2.V2Session, error) {
+	var session v2.V2Session
+	err := r.db.Where("id = ?", id).First(&session).Error
+	return &session, err
+}
+
+// Rotate 현재 토큰 해시가 oldHash인 활성 세션만 새 토큰으로 교체 (false면 이미 회전/폐기된 토큰)
+func (r *sessionRepository) Rotate(id, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	res := r.db.Model(&v2.V2Session{}).
+		Where("id = ? AND refresh_hash = ? AND revoked_at IS NULL", id, oldHash).
+		Updates(map[string]interface{}{
+			"refresh_hash": newHash,
+			"expires_at":   expiresAt,
+			"last_used_at": time.Now(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *sessionRepository) Revoke(id string) error {
+	return r.db.Model(&v2.V2Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *sessionRepository) RevokeForUser(userID uint64, id string) (bool, error) {
+	res := r.db.Model(&v2.V2Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *sessionRepository) RevokeOthers(userID uint64, keepID string) (int64, error) {
+	res := r.db.Model(&v2.V2Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepID).
+		Update("revoked_at", time.Now())
+	return res.RowsAffected, res.Error
+}
+
+func (r *sessionRepository) FindActiveByUser(userID uint64) ([]*v2.V2Session, error) {
+	var sessions []*v2.V2Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
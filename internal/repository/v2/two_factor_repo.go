@@ -0,0 +1,135 @@
+package v2
+
+import (
+	"time"
+
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TwoFactorRepository v2 TOTP second factor data access
+type TwoFactorRepository interface {
+	FindByUserID(userID string) (*v2.V2TwoFactor, error)
+	SavePending(userID, secret string) error
+	Enable(userID string, step int64, codeHashes []string) (bool, error)
+	UseStep(userID string, step int64) (bool, error)
+	RecordFailure(userID string, lockAfter int, lockedUntil time.Time) error
+	ResetFailures(userID string) error
+	Delete(userID string) error
+	ReplaceRecoveryCodes(userID string, codeHashes []string) error
+	UseRecoveryCode(userID, codeHash string) (bool, error)
+	CountRecoveryCodes(userID string) (int64, error)
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository creates a new v2 TwoFactorRepository
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+func (r *twoFactorRepository) FindByUserID(userID string) (*v2.V2TwoFactor, error) {
+	var tf v2.V2TwoFactor
+	err := r.db.Where("user_id = ?", userID).First(&tf).Error
+	return &tf, err
+}
+
+// SavePending 등록 확인 전 비밀 키를 저장 (기존 대기 중 등록은 새 키로 교체)
+func (r *twoFactorRepository) SavePending(userID, secret string) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"secret": secret, "enabled_at": nil, "last_used_step": 0, "updated_at": time.Now(),
+		}),
+	}).Create(&v2.V2TwoFactor{UserID: userID, Secret: secret}).Error
+}
+
+// Enable 대기 중인 2FA를 활성화하고 복구 코드를 발급 (false면 대기 중인 등록 없음)
+func (r *twoFactorRepository) Enable(userID string, step int64, codeHashes []string) (bool, error) {
+	enabled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&v2.V2TwoFactor{}).
+			Where("user_id = ? AND enabled_at IS NULL", userID).
+			Updates(map[string]interface{}{"enabled_at": time.Now(), "last_used_step": step})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		enabled = true
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+	return enabled, err
+}
+
+// UseStep 마지막 사용 step보다 새로운 OTP일 때만 기록 (false면 이미 사용된 OTP)
+func (r *twoFactorRepository) UseStep(userID string, step int64) (bool, error) {
+	res := r.db.Model(&v2.V2TwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return res.RowsAffected > 0, res.Error
+}
+
+// RecordFailure 실패 횟수를 올리고 lockAfter에 도달하면 lockedUntil까지 잠금 (잠금 시 횟수 초기화)
+func (r *twoFactorRepository) RecordFailure(userID string, lockAfter int, lockedUntil time.Time) error {
+	if err := r.db.Model(&v2.V2TwoFactor{}).
+		Where("user_id = ?", userID).
+		Update("failed_count", gorm.Expr("failed_count + 1")).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&v2.V2TwoFactor{}).
+		Where("user_id = ? AND failed_count >= ?", userID, lockAfter).
+		Updates(map[string]interface{}{"failed_count": 0, "locked_until": lockedUntil}).Error
+}
+
+func (r *twoFactorRepository) ResetFailures(userID string) error {
+	return r.db.Model(&v2.V2TwoFactor{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{"failed_count": 0, "locked_until": nil}).Error
+}
+
+func (r *twoFactorRepository) Delete(userID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&v2.V2RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&v2.V2TwoFactor{}).Error
+	})
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID string, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&v2.V2RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]v2.V2RecoveryCode, len(codeHashes))
+	for i, h := range codeHashes {
+		codes[i] = v2.V2RecoveryCode{UserID: userID, CodeHash: h}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
+
+// UseRecoveryCode 미사용 복구 코드를 사용 처리 (false면 없는 코드이거나 이미 사용됨)
+func (r *twoFactorRepository) UseRecoveryCode(userID, codeHash string) (bool, error) {
+	res := r.db.Model(&v2.V2RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *twoFactorRepository) CountRecoveryCodes(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&v2.V2RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
@@ -1,18 +1,25 @@
 package v2
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 )
 
+// ErrUserExists is returned by Create when the username or email is already taken (동시 가입 경합)
+var ErrUserExists = errors.New("user already exists")
+
 // UserRepository v2 user data access
 type UserRepository interface {
 	FindByID(id uint64) (*v2.V2User, error)
 	FindByUsername(username string) (*v2.V2User, error)
 	FindByEmail(email string) (*v2.V2User, error)
+	FindByNickname(nickname string) (*v2.V2User, error)
 	Create(user *v2.V2User) error
 	Update(user *v2.V2User) error
 	FindAll(page, limit int, keyword string) ([]*v2.V2User, int64, error)
@@ -48,8 +55,27 @@ func (r *userRepository) FindByEmail(email string) (*v2.V2User, error) {
 	return &user, err
 }
 
+func (r *userRepository) FindByNickname(nickname string) (*v2.V2User, error) {
+	var user v2.V2User
+	err := r.db.Where("nickname = ?", nickname).First(&user).Error
+	return &user, err
+}
+
 func (r *userRepository) Create(user *v2.V2User) error {
-	return r.db.Create(user).Error
+	err := r.db.Create(user).Error
+	if isDuplicateKey(err) {
+		return ErrUserExists
+	}
+	return err
+}
+
+// isDuplicateKey reports a unique index violation (MySQL 1062, SQLite는 테스트용)
+func isDuplicateKey(err error) bool {
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return myErr.Number == 1062
+	}
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
 }
 
 func (r *userRepository) Update(user *v2.V2User) error {
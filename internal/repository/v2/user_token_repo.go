@@ -0,0 +1,62 @@
+package v2
+
+import (
+	"time"
+
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+// UserTokenRepository v2 emailed single-use token data access
+type UserTokenRepository interface {
+	Create(token *v2.V2UserToken) error
+	Consume(purpose, tokenHash string) (*v2.V2UserToken, error)
+	InvalidatePending(userID uint64, purpose string) error
+	CountSince(userID uint64, purpose string, since time.Time) (int64, error)
+}
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewUserTokenRepository creates a new v2 UserTokenRepository
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+func (r *userTokenRepository) Create(token *v2.V2UserToken) error {
+	return r.db.Create(token).Error
+}
+
+// Consume 만료 전 미사용 토큰을 사용 처리하고 반환 (없거나 만료/사용됨이면 gorm.ErrRecordNotFound)
+// 조건부 UPDATE로 처리해 같은 토큰의 동시 사용도 한 번만 성공한다.
+func (r *userTokenRepository) Consume(purpose, tokenHash string) (*v2.V2UserToken, error) {
+	now := time.Now()
+	res := r.db.Model(&v2.V2UserToken{}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, now).
+		Update("used_at", now)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	var token v2.V2UserToken
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	return &token, err
+}
+
+// InvalidatePending 회원의 미사용 토큰을 모두 사용 처리 (새 토큰 발급/비밀번호 변경 시)
+func (r *userTokenRepository) InvalidatePending(userID uint64, purpose string) error {
+	return r.db.Model(&v2.V2UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
+
+func (r *userTokenRepository) CountSince(userID uint64, purpose string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&v2.V2UserToken{}).
+		Where("user_id = ? AND purpose = ? AND created_at >= ?", userID, purpose, since).
+		Count(&count).Error
+	return count, err
+}
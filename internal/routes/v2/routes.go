@@ -1,11 +1,15 @@
 package v2
 
 import (
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
 	"github.com/damoang/angple-backend/internal/handler"
 	v2handler "github.com/damoang/angple-backend/internal/handler/v2"
 	"github.com/damoang/angple-backend/internal/middleware"
 	"github.com/damoang/angple-backend/pkg/jwt"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
@@ -15,6 +19,13 @@ func SetupAuth(router *gin.Engine, h *v2handler.V2AuthHandler, jwtManager *jwt.M
 	authGroup.POST("/login", h.Login)
 	authGroup.POST("/refresh", h.RefreshToken)
 	authGroup.POST("/logout", h.Logout)
+	authGroup.POST("/2fa/verify", h.VerifyTwoFactor)
+	authGroup.POST("/register", h.Register)
+	authGroup.GET("/register/check", h.CheckRegistration)
+	authGroup.POST("/verify-email", h.VerifyEmail)
+	authGroup.POST("/verify-email/resend", h.ResendVerification)
+	authGroup.POST("/password/forgot", h.ForgotPassword)
+	authGroup.POST("/password/reset", h.ResetPassword)
 	authGroup.GET("/me", middleware.JWTAuth(jwtManager), h.GetMe)
 	authGroup.GET("/profile", middleware.JWTAuth(jwtManager), h.GetMe) // alias for /me
 	// TODO: v2 마이그레이션 - exchange는 레거시 SSO용, 향후 세션 기반으로 전환
@@ -23,13 +34,41 @@ func SetupAuth(router *gin.Engine, h *v2handler.V2AuthHandler, jwtManager *jwt.M
 	// v1 미러
 	v1Auth := router.Group("/api/v1/auth")
 	v1Auth.POST("/exchange", h.ExchangeToken)
+
+	// 로그인 기기(세션) 관리
+	sessions := router.Group("/api/v2/me/sessions", middleware.JWTAuth(jwtManager))
+	sessions.GET("", h.ListSessions)
+	sessions.DELETE("", h.RevokeOtherSessions)
+	sessions.DELETE("/:id", h.RevokeSession)
+
+	// 2단계 인증(TOTP) 등록/해제
+	twoFactor := router.Group("/api/v2/me/2fa", middleware.JWTAuth(jwtManager))
+	twoFactor.GET("", h.GetTwoFactor)
+	twoFactor.POST("/setup", h.SetupTwoFactor)
+	twoFactor.POST("/enable", h.EnableTwoFactor)
+	twoFactor.POST("/recovery-codes", h.RegenerateRecoveryCodes)
+	twoFactor.DELETE("", h.DisableTwoFactor)
+
+	// 로그인 잠금 관리 / 로그인 감사 로그 (관리자)
+	admin := router.Group("/api/v2/admin/auth", middleware.JWTAuth(jwtManager), middleware.RequireAdmin())
+	admin.GET("/lockouts", h.ListLockouts)
+	admin.DELETE("/lockouts", h.ClearLockout)
+	admin.GET("/login-attempts", h.ListLoginAttempts)
 }
 
+// v2h shortens method expressions for tenant-scoped routes (h.Tenant)
+type v2h = v2handler.V2Handler
+
 // Setup configures v2 API routes (new DB schema)
-func Setup(router *gin.Engine, h *v2handler.V2Handler, jwtManager *jwt.Manager, boardPermChecker middleware.BoardPermissionChecker, gnuDB *gorm.DB) {
+// apiKeyAuth가 nil이 아니면 게시글/댓글 API를 API 키(read:posts, write:posts, admin:*)로도 호출할 수 있다.
+func Setup(router *gin.Engine, h *v2handler.V2Handler, jwtManager *jwt.Manager, boardPermChecker middleware.BoardPermissionChecker, gnuDB *gorm.DB, apiKeyAuth *middleware.APIKeyAuth) {
 	api := router.Group("/api/v2")
 	auth := middleware.JWTAuth(jwtManager)
 	banCheck := middleware.BanCheck(gnuDB)
+	t := h.Tenant // 게시판/게시글/댓글은 요청의 테넌트 DB에서 처리
+	readKey := apiKeyAuth.Require(domain.ScopeReadPosts)
+	writeKey := apiKeyAuth.Require(domain.ScopeWritePosts)
+	adminKey := apiKeyAuth.Require(domain.ScopeAdminAll)
 
 	// Users
 	users := api.Group("/users")
@@ -38,46 +77,47 @@ func Setup(router *gin.Engine, h *v2handler.V2Handler, jwtManager *jwt.Manager,
 	users.GET("/username/:username", h.GetUserByUsername)
 
 	// Boards (OptionalJWTAuth로 인증된 사용자에게 permissions 제공)
+	// 그룹에서는 어떤 게시판 scope든 키를 인증하고, 라우트별로 필요한 scope를 확인한다
 	boards := api.Group("/boards")
-	boards.Use(middleware.OptionalJWTAuth(jwtManager))
+	boards.Use(apiKeyAuth.Require(domain.ScopeReadPosts, domain.ScopeWritePosts, domain.ScopeAdminAll), middleware.OptionalJWTAuth(jwtManager))
 	boards.Use(middleware.ArchiveBoardCheck())
-	boards.GET("", h.ListBoards)
-	boards.GET("/:slug", h.GetBoard)
+	boards.GET("", readKey, t((*v2h).ListBoards))
+	boards.GET("/:slug", readKey, t((*v2h).GetBoard))
 
 	// Posts (nested under boards)
 	boardPosts := boards.Group("/:slug/posts")
-	boardPosts.GET("", h.ListPosts)
-	boardPosts.POST("", auth, banCheck, middleware.RequireWrite(boardPermChecker), h.CreatePost)
-	boardPosts.GET("/:id", h.GetPost)
-	boardPosts.PUT("/:id", auth, banCheck, h.UpdatePost)
-	boardPosts.DELETE("/:id", auth, banCheck, h.DeletePost)
-	boardPosts.PATCH("/:id/soft-delete", auth, banCheck, h.SoftDeletePost)
-	boardPosts.POST("/:id/restore", auth, middleware.RequireAdmin(), h.RestorePost)
-	boardPosts.DELETE("/:id/permanent", auth, middleware.RequireAdmin(), h.PermanentDeletePost)
-	boardPosts.GET("/:id/revisions", auth, h.GetPostRevisions)
-	boardPosts.POST("/:id/revisions/:version/restore", auth, middleware.RequireAdmin(), h.RestoreRevision)
+	boardPosts.GET("", readKey, t((*v2h).ListPosts))
+	boardPosts.POST("", writeKey, auth, banCheck, middleware.RequireWrite(boardPermChecker), t((*v2h).CreatePost))
+	boardPosts.GET("/:id", readKey, t((*v2h).GetPost))
+	boardPosts.PUT("/:id", writeKey, auth, banCheck, t((*v2h).UpdatePost))
+	boardPosts.DELETE("/:id", writeKey, auth, banCheck, t((*v2h).DeletePost))
+	boardPosts.PATCH("/:id/soft-delete", writeKey, auth, banCheck, t((*v2h).SoftDeletePost))
+	boardPosts.POST("/:id/restore", adminKey, auth, middleware.RequireAdmin(), t((*v2h).RestorePost))
+	boardPosts.DELETE("/:id/permanent", adminKey, auth, middleware.RequireAdmin(), t((*v2h).PermanentDeletePost))
+	boardPosts.GET("/:id/revisions", readKey, auth, t((*v2h).GetPostRevisions))
+	boardPosts.POST("/:id/revisions/:version/restore", adminKey, auth, middleware.RequireAdmin(), t((*v2h).RestoreRevision))
 
 	// Comments (nested under posts)
 	comments := boardPosts.Group("/:id/comments")
-	comments.GET("", h.ListComments)
-	comments.POST("", auth, banCheck, middleware.RequireComment(boardPermChecker), h.CreateComment)
-	comments.PUT("/:comment_id", auth, banCheck, h.UpdateComment)
-	comments.DELETE("/:comment_id", auth, banCheck, h.DeleteComment)
+	comments.GET("", readKey, t((*v2h).ListComments))
+	comments.POST("", writeKey, auth, banCheck, middleware.RequireComment(boardPermChecker), t((*v2h).CreateComment))
+	comments.PUT("/:comment_id", writeKey, auth, banCheck, t((*v2h).UpdateComment))
+	comments.DELETE("/:comment_id", writeKey, auth, banCheck, t((*v2h).DeleteComment))
 }
 
 // SetupAdminPosts configures v2 admin post routes (deleted posts)
-func SetupAdminPosts(router *gin.Engine, h *v2handler.V2Handler, jwtManager *jwt.Manager) {
+func SetupAdminPosts(router *gin.Engine, h *v2handler.V2Handler, jwtManager *jwt.Manager, apiKeyAuth *middleware.APIKeyAuth) {
 	admin := router.Group("/api/v1/admin")
-	admin.Use(middleware.JWTAuth(jwtManager), middleware.RequireAdmin())
+	admin.Use(apiKeyAuth.Require(domain.ScopeAdminAll), middleware.JWTAuth(jwtManager), middleware.RequireAdmin())
 	admin.GET("/posts/deleted", h.GetDeletedPosts)
 	admin.POST("/posts/:id/restore", h.RestorePost)
 	admin.DELETE("/posts/:id/permanent", h.PermanentDeletePost)
 }
 
 // SetupAdmin configures v2 admin API routes
-func SetupAdmin(router *gin.Engine, h *v2handler.AdminHandler, jwtManager *jwt.Manager) {
+func SetupAdmin(router *gin.Engine, h *v2handler.AdminHandler, jwtManager *jwt.Manager, apiKeyAuth *middleware.APIKeyAuth) {
 	admin := router.Group("/api/v2/admin")
-	admin.Use(middleware.JWTAuth(jwtManager), middleware.RequireAdmin())
+	admin.Use(apiKeyAuth.Require(domain.ScopeAdminAll), middleware.JWTAuth(jwtManager), middleware.RequireAdmin())
 
 	// Admin Boards
 	adminBoards := admin.Group("/boards")
@@ -251,14 +291,41 @@ func SetupPromotion(router *gin.Engine, h *v2handler.PromotionHandler) {
 	v2Promo.GET("/posts/insert", h.GetInsertPosts)
 }
 
-// SetupLicense configures license verification routes (v1 + v2)
-// TODO: v2 마이그레이션 - 라이선스 테이블 설계 후 실제 검증 로직 구현
-func SetupLicense(router *gin.Engine, h *v2handler.LicenseHandler) {
+// SetupLicense configures license verification routes (v1 + v2) and admin license management
+func SetupLicense(router *gin.Engine, h *v2handler.LicenseHandler, jwtManager *jwt.Manager) {
 	// v1 routes (primary)
 	router.POST("/api/v1/licenses/verify", h.Verify)
+	router.GET("/api/v1/licenses/public-key", h.PublicKey)
 
 	// v2 routes (frontend 호환)
 	router.POST("/api/v2/licenses/verify", h.Verify)
+	router.GET("/api/v2/licenses/public-key", h.PublicKey)
+
+	// Admin routes (requires authentication + admin)
+	admin := router.Group("/api/v2/admin/licenses")
+	admin.Use(middleware.JWTAuth(jwtManager), middleware.RequireAdmin())
+	admin.GET("", h.ListLicenses)
+	admin.POST("", h.IssueLicense)
+	admin.POST("/:id/revoke", h.RevokeLicense)
+}
+
+// SetupSaaS configures SaaS provisioning and subscription billing routes.
+// 커뮤니티 삭제, 플랜 변경/해지, 청구서, 결제 수단 등록은 사이트 소유자 또는 관리자만
+func SetupSaaS(router *gin.Engine, provisioning *handler.ProvisioningHandler, billing *handler.BillingHandler, jwtManager *jwt.Manager, siteRoles middleware.SiteRoleChecker, redisClient *redis.Client) {
+	saas := router.Group("/api/v2/saas")
+	saas.GET("/pricing", middleware.CacheWithTTL(redisClient, 10*time.Minute), provisioning.GetPricing)
+	saas.POST("/communities", provisioning.ProvisionCommunity)
+	saas.GET("/communities/:id/subscription", provisioning.GetSubscription)
+	saas.POST("/billing/webhooks/:provider", billing.Webhook)
+
+	siteOwner := saas.Group("/communities/:id", middleware.JWTAuth(jwtManager), middleware.RequireSiteOwner(siteRoles))
+	siteOwner.DELETE("", provisioning.DeleteCommunity)
+	siteOwner.PUT("/subscription/plan", provisioning.ChangePlan)
+	siteOwner.POST("/subscription/cancel", provisioning.CancelSubscription)
+	siteOwner.GET("/invoices", provisioning.GetInvoices)
+	siteOwner.POST("/checkout", billing.CreateCheckout)
+	siteOwner.POST("/checkout/confirm", billing.ConfirmCheckout)
+	siteOwner.GET("/subscription/events", billing.ListEvents)
 }
 
 // SetupContent configures content page routes (admin + public)
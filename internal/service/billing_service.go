@@ -0,0 +1,530 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/payment"
+	"github.com/damoang/angple-backend/internal/repository"
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+)
+
+// Invoice status constants
+const (
+	invoiceStatusPending  = "pending"
+	invoiceStatusPaid     = "paid"
+	invoiceStatusFailed   = "failed"
+	invoiceStatusRefunded = "refunded"
+	invoiceStatusCredit   = "credit"  // 다운그레이드 일할 크레딧 (다음 청구에서 차감)
+	invoiceStatusApplied  = "applied" // 청구에 차감된 크레딧
+
+	billingCycleYearly = "yearly"
+)
+
+// dunningRetrySchedule is the delay before each retry of a failed invoice (마지막 재시도 실패 시 재시도 종료)
+var dunningRetrySchedule = []time.Duration{24 * time.Hour, 3 * 24 * time.Hour, 5 * 24 * time.Hour, 7 * 24 * time.Hour}
+
+// Audit trail event types (domain.SubscriptionEvent.Type)
+const (
+	subEventStatusChanged      = "status_changed"
+	subEventPaymentFailed      = "payment_failed"
+	subEventTrialEndingNotice  = "trial_ending_notice"
+	subEventTrialDowngraded    = "trial_downgraded"
+	subEventCanceledDowngraded = "canceled_downgraded"
+	subEventPlanChanged        = "plan_changed"
+	subEventPaymentRetry       = "payment_retry"
+	subEventSuspended          = "suspended"
+)
+
+// ErrUnknownPaymentProvider is returned for an unregistered provider name
+var ErrUnknownPaymentProvider = errors.New("지원하지 않는 결제사입니다")
+
+// subscriptionTransitions lists the allowed subscription status changes
+var subscriptionTransitions = map[string][]string{
+	paymentStatusTrialing: {paymentStatusActive, paymentStatusPastDue, paymentStatusCanceled},
+	paymentStatusActive:   {paymentStatusPastDue, paymentStatusCanceled},
+	paymentStatusPastDue:  {paymentStatusActive, paymentStatusCanceled},
+	paymentStatusCanceled: {paymentStatusActive}, // 재결제로 재활성화
+}
+
+// BillingService charges subscriptions through payment providers and keeps
+// subscription status (trialing → active ⇄ past_due → canceled) and invoices in sync
+type BillingService struct {
+	subRepo         *repository.SubscriptionRepository
+	siteRepo        *repository.SiteRepository
+	providers       map[string]payment.Provider
+	defaultProvider string
+}
+
+// NewBillingService creates a new BillingService
+func NewBillingService(subRepo *repository.SubscriptionRepository, siteRepo *repository.SiteRepository) *BillingService {
+	return &BillingService{
+		subRepo:   subRepo,
+		siteRepo:  siteRepo,
+		providers: make(map[string]payment.Provider),
+	}
+}
+
+// RegisterProvider registers a payment provider (처음 등록한 결제사가 기본값)
+func (s *BillingService) RegisterProvider(p payment.Provider) {
+	if s.defaultProvider == "" {
+		s.defaultProvider = p.Name()
+	}
+	s.providers[p.Name()] = p
+}
+
+func (s *BillingService) provider(name string) (payment.Provider, error) {
+	if name == "" {
+		name = s.defaultProvider
+	}
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, ErrUnknownPaymentProvider
+	}
+	return p, nil
+}
+
+// CreateCheckout creates the first invoice for the current plan and a checkout session to register a payment method
+func (s *BillingService) CreateCheckout(ctx context.Context, siteID string, req *domain.CheckoutRequest) (*payment.CheckoutSession, error) {
+	provider, err := s.provider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	sub, site, err := s.subscription(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	amount := cyclePrice(sub.Plan, sub.BillingCycle)
+	if amount <= 0 {
+		return nil, errors.New("무료 플랜은 결제가 필요하지 않습니다")
+	}
+
+	// 체험 중이면 체험 종료 시점부터 청구 기간이 시작된다
+	start := time.Now()
+	if sub.Status == paymentStatusTrialing && sub.CurrentPeriodEnd.After(start) {
+		start = sub.CurrentPeriodEnd
+	}
+	inv := &domain.Invoice{
+		SiteID:          siteID,
+		Status:          invoiceStatusPending,
+		PaymentProvider: provider.Name(),
+		Description:     fmt.Sprintf("%s 플랜 (%s)", sub.Plan, sub.BillingCycle),
+		Currency:        "KRW",
+		AmountKRW:       amount,
+		PeriodStart:     start,
+		PeriodEnd:       cycleEnd(start, sub.BillingCycle),
+	}
+	if err := s.createInvoice(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	return provider.CreateCheckout(ctx, &payment.CheckoutRequest{
+		CustomerKey: siteID,
+		OrderID:     inv.ExternalInvID,
+		OrderName:   inv.Description,
+		AmountKRW:   amount,
+		Email:       site.OwnerEmail,
+		SuccessURL:  req.SuccessURL,
+		FailURL:     req.FailURL,
+	})
+}
+
+// ConfirmCheckout stores the billing key and charges the checkout invoice.
+// 체험 기간 중이면 청구서는 체험 종료일까지 대기 상태로 남는다.
+func (s *BillingService) ConfirmCheckout(ctx context.Context, siteID string, req *domain.CheckoutConfirmRequest) (*domain.Invoice, error) {
+	inv, err := s.subRepo.FindInvoiceByExternalID(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if inv == nil || inv.SiteID != siteID {
+		return nil, errors.New("청구서를 찾을 수 없습니다")
+	}
+	if inv.Status != invoiceStatusPending {
+		return nil, errors.New("이미 처리된 청구서입니다")
+	}
+	provider, err := s.provider(inv.PaymentProvider)
+	if err != nil {
+		return nil, err
+	}
+	sub, _, err := s.subscription(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	billingKey, err := provider.ConfirmCheckout(ctx, &payment.ConfirmRequest{CustomerKey: siteID, AuthKey: req.AuthKey})
+	if err != nil {
+		return nil, fmt.Errorf("결제 수단 등록 실패: %w", err)
+	}
+	sub.PaymentProvider = provider.Name()
+	sub.ExternalSubID = billingKey
+	if err := s.subRepo.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	if sub.Status == paymentStatusTrialing && inv.PeriodStart.After(time.Now()) {
+		return inv, nil
+	}
+	if err := s.ChargeInvoice(ctx, sub, inv, "checkout"); err != nil {
+		return inv, err
+	}
+	return inv, nil
+}
+
+// ChargeInvoice charges a pending or failed invoice against the subscription's billing key.
+// 결제사가 거절하면 청구서는 failed(재시도 예약), 구독은 past_due가 된다.
+func (s *BillingService) ChargeInvoice(ctx context.Context, sub *domain.Subscription, inv *domain.Invoice, actor string) error {
+	result, err := s.charge(ctx, sub, inv)
+	var declined *payment.ChargeError
+	if errors.As(err, &declined) {
+		if failErr := s.markFailed(ctx, sub, inv, declined.Error(), actor); failErr != nil {
+			return failErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	return s.markPaid(ctx, sub, inv, result.PaymentKey, result.ApprovedAt, actor)
+}
+
+// markFailed marks the invoice failed, schedules the next retry and moves the subscription to past_due
+func (s *BillingService) markFailed(ctx context.Context, sub *domain.Subscription, inv *domain.Invoice, reason, actor string) error {
+	inv.Status = invoiceStatusFailed
+	inv.Attempts++
+	inv.NextRetryAt = nil
+	if inv.Attempts <= len(dunningRetrySchedule) {
+		next := time.Now().Add(dunningRetrySchedule[inv.Attempts-1])
+		inv.NextRetryAt = &next
+	}
+	if err := s.subRepo.UpdateInvoice(ctx, inv); err != nil {
+		return err
+	}
+	s.recordEvent(ctx, &domain.SubscriptionEvent{
+		SiteID: sub.SiteID, Type: subEventPaymentFailed, Actor: actor, InvoiceID: &inv.ID,
+		Message: fmt.Sprintf("결제 실패 (%d회): %s", inv.Attempts, reason),
+	})
+	if err := s.transition(ctx, sub, paymentStatusPastDue, actor, "결제 실패"); err != nil {
+		pkglogger.Info("[Billing] %s: %v", sub.SiteID, err)
+	}
+	return nil
+}
+
+// charge applies open credits and bills the remaining amount
+func (s *BillingService) charge(ctx context.Context, sub *domain.Subscription, inv *domain.Invoice) (*payment.ChargeResult, error) {
+	if sub.ExternalSubID == "" {
+		// 결제 거절과 동일하게 처리해 재시도/정지 흐름을 탄다
+		return nil, &payment.ChargeError{Code: "NO_PAYMENT_METHOD", Message: "등록된 결제 수단이 없습니다"}
+	}
+	provider, err := s.provider(sub.PaymentProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	credits, err := s.subRepo.ListInvoicesByStatus(ctx, sub.SiteID, invoiceStatusCredit)
+	if err != nil {
+		return nil, err
+	}
+	amount := inv.AmountKRW
+	for i := range credits {
+		amount += credits[i].AmountKRW // 크레딧은 음수 금액
+	}
+	if amount <= 0 {
+		// 크레딧으로 전액 충당, 남은 크레딧은 다음 청구로 이월
+		if err := s.applyCredits(ctx, credits); err != nil {
+			return nil, err
+		}
+		if amount < 0 {
+			carry := &domain.Invoice{
+				SiteID:          sub.SiteID,
+				Status:          invoiceStatusCredit,
+				PaymentProvider: sub.PaymentProvider,
+				Description:     "이월 크레딧",
+				Currency:        "KRW",
+				AmountKRW:       amount,
+				PeriodStart:     inv.PeriodStart,
+				PeriodEnd:       inv.PeriodEnd,
+			}
+			if err := s.createInvoice(ctx, carry); err != nil {
+				return nil, err
+			}
+		}
+		return &payment.ChargeResult{OrderID: inv.ExternalInvID, ApprovedAt: time.Now()}, nil
+	}
+
+	email := ""
+	if site, err := s.siteRepo.FindByID(ctx, sub.SiteID); err == nil && site != nil {
+		email = site.OwnerEmail
+	}
+	result, err := provider.Charge(ctx, &payment.ChargeRequest{
+		BillingKey:  sub.ExternalSubID,
+		CustomerKey: sub.SiteID,
+		OrderID:     inv.ExternalInvID,
+		OrderName:   inv.Description,
+		AmountKRW:   amount,
+		Email:       email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, s.applyCredits(ctx, credits)
+}
+
+func (s *BillingService) applyCredits(ctx context.Context, credits []domain.Invoice) error {
+	for i := range credits {
+		credits[i].Status = invoiceStatusApplied
+		if err := s.subRepo.UpdateInvoice(ctx, &credits[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markPaid marks the invoice paid, activates the subscription and extends its period
+func (s *BillingService) markPaid(ctx context.Context, sub *domain.Subscription, inv *domain.Invoice, paymentKey string, paidAt time.Time, actor string) error {
+	inv.Status = invoiceStatusPaid
+	inv.PaidAt = &paidAt
+	inv.NextRetryAt = nil
+	if paymentKey != "" {
+		inv.PaymentKey = paymentKey
+	}
+	if err := s.subRepo.UpdateInvoice(ctx, inv); err != nil {
+		return err
+	}
+	if inv.PeriodEnd.After(sub.CurrentPeriodEnd) {
+		sub.CurrentPeriodStart = inv.PeriodStart
+		sub.CurrentPeriodEnd = inv.PeriodEnd
+	}
+	if sub.Status == paymentStatusActive {
+		return s.subRepo.Update(ctx, sub)
+	}
+	return s.transition(ctx, sub, paymentStatusActive, actor, fmt.Sprintf("청구서 %s 결제 완료", inv.ExternalInvID))
+}
+
+// transition moves a subscription to a new status if allowed, saves it and records the change
+func (s *BillingService) transition(ctx context.Context, sub *domain.Subscription, to, actor, message string) error {
+	if sub.Status == to {
+		return nil
+	}
+	allowed := false
+	for _, next := range subscriptionTransitions[sub.Status] {
+		if next == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("구독 상태를 %s에서 %s(으)로 변경할 수 없습니다", sub.Status, to)
+	}
+
+	from := sub.Status
+	sub.Status = to
+	switch to {
+	case paymentStatusCanceled:
+		now := time.Now()
+		sub.CanceledAt = &now
+	case paymentStatusActive:
+		sub.CanceledAt = nil
+	}
+	if err := s.subRepo.Update(ctx, sub); err != nil {
+		return err
+	}
+	s.recordEvent(ctx, &domain.SubscriptionEvent{
+		SiteID: sub.SiteID, Type: subEventStatusChanged, FromStatus: from, ToStatus: to, Actor: actor, Message: message,
+	})
+	return nil
+}
+
+// changePlan saves a plan/cycle change and records it in the audit trail (상태는 바꾸지 않음)
+func (s *BillingService) changePlan(ctx context.Context, sub *domain.Subscription, plan, cycle, actor string) error {
+	message := fmt.Sprintf("플랜 변경: %s(%s) → %s(%s)", sub.Plan, sub.BillingCycle, plan, cycle)
+	sub.Plan = plan
+	sub.MonthlyPriceKRW = planPricing[plan].MonthlyKRW
+	sub.BillingCycle = cycle
+	if err := s.subRepo.Update(ctx, sub); err != nil {
+		return err
+	}
+	s.recordEvent(ctx, &domain.SubscriptionEvent{
+		SiteID: sub.SiteID, Type: subEventPlanChanged, FromStatus: sub.Status, ToStatus: sub.Status, Actor: actor, Message: message,
+	})
+	return nil
+}
+
+// recordEvent appends to the subscription audit trail (실패해도 결제 흐름은 계속)
+func (s *BillingService) recordEvent(ctx context.Context, event *domain.SubscriptionEvent) {
+	pkglogger.Info("[Billing] %s %s %s->%s (%s): %s", event.SiteID, event.Type, event.FromStatus, event.ToStatus, event.Actor, event.Message)
+	if err := s.subRepo.CreateEvent(ctx, event); err != nil {
+		pkglogger.Info("[Billing] audit trail write failed for %s: %v", event.SiteID, err)
+	}
+}
+
+// ListEvents returns the subscription audit trail for a site
+func (s *BillingService) ListEvents(ctx context.Context, siteID string, page, perPage int) ([]domain.SubscriptionEvent, int64, error) {
+	return s.subRepo.ListEvents(ctx, siteID, perPage, (page-1)*perPage)
+}
+
+// HandleWebhook verifies and applies a provider webhook.
+// 같은 이벤트가 여러 번 와도 청구서/구독 상태를 기준으로 한 번만 반영된다.
+func (s *BillingService) HandleWebhook(ctx context.Context, providerName string, header http.Header, body []byte) error {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return ErrUnknownPaymentProvider
+	}
+	actor := "webhook:" + providerName
+	event, err := provider.ParseWebhook(header, body)
+	if errors.Is(err, payment.ErrUnknownEvent) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if event.Type == payment.EventSubscriptionCanceled {
+		sub, err := s.subRepo.FindBySiteID(ctx, event.CustomerKey)
+		if err != nil || sub == nil {
+			return err
+		}
+		return s.transition(ctx, sub, paymentStatusCanceled, actor, "결제사에서 구독 해지")
+	}
+
+	inv, err := s.subRepo.FindInvoiceByExternalID(ctx, event.OrderID)
+	if err != nil {
+		return err
+	}
+	if inv == nil {
+		pkglogger.Info("[Billing] webhook %s for unknown order %s ignored", event.ID, event.OrderID)
+		return nil
+	}
+	sub, err := s.subRepo.FindBySiteID(ctx, inv.SiteID)
+	if err != nil || sub == nil {
+		return err
+	}
+
+	switch event.Type {
+	case payment.EventPaymentSucceeded:
+		// 결제 대기/실패 청구서만 결제 완료로 — 재전송된 DONE이 환불된 청구서를 되살리지 않도록
+		if inv.Status != invoiceStatusPending && inv.Status != invoiceStatusFailed {
+			return nil
+		}
+		return s.markPaid(ctx, sub, inv, event.PaymentKey, event.OccurredAt, actor)
+	case payment.EventPaymentFailed:
+		if inv.Status != invoiceStatusPending {
+			return nil
+		}
+		return s.markFailed(ctx, sub, inv, "결제사 결제 실패 알림", actor)
+	case payment.EventPaymentRefunded:
+		if inv.Status != invoiceStatusPaid {
+			return nil
+		}
+		inv.Status = invoiceStatusRefunded
+		return s.subRepo.UpdateInvoice(ctx, inv)
+	}
+	return nil
+}
+
+// ProratePlanChange bills or credits the difference for the rest of the current period.
+// 같은 주기면 (새 가격 - 기존 가격) × 남은 기간 비율, 주기가 바뀌면 새 주기 전액에서 기존 잔여분을 뺀다.
+// 체험/취소 상태에서는 일할 계산하지 않는다. 추가 금액은 즉시 청구하며, 거절되면 플랜 변경을 막는다.
+func (s *BillingService) ProratePlanChange(ctx context.Context, sub *domain.Subscription, newPlan, newCycle string) (*domain.Invoice, error) {
+	if s == nil || sub.Status == paymentStatusTrialing || sub.Status == paymentStatusCanceled {
+		return nil, nil
+	}
+	now := time.Now()
+	oldPrice := cyclePrice(sub.Plan, sub.BillingCycle)
+	newPrice := cyclePrice(newPlan, newCycle)
+
+	remaining := 0.0
+	if total := sub.CurrentPeriodEnd.Sub(sub.CurrentPeriodStart); total > 0 && now.Before(sub.CurrentPeriodEnd) {
+		remaining = float64(sub.CurrentPeriodEnd.Sub(now)) / float64(total)
+	}
+
+	inv := &domain.Invoice{
+		SiteID:          sub.SiteID,
+		PaymentProvider: sub.PaymentProvider,
+		Currency:        "KRW",
+		PeriodStart:     now,
+		PeriodEnd:       sub.CurrentPeriodEnd,
+	}
+	if newCycle == sub.BillingCycle {
+		inv.AmountKRW = int(math.Round(float64(newPrice-oldPrice) * remaining))
+	} else {
+		inv.AmountKRW = newPrice - int(math.Round(float64(oldPrice)*remaining))
+		inv.PeriodEnd = cycleEnd(now, newCycle)
+	}
+	if inv.AmountKRW == 0 {
+		return nil, nil
+	}
+
+	if inv.AmountKRW < 0 {
+		inv.Status = invoiceStatusCredit
+		inv.Description = fmt.Sprintf("%s → %s 플랜 변경 크레딧 (일할 계산)", sub.Plan, newPlan)
+		return inv, s.createInvoice(ctx, inv)
+	}
+
+	inv.Status = invoiceStatusPending
+	inv.Description = fmt.Sprintf("%s → %s 플랜 변경 (일할 계산)", sub.Plan, newPlan)
+	if err := s.createInvoice(ctx, inv); err != nil {
+		return nil, err
+	}
+	if sub.ExternalSubID == "" {
+		// 결제 수단이 없으면(수동 청구) 대기 청구서로 남긴다
+		return inv, nil
+	}
+	result, err := s.charge(ctx, sub, inv)
+	if err != nil {
+		inv.Status = invoiceStatusFailed
+		if updateErr := s.subRepo.UpdateInvoice(ctx, inv); updateErr != nil {
+			pkglogger.Info("[Billing] invoice %d update failed: %v", inv.ID, updateErr)
+		}
+		return inv, fmt.Errorf("플랜 변경 결제 실패: %w", err)
+	}
+	inv.Status = invoiceStatusPaid
+	inv.PaidAt = &result.ApprovedAt
+	inv.PaymentKey = result.PaymentKey
+	return inv, s.subRepo.UpdateInvoice(ctx, inv)
+}
+
+// createInvoice saves an invoice and assigns its gateway order ID
+func (s *BillingService) createInvoice(ctx context.Context, inv *domain.Invoice) error {
+	if err := s.subRepo.CreateInvoice(ctx, inv); err != nil {
+		return fmt.Errorf("청구서 생성 실패: %w", err)
+	}
+	inv.ExternalInvID = fmt.Sprintf("angple-inv-%d", inv.ID)
+	return s.subRepo.UpdateInvoice(ctx, inv)
+}
+
+func (s *BillingService) subscription(ctx context.Context, siteID string) (*domain.Subscription, *domain.Site, error) {
+	sub, err := s.subRepo.FindBySiteID(ctx, siteID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sub == nil {
+		return nil, nil, errors.New("구독 정보를 찾을 수 없습니다")
+	}
+	site, err := s.siteRepo.FindByID(ctx, siteID)
+	if err != nil || site == nil {
+		return nil, nil, errors.New("사이트를 찾을 수 없습니다")
+	}
+	return sub, site, nil
+}
+
+// cyclePrice returns the plan price for one billing cycle
+func cyclePrice(plan, cycle string) int {
+	pricing := planPricing[plan]
+	if cycle == billingCycleYearly {
+		return pricing.YearlyKRW
+	}
+	return pricing.MonthlyKRW
+}
+
+// cycleEnd returns the end of a billing cycle starting at start
+func cycleEnd(start time.Time, cycle string) time.Time {
+	if cycle == billingCycleYearly {
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 1, 0)
+}
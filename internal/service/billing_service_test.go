@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/payment"
+	"github.com/damoang/angple-backend/internal/repository"
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupBilling(t *testing.T, status string) (*BillingService, *payment.FakeProvider, *repository.SubscriptionRepository, *gorm.DB) {
+	t.Helper()
+	pkglogger.Init()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Site{}))
+	subRepo := repository.NewSubscriptionRepository(db)
+	require.NoError(t, subRepo.AutoMigrate())
+
+	ctx := context.Background()
+	require.NoError(t, db.Create(&domain.Site{ID: "site-1", Subdomain: "one", OwnerEmail: "owner@example.com", Plan: "pro", DBStrategy: "shared", Active: true}).Error)
+	now := time.Now()
+	require.NoError(t, subRepo.Create(ctx, &domain.Subscription{
+		SiteID: "site-1", Plan: "pro", Status: status, BillingCycle: "monthly", MonthlyPriceKRW: 29000,
+		CurrentPeriodStart: now.AddDate(0, 0, -15), CurrentPeriodEnd: now.AddDate(0, 0, 15),
+	}))
+
+	fake := payment.NewFakeProvider("whsec")
+	svc := NewBillingService(subRepo, repository.NewSiteRepository(db))
+	svc.RegisterProvider(fake)
+	return svc, fake, subRepo, db
+}
+
+func TestBillingService_CheckoutAndWebhooks(t *testing.T) {
+	ctx := context.Background()
+	svc, fake, subRepo, _ := setupBilling(t, paymentStatusPastDue)
+
+	session, err := svc.CreateCheckout(ctx, "site-1", &domain.CheckoutRequest{SuccessURL: "https://a/ok", FailURL: "https://a/fail"})
+	require.NoError(t, err)
+	assert.Equal(t, 29000, session.AmountKRW)
+
+	inv, err := svc.ConfirmCheckout(ctx, "site-1", &domain.CheckoutConfirmRequest{OrderID: session.OrderID, AuthKey: "auth"})
+	require.NoError(t, err)
+	assert.Equal(t, invoiceStatusPaid, inv.Status)
+	sub, _ := subRepo.FindBySiteID(ctx, "site-1")
+	assert.Equal(t, paymentStatusActive, sub.Status)
+	assert.Equal(t, "fake_bk_site-1", sub.ExternalSubID)
+
+	// 잘못된 서명
+	body, header := fake.BuildWebhook(&payment.WebhookEvent{ID: "evt-1", Type: payment.EventPaymentRefunded, OrderID: session.OrderID})
+	header.Set(payment.FakeSignatureHeader, "v1:forged")
+	assert.ErrorIs(t, svc.HandleWebhook(ctx, payment.ProviderFake, header, body), payment.ErrInvalidSignature)
+
+	// 환불 웹훅 (두 번 와도 한 번만 반영)
+	body, header = fake.BuildWebhook(&payment.WebhookEvent{ID: "evt-1", Type: payment.EventPaymentRefunded, OrderID: session.OrderID})
+	require.NoError(t, svc.HandleWebhook(ctx, payment.ProviderFake, header, body))
+	require.NoError(t, svc.HandleWebhook(ctx, payment.ProviderFake, header, body))
+	inv, _ = subRepo.FindInvoiceByExternalID(ctx, session.OrderID)
+	assert.Equal(t, invoiceStatusRefunded, inv.Status)
+
+	// 재전송된 결제 완료 웹훅은 환불된 청구서를 되살리지 않음
+	body, header = fake.BuildWebhook(&payment.WebhookEvent{ID: "evt-3", Type: payment.EventPaymentSucceeded, OrderID: session.OrderID, PaymentKey: "pk"})
+	require.NoError(t, svc.HandleWebhook(ctx, payment.ProviderFake, header, body))
+	inv, _ = subRepo.FindInvoiceByExternalID(ctx, session.OrderID)
+	assert.Equal(t, invoiceStatusRefunded, inv.Status)
+
+	body, header = fake.BuildWebhook(&payment.WebhookEvent{ID: "evt-2", Type: payment.EventSubscriptionCanceled, CustomerKey: "site-1"})
+	require.NoError(t, svc.HandleWebhook(ctx, payment.ProviderFake, header, body))
+	sub, _ = subRepo.FindBySiteID(ctx, "site-1")
+	assert.Equal(t, paymentStatusCanceled, sub.Status)
+	assert.NotNil(t, sub.CanceledAt)
+}
+
+func TestBillingService_DeclineMovesToPastDue(t *testing.T) {
+	ctx := context.Background()
+	svc, fake, subRepo, _ := setupBilling(t, paymentStatusActive)
+
+	session, err := svc.CreateCheckout(ctx, "site-1", &domain.CheckoutRequest{SuccessURL: "https://a/ok", FailURL: "https://a/fail"})
+	require.NoError(t, err)
+	fake.SetDecline(true)
+	_, err = svc.ConfirmCheckout(ctx, "site-1", &domain.CheckoutConfirmRequest{OrderID: session.OrderID, AuthKey: "auth"})
+	var declined *payment.ChargeError
+	require.ErrorAs(t, err, &declined)
+
+	sub, _ := subRepo.FindBySiteID(ctx, "site-1")
+	assert.Equal(t, paymentStatusPastDue, sub.Status)
+	inv, _ := subRepo.FindInvoiceByExternalID(ctx, session.OrderID)
+	assert.Equal(t, invoiceStatusFailed, inv.Status)
+}
+
+func TestBillingService_ProratePlanChange(t *testing.T) {
+	ctx := context.Background()
+	svc, fake, subRepo, _ := setupBilling(t, paymentStatusActive)
+	sub, _ := subRepo.FindBySiteID(ctx, "site-1")
+	sub.PaymentProvider = payment.ProviderFake
+	sub.ExternalSubID = "fake_bk_site-1"
+
+	// pro(29,000) → business(99,000), 절반 남음 → 약 35,000 즉시 청구
+	inv, err := svc.ProratePlanChange(ctx, sub, "business", "monthly")
+	require.NoError(t, err)
+	assert.InDelta(t, 35000, inv.AmountKRW, 100)
+	assert.Equal(t, invoiceStatusPaid, inv.Status)
+	require.Len(t, fake.Charges, 1)
+
+	// business → free 다운그레이드는 크레딧, 다음 청구에서 차감
+	sub.Plan = "business"
+	credit, err := svc.ProratePlanChange(ctx, sub, "free", "monthly")
+	require.NoError(t, err)
+	assert.Equal(t, invoiceStatusCredit, credit.Status)
+	assert.Less(t, credit.AmountKRW, 0)
+
+	renewal := &domain.Invoice{SiteID: "site-1", Status: invoiceStatusPending, AmountKRW: 99000, PeriodStart: sub.CurrentPeriodEnd, PeriodEnd: sub.CurrentPeriodEnd.AddDate(0, 1, 0)}
+	require.NoError(t, svc.createInvoice(ctx, renewal))
+	require.NoError(t, svc.ChargeInvoice(ctx, sub, renewal, "test"))
+	require.Len(t, fake.Charges, 2)
+	assert.Equal(t, 99000+credit.AmountKRW, fake.Charges[1].AmountKRW)
+
+	// 체험 중에는 일할 계산하지 않음
+	sub.Status = paymentStatusTrialing
+	inv, err = svc.ProratePlanChange(ctx, sub, "enterprise", "monthly")
+	require.NoError(t, err)
+	assert.Nil(t, inv)
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/payment"
+	"github.com/damoang/angple-backend/internal/repository"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+)
+
+const (
+	dunningActor         = "system:dunning"
+	trialNoticeLeadTime  = 3 * 24 * time.Hour
+	billingNotifyFromKey = "billing"
+)
+
+// OwnerNotifier notifies a site owner about billing events
+type OwnerNotifier interface {
+	NotifyOwner(ctx context.Context, site *domain.Site, subject, message string) error
+}
+
+// DunningResult is the result of one dunning run
+type DunningResult struct {
+	TrialNotices       int    `json:"trial_notices"`
+	TrialsConverted    int    `json:"trials_converted"`
+	TrialsDowngraded   int    `json:"trials_downgraded"`
+	CanceledDowngraded int    `json:"canceled_downgraded"`
+	Renewed            int    `json:"renewed"`
+	Retried            int    `json:"retried"`
+	Recovered          int    `json:"recovered"`
+	Suspended          int    `json:"suspended"`
+	Errors             int    `json:"errors"`
+	ExecutedAt         string `json:"executed_at"`
+}
+
+// DunningService handles trial expiry, renewals and failed-payment retries for SaaS subscriptions.
+// cron(/api/internal/cron/billing-dunning)으로 매시간 실행한다.
+//
+//   - 체험 종료 3일 전: 소유자에게 알림
+//   - 체험 종료: 결제 수단이 있으면 청구, 없으면 무료 플랜으로 전환
+//   - 결제 기간 종료: 갱신 청구 (해지된 구독은 무료 플랜으로 전환)
+//   - 결제 실패: dunningRetrySchedule에 따라 재시도, 모두 실패하면 구독 해지 + TenantService.SuspendTenant
+type DunningService struct {
+	subRepo   *repository.SubscriptionRepository
+	siteRepo  *repository.SiteRepository
+	billing   *BillingService
+	tenantSvc *TenantService
+	notifier  OwnerNotifier
+}
+
+// NewDunningService creates a new DunningService
+func NewDunningService(subRepo *repository.SubscriptionRepository, siteRepo *repository.SiteRepository, billing *BillingService, tenantSvc *TenantService) *DunningService {
+	return &DunningService{subRepo: subRepo, siteRepo: siteRepo, billing: billing, tenantSvc: tenantSvc}
+}
+
+// SetNotifier sets the owner notifier (없으면 감사 로그에만 기록)
+func (d *DunningService) SetNotifier(notifier OwnerNotifier) {
+	d.notifier = notifier
+}
+
+// Run executes one dunning pass
+func (d *DunningService) Run(ctx context.Context) (*DunningResult, error) {
+	now := time.Now()
+	result := &DunningResult{ExecutedAt: now.Format("2006-01-02 15:04:05")}
+
+	steps := []func(context.Context, time.Time, *DunningResult) error{
+		d.noticeEndingTrials,
+		d.expireTrials,
+		d.renewSubscriptions,
+		d.endCanceledSubscriptions,
+		d.retryFailedInvoices,
+	}
+	for _, step := range steps {
+		if err := step(ctx, now, result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// noticeEndingTrials notifies owners once when a trial ends within trialNoticeLeadTime
+func (d *DunningService) noticeEndingTrials(ctx context.Context, now time.Time, result *DunningResult) error {
+	subs, err := d.subRepo.ListByStatusEndingBefore(ctx, paymentStatusTrialing, now.Add(trialNoticeLeadTime))
+	if err != nil {
+		return fmt.Errorf("체험 구독 조회 실패: %w", err)
+	}
+	for i := range subs {
+		sub := &subs[i]
+		if !sub.CurrentPeriodEnd.After(now) {
+			continue
+		}
+		sent, err := d.subRepo.HasEventSince(ctx, sub.SiteID, subEventTrialEndingNotice, sub.CurrentPeriodStart)
+		if err != nil || sent {
+			continue
+		}
+
+		message := fmt.Sprintf("무료 체험이 %s에 종료됩니다. 결제 수단을 등록하지 않으면 무료 플랜으로 전환됩니다.", sub.CurrentPeriodEnd.Format("2006-01-02 15:04"))
+		if sub.ExternalSubID != "" {
+			message = fmt.Sprintf("무료 체험이 %s에 종료되며 등록된 결제 수단으로 %s 플랜 요금이 청구됩니다.", sub.CurrentPeriodEnd.Format("2006-01-02 15:04"), sub.Plan)
+		}
+		d.billing.recordEvent(ctx, &domain.SubscriptionEvent{SiteID: sub.SiteID, Type: subEventTrialEndingNotice, Actor: dunningActor, Message: message})
+		d.notify(ctx, sub.SiteID, "무료 체험 종료 안내", message)
+		result.TrialNotices++
+	}
+	return nil
+}
+
+// expireTrials charges trials with a payment method and downgrades the rest to the free plan
+func (d *DunningService) expireTrials(ctx context.Context, now time.Time, result *DunningResult) error {
+	subs, err := d.subRepo.ListByStatusEndingBefore(ctx, paymentStatusTrialing, now)
+	if err != nil {
+		return fmt.Errorf("만료 체험 조회 실패: %w", err)
+	}
+	for i := range subs {
+		sub := &subs[i]
+		if sub.ExternalSubID == "" {
+			if err := d.downgradeTrial(ctx, sub); err != nil {
+				pkglogger.Info("[Dunning] trial downgrade failed for %s: %v", sub.SiteID, err)
+				result.Errors++
+				continue
+			}
+			result.TrialsDowngraded++
+			continue
+		}
+
+		err := d.chargePeriod(ctx, sub)
+		switch {
+		case err == nil:
+			result.TrialsConverted++
+		case isDecline(err):
+			d.notify(ctx, sub.SiteID, "결제 실패 안내", "체험 종료 후 첫 결제가 실패했습니다. 결제 수단을 확인해 주세요.")
+		default:
+			pkglogger.Info("[Dunning] trial charge failed for %s: %v", sub.SiteID, err)
+			result.Errors++
+		}
+	}
+	return nil
+}
+
+// downgradeTrial moves a lapsed trial to the free plan
+func (d *DunningService) downgradeTrial(ctx context.Context, sub *domain.Subscription) error {
+	message := fmt.Sprintf("결제 수단 없이 체험이 종료되어 %s 플랜에서 무료 플랜으로 전환", sub.Plan)
+	if err := d.downgradeToFree(ctx, sub, subEventTrialDowngraded, message); err != nil {
+		return err
+	}
+	d.notify(ctx, sub.SiteID, "무료 플랜 전환 안내", "무료 체험이 종료되어 무료 플랜으로 전환되었습니다. 유료 플랜은 언제든 다시 결제할 수 있습니다.")
+	return nil
+}
+
+// downgradeToFree moves the subscription and site to an active free plan.
+// 데이터 이전을 피하기 위해 DB 전략은 유지하고 플랜 한도만 무료로 적용한다.
+func (d *DunningService) downgradeToFree(ctx context.Context, sub *domain.Subscription, eventType, message string) error {
+	site, err := d.siteRepo.FindByID(ctx, sub.SiteID)
+	if err != nil || site == nil {
+		return errors.New("사이트를 찾을 수 없습니다")
+	}
+	site.Plan = planFree
+	if err := d.siteRepo.Update(ctx, site); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sub.Plan = planFree
+	sub.MonthlyPriceKRW = 0
+	sub.CurrentPeriodStart = now
+	sub.CurrentPeriodEnd = cycleEnd(now, sub.BillingCycle)
+	if err := d.billing.transition(ctx, sub, paymentStatusActive, dunningActor, message); err != nil {
+		return err
+	}
+	d.billing.recordEvent(ctx, &domain.SubscriptionEvent{SiteID: sub.SiteID, Type: eventType, Actor: dunningActor, Message: message})
+	return nil
+}
+
+// renewSubscriptions bills active subscriptions whose period ended (무료 플랜은 기간만 연장)
+func (d *DunningService) renewSubscriptions(ctx context.Context, now time.Time, result *DunningResult) error {
+	subs, err := d.subRepo.ListByStatusEndingBefore(ctx, paymentStatusActive, now)
+	if err != nil {
+		return fmt.Errorf("갱신 대상 조회 실패: %w", err)
+	}
+	for i := range subs {
+		sub := &subs[i]
+		if cyclePrice(sub.Plan, sub.BillingCycle) <= 0 {
+			sub.CurrentPeriodStart = sub.CurrentPeriodEnd
+			sub.CurrentPeriodEnd = cycleEnd(sub.CurrentPeriodEnd, sub.BillingCycle)
+			if err := d.subRepo.Update(ctx, sub); err != nil {
+				result.Errors++
+			}
+			continue
+		}
+
+		err := d.chargePeriod(ctx, sub)
+		switch {
+		case err == nil:
+			result.Renewed++
+		case isDecline(err):
+			d.notify(ctx, sub.SiteID, "결제 실패 안내", "구독 갱신 결제가 실패했습니다. 결제 수단을 확인해 주세요.")
+		default:
+			pkglogger.Info("[Dunning] renewal failed for %s: %v", sub.SiteID, err)
+			result.Errors++
+		}
+	}
+	return nil
+}
+
+// chargePeriod charges the invoice for the period starting at the subscription's period end
+func (d *DunningService) chargePeriod(ctx context.Context, sub *domain.Subscription) error {
+	start := sub.CurrentPeriodEnd
+	inv, err := d.subRepo.FindOpenInvoiceForPeriod(ctx, sub.SiteID, start)
+	if err != nil {
+		return err
+	}
+	if inv == nil {
+		inv = &domain.Invoice{
+			SiteID:          sub.SiteID,
+			Status:          invoiceStatusPending,
+			PaymentProvider: sub.PaymentProvider,
+			Description:     fmt.Sprintf("%s 플랜 (%s)", sub.Plan, sub.BillingCycle),
+			Currency:        "KRW",
+			AmountKRW:       cyclePrice(sub.Plan, sub.BillingCycle),
+			PeriodStart:     start,
+			PeriodEnd:       cycleEnd(start, sub.BillingCycle),
+		}
+		if err := d.billing.createInvoice(ctx, inv); err != nil {
+			return err
+		}
+	}
+	if inv.Status == invoiceStatusFailed {
+		// 이미 실패한 청구서는 재시도 일정(retryFailedInvoices)을 따른다
+		return nil
+	}
+	return d.billing.ChargeInvoice(ctx, sub, inv, dunningActor)
+}
+
+// endCanceledSubscriptions downgrades sites whose canceled subscription reached period end to the free plan.
+// 결제 실패로 해지·정지된 사이트(suspend)는 정지 상태를 유지한다.
+func (d *DunningService) endCanceledSubscriptions(ctx context.Context, now time.Time, result *DunningResult) error {
+	subs, err := d.subRepo.ListByStatusEndingBefore(ctx, paymentStatusCanceled, now)
+	if err != nil {
+		return fmt.Errorf("해지 구독 조회 실패: %w", err)
+	}
+	for i := range subs {
+		sub := &subs[i]
+		site, err := d.siteRepo.FindByID(ctx, sub.SiteID)
+		if err != nil || site == nil || !site.Active || site.Suspended {
+			continue
+		}
+		message := fmt.Sprintf("해지된 %s 플랜의 결제 기간이 끝나 무료 플랜으로 전환", sub.Plan)
+		if err := d.downgradeToFree(ctx, sub, subEventCanceledDowngraded, message); err != nil {
+			pkglogger.Info("[Dunning] canceled downgrade failed for %s: %v", sub.SiteID, err)
+			result.Errors++
+			continue
+		}
+		d.notify(ctx, sub.SiteID, "무료 플랜 전환 안내", "구독이 해지되어 무료 플랜으로 전환되었습니다. 유료 플랜은 언제든 다시 결제할 수 있습니다.")
+		result.CanceledDowngraded++
+	}
+	return nil
+}
+
+// retryFailedInvoices retries failed invoices on schedule and suspends sites when retries run out
+func (d *DunningService) retryFailedInvoices(ctx context.Context, now time.Time, result *DunningResult) error {
+	invoices, err := d.subRepo.ListRetryDueInvoices(ctx, now)
+	if err != nil {
+		return fmt.Errorf("재시도 청구서 조회 실패: %w", err)
+	}
+	for i := range invoices {
+		inv := &invoices[i]
+		sub, err := d.subRepo.FindBySiteID(ctx, inv.SiteID)
+		if err != nil || sub == nil {
+			result.Errors++
+			continue
+		}
+		if sub.Status == paymentStatusCanceled {
+			inv.NextRetryAt = nil
+			if err := d.subRepo.UpdateInvoice(ctx, inv); err != nil {
+				result.Errors++
+			}
+			continue
+		}
+
+		result.Retried++
+		d.billing.recordEvent(ctx, &domain.SubscriptionEvent{
+			SiteID: sub.SiteID, Type: subEventPaymentRetry, Actor: dunningActor, InvoiceID: &inv.ID,
+			Message: fmt.Sprintf("청구서 %s 결제 재시도 (%d회차)", inv.ExternalInvID, inv.Attempts+1),
+		})
+		err = d.billing.ChargeInvoice(ctx, sub, inv, dunningActor)
+		switch {
+		case err == nil:
+			result.Recovered++
+			d.notify(ctx, sub.SiteID, "결제 완료 안내", "밀린 구독 요금 결제가 완료되었습니다.")
+		case isDecline(err) && inv.NextRetryAt == nil:
+			if d.suspend(ctx, sub, fmt.Sprintf("결제 %d회 실패", inv.Attempts)) {
+				result.Suspended++
+			}
+		case isDecline(err):
+			d.notify(ctx, sub.SiteID, "결제 실패 안내",
+				fmt.Sprintf("구독 요금 결제가 실패했습니다. %s에 다시 시도합니다.", inv.NextRetryAt.Format("2006-01-02")))
+		default:
+			pkglogger.Info("[Dunning] retry failed for %s: %v", sub.SiteID, err)
+			result.Errors++
+		}
+	}
+	return nil
+}
+
+// suspend cancels the subscription and suspends the tenant
+func (d *DunningService) suspend(ctx context.Context, sub *domain.Subscription, reason string) bool {
+	if err := d.billing.transition(ctx, sub, paymentStatusCanceled, dunningActor, reason); err != nil {
+		pkglogger.Info("[Dunning] cancel failed for %s: %v", sub.SiteID, err)
+	}
+	if err := d.tenantSvc.SuspendTenant(ctx, sub.SiteID, reason); err != nil {
+		pkglogger.Info("[Dunning] suspend failed for %s: %v", sub.SiteID, err)
+		return false
+	}
+	d.billing.recordEvent(ctx, &domain.SubscriptionEvent{
+		SiteID: sub.SiteID, Type: subEventSuspended, ToStatus: sub.Status, Actor: dunningActor, Message: reason,
+	})
+	d.notify(ctx, sub.SiteID, "사이트 정지 안내", "구독 요금이 결제되지 않아 사이트가 정지되었습니다. 결제 후 관리자에게 문의해 주세요.")
+	return true
+}
+
+// notify sends a best-effort notification to the site owner
+func (d *DunningService) notify(ctx context.Context, siteID, subject, message string) {
+	if d.notifier == nil {
+		return
+	}
+	site, err := d.siteRepo.FindByID(ctx, siteID)
+	if err != nil || site == nil {
+		return
+	}
+	if err := d.notifier.NotifyOwner(ctx, site, subject, message); err != nil {
+		pkglogger.Info("[Dunning] notify %s failed: %v", site.OwnerEmail, err)
+	}
+}
+
+func isDecline(err error) bool {
+	var declined *payment.ChargeError
+	return errors.As(err, &declined)
+}
+
+// NotiOwnerNotifier delivers billing notices as member notifications (g5_na_noti)
+// to the member whose email matches Site.OwnerEmail
+type NotiOwnerNotifier struct {
+	memberRepo gnurepo.MemberRepository
+	notiRepo   gnurepo.NotiRepository
+}
+
+// NewNotiOwnerNotifier creates a new NotiOwnerNotifier
+func NewNotiOwnerNotifier(memberRepo gnurepo.MemberRepository, notiRepo gnurepo.NotiRepository) *NotiOwnerNotifier {
+	return &NotiOwnerNotifier{memberRepo: memberRepo, notiRepo: notiRepo}
+}
+
+// NotifyOwner creates a system notification for the owner
+func (n *NotiOwnerNotifier) NotifyOwner(_ context.Context, site *domain.Site, subject, message string) error {
+	member, err := n.memberRepo.FindByEmail(site.OwnerEmail)
+	if err != nil || member == nil {
+		return fmt.Errorf("owner member not found: %s", site.OwnerEmail)
+	}
+	return n.notiRepo.Create(&gnurepo.Notification{
+		MbID:          member.MbID,
+		PhFromCase:    billingNotifyFromKey,
+		PhToCase:      "me",
+		BoTable:       "@system",
+		RelMbID:       "system",
+		RelMbNick:     site.SiteName,
+		RelMsg:        message,
+		RelURL:        "/admin/billing",
+		PhReaded:      "N",
+		PhDatetime:    time.Now(),
+		ParentSubject: subject,
+	})
+}
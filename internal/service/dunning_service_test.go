@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/payment"
+	"github.com/damoang/angple-backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeOwnerNotifier struct {
+	subjects []string
+}
+
+func (n *fakeOwnerNotifier) NotifyOwner(_ context.Context, _ *domain.Site, subject, _ string) error {
+	n.subjects = append(n.subjects, subject)
+	return nil
+}
+
+func setupDunning(t *testing.T) (*DunningService, *BillingService, *payment.FakeProvider, *repository.SubscriptionRepository, *gorm.DB, *fakeOwnerNotifier) {
+	t.Helper()
+	billing, fake, subRepo, db := setupBilling(t, paymentStatusActive)
+	siteRepo := repository.NewSiteRepository(db)
+	notifier := &fakeOwnerNotifier{}
+	dunning := NewDunningService(subRepo, siteRepo, billing, NewTenantService(siteRepo, db, nil))
+	dunning.SetNotifier(notifier)
+	return dunning, billing, fake, subRepo, db, notifier
+}
+
+func TestDunningService_TrialNoticeAndDowngrade(t *testing.T) {
+	ctx := context.Background()
+	dunning, _, _, subRepo, db, notifier := setupDunning(t)
+
+	sub, _ := subRepo.FindBySiteID(ctx, "site-1")
+	sub.Status = paymentStatusTrialing
+	sub.CurrentPeriodStart = time.Now().AddDate(0, 0, -12)
+	sub.CurrentPeriodEnd = time.Now().Add(48 * time.Hour)
+	require.NoError(t, subRepo.Update(ctx, sub))
+
+	// 종료 3일 전 알림은 한 번만
+	for i := 0; i < 2; i++ {
+		result, err := dunning.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1-i, result.TrialNotices)
+	}
+	assert.Equal(t, []string{"무료 체험 종료 안내"}, notifier.subjects)
+
+	// 결제 수단 없이 체험 종료 → 무료 플랜 전환
+	sub.CurrentPeriodEnd = time.Now().Add(-time.Minute)
+	require.NoError(t, subRepo.Update(ctx, sub))
+	result, err := dunning.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.TrialsDowngraded)
+
+	sub, _ = subRepo.FindBySiteID(ctx, "site-1")
+	assert.Equal(t, planFree, sub.Plan)
+	assert.Equal(t, paymentStatusActive, sub.Status)
+	var site domain.Site
+	require.NoError(t, db.First(&site, "id = ?", "site-1").Error)
+	assert.Equal(t, planFree, site.Plan)
+	assert.Equal(t, "shared", site.DBStrategy)
+
+	events, _, err := subRepo.ListEvents(ctx, "site-1", 10, 0)
+	require.NoError(t, err)
+	types := make([]string, 0, len(events))
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	assert.Contains(t, types, subEventTrialEndingNotice)
+	assert.Contains(t, types, subEventTrialDowngraded)
+	assert.Contains(t, types, subEventStatusChanged)
+}
+
+func TestDunningService_RetryThenSuspend(t *testing.T) {
+	ctx := context.Background()
+	dunning, _, fake, subRepo, db, _ := setupDunning(t)
+
+	sub, _ := subRepo.FindBySiteID(ctx, "site-1")
+	sub.PaymentProvider = payment.ProviderFake
+	sub.ExternalSubID = "fake_bk_site-1"
+	sub.CurrentPeriodEnd = time.Now().Add(-time.Hour)
+	require.NoError(t, subRepo.Update(ctx, sub))
+
+	// 갱신 결제 실패 → past_due, 재시도 예약
+	fake.SetDecline(true)
+	result, err := dunning.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Renewed)
+	sub, _ = subRepo.FindBySiteID(ctx, "site-1")
+	assert.Equal(t, paymentStatusPastDue, sub.Status)
+
+	// 재시도 일정을 모두 소진하면 구독 해지 + 사이트 정지
+	for i := 0; i < len(dunningRetrySchedule); i++ {
+		require.NoError(t, db.Model(&domain.Invoice{}).Where("status = ?", invoiceStatusFailed).
+			Update("next_retry_at", time.Now().Add(-time.Minute)).Error)
+		result, err = dunning.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Retried)
+	}
+	assert.Equal(t, 1, result.Suspended)
+
+	sub, _ = subRepo.FindBySiteID(ctx, "site-1")
+	assert.Equal(t, paymentStatusCanceled, sub.Status)
+	var site domain.Site
+	require.NoError(t, db.First(&site, "id = ?", "site-1").Error)
+	assert.True(t, site.Suspended)
+	assert.Len(t, fake.Charges, 1+len(dunningRetrySchedule))
+}
+
+func TestDunningService_CanceledSubscriptionDowngradesToFree(t *testing.T) {
+	ctx := context.Background()
+	dunning, billing, _, subRepo, db, _ := setupDunning(t)
+	provisioning := NewProvisioningService(repository.NewSiteRepository(db), subRepo, nil, db, "example.com")
+	provisioning.SetBillingService(billing)
+
+	// 사용자 해지는 감사 로그에 남고 기간 종료 전에는 유료 플랜 유지
+	require.NoError(t, provisioning.CancelSubscription(ctx, "site-1"))
+	result, err := dunning.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.CanceledDowngraded)
+
+	sub, _ := subRepo.FindBySiteID(ctx, "site-1")
+	assert.Equal(t, paymentStatusCanceled, sub.Status)
+	sub.CurrentPeriodEnd = time.Now().Add(-time.Minute)
+	require.NoError(t, subRepo.Update(ctx, sub))
+
+	result, err = dunning.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.CanceledDowngraded)
+	assert.Equal(t, 0, result.Suspended)
+
+	sub, _ = subRepo.FindBySiteID(ctx, "site-1")
+	assert.Equal(t, planFree, sub.Plan)
+	assert.Equal(t, paymentStatusActive, sub.Status)
+	var site domain.Site
+	require.NoError(t, db.First(&site, "id = ?", "site-1").Error)
+	assert.Equal(t, planFree, site.Plan)
+	assert.False(t, site.Suspended)
+
+	events, _, err := subRepo.ListEvents(ctx, "site-1", 10, 0)
+	require.NoError(t, err)
+	actors := make([]string, 0, len(events))
+	types := make([]string, 0, len(events))
+	for _, e := range events {
+		actors = append(actors, e.Actor)
+		types = append(types, e.Type)
+	}
+	assert.Contains(t, actors, provisioningActor)
+	assert.Contains(t, types, subEventCanceledDowngraded)
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path"
+	"strings"
+	"sync"
+
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"github.com/damoang/angple-backend/pkg/storage"
+)
+
+// VariantOriginal 원본 이미지 변형 이름 (업로드된 키 그대로)
+const VariantOriginal = "original"
+
+const (
+	variantKeyPrefix = "variants/"
+	maxVariantPixels = 40 * 1000 * 1000 // 디코딩 허용 최대 픽셀 수 (압축 폭탄 방지)
+)
+
+// 이미지 변형 에러
+var (
+	ErrUnknownVariant     = errors.New("unknown image variant")
+	ErrVariantPoolStopped = errors.New("image variant pool stopped")
+	ErrVariantQueueFull   = errors.New("image variant queue is full")
+	ErrInvalidVariantKey  = errors.New("variants are only served for uploaded images")
+)
+
+// ImageVariant 반응형 이미지 변형 규격 (원본보다 좁을 때만 축소)
+type ImageVariant struct {
+	Name  string
+	Width int
+}
+
+// ImageVariants 업로드 이미지마다 생성하는 변형 (original 제외)
+var ImageVariants = []ImageVariant{
+	{Name: "thumb", Width: 200},
+	{Name: "small", Width: 480},
+	{Name: "medium", Width: 1024},
+}
+
+// MediaVariant 이미지 변형 정보
+type MediaVariant struct {
+	Key    string `json:"key"`
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// VariantKey 원본 키(full key)에 대한 변형 업로드 키
+func VariantKey(name, key string) string {
+	return variantKeyPrefix + name + "/" + key
+}
+
+// findVariant 이름으로 변형 규격 조회
+func findVariant(name string) (ImageVariant, bool) {
+	for _, v := range ImageVariants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return ImageVariant{}, false
+}
+
+// supportsVariants 변형을 생성하는 원본 형식 (SVG/GIF 등은 원본만 제공, 변형의 변형은 만들지 않음)
+func supportsVariants(key string) bool {
+	if strings.Contains(key, variantKeyPrefix) {
+		return false
+	}
+	switch strings.ToLower(path.Ext(key)) {
+	case ".jpg", ".jpeg", ".png":
+		return true
+	}
+	return false
+}
+
+// variantSize 원본 크기를 변형 폭에 맞춘 크기 (원본이 더 좁으면 그대로)
+func variantSize(width, height, maxWidth int) (int, int) {
+	if width <= maxWidth || width == 0 {
+		return width, height
+	}
+	return maxWidth, height * maxWidth / width
+}
+
+// dominantColor 이미지의 평균 색 (#rrggbb, 최대 64x64 샘플)
+func dominantColor(img image.Image) string {
+	b := img.Bounds()
+	if b.Empty() {
+		return ""
+	}
+	stepX, stepY := max(b.Dx()/64, 1), max(b.Dy()/64, 1)
+	var r, g, bl, n uint64
+	for y := b.Min.Y; y < b.Max.Y; y += stepY {
+		for x := b.Min.X; x < b.Max.X; x += stepX {
+			cr, cg, cb, _ := img.At(x, y).RGBA()
+			r, g, bl, n = r+uint64(cr>>8), g+uint64(cg>>8), bl+uint64(cb>>8), n+1
+		}
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r/n, g/n, bl/n)
+}
+
+// variantJob 키 하나의 변형 생성 작업 (같은 키의 동시 요청은 하나의 작업을 공유)
+type variantJob struct {
+	key  string
+	done chan struct{}
+	err  error
+}
+
+// ImageVariantPool 이미지 변형을 생성하는 고정 크기 워커 풀
+// 업로드 직후 Enqueue로 예약하고, 변형이 없는 기존 키는 요청 시 Ensure로 생성을 기다린다.
+type ImageVariantPool struct {
+	store    storage.Backend
+	workers  int
+	jobs     chan *variantJob
+	mu       sync.Mutex
+	inflight map[string]*variantJob
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewImageVariantPool creates a pool with the given number of workers and queue size
+func NewImageVariantPool(store storage.Backend, workers, queueSize int) *ImageVariantPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ImageVariantPool{
+		store:    store,
+		workers:  max(workers, 1),
+		jobs:     make(chan *variantJob, queueSize),
+		inflight: make(map[string]*variantJob),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start 워커 시작
+func (p *ImageVariantPool) Start() {
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for {
+				select {
+				case <-p.ctx.Done():
+					return
+				case job := <-p.jobs:
+					p.run(job)
+				}
+			}
+		}()
+	}
+	pkglogger.Info("[ImageVariantPool] Started %d workers", p.workers)
+}
+
+// Stop 워커 정지 (대기 중인 작업은 버려지고 요청 시 다시 생성된다)
+func (p *ImageVariantPool) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// Enqueue 변형 생성 예약 (큐가 가득 차면 버림 — 요청 시 지연 생성됨)
+func (p *ImageVariantPool) Enqueue(key string) {
+	if _, err := p.submit(p.ctx, key, false); err != nil {
+		pkglogger.Info("[ImageVariantPool] %s not queued: %v", key, err)
+	}
+}
+
+// Ensure key의 변형 생성이 끝날 때까지 대기
+func (p *ImageVariantPool) Ensure(ctx context.Context, key string) error {
+	job, err := p.submit(ctx, key, true)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-job.done:
+		return job.err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.ctx.Done():
+		return ErrVariantPoolStopped
+	}
+}
+
+// submit 진행 중인 같은 키의 작업이 있으면 공유하고, 없으면 큐에 넣음
+func (p *ImageVariantPool) submit(ctx context.Context, key string, wait bool) (*variantJob, error) {
+	p.mu.Lock()
+	if job, ok := p.inflight[key]; ok {
+		p.mu.Unlock()
+		return job, nil
+	}
+	job := &variantJob{key: key, done: make(chan struct{})}
+	p.inflight[key] = job
+	p.mu.Unlock()
+
+	var err error
+	if wait {
+		select {
+		case p.jobs <- job:
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-p.ctx.Done():
+			err = ErrVariantPoolStopped
+		}
+	} else {
+		select {
+		case p.jobs <- job:
+		default:
+			err = ErrVariantQueueFull
+		}
+	}
+	if err != nil {
+		p.finish(job, err)
+		return nil, err
+	}
+	return job, nil
+}
+
+func (p *ImageVariantPool) run(job *variantJob) {
+	err := p.generate(p.ctx, job.key)
+	if err != nil {
+		pkglogger.Error("[ImageVariantPool] %s failed: %v", job.key, err)
+	}
+	p.finish(job, err)
+}
+
+func (p *ImageVariantPool) finish(job *variantJob, err error) {
+	p.mu.Lock()
+	delete(p.inflight, job.key)
+	p.mu.Unlock()
+	job.err = err
+	close(job.done)
+}
+
+// generate 원본을 내려받아 없는 변형만 축소/인코딩해 업로드
+// 원본이 변형 폭보다 좁으면 원본 바이트를 그대로 저장해 다음 요청에서 다시 디코딩하지 않는다.
+func (p *ImageVariantPool) generate(ctx context.Context, key string) error {
+	var missing []ImageVariant
+	for _, v := range ImageVariants {
+		ok, err := p.store.Exists(ctx, p.store.FullKey(VariantKey(v.Name, key)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	data, err := p.download(ctx, key)
+	if err != nil {
+		return err
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decode %s: %w", key, err)
+	}
+	if cfg.Width*cfg.Height > maxVariantPixels {
+		return fmt.Errorf("image %s is too large (%dx%d)", key, cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decode %s: %w", key, err)
+	}
+
+	for _, v := range missing {
+		body, contentType, err := encodeVariant(img, format, data, v.Width)
+		if err != nil {
+			return err
+		}
+		if _, err := p.store.Upload(ctx, VariantKey(v.Name, key), bytes.NewReader(body), contentType, int64(len(body))); err != nil {
+			return fmt.Errorf("upload %s variant of %s: %w", v.Name, key, err)
+		}
+	}
+	return nil
+}
+
+func (p *ImageVariantPool) download(ctx context.Context, key string) ([]byte, error) {
+	body, err := p.store.Download(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(io.LimitReader(body, 50*1024*1024))
+}
+
+// encodeVariant 원본 형식(PNG/JPEG)을 유지해 maxWidth로 축소 인코딩
+func encodeVariant(img image.Image, format string, original []byte, maxWidth int) ([]byte, string, error) {
+	contentType := "image/jpeg"
+	if format == "png" {
+		contentType = "image/png"
+	}
+	if img.Bounds().Dx() <= maxWidth {
+		return original, contentType, nil
+	}
+
+	resized := resizeImage(img, maxWidth)
+	var buf bytes.Buffer
+	var err error
+	if format == "png" {
+		err = png.Encode(&buf, resized)
+	} else {
+		err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 80})
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), contentType, nil
+}
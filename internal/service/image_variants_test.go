@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"github.com/damoang/angple-backend/pkg/storage"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageVariants(t *testing.T) {
+	pkglogger.Init()
+	ctx := context.Background()
+	store, err := storage.NewLocalStorage(storage.LocalConfig{Root: t.TempDir(), SigningSecret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := NewImageVariantPool(store, 2, 8)
+	pool.Start()
+	defer pool.Stop()
+	media := NewMediaService(store)
+	media.SetVariantPool(pool)
+
+	wide := testPNG(t, 1200, 600)
+	if _, err := store.Upload(ctx, "images/wide.png", bytes.NewReader(wide), "image/png", int64(len(wide))); err != nil {
+		t.Fatal(err)
+	}
+	narrow := testPNG(t, 300, 100)
+	if _, err := store.Upload(ctx, "images/narrow.png", bytes.NewReader(narrow), "image/png", int64(len(narrow))); err != nil {
+		t.Fatal(err)
+	}
+
+	// 기존 키의 변형은 요청 시 생성
+	url, err := media.VariantURL(ctx, "images/wide.png", "thumb")
+	if err != nil {
+		t.Fatalf("variant url: %v", err)
+	}
+	if !strings.Contains(url, "variants/thumb/images/wide.png") {
+		t.Errorf("unexpected variant url %s", url)
+	}
+	for name, want := range map[string]image.Point{"thumb": {200, 100}, "small": {480, 240}, "medium": {1024, 512}} {
+		body, err := store.Download(ctx, VariantKey(name, "images/wide.png"))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		cfg, _, err := image.DecodeConfig(body)
+		_ = body.Close() //nolint:errcheck // read-only file
+		if err != nil || cfg.Width != want.X || cfg.Height != want.Y {
+			t.Errorf("%s: expected %v, got %dx%d (%v)", name, want, cfg.Width, cfg.Height, err)
+		}
+	}
+
+	if err := pool.Ensure(ctx, "images/narrow.png"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := store.Exists(ctx, VariantKey("medium", "images/narrow.png")); !ok {
+		t.Error("expected images narrower than a variant to be stored as-is")
+	}
+
+	if _, err := media.VariantURL(ctx, "images/wide.png", "huge"); !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("expected ErrUnknownVariant, got %v", err)
+	}
+	// 인증 없는 라우트: original과 images/ 밖의 객체는 서명 URL을 만들지 않음
+	for _, tc := range []struct{ key, name string }{
+		{"images/wide.png", VariantOriginal},
+		{"attachments/2026/01/02/report.pdf", "thumb"},
+		{"plugins/packages/shop-1.0.0.zip", "thumb"},
+		{"images/../attachments/secret.png", "thumb"},
+	} {
+		if url, err := media.VariantURL(ctx, tc.key, tc.name); err == nil {
+			t.Errorf("expected %s/%s to be rejected, got %s", tc.key, tc.name, url)
+		}
+	}
+	if _, err := media.VariantURL(ctx, "images/missing.png", "thumb"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if url, err := media.VariantURL(ctx, "images/anim.gif", "thumb"); err != nil || !strings.Contains(url, "images/anim.gif") {
+		t.Errorf("expected GIFs to fall back to the original, got %s %v", url, err)
+	}
+
+	if err := media.DeleteFile(ctx, "images/wide.png", "", true); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := store.Exists(ctx, VariantKey("thumb", "images/wide.png")); ok {
+		t.Error("expected variants to be deleted with the original")
+	}
+}
+
+func TestVariantSizeAndColor(t *testing.T) {
+	if w, h := variantSize(1000, 500, 200); w != 200 || h != 100 {
+		t.Errorf("unexpected size %dx%d", w, h)
+	}
+	if w, h := variantSize(100, 50, 200); w != 100 || h != 50 {
+		t.Errorf("expected narrow images to keep their size, got %dx%d", w, h)
+	}
+	img, _, err := image.Decode(bytes.NewReader(testPNG(t, 10, 10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := dominantColor(img); c != "#c86432" {
+		t.Errorf("unexpected dominant color %s", c)
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/damoang/angple-backend/internal/repository"
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"github.com/damoang/angple-backend/pkg/storage"
+)
+
+const (
+	// DefaultMediaGCGrace 참조가 없어진 객체를 지우기 전 유예 기간 (작성 중인 글, 복구 대비)
+	DefaultMediaGCGrace = 24 * time.Hour
+
+	mediaGCInterval = time.Hour
+	mediaGCBatch    = 100
+)
+
+// 미디어 라이브러리 에러
+var (
+	ErrMediaInUse    = errors.New("media is still referenced by a post")
+	ErrMediaNotOwner = errors.New("media belongs to another member")
+)
+
+// mediaKeyPattern 본문 URL에서 업로드 키(GenerateKey 형식) 추출
+// 변형 URL(variants/<name>/<key>)과 URL 인코딩된 파일명도 원본 키로 인식한다.
+var mediaKeyPattern = regexp.MustCompile(`(?:images|attachments|videos)/\d{4}/\d{2}/\d{2}/[^\s"'<>?#()\\]+`)
+
+// MediaLibrary deduplicates uploads by content hash, tracks which posts reference
+// each object and garbage-collects objects left unreferenced past the grace period.
+type MediaLibrary struct {
+	repo   *repository.MediaRepository
+	store  storage.Backend
+	grace  time.Duration
+	usage  *UsageMeter
+	quota  *QuotaService
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewMediaLibrary creates a new MediaLibrary
+func NewMediaLibrary(repo *repository.MediaRepository, store storage.Backend, grace time.Duration) *MediaLibrary {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MediaLibrary{repo: repo, store: store, grace: grace, ctx: ctx, cancel: cancel}
+}
+
+// SetUsageMeter sets the tenant usage meter (삭제·GC된 객체 용량 차감)
+func (l *MediaLibrary) SetUsageMeter(m *UsageMeter) {
+	l.usage = m
+}
+
+// SetQuotaService sets the tenant quota service (삭제·GC된 객체의 저장 용량 반환)
+func (l *MediaLibrary) SetQuotaService(q *QuotaService) {
+	l.quota = q
+}
+
+// Lookup 같은 사이트에 같은 내용의 객체가 있으면 반환하고 GC 유예를 다시 시작 (없으면 nil)
+func (l *MediaLibrary) Lookup(ctx context.Context, hash string) (*domain.MediaObject, error) {
+	obj, err := l.repo.FindByHash(ctx, repository.SiteIDFromContext(ctx), hash)
+	if err != nil || obj == nil {
+		return nil, err
+	}
+	alive, err := l.repo.Touch(ctx, obj.ID)
+	if err != nil || !alive {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Register 새로 업로드한 객체 등록 (게시글이 참조하기 전까지는 미참조 상태)
+// 동시 업로드로 같은 내용이 먼저 등록되었으면 그 객체와 false를 반환하므로 호출자가 새 객체를 지운다.
+func (l *MediaLibrary) Register(ctx context.Context, obj *domain.MediaObject) (*domain.MediaObject, bool, error) {
+	now := time.Now()
+	obj.SiteID = repository.SiteIDFromContext(ctx)
+	obj.UnreferencedAt = &now
+	err := l.repo.Create(ctx, obj)
+	if err == nil {
+		return obj, true, nil
+	}
+	if existing, findErr := l.repo.FindByHash(ctx, obj.SiteID, obj.Hash); findErr == nil && existing != nil {
+		return existing, false, nil
+	}
+	return nil, false, err
+}
+
+// SyncPost 게시글 본문이 참조하는 객체로 참조 목록 교체 (nil receiver 허용)
+func (l *MediaLibrary) SyncPost(ctx context.Context, boardID, postID interface{}, content string) {
+	if l == nil {
+		return
+	}
+	ids, err := l.repo.FindIDsByKeys(ctx, l.contentKeys(content))
+	if err == nil {
+		err = l.repo.ReplaceReferences(ctx, domain.MediaRefPost, postRef(boardID, postID), ids)
+	}
+	if err != nil {
+		pkglogger.Error("[MediaLibrary] sync references of post %s failed: %v", postRef(boardID, postID), err)
+	}
+}
+
+// RemovePost 영구 삭제된 게시글의 참조 제거 (nil receiver 허용)
+func (l *MediaLibrary) RemovePost(ctx context.Context, boardID, postID interface{}) {
+	if l == nil {
+		return
+	}
+	if err := l.repo.ReplaceReferences(ctx, domain.MediaRefPost, postRef(boardID, postID), nil); err != nil {
+		pkglogger.Error("[MediaLibrary] remove references of post %s failed: %v", postRef(boardID, postID), err)
+	}
+}
+
+// RegisterHooks 게시글 작성/수정/삭제 이벤트로 참조 갱신 (모든 작성 경로가 post.after_* 를 발생시킴)
+// soft delete된 게시글은 복구될 수 있으므로 참조를 유지하고, 영구 삭제(permanent)일 때만 제거한다.
+func (l *MediaLibrary) RegisterHooks(hm *plugin.HookManager) {
+	if l == nil || hm == nil {
+		return
+	}
+	syncRefs := func(hc *plugin.HookContext) error {
+		content, _ := hc.Input["content"].(string) //nolint:errcheck // type assertion, not error
+		l.SyncPost(l.ctx, hc.Input["board_id"], hc.Input["post_id"], content)
+		return nil
+	}
+	hm.Register(plugin.HookPostAfterCreate, "core:media", syncRefs, 100)
+	hm.Register(plugin.HookPostAfterUpdate, "core:media", syncRefs, 100)
+	hm.Register(plugin.HookPostAfterDelete, "core:media", func(hc *plugin.HookContext) error {
+		if permanent, _ := hc.Input["permanent"].(bool); permanent { //nolint:errcheck // type assertion, not error
+			l.RemovePost(l.ctx, hc.Input["board_id"], hc.Input["post_id"])
+		}
+		return nil
+	}, 100)
+}
+
+// Release 회원의 삭제 요청 검증 후 객체 기록 삭제
+// 게시글이 참조 중이거나 다른 회원의 객체면 거부한다. 관리자는 소유자 검사를 건너뛴다.
+// 기록이 없는 키(라이브러리 도입 전 업로드)는 소유자를 알 수 없으므로 관리자만 삭제할 수 있다.
+func (l *MediaLibrary) Release(ctx context.Context, key, memberID string, admin bool) error {
+	obj, err := l.repo.FindByKey(ctx, key)
+	if err != nil {
+		return err
+	}
+	if obj == nil {
+		if admin {
+			return nil
+		}
+		return ErrMediaNotOwner
+	}
+	if !admin && obj.OwnerID != memberID {
+		return ErrMediaNotOwner
+	}
+	refs, err := l.repo.CountReferences(ctx, obj.ID)
+	if err != nil {
+		return err
+	}
+	if refs > 0 {
+		return fmt.Errorf("%w (%d references)", ErrMediaInUse, refs)
+	}
+	if err := l.repo.Delete(ctx, obj.ID); err != nil {
+		return err
+	}
+	l.released(ctx, obj)
+	return nil
+}
+
+// released 기록이 삭제된 객체의 용량을 사이트 사용량과 저장 용량 한도에서 반환
+// GC는 요청 컨텍스트가 없으므로 객체의 사이트로 한도 카운터를 찾는다.
+func (l *MediaLibrary) released(ctx context.Context, obj *domain.MediaObject) {
+	l.usage.RemoveStorage(ctx, obj.SiteID, obj.Size)
+	l.quota.Release(repository.ContextWithSiteID(ctx, obj.SiteID), common.QuotaStorage, obj.Size)
+}
+
+// Usage 회원의 저장 용량 (중복 제거 후, 최초 업로드 회원 기준)
+func (l *MediaLibrary) Usage(ctx context.Context, memberID string) (*domain.MediaUsage, error) {
+	return l.repo.UsageByOwner(ctx, memberID)
+}
+
+// TopUsage 저장 용량이 큰 회원 목록
+func (l *MediaLibrary) TopUsage(ctx context.Context, limit int) ([]domain.MediaUsage, error) {
+	return l.repo.TopUsage(ctx, limit)
+}
+
+// CollectGarbage 유예 기간이 지난 미참조 객체와 변형을 저장소에서 삭제
+func (l *MediaLibrary) CollectGarbage(ctx context.Context) (int, error) {
+	before := time.Now().Add(-l.grace)
+	objs, err := l.repo.FindUnreferenced(ctx, before, mediaGCBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for i := range objs {
+		obj := &objs[i]
+		// 행을 먼저 조건부 삭제해 그 사이 참조/재업로드된 객체는 남긴다
+		deleted, err := l.repo.DeleteUnreferenced(ctx, obj.ID, before)
+		if err != nil {
+			return removed, err
+		}
+		if !deleted {
+			continue
+		}
+		l.released(ctx, obj)
+		if err := deleteMediaObject(ctx, l.store, obj.Key); err != nil {
+			pkglogger.Error("[MediaLibrary] delete %s failed: %v", obj.Key, err)
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Start 주기적으로 GC 실행
+func (l *MediaLibrary) Start() {
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		ticker := time.NewTicker(mediaGCInterval)
+		defer ticker.Stop()
+		pkglogger.Info("[MediaLibrary] Started (grace %s)", l.grace)
+		for {
+			select {
+			case <-l.ctx.Done():
+				pkglogger.Info("[MediaLibrary] Stopped")
+				return
+			case <-ticker.C:
+				if n, err := l.CollectGarbage(l.ctx); err != nil {
+					pkglogger.Error("[MediaLibrary] GC failed: %v", err)
+				} else if n > 0 {
+					pkglogger.Info("[MediaLibrary] GC removed %d objects", n)
+				}
+			}
+		}
+	}()
+}
+
+// Stop GC 정지
+func (l *MediaLibrary) Stop() {
+	l.cancel()
+	l.wg.Wait()
+}
+
+// contentKeys 본문에 포함된 업로드 객체의 full key 목록
+func (l *MediaLibrary) contentKeys(content string) []string {
+	matches := mediaKeyPattern.FindAllString(content, -1)
+	seen := make(map[string]bool, len(matches))
+	keys := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if unescaped, err := url.PathUnescape(m); err == nil {
+			m = unescaped
+		}
+		key := l.store.FullKey(m)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func postRef(boardID, postID interface{}) string {
+	return fmt.Sprintf("%v/%v", boardID, postID)
+}
+
+// hashContent SHA-256 계산 후 읽기 위치를 처음으로 되돌림
+func hashContent(body io.ReadSeeker) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, body); err != nil {
+		return "", err
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/damoang/angple-backend/internal/repository"
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"github.com/damoang/angple-backend/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMediaLibrary(t *testing.T) {
+	pkglogger.Init()
+	ctx := context.Background()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	repo := repository.NewMediaRepository(db)
+	require.NoError(t, repo.AutoMigrate())
+
+	store, err := storage.NewLocalStorage(storage.LocalConfig{Root: t.TempDir(), SigningSecret: "secret"})
+	require.NoError(t, err)
+	// 유예 기간 0 — 참조가 없어지면 바로 GC 대상
+	library := NewMediaLibrary(repo, store, 0)
+	media := NewMediaService(store)
+	media.SetMediaLibrary(library)
+
+	data := []byte("same bytes")
+	first, err := media.upload(ctx, "alice", "attachments/2026/10/17/a.txt", bytes.NewReader(data), "text/plain", int64(len(data)))
+	require.NoError(t, err)
+	second, err := media.upload(ctx, "bob", "attachments/2026/10/17/b.txt", bytes.NewReader(data), "text/plain", int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, first.Key, second.Key, "identical uploads should share one object")
+	ok, err := store.Exists(ctx, "attachments/2026/10/17/b.txt")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	usage, err := media.Usage(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), usage.Objects)
+	assert.Equal(t, int64(len(data)), usage.Bytes)
+
+	// 게시글이 참조하는 동안은 삭제/GC 불가
+	library.SyncPost(ctx, "free", 1, `<a href="https://cdn.example.com/`+first.Key+`?v=1">file</a>`)
+	err = media.DeleteFile(ctx, first.Key, "alice", false)
+	assert.True(t, errors.Is(err, ErrMediaInUse), "expected ErrMediaInUse, got %v", err)
+	err = media.DeleteFile(ctx, first.Key, "bob", false)
+	assert.True(t, errors.Is(err, ErrMediaNotOwner), "expected ErrMediaNotOwner, got %v", err)
+	removed, err := library.CollectGarbage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+
+	// soft delete는 복구될 수 있으므로 참조 유지, 영구 삭제에서만 GC 대상
+	hm := plugin.NewHookManager(plugin.NewDefaultLogger("test"))
+	library.RegisterHooks(hm)
+	hm.Do(plugin.HookPostAfterDelete, map[string]interface{}{"board_id": "free", "post_id": 1})
+	removed, err = library.CollectGarbage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed, "soft-deleted posts should keep their media")
+	hm.Do(plugin.HookPostAfterDelete, map[string]interface{}{"board_id": "free", "post_id": 1, "permanent": true})
+	removed, err = library.CollectGarbage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	ok, err = store.Exists(ctx, first.Key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// 삭제된 객체와 같은 내용은 다시 업로드된다
+	third, err := media.upload(ctx, "bob", "attachments/2026/10/17/c.txt", bytes.NewReader(data), "text/plain", int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "attachments/2026/10/17/c.txt", third.Key)
+	require.NoError(t, media.DeleteFile(ctx, third.Key, "bob", false))
+
+	// 기록이 없는 기존 업로드는 소유자를 확인할 수 없으므로 관리자만 삭제
+	legacy := "attachments/2025/01/01/legacy.txt"
+	_, err = store.Upload(ctx, legacy, bytes.NewReader(data), "text/plain", int64(len(data)))
+	require.NoError(t, err)
+	err = media.DeleteFile(ctx, legacy, "bob", false)
+	assert.True(t, errors.Is(err, ErrMediaNotOwner), "expected ErrMediaNotOwner, got %v", err)
+	ok, err = store.Exists(ctx, legacy)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	require.NoError(t, media.DeleteFile(ctx, legacy, "", true))
+	ok, err = store.Exists(ctx, legacy)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -11,24 +11,31 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"os"
 	"path"
 	"strings"
 
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/domain"
 	pkglogger "github.com/damoang/angple-backend/pkg/logger"
 	"github.com/damoang/angple-backend/pkg/storage"
 )
 
-// MediaService handles file uploads with image processing and S3 storage
+// MediaService handles file uploads with image processing and S3/local storage
 type MediaService struct {
-	s3        *storage.S3Client
+	store     storage.Backend
 	maxSize   int64    // max file size in bytes
 	allowExts []string // allowed file extensions
+	quota     *QuotaService
+	usage     *UsageMeter
+	variants  *ImageVariantPool
+	library   *MediaLibrary
 }
 
 // NewMediaService creates a new MediaService
-func NewMediaService(s3Client *storage.S3Client) *MediaService {
+func NewMediaService(store storage.Backend) *MediaService {
 	return &MediaService{
-		s3:      s3Client,
+		store:   store,
 		maxSize: 50 * 1024 * 1024, // 50MB
 		allowExts: []string{
 			".jpg", ".jpeg", ".png", ".gif", ".webp", ".svg",
@@ -40,6 +47,26 @@ func NewMediaService(s3Client *storage.S3Client) *MediaService {
 	}
 }
 
+// SetQuotaService sets the tenant plan quota service (파일 크기/저장 용량 한도)
+func (s *MediaService) SetQuotaService(q *QuotaService) {
+	s.quota = q
+}
+
+// SetUsageMeter sets the tenant usage meter (업로드 용량 집계)
+func (s *MediaService) SetUsageMeter(m *UsageMeter) {
+	s.usage = m
+}
+
+// SetVariantPool sets the image variant worker pool (thumb/small/medium 변형 생성)
+func (s *MediaService) SetVariantPool(p *ImageVariantPool) {
+	s.variants = p
+}
+
+// SetMediaLibrary sets the media library (내용 해시 중복 제거, 게시글 참조 추적)
+func (s *MediaService) SetMediaLibrary(l *MediaLibrary) {
+	s.library = l
+}
+
 // UploadResult represents the result of an upload operation
 type MediaUploadResult struct {
 	Key         string `json:"key"`
@@ -50,14 +77,21 @@ type MediaUploadResult struct {
 	Size        int64  `json:"size"`
 	Width       int    `json:"width,omitempty"`
 	Height      int    `json:"height,omitempty"`
+	// 이미지 변형 (original/thumb/small/medium) — 원본 외 변형은 백그라운드에서 생성됨
+	Variants      map[string]MediaVariant `json:"variants,omitempty"`
+	DominantColor string                  `json:"dominant_color,omitempty"`
 }
 
 // UploadImage uploads an image, optionally converting to JPEG and resizing
-func (s *MediaService) UploadImage(ctx context.Context, file *multipart.FileHeader, maxWidth int) (*MediaUploadResult, error) {
+func (s *MediaService) UploadImage(ctx context.Context, ownerID string, file *multipart.FileHeader, maxWidth int) (*MediaUploadResult, error) {
 	if file.Size > s.maxSize {
 		return nil, fmt.Errorf("file too large (max %dMB)", s.maxSize/(1024*1024))
 	}
 
+	if err := s.quota.CheckFileSize(ctx, file.Size); err != nil {
+		return nil, err
+	}
+
 	ext := strings.ToLower(path.Ext(file.Filename))
 	if !isImageExt(ext) {
 		return nil, fmt.Errorf("unsupported image format: %s", ext)
@@ -76,9 +110,10 @@ func (s *MediaService) UploadImage(ctx context.Context, file *multipart.FileHead
 	}
 
 	contentType := http.DetectContentType(data)
-	var reader io.Reader = bytes.NewReader(data)
+	var reader io.ReadSeeker = bytes.NewReader(data)
 	size := int64(len(data))
 	var width, height int
+	var color string
 
 	// Try to decode and resize if it's a raster image (not SVG/GIF)
 	if ext != ".svg" && ext != ".gif" {
@@ -95,13 +130,14 @@ func (s *MediaService) UploadImage(ctx context.Context, file *multipart.FileHead
 				width = bounds.Dx()
 				height = bounds.Dy()
 			}
+			color = dominantColor(img)
 
 			// Re-encode
 			var buf bytes.Buffer
 			switch format {
 			case "png":
 				if err := png.Encode(&buf, img); err == nil {
-					reader = &buf
+					reader = bytes.NewReader(buf.Bytes())
 					size = int64(buf.Len())
 					contentType = "image/png"
 					ext = ".png"
@@ -109,7 +145,7 @@ func (s *MediaService) UploadImage(ctx context.Context, file *multipart.FileHead
 			default:
 				// Encode as JPEG for everything else
 				if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err == nil {
-					reader = &buf
+					reader = bytes.NewReader(buf.Bytes())
 					size = int64(buf.Len())
 					contentType = "image/jpeg"
 					ext = ".jpg"
@@ -120,7 +156,7 @@ func (s *MediaService) UploadImage(ctx context.Context, file *multipart.FileHead
 
 	key := storage.GenerateKey("images", sanitizeFilename(file.Filename, ext))
 
-	result, err := s.s3.Upload(ctx, key, reader, contentType, size)
+	result, err := s.upload(ctx, ownerID, key, reader, contentType, size)
 	if err != nil {
 		return nil, err
 	}
@@ -131,23 +167,80 @@ func (s *MediaService) UploadImage(ctx context.Context, file *multipart.FileHead
 		Msg("image uploaded")
 
 	return &MediaUploadResult{
-		Key:         result.Key,
-		URL:         result.URL,
-		CDNURL:      result.CDNURL,
-		Filename:    file.Filename,
-		ContentType: contentType,
-		Size:        size,
-		Width:       width,
-		Height:      height,
+		Key:           result.Key,
+		URL:           result.URL,
+		CDNURL:        result.CDNURL,
+		Filename:      file.Filename,
+		ContentType:   contentType,
+		Size:          size,
+		Width:         width,
+		Height:        height,
+		Variants:      s.scheduleVariants(result.Key, width, height),
+		DominantColor: color,
 	}, nil
 }
 
+// scheduleVariants queues variant generation and returns the variant URLs (크기는 원본에서 계산)
+func (s *MediaService) scheduleVariants(key string, width, height int) map[string]MediaVariant {
+	if s.variants == nil || width == 0 || !supportsVariants(key) {
+		return nil
+	}
+	s.variants.Enqueue(key)
+
+	variants := map[string]MediaVariant{
+		VariantOriginal: {Key: key, URL: s.store.GetCDNURL(key), Width: width, Height: height},
+	}
+	for _, v := range ImageVariants {
+		vKey := s.store.FullKey(VariantKey(v.Name, key))
+		w, h := variantSize(width, height, v.Width)
+		variants[v.Name] = MediaVariant{Key: vKey, URL: s.store.GetCDNURL(vKey), Width: w, Height: h}
+	}
+	return variants
+}
+
+// VariantURL returns the URL of an image variant, generating it first if it does not exist yet.
+// 인증 없는 라우트에서 쓰이므로 업로드 이미지(images/) 키만 받고 original은 거부한다
+// (첨부/플러그인 패키지 등 임의 객체의 서명 URL을 만들어 주지 않도록).
+// GIF/SVG 등 변형을 만들지 않는 이미지와 워커 풀이 없는 경우에는 원본 이미지 URL을 반환한다.
+func (s *MediaService) VariantURL(ctx context.Context, key, name string) (string, error) {
+	if _, ok := findVariant(name); !ok {
+		return "", ErrUnknownVariant
+	}
+	if !s.isImageKey(key) {
+		return "", ErrInvalidVariantKey
+	}
+	if s.variants == nil || !supportsVariants(key) {
+		return s.store.GetCDNURL(key), nil
+	}
+
+	vKey := s.store.FullKey(VariantKey(name, key))
+	exists, err := s.store.Exists(ctx, vKey)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		if err := s.variants.Ensure(ctx, key); err != nil {
+			return "", err
+		}
+	}
+	return s.store.GetCDNURL(vKey), nil
+}
+
+// isImageKey reports whether key is an uploaded image (UploadImage가 만든 images/ 아래 원본, 변형 키 제외)
+func (s *MediaService) isImageKey(key string) bool {
+	return strings.HasPrefix(key, s.store.FullKey("images/")) && path.Clean(key) == key && isImageExt(strings.ToLower(path.Ext(key)))
+}
+
 // UploadAttachment uploads a general file attachment
-func (s *MediaService) UploadAttachment(ctx context.Context, file *multipart.FileHeader) (*MediaUploadResult, error) {
+func (s *MediaService) UploadAttachment(ctx context.Context, ownerID string, file *multipart.FileHeader) (*MediaUploadResult, error) {
 	if file.Size > s.maxSize {
 		return nil, fmt.Errorf("file too large (max %dMB)", s.maxSize/(1024*1024))
 	}
 
+	if err := s.quota.CheckFileSize(ctx, file.Size); err != nil {
+		return nil, err
+	}
+
 	ext := strings.ToLower(path.Ext(file.Filename))
 	if !s.isAllowedExt(ext) {
 		return nil, fmt.Errorf("file type not allowed: %s", ext)
@@ -179,7 +272,7 @@ func (s *MediaService) UploadAttachment(ctx context.Context, file *multipart.Fil
 
 	key := storage.GenerateKey("attachments", sanitizeFilename(file.Filename, ext))
 
-	result, err := s.s3.Upload(ctx, key, src, contentType, file.Size)
+	result, err := s.upload(ctx, ownerID, key, src, contentType, file.Size)
 	if err != nil {
 		return nil, err
 	}
@@ -201,12 +294,16 @@ func (s *MediaService) UploadAttachment(ctx context.Context, file *multipart.Fil
 }
 
 // UploadVideo uploads a video file
-func (s *MediaService) UploadVideo(ctx context.Context, file *multipart.FileHeader) (*MediaUploadResult, error) {
+func (s *MediaService) UploadVideo(ctx context.Context, ownerID string, file *multipart.FileHeader) (*MediaUploadResult, error) {
 	maxVideoSize := int64(500 * 1024 * 1024) // 500MB for video
 	if file.Size > maxVideoSize {
 		return nil, fmt.Errorf("video too large (max %dMB)", maxVideoSize/(1024*1024))
 	}
 
+	if err := s.quota.CheckFileSize(ctx, file.Size); err != nil {
+		return nil, err
+	}
+
 	ext := strings.ToLower(path.Ext(file.Filename))
 	if !isVideoExt(ext) {
 		return nil, fmt.Errorf("unsupported video format: %s", ext)
@@ -225,7 +322,7 @@ func (s *MediaService) UploadVideo(ctx context.Context, file *multipart.FileHead
 
 	key := storage.GenerateKey("videos", sanitizeFilename(file.Filename, ext))
 
-	result, err := s.s3.Upload(ctx, key, src, contentType, file.Size)
+	result, err := s.upload(ctx, ownerID, key, src, contentType, file.Size)
 	if err != nil {
 		return nil, err
 	}
@@ -245,14 +342,117 @@ func (s *MediaService) UploadVideo(ctx context.Context, file *multipart.FileHead
 	}, nil
 }
 
-// DeleteFile removes a file from storage
-func (s *MediaService) DeleteFile(ctx context.Context, key string) error {
-	return s.s3.Delete(ctx, key)
+// upload stores the object after reserving tenant storage quota (실패 시 반환)
+// 미디어 라이브러리가 있으면 같은 내용의 기존 객체를 재사용하고 새 객체를 소유 회원과 함께 등록한다.
+func (s *MediaService) upload(ctx context.Context, ownerID, key string, body io.ReadSeeker, contentType string, size int64) (*storage.UploadResult, error) {
+	var hash string
+	if s.library != nil {
+		var err error
+		if hash, err = hashContent(body); err != nil {
+			return nil, err
+		}
+		existing, err := s.library.Lookup(ctx, hash)
+		if err != nil {
+			pkglogger.Error("[Media] dedup lookup failed: %v", err)
+		} else if existing != nil {
+			return s.existingResult(existing), nil
+		}
+	}
+
+	if err := s.quota.Reserve(ctx, common.QuotaStorage, size); err != nil {
+		return nil, err
+	}
+	result, err := s.store.Upload(ctx, key, body, contentType, size)
+	if err != nil {
+		s.quota.Release(ctx, common.QuotaStorage, size)
+		return nil, err
+	}
+
+	if s.library != nil {
+		obj, created, regErr := s.library.Register(ctx, &domain.MediaObject{
+			Hash: hash, Key: result.Key, OwnerID: ownerID, ContentType: contentType, Size: size,
+		})
+		switch {
+		case regErr != nil:
+			pkglogger.Error("[Media] register %s failed: %v", result.Key, regErr)
+		case !created:
+			// 동시에 같은 내용이 먼저 등록됨 — 방금 올린 객체 대신 기존 객체 사용
+			if err := s.store.Delete(ctx, result.Key); err != nil {
+				pkglogger.Error("[Media] delete duplicate %s failed: %v", result.Key, err)
+			}
+			s.quota.Release(ctx, common.QuotaStorage, size)
+			return s.existingResult(obj), nil
+		}
+	}
+	s.usage.AddStorage(ctx, size)
+	return result, nil
+}
+
+func (s *MediaService) existingResult(obj *domain.MediaObject) *storage.UploadResult {
+	url := s.store.GetCDNURL(obj.Key)
+	return &storage.UploadResult{Key: obj.Key, URL: url, CDNURL: url, ContentType: obj.ContentType, Size: obj.Size}
+}
+
+// DeleteFile removes a file and its image variants from storage.
+// 미디어 라이브러리가 있으면 게시글이 참조 중인 객체(ErrMediaInUse)와 다른 회원의 객체(ErrMediaNotOwner)는 지우지 않는다.
+func (s *MediaService) DeleteFile(ctx context.Context, key, memberID string, admin bool) error {
+	if s.library != nil {
+		if err := s.library.Release(ctx, key, memberID, admin); err != nil {
+			return err
+		}
+	}
+	return deleteMediaObject(ctx, s.store, key)
+}
+
+// deleteMediaObject removes an object and its image variants
+func deleteMediaObject(ctx context.Context, store storage.Backend, key string) error {
+	if err := store.Delete(ctx, key); err != nil {
+		return err
+	}
+	if supportsVariants(key) {
+		for _, v := range ImageVariants {
+			if err := store.Delete(ctx, store.FullKey(VariantKey(v.Name, key))); err != nil {
+				pkglogger.Error("failed to delete %s variant of %s: %v", v.Name, key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ErrMediaLibraryDisabled is returned for usage queries when media tracking is not configured
+var ErrMediaLibraryDisabled = errors.New("media library is not enabled")
+
+// Usage returns a member's deduplicated storage total
+func (s *MediaService) Usage(ctx context.Context, memberID string) (*domain.MediaUsage, error) {
+	if s.library == nil {
+		return nil, ErrMediaLibraryDisabled
+	}
+	return s.library.Usage(ctx, memberID)
+}
+
+// TopUsage lists the members using the most storage
+func (s *MediaService) TopUsage(ctx context.Context, limit int) ([]domain.MediaUsage, error) {
+	if s.library == nil {
+		return nil, ErrMediaLibraryDisabled
+	}
+	return s.library.TopUsage(ctx, limit)
 }
 
 // GetCDNURL returns the CDN URL for a storage key
 func (s *MediaService) GetCDNURL(key string) string {
-	return s.s3.GetCDNURL(key)
+	return s.store.GetCDNURL(key)
+}
+
+// ErrSignedDownloadUnsupported is returned when the storage backend serves files itself (S3/CDN)
+var ErrSignedDownloadUnsupported = errors.New("signed downloads are only served for local storage")
+
+// OpenSignedFile verifies a local-storage download signature and opens the file
+func (s *MediaService) OpenSignedFile(key, expires, signature string) (*os.File, error) {
+	local, ok := s.store.(*storage.LocalStorage)
+	if !ok {
+		return nil, ErrSignedDownloadUnsupported
+	}
+	return local.OpenSigned(key, expires, signature)
 }
 
 func (s *MediaService) isAllowedExt(ext string) bool {
@@ -27,14 +27,14 @@ const (
 
 // MemberService handles member profile image operations
 type MemberService struct {
-	s3         *storage.S3Client
+	store      storage.Backend
 	memberRepo gnurepo.MemberRepository
 }
 
 // NewMemberService creates a new MemberService
-func NewMemberService(s3Client *storage.S3Client, memberRepo gnurepo.MemberRepository) *MemberService {
+func NewMemberService(store storage.Backend, memberRepo gnurepo.MemberRepository) *MemberService {
 	return &MemberService{
-		s3:         s3Client,
+		store:      store,
 		memberRepo: memberRepo,
 	}
 }
@@ -110,10 +110,10 @@ func (s *MemberService) UpdateMemberImage(ctx context.Context, mbID string, file
 		return "", fmt.Errorf("회원 조회 실패: %w", err)
 	}
 	if member.MbImageUrl != "" {
-		_ = s.s3.Delete(ctx, member.MbImageUrl)
+		_ = s.store.Delete(ctx, member.MbImageUrl)
 	}
 
-	// S3 업로드
+	// 저장소 업로드
 	prefix := mbID[:2]
 	if len(mbID) < 2 {
 		prefix = mbID
@@ -121,9 +121,9 @@ func (s *MemberService) UpdateMemberImage(ctx context.Context, mbID string, file
 	key := fmt.Sprintf("data/member_image/%s/%s_%d%s",
 		strings.ToLower(prefix), mbID, time.Now().Unix(), ext)
 
-	result, err := s.s3.Upload(ctx, key, reader, contentType, size)
+	result, err := s.store.Upload(ctx, key, reader, contentType, size)
 	if err != nil {
-		return "", fmt.Errorf("S3 업로드 실패: %w", err)
+		return "", fmt.Errorf("저장소 업로드 실패: %w", err)
 	}
 
 	// DB 업데이트
@@ -153,12 +153,12 @@ func (s *MemberService) DeleteMemberImage(ctx context.Context, mbID string) erro
 	}
 
 	if member.MbImageUrl != "" {
-		if delErr := s.s3.Delete(ctx, member.MbImageUrl); delErr != nil {
+		if delErr := s.store.Delete(ctx, member.MbImageUrl); delErr != nil {
 			pkglogger.GetLogger().Warn().
 				Str("mb_id", mbID).
 				Str("key", member.MbImageUrl).
 				Err(delErr).
-				Msg("failed to delete profile image from S3")
+				Msg("failed to delete profile image from storage")
 		}
 	}
 
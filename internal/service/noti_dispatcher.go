@@ -0,0 +1,137 @@
+package service
+
+import (
+	"log"
+
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	"github.com/damoang/angple-backend/internal/ws"
+)
+
+// NotiDispatcher wraps NotiRepository: 알림 설정(NotiPreference)을 확인한 뒤 저장하고,
+// WebSocket Hub로 "notification" / "unread_count" 이벤트를 실시간 전송한다.
+// NotiRepository를 그대로 구현하므로 기존 notiRepo 자리에 주입하면 된다.
+type NotiDispatcher struct {
+	gnurepo.NotiRepository
+	prefRepo gnurepo.NotiPreferenceRepository
+	hub      *ws.Hub
+	payload  func(gnurepo.Notification) interface{}
+}
+
+// NewNotiDispatcher creates a new NotiDispatcher (prefRepo, hub는 nil 허용)
+func NewNotiDispatcher(repo gnurepo.NotiRepository, prefRepo gnurepo.NotiPreferenceRepository, hub *ws.Hub) *NotiDispatcher {
+	return &NotiDispatcher{NotiRepository: repo, prefRepo: prefRepo, hub: hub}
+}
+
+// SetPayloadFormatter sets how a notification is serialized in the "notification" event
+// (API 응답과 같은 형태를 보내도록 핸들러가 주입, 설정하지 않으면 저장된 알림 그대로 전송)
+func (d *NotiDispatcher) SetPayloadFormatter(format func(gnurepo.Notification) interface{}) {
+	d.payload = format
+}
+
+// Create 수신자가 해당 알림 유형을 끈 경우 저장하지 않고 nil 반환, 저장 후 실시간 전송
+func (d *NotiDispatcher) Create(noti *gnurepo.Notification) error {
+	if !d.allowed(noti) {
+		return nil
+	}
+	if err := d.NotiRepository.Create(noti); err != nil {
+		return err
+	}
+	var payload interface{} = noti
+	if d.payload != nil {
+		payload = d.payload(*noti)
+	}
+	d.push(noti.MbID, &ws.Event{Type: "notification", Payload: payload})
+	d.pushUnreadCount(noti.MbID)
+	return nil
+}
+
+// MarkAsRead 읽음 처리 후 다른 기기에 미읽음 수 동기화
+func (d *NotiDispatcher) MarkAsRead(mbID string, phID int) error {
+	if err := d.NotiRepository.MarkAsRead(mbID, phID); err != nil {
+		return err
+	}
+	d.pushUnreadCount(mbID)
+	return nil
+}
+
+// MarkAllAsRead 전체 읽음 처리 후 미읽음 수 동기화
+func (d *NotiDispatcher) MarkAllAsRead(mbID string) error {
+	if err := d.NotiRepository.MarkAllAsRead(mbID); err != nil {
+		return err
+	}
+	d.pushUnreadCount(mbID)
+	return nil
+}
+
+// MarkGroupAsRead 그룹 읽음 처리 후 미읽음 수 동기화
+func (d *NotiDispatcher) MarkGroupAsRead(mbID, boTable string, wrID int, fromCase string) error {
+	if err := d.NotiRepository.MarkGroupAsRead(mbID, boTable, wrID, fromCase); err != nil {
+		return err
+	}
+	d.pushUnreadCount(mbID)
+	return nil
+}
+
+// Delete 삭제 후 미읽음 수 동기화
+func (d *NotiDispatcher) Delete(mbID string, phID int) error {
+	if err := d.NotiRepository.Delete(mbID, phID); err != nil {
+		return err
+	}
+	d.pushUnreadCount(mbID)
+	return nil
+}
+
+// DeleteGroup 그룹 삭제 후 미읽음 수 동기화
+func (d *NotiDispatcher) DeleteGroup(mbID, boTable string, wrID int, fromCase string) error {
+	if err := d.NotiRepository.DeleteGroup(mbID, boTable, wrID, fromCase); err != nil {
+		return err
+	}
+	d.pushUnreadCount(mbID)
+	return nil
+}
+
+// allowed 수신자의 알림 설정 확인 (조회 실패 시 기본값: 허용)
+func (d *NotiDispatcher) allowed(noti *gnurepo.Notification) bool {
+	if d.prefRepo == nil {
+		return true
+	}
+	pref, err := d.prefRepo.Get(noti.MbID)
+	if err != nil || pref == nil {
+		return true
+	}
+	switch noti.PhToCase {
+	case "comment":
+		return pref.NotiComment
+	case "comment_reply", "reply":
+		return pref.NotiReply
+	case "mention":
+		return pref.NotiMention
+	case "follow", "subscribe":
+		return pref.NotiFollow
+	}
+	switch noti.PhFromCase {
+	case "good", "nogood":
+		return pref.NotiLike
+	}
+	// 쪽지, 시스템(me) 알림은 항상 전송
+	return true
+}
+
+func (d *NotiDispatcher) pushUnreadCount(mbID string) {
+	if d.hub == nil {
+		return
+	}
+	count, err := d.NotiRepository.CountUnread(mbID)
+	if err != nil {
+		log.Printf("[noti] unread count failed for %s: %v", mbID, err)
+		return
+	}
+	d.push(mbID, &ws.Event{Type: "unread_count", Payload: map[string]interface{}{"total_unread": count}})
+}
+
+func (d *NotiDispatcher) push(mbID string, event *ws.Event) {
+	if d.hub == nil || mbID == "" {
+		return
+	}
+	d.hub.SendToMember(mbID, event)
+}
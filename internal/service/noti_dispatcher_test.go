@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNotiRepo struct {
+	gnurepo.NotiRepository
+	created []*gnurepo.Notification
+}
+
+func (r *fakeNotiRepo) Create(noti *gnurepo.Notification) error {
+	r.created = append(r.created, noti)
+	return nil
+}
+
+type fakeNotiPrefRepo struct {
+	prefs map[string]*gnurepo.NotiPreference
+}
+
+func (r *fakeNotiPrefRepo) Get(mbID string) (*gnurepo.NotiPreference, error) {
+	if pref, ok := r.prefs[mbID]; ok {
+		return pref, nil
+	}
+	return &gnurepo.NotiPreference{MbID: mbID, NotiComment: true, NotiReply: true, NotiMention: true, NotiLike: true, NotiFollow: true}, nil
+}
+
+func (r *fakeNotiPrefRepo) Upsert(_ *gnurepo.NotiPreference) error { return nil }
+
+func TestNotiDispatcherHonoursPreference(t *testing.T) {
+	repo := &fakeNotiRepo{}
+	prefs := &fakeNotiPrefRepo{prefs: map[string]*gnurepo.NotiPreference{
+		"quiet": {MbID: "quiet", NotiComment: false, NotiReply: false, NotiFollow: false, NotiLike: false},
+	}}
+	d := NewNotiDispatcher(repo, prefs, nil)
+
+	cases := []struct {
+		toCase, fromCase string
+	}{
+		{"comment", "comment"},
+		{"comment_reply", "comment"},
+		{"follow", "write"},
+		{"subscribe", "write"},
+		{"", "good"},
+	}
+	for _, tc := range cases {
+		assert.NoError(t, d.Create(&gnurepo.Notification{MbID: "quiet", PhToCase: tc.toCase, PhFromCase: tc.fromCase}))
+		assert.NoError(t, d.Create(&gnurepo.Notification{MbID: "loud", PhToCase: tc.toCase, PhFromCase: tc.fromCase}))
+	}
+	assert.Len(t, repo.created, len(cases))
+	for _, n := range repo.created {
+		assert.Equal(t, "loud", n.MbID)
+	}
+
+	// 쪽지, 시스템 알림은 설정과 무관하게 저장
+	assert.NoError(t, d.Create(&gnurepo.Notification{MbID: "quiet", PhToCase: "memo", PhFromCase: "memo"}))
+	assert.NoError(t, d.Create(&gnurepo.Notification{MbID: "quiet", PhToCase: "me", PhFromCase: "promotion"}))
+	assert.Len(t, repo.created, len(cases)+2)
+}
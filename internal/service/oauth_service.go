@@ -10,10 +10,14 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/damoang/angple-backend/internal/common"
 	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/repository"
+	v2svc "github.com/damoang/angple-backend/internal/service/v2"
 	"github.com/damoang/angple-backend/pkg/jwt"
 	pkglogger "github.com/damoang/angple-backend/pkg/logger"
 	"gorm.io/gorm"
@@ -24,14 +28,23 @@ type OAuthService struct {
 	db         *gorm.DB
 	jwtManager *jwt.Manager
 	providers  map[domain.OAuthProvider]*domain.OAuthConfig
+	quota      *QuotaService
+	twoFactor  *v2svc.TwoFactorService
+	sessions   *v2svc.V2AuthService
 }
 
+// oauthMemberLevel is the member level of OAuth tokens
+const oauthMemberLevel = 1
+
 // NewOAuthService creates a new OAuthService
 func NewOAuthService(db *gorm.DB, jwtManager *jwt.Manager) *OAuthService {
 	if db != nil {
 		if err := db.AutoMigrate(&domain.OAuthAccount{}); err != nil {
 			pkglogger.GetLogger().Warn().Err(err).Msg("failed to auto-migrate OAuthAccount")
 		}
+		if err := db.AutoMigrate(&domain.APIKey{}); err != nil {
+			pkglogger.GetLogger().Warn().Err(err).Msg("failed to auto-migrate APIKey")
+		}
 	}
 	return &OAuthService{
 		db:         db,
@@ -40,6 +53,30 @@ func NewOAuthService(db *gorm.DB, jwtManager *jwt.Manager) *OAuthService {
 	}
 }
 
+// SetQuotaService sets the tenant plan quota service (회원 수 한도)
+func (s *OAuthService) SetQuotaService(q *QuotaService) {
+	s.quota = q
+}
+
+// SetTwoFactorService enables the TOTP second factor after the provider callback
+func (s *OAuthService) SetTwoFactorService(twoFactor *v2svc.TwoFactorService) {
+	s.twoFactor = twoFactor
+}
+
+// SetSessionService issues OAuth refresh tokens as server-side sessions (회전·재사용 탐지·로그아웃 적용)
+func (s *OAuthService) SetSessionService(sessions *v2svc.V2AuthService) {
+	s.sessions = sessions
+}
+
+// ExternalAccount returns the nickname and level of an OAuth member for token refresh
+func (s *OAuthService) ExternalAccount(subject string) (string, int, error) {
+	var account domain.OAuthAccount
+	if err := s.db.Where("user_id = ?", subject).First(&account).Error; err != nil {
+		return "", 0, err
+	}
+	return account.Name, oauthMemberLevel, nil
+}
+
 // RegisterProvider registers an OAuth provider configuration
 func (s *OAuthService) RegisterProvider(provider domain.OAuthProvider, cfg *domain.OAuthConfig) {
 	s.providers[provider] = cfg
@@ -88,7 +125,7 @@ func (s *OAuthService) GetAuthURL(provider domain.OAuthProvider, state string) (
 }
 
 // HandleCallback exchanges the authorization code for tokens and user info
-func (s *OAuthService) HandleCallback(ctx context.Context, provider domain.OAuthProvider, code string) (*domain.OAuthLoginResponse, error) {
+func (s *OAuthService) HandleCallback(ctx context.Context, provider domain.OAuthProvider, code string, client v2svc.SessionClient) (*domain.OAuthLoginResponse, error) {
 	cfg, ok := s.providers[provider]
 	if !ok {
 		return nil, fmt.Errorf("unsupported provider: %s", provider)
@@ -150,12 +187,69 @@ func (s *OAuthService) HandleCallback(ctx context.Context, provider domain.OAuth
 		}
 	}
 
-	// Generate JWT tokens
-	accessToken, err := s.jwtManager.GenerateAccessToken(oauthAccount.UserID, oauthAccount.UserID, userInfo.Name, 1)
+	// 테넌트 사이트 첫 로그인 = 회원 가입 (플랜 회원 수 한도)
+	if err := s.joinSite(ctx, oauthAccount.UserID); err != nil {
+		return nil, err
+	}
+
+	// 2단계 인증 사용 회원은 OTP 확인 후 토큰 발급 (비밀번호 로그인과 같은 흐름)
+	required, err := s.twoFactor.Enabled(oauthAccount.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("check two-factor failed: %w", err)
+	}
+	if required {
+		challenge, err := s.twoFactor.Challenge(v2svc.TwoFactorPurposeOAuth, oauthAccount.UserID, oauthAccount.UserID, userInfo.Name, oauthMemberLevel)
+		if err != nil {
+			return nil, fmt.Errorf("generate two-factor challenge failed: %w", err)
+		}
+		return &domain.OAuthLoginResponse{
+			IsNewUser:         isNewUser,
+			UserID:            oauthAccount.UserID,
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+		}, nil
+	}
+
+	resp, err := s.issueTokens(oauthAccount.UserID, userInfo.Name, client, false)
+	if err != nil {
+		return nil, err
+	}
+	resp.IsNewUser = isNewUser
+	return resp, nil
+}
+
+// VerifyTwoFactor completes an OAuth login with the callback's challenge token and a TOTP or recovery code
+func (s *OAuthService) VerifyTwoFactor(challengeToken, code string, client v2svc.SessionClient) (*domain.OAuthLoginResponse, error) {
+	if s.twoFactor == nil {
+		return nil, v2svc.ErrTwoFactorUnavailable
+	}
+	claims, err := s.twoFactor.ParseChallenge(challengeToken, v2svc.TwoFactorPurposeOAuth)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactor.Verify(claims.UserID, code); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(claims.UserID, claims.Nickname, client, true)
+}
+
+// issueTokens generates the JWT pair for an OAuth member
+// 리프레시 토큰은 세션 서비스가 발급해 v2 회원과 같은 회전 규칙을 따르고, 2단계 인증 여부(mfa)를 유지한다.
+func (s *OAuthService) issueTokens(userID, name string, client v2svc.SessionClient, mfa bool) (*domain.OAuthLoginResponse, error) {
+	generate := s.jwtManager.GenerateAccessToken
+	if mfa {
+		generate = s.jwtManager.GenerateMFAAccessToken
+	}
+	accessToken, err := generate(userID, userID, name, oauthMemberLevel)
 	if err != nil {
 		return nil, fmt.Errorf("generate access token failed: %w", err)
 	}
-	refreshToken, err := s.jwtManager.GenerateRefreshToken(oauthAccount.UserID)
+	var refreshToken string
+	if s.sessions != nil {
+		refreshToken, err = s.sessions.IssueExternalRefreshToken(userID, client, mfa)
+	} else {
+		refreshToken, err = s.jwtManager.GenerateSessionRefreshToken(userID, "", mfa)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("generate refresh token failed: %w", err)
 	}
@@ -163,11 +257,36 @@ func (s *OAuthService) HandleCallback(ctx context.Context, provider domain.OAuth
 	return &domain.OAuthLoginResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
-		IsNewUser:    isNewUser,
-		UserID:       oauthAccount.UserID,
+		UserID:       userID,
 	}, nil
 }
 
+// joinSite registers the user as a viewer of the request's tenant site if not yet a member
+func (s *OAuthService) joinSite(ctx context.Context, userID string) error {
+	siteID := repository.SiteIDFromContext(ctx)
+	if siteID == "" {
+		return nil
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&domain.SiteUser{}).
+		Where("site_id = ? AND user_id = ?", siteID, userID).Count(&count).Error; err != nil {
+		return fmt.Errorf("site membership lookup failed: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if err := s.quota.Reserve(ctx, common.QuotaUsers, 1); err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Create(&domain.SiteUser{SiteID: siteID, UserID: userID, Role: "viewer"}).Error; err != nil {
+		s.quota.Release(ctx, common.QuotaUsers, 1)
+		return fmt.Errorf("site membership create failed: %w", err)
+	}
+	return nil
+}
+
 // exchangeCode exchanges authorization code for access token
 func (s *OAuthService) exchangeCode(provider domain.OAuthProvider, cfg *domain.OAuthConfig, code string) (map[string]interface{}, error) {
 	var tokenURL string
@@ -309,27 +428,40 @@ func (s *OAuthService) parseUserInfo(provider domain.OAuthProvider, raw map[stri
 
 // --- API Key Management ---
 
+// ErrAPIKeyNotFound is returned when a key does not exist or belongs to another user
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// apiKeyLastUsedInterval last_used_at 갱신 최소 간격 (요청마다 UPDATE 하지 않음)
+const apiKeyLastUsedInterval = time.Minute
+
+// newAPIKeyValue "ak_" + 60 hex (api_key 컬럼 size:64 이내)
+func newAPIKeyValue() (string, error) {
+	keyBytes := make([]byte, 30)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return "", err
+	}
+	return "ak_" + hex.EncodeToString(keyBytes), nil
+}
+
 // GenerateAPIKey creates a new API key for a user
-func (s *OAuthService) GenerateAPIKey(ctx context.Context, userID, name, scopes string) (*domain.APIKey, error) {
+func (s *OAuthService) GenerateAPIKey(ctx context.Context, userID, name, scopes string, rateLimit int, mfa bool) (*domain.APIKey, error) {
 	if s.db == nil {
 		return nil, fmt.Errorf("database not available")
 	}
-	if err := s.db.AutoMigrate(&domain.APIKey{}); err != nil {
-		pkglogger.GetLogger().Warn().Err(err).Msg("failed to auto-migrate APIKey")
-	}
 
-	keyBytes := make([]byte, 32)
-	if _, err := rand.Read(keyBytes); err != nil {
+	key, err := newAPIKeyValue()
+	if err != nil {
 		return nil, err
 	}
-	key := "ak_" + hex.EncodeToString(keyBytes)
 
 	apiKey := &domain.APIKey{
-		Key:    key,
-		Name:   name,
-		UserID: userID,
-		Scopes: scopes,
-		Active: true,
+		Key:       key,
+		Name:      name,
+		UserID:    userID,
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		Active:    true,
+		MFA:       mfa,
 	}
 
 	if err := s.db.WithContext(ctx).Create(apiKey).Error; err != nil {
@@ -360,11 +492,104 @@ func (s *OAuthService) ValidateAPIKey(ctx context.Context, key string) (*domain.
 		return nil, fmt.Errorf("API key expired")
 	}
 
-	// Update last used
+	// Update last used (분 단위로만 갱신해 요청마다 쓰기가 발생하지 않도록)
 	now := time.Now()
-	if err := s.db.WithContext(ctx).Model(&apiKey).Update("last_used_at", now).Error; err != nil {
-		return nil, fmt.Errorf("update last used: %w", err)
+	if apiKey.LastUsed == nil || now.Sub(*apiKey.LastUsed) >= apiKeyLastUsedInterval {
+		if err := s.db.WithContext(ctx).Model(&apiKey).Update("last_used_at", now).Error; err != nil {
+			return nil, fmt.Errorf("update last used: %w", err)
+		}
+		apiKey.LastUsed = &now
+	}
+
+	return &apiKey, nil
+}
+
+// APIKeyOwnerLevel returns the current member level of a key's owner
+// 발급 이후 등급 변경(강등 등)이 즉시 반영되도록 요청마다 조회한다.
+func (s *OAuthService) APIKeyOwnerLevel(ctx context.Context, userID string) (int, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("database not available")
+	}
+
+	var levels []int
+	if _, err := strconv.ParseUint(userID, 10, 64); err == nil {
+		if err := s.db.WithContext(ctx).Table("v2_users").Where("id = ?", userID).Limit(1).Pluck("level", &levels).Error; err != nil {
+			return 0, err
+		}
+	}
+	if len(levels) == 0 {
+		if err := s.db.WithContext(ctx).Table("g5_member").Where("mb_id = ?", userID).Limit(1).Pluck("mb_level", &levels).Error; err != nil {
+			return 0, err
+		}
+	}
+	if len(levels) == 0 {
+		return 0, fmt.Errorf("API key owner not found")
+	}
+	return levels[0], nil
+}
+
+// ListAPIKeys returns a user's keys (newest first)
+func (s *OAuthService) ListAPIKeys(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	var keys []domain.APIKey
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// RevokeAPIKey deactivates a user's key
+func (s *OAuthService) RevokeAPIKey(ctx context.Context, userID string, id int64) error {
+	if s.db == nil {
+		return fmt.Errorf("database not available")
 	}
 
+	result := s.db.WithContext(ctx).Model(&domain.APIKey{}).
+		Where("id = ? AND user_id = ? AND active = ?", id, userID, true).
+		Update("active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	pkglogger.GetLogger().Info().
+		Str("user_id", userID).
+		Int64("key_id", id).
+		Msg("API key revoked")
+	return nil
+}
+
+// RotateAPIKey replaces a key's secret; the old value stops working immediately
+func (s *OAuthService) RotateAPIKey(ctx context.Context, userID string, id int64, mfa bool) (*domain.APIKey, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+
+	var apiKey domain.APIKey
+	err := s.db.WithContext(ctx).Where("id = ? AND user_id = ? AND active = ?", id, userID, true).First(&apiKey).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newAPIKeyValue()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Model(&apiKey).Updates(map[string]interface{}{"api_key": key, "mfa": mfa}).Error; err != nil {
+		return nil, err
+	}
+	apiKey.Key = key
+	apiKey.MFA = mfa
+
+	pkglogger.GetLogger().Info().
+		Str("user_id", userID).
+		Int64("key_id", id).
+		Msg("API key rotated")
 	return &apiKey, nil
 }
@@ -23,8 +23,13 @@ const (
 	planBusiness        = "business"
 	planEnterprise      = "enterprise"
 
+	paymentStatusTrialing = "trialing"
 	paymentStatusActive   = "active"
+	paymentStatusPastDue  = "past_due"
 	paymentStatusCanceled = "canceled"
+
+	// provisioningActor 구독 감사 로그의 주체 (사이트 소유자 요청)
+	provisioningActor = "owner"
 )
 
 // ProvisioningService handles one-click community creation and subscription management
@@ -33,6 +38,7 @@ type ProvisioningService struct {
 	subRepo    *repository.SubscriptionRepository
 	dbResolver *middleware.TenantDBResolver
 	db         *gorm.DB
+	billing    *BillingService
 	baseDomain string // e.g. "angple.com"
 }
 
@@ -49,10 +55,17 @@ func NewProvisioningService(
 		subRepo:    subRepo,
 		dbResolver: dbResolver,
 		db:         db,
+		billing:    NewBillingService(subRepo, siteRepo),
 		baseDomain: baseDomain,
 	}
 }
 
+// SetBillingService sets the billing service with registered payment providers (일할 계산 결제).
+// 설정하지 않으면 결제사 없이 상태 변경/감사 로그만 기록한다.
+func (s *ProvisioningService) SetBillingService(billing *BillingService) {
+	s.billing = billing
+}
+
 // planPricing holds pricing configuration
 var planPricing = map[string]domain.PlanPricing{
 	planFree:       {Plan: planFree, MonthlyKRW: 0, YearlyKRW: 0, TrialDays: 0},
@@ -156,7 +169,7 @@ func (s *ProvisioningService) ProvisionCommunity(ctx context.Context, req *domai
 		CurrentPeriodEnd:   now.AddDate(0, 1, 0),
 	}
 	if pricing.TrialDays > 0 {
-		sub.Status = "trialing"
+		sub.Status = paymentStatusTrialing
 		sub.CurrentPeriodEnd = now.AddDate(0, 0, pricing.TrialDays)
 	} else {
 		sub.Status = paymentStatusActive
@@ -215,19 +228,26 @@ func (s *ProvisioningService) ChangePlan(ctx context.Context, siteID string, req
 		return errors.New("현재와 동일한 플랜입니다")
 	}
 
-	pricing, ok := planPricing[req.Plan]
-	if !ok {
+	if _, ok := planPricing[req.Plan]; !ok {
 		return fmt.Errorf("유효하지 않은 플랜: %s", req.Plan)
 	}
 
-	// Update subscription
-	sub.Plan = req.Plan
-	sub.MonthlyPriceKRW = pricing.MonthlyKRW
+	cycle := sub.BillingCycle
 	if req.BillingCycle != "" {
-		sub.BillingCycle = req.BillingCycle
+		cycle = req.BillingCycle
+	}
+
+	// 남은 기간 일할 계산 (추가 금액 결제 실패 시 변경하지 않음)
+	prorated, err := s.billing.ProratePlanChange(ctx, sub, req.Plan, cycle)
+	if err != nil {
+		return err
+	}
+	if prorated != nil && cycle != sub.BillingCycle {
+		sub.CurrentPeriodStart = prorated.PeriodStart
+		sub.CurrentPeriodEnd = prorated.PeriodEnd
 	}
 
-	if err := s.subRepo.Update(ctx, sub); err != nil {
+	if err := s.billing.changePlan(ctx, sub, req.Plan, cycle, provisioningActor); err != nil {
 		return err
 	}
 
@@ -270,11 +290,7 @@ func (s *ProvisioningService) CancelSubscription(ctx context.Context, siteID str
 	if sub.Status == paymentStatusCanceled {
 		return errors.New("이미 취소된 구독입니다")
 	}
-
-	now := time.Now()
-	sub.CanceledAt = &now
-	sub.Status = paymentStatusCanceled
-	return s.subRepo.Update(ctx, sub)
+	return s.billing.transition(ctx, sub, paymentStatusCanceled, provisioningActor, "사용자 구독 해지 (기간 종료 후 무료 플랜 전환)")
 }
 
 // GetInvoices returns invoices for a site
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/domain"
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/repository"
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// ErrQuotaSourceUnavailable is returned by a QuotaCounter that has no authoritative value yet
+var ErrQuotaSourceUnavailable = errors.New("quota source unavailable")
+
+// QuotaCounter returns the authoritative usage of a resource for a site (Redis 카운터 초기화/야간 보정용)
+type QuotaCounter func(ctx context.Context, site *domain.Site) (int64, error)
+
+// quotaReserveScript atomically checks the limit and increments the counter.
+// -2: 카운터 없음 (초기화 필요), -1: 한도 초과, 그 외: 증가 후 값
+var quotaReserveScript = redis.NewScript(`
+local cur = redis.call('GET', KEYS[1])
+if not cur then
+  return -2
+end
+cur = tonumber(cur)
+local amount = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+if limit >= 0 and cur + amount > limit then
+  return -1
+end
+return redis.call('INCRBY', KEYS[1], amount)
+`)
+
+// quotaReleaseScript decrements an existing counter without going below zero
+var quotaReleaseScript = redis.NewScript(`
+local cur = redis.call('GET', KEYS[1])
+if not cur then
+  return 0
+end
+local next = tonumber(cur) - tonumber(ARGV[1])
+if next < 0 then
+  next = 0
+end
+redis.call('SET', KEYS[1], next)
+return next
+`)
+
+// QuotaService enforces middleware.PlanLimits for tenant sites at write time.
+// 사이트별 사용량 카운터는 Redis(quota:<site_id>:<resource>)에 두고, 매일 밤 Reconcile로 DB/SiteUsage 기준 값으로 보정한다.
+// 메인 사이트(사이트 ID 없음)는 제한하지 않는다.
+type QuotaService struct {
+	siteRepo *repository.SiteRepository
+	db       *gorm.DB
+	resolver *middleware.TenantDBResolver
+	redis    *redis.Client
+	counters map[string]QuotaCounter
+}
+
+// NewQuotaService creates a new QuotaService (redisClient가 nil이면 매번 DB에서 집계)
+func NewQuotaService(siteRepo *repository.SiteRepository, db *gorm.DB, resolver *middleware.TenantDBResolver, redisClient *redis.Client) *QuotaService {
+	s := &QuotaService{
+		siteRepo: siteRepo,
+		db:       db,
+		resolver: resolver,
+		redis:    redisClient,
+	}
+	s.counters = map[string]QuotaCounter{
+		common.QuotaPosts:   s.countPosts,
+		common.QuotaBoards:  s.countBoards,
+		common.QuotaUsers:   s.countUsers,
+		common.QuotaStorage: s.storageFromUsage,
+	}
+	return s
+}
+
+// SetCounter sets the authoritative counter for a resource (예: 플러그인 설치 수)
+func (s *QuotaService) SetCounter(resource string, counter QuotaCounter) {
+	s.counters[resource] = counter
+}
+
+// Reserve reserves amount units of resource for the request's site.
+// 한도를 넘으면 *common.QuotaExceededError를 반환한다. 쓰기가 실패하면 Release로 되돌린다.
+func (s *QuotaService) Reserve(ctx context.Context, resource string, amount int64) error {
+	if s == nil || amount <= 0 {
+		return nil
+	}
+	site, err := s.site(ctx)
+	if err != nil || site == nil {
+		return err
+	}
+	limit := QuotaLimit(site.Plan, resource)
+	if limit < 0 {
+		return nil
+	}
+
+	if s.redis == nil {
+		used, err := s.count(ctx, site, resource)
+		if err != nil && !errors.Is(err, ErrQuotaSourceUnavailable) {
+			return err
+		}
+		if used+amount > limit {
+			return &common.QuotaExceededError{Resource: resource, Plan: site.Plan, Limit: limit, Used: used}
+		}
+		return nil
+	}
+
+	key := quotaKey(site.ID, resource)
+	for attempt := 0; attempt < 2; attempt++ {
+		res, err := quotaReserveScript.Run(ctx, s.redis, []string{key}, amount, limit).Int64()
+		if err != nil {
+			return fmt.Errorf("quota reserve: %w", err)
+		}
+		switch res {
+		case -1:
+			used, _ := s.redis.Get(ctx, key).Int64() //nolint:errcheck // informational only
+			return &common.QuotaExceededError{Resource: resource, Plan: site.Plan, Limit: limit, Used: used}
+		case -2:
+			used, err := s.count(ctx, site, resource)
+			if err != nil && !errors.Is(err, ErrQuotaSourceUnavailable) {
+				return err
+			}
+			if err := s.redis.SetNX(ctx, key, used, 0).Err(); err != nil {
+				return fmt.Errorf("quota seed: %w", err)
+			}
+		default:
+			return nil
+		}
+	}
+	return fmt.Errorf("quota reserve: counter %s not initialized", key)
+}
+
+// Release returns previously reserved units (쓰기 실패 또는 삭제 시)
+func (s *QuotaService) Release(ctx context.Context, resource string, amount int64) {
+	if s == nil || s.redis == nil || amount <= 0 {
+		return
+	}
+	siteID := repository.SiteIDFromContext(ctx)
+	if siteID == "" {
+		return
+	}
+	if err := quotaReleaseScript.Run(ctx, s.redis, []string{quotaKey(siteID, resource)}, amount).Err(); err != nil {
+		pkglogger.Info("quota release failed for %s/%s: %v", siteID, resource, err)
+	}
+}
+
+// CheckFileSize rejects a single file larger than the plan's MaxFileSize
+func (s *QuotaService) CheckFileSize(ctx context.Context, size int64) error {
+	if s == nil {
+		return nil
+	}
+	site, err := s.site(ctx)
+	if err != nil || site == nil {
+		return err
+	}
+	if limit := QuotaLimit(site.Plan, common.QuotaFile); limit >= 0 && size > limit {
+		return &common.QuotaExceededError{Resource: common.QuotaFile, Plan: site.Plan, Limit: limit, Used: size}
+	}
+	return nil
+}
+
+// QuotaReconcileResult is the result of a nightly reconcile run
+type QuotaReconcileResult struct {
+	Sites      int    `json:"sites"`
+	Counters   int    `json:"counters"`
+	Errors     int    `json:"errors"`
+	ExecutedAt string `json:"executed_at"`
+}
+
+// Reconcile resets every active site's Redis counters to the authoritative values.
+// 게시글/게시판/회원은 DB, 저장 용량은 최신 SiteUsage를 기준으로 한다. 매일 밤 cron으로 실행한다.
+func (s *QuotaService) Reconcile(ctx context.Context) (*QuotaReconcileResult, error) {
+	result := &QuotaReconcileResult{ExecutedAt: time.Now().Format("2006-01-02 15:04:05")}
+	if s.redis == nil {
+		return result, nil
+	}
+
+	var sites []domain.Site
+	if err := s.db.WithContext(ctx).Where("active = ?", true).Find(&sites).Error; err != nil {
+		return nil, fmt.Errorf("사이트 조회 실패: %w", err)
+	}
+
+	for i := range sites {
+		site := &sites[i]
+		result.Sites++
+		for resource := range s.counters {
+			used, err := s.count(ctx, site, resource)
+			if errors.Is(err, ErrQuotaSourceUnavailable) {
+				continue
+			}
+			if err == nil {
+				err = s.redis.Set(ctx, quotaKey(site.ID, resource), used, 0).Err()
+			}
+			if err != nil {
+				pkglogger.Info("quota reconcile failed for %s/%s: %v", site.ID, resource, err)
+				result.Errors++
+				continue
+			}
+			result.Counters++
+		}
+	}
+	return result, nil
+}
+
+// QuotaLimit returns the plan limit for a resource (-1 = 무제한, 스토리지/파일 크기는 bytes)
+func QuotaLimit(plan, resource string) int64 {
+	limits := middleware.GetPlanLimits(plan)
+	switch resource {
+	case common.QuotaPosts:
+		return int64(limits.MaxPosts)
+	case common.QuotaBoards:
+		return int64(limits.MaxBoards)
+	case common.QuotaUsers:
+		return int64(limits.MaxUsers)
+	case common.QuotaStorage:
+		return megabytes(limits.MaxStorage)
+	case common.QuotaFile:
+		return megabytes(limits.MaxFileSize)
+	case common.QuotaPlugins:
+		if !limits.PluginsAllowed {
+			return 0
+		}
+		return int64(limits.MaxPlugins)
+	default:
+		return -1
+	}
+}
+
+func megabytes(mb int64) int64 {
+	if mb < 0 {
+		return -1
+	}
+	return mb * 1024 * 1024
+}
+
+func quotaKey(siteID, resource string) string {
+	return "quota:" + siteID + ":" + resource
+}
+
+// site returns the active site of the request context (메인 사이트면 nil)
+func (s *QuotaService) site(ctx context.Context) (*domain.Site, error) {
+	siteID := repository.SiteIDFromContext(ctx)
+	if siteID == "" {
+		return nil, nil
+	}
+	site, err := s.siteRepo.FindByID(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("quota site lookup: %w", err)
+	}
+	return site, nil
+}
+
+func (s *QuotaService) count(ctx context.Context, site *domain.Site, resource string) (int64, error) {
+	counter, ok := s.counters[resource]
+	if !ok {
+		return 0, nil
+	}
+	used, err := counter(ctx, site)
+	if errors.Is(err, ErrQuotaSourceUnavailable) {
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("quota count %s: %w", resource, err)
+	}
+	return used, nil
+}
+
+// tenantDB returns the site's content DB scoped to its site ID
+func (s *QuotaService) tenantDB(ctx context.Context, site *domain.Site) (*gorm.DB, error) {
+	db := s.db
+	if s.resolver != nil {
+		resolved, err := s.resolver.Resolve(site)
+		if err != nil {
+			return nil, err
+		}
+		db = resolved
+	}
+	return db.WithContext(repository.ContextWithSiteID(ctx, site.ID)), nil
+}
+
+func (s *QuotaService) countPosts(ctx context.Context, site *domain.Site) (int64, error) {
+	db, err := s.tenantDB(ctx, site)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	err = db.Model(&v2.V2Post{}).Where("deleted_at IS NULL").Count(&n).Error
+	return n, err
+}
+
+func (s *QuotaService) countBoards(ctx context.Context, site *domain.Site) (int64, error) {
+	db, err := s.tenantDB(ctx, site)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	err = db.Model(&v2.V2Board{}).Count(&n).Error
+	return n, err
+}
+
+func (s *QuotaService) countUsers(ctx context.Context, site *domain.Site) (int64, error) {
+	var n int64
+	err := s.db.WithContext(ctx).Model(&domain.SiteUser{}).Where("site_id = ?", site.ID).Count(&n).Error
+	return n, err
+}
+
+// storageFromUsage uses the latest SiteUsage row (행이 없으면 Redis 값을 유지)
+func (s *QuotaService) storageFromUsage(ctx context.Context, site *domain.Site) (int64, error) {
+	var usage domain.SiteUsage
+	err := s.db.WithContext(ctx).Where("site_id = ?", site.ID).Order("date DESC").First(&usage).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrQuotaSourceUnavailable
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int64(usage.StorageUsedMB * 1024 * 1024), nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/domain"
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"github.com/damoang/angple-backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestQuotaService_ReserveWithoutRedis(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Site{}, &v2.V2Board{}))
+	require.NoError(t, repository.RegisterTenantScope(db))
+
+	require.NoError(t, db.Create(&domain.Site{ID: "free-site", Subdomain: "free", Plan: "free", DBStrategy: "shared", Active: true}).Error)
+	require.NoError(t, db.Create(&domain.Site{ID: "big-site", Subdomain: "big", Plan: "enterprise", DBStrategy: "shared", Active: true}).Error)
+
+	freeCtx := repository.ContextWithSiteID(context.Background(), "free-site")
+	for i := 0; i < 5; i++ {
+		board := &v2.V2Board{Slug: string(rune('a' + i)), Name: "board"}
+		require.NoError(t, db.WithContext(freeCtx).Create(board).Error)
+	}
+
+	svc := NewQuotaService(repository.NewSiteRepository(db), db, nil, nil)
+
+	err = svc.Reserve(freeCtx, common.QuotaBoards, 1)
+	var qe *common.QuotaExceededError
+	require.True(t, errors.As(err, &qe), "expected quota error, got %v", err)
+	assert.Equal(t, common.QuotaBoards, qe.Resource)
+	assert.Equal(t, int64(5), qe.Limit)
+	assert.Equal(t, int64(5), qe.Used)
+
+	// 메인 사이트 / 무제한 플랜
+	assert.NoError(t, svc.Reserve(context.Background(), common.QuotaBoards, 1))
+	assert.NoError(t, svc.Reserve(repository.ContextWithSiteID(context.Background(), "big-site"), common.QuotaBoards, 1))
+
+	// 파일 크기 (free: 5MB), 플러그인 (free: 허용 안 함)
+	assert.Error(t, svc.CheckFileSize(freeCtx, 6*1024*1024))
+	assert.NoError(t, svc.CheckFileSize(freeCtx, 1024))
+	assert.Error(t, svc.Reserve(freeCtx, common.QuotaPlugins, 1))
+
+	// 플러그인 수는 등록된 카운터(설치 레코드 수) 기준 (pro: 5개)
+	require.NoError(t, db.Create(&domain.Site{ID: "pro-site", Subdomain: "pro", Plan: "pro", DBStrategy: "shared", Active: true}).Error)
+	proCtx := repository.ContextWithSiteID(context.Background(), "pro-site")
+	installed := int64(5)
+	svc.SetCounter(common.QuotaPlugins, func(context.Context, *domain.Site) (int64, error) { return installed, nil })
+	require.True(t, errors.As(svc.Reserve(proCtx, common.QuotaPlugins, 1), &qe))
+	assert.Equal(t, int64(5), qe.Used)
+	installed = 4
+	assert.NoError(t, svc.Reserve(proCtx, common.QuotaPlugins, 1))
+}
+
+func TestQuotaLimit(t *testing.T) {
+	assert.Equal(t, int64(1000), QuotaLimit("free", common.QuotaPosts))
+	assert.Equal(t, int64(500*1024*1024), QuotaLimit("free", common.QuotaStorage))
+	assert.Equal(t, int64(0), QuotaLimit("free", common.QuotaPlugins))
+	assert.Equal(t, int64(5), QuotaLimit("pro", common.QuotaPlugins))
+	assert.Equal(t, int64(-1), QuotaLimit("enterprise", common.QuotaStorage))
+	assert.Equal(t, int64(-1), QuotaLimit("free", "unknown"))
+}
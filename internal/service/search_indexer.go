@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// 검색 색인 큐 Redis 키
+const (
+	searchIndexQueueKey      = "search:index:queue"      // LIST: 처리 대기 작업 (LPUSH / BLMOVE)
+	searchIndexProcessingKey = "search:index:processing" // LIST: 처리 중 작업 (완료 시 LREM, 재시작 시 대기 큐로 복구)
+	searchIndexRetryKey      = "search:index:retry"      // ZSET: 재시도 작업 (score = 다음 시도 unix 초)
+	searchIndexDeadKey       = "search:index:dead"       // LIST: 재시도 한도를 넘긴 작업
+	searchIndexStatsKey      = "search:index:stats"      // HASH: 처리 통계
+
+	searchIndexMaxBackoff  = 5 * time.Minute
+	searchIndexTimeout     = 10 * time.Second
+	searchIndexMaxAttempts = 12 // 백오프 합계 약 30분 후 dead letter
+)
+
+// 검색 색인 작업 종류
+const (
+	SearchJobPost    = "post"
+	SearchJobComment = "comment"
+)
+
+// SearchIndexJob 색인 동기화 작업
+// 작업은 "현재 DB 상태로 맞춤" 의미라 순서가 바뀌거나 중복 실행되어도 안전하다.
+type SearchIndexJob struct {
+	Type         string `json:"type"`
+	BoardID      string `json:"board_id"`
+	PostID       int    `json:"post_id"`
+	CommentID    int    `json:"comment_id,omitempty"`
+	WithComments bool   `json:"with_comments,omitempty"`
+	EnqueuedAt   int64  `json:"enqueued_at"` // unix ms
+	Attempts     int    `json:"attempts,omitempty"`
+}
+
+// searchSyncer SearchIndexer가 사용하는 색인 동기화 대상 (SearchService)
+type searchSyncer interface {
+	SyncPost(ctx context.Context, boardID string, postID int, withComments bool) error
+	SyncComment(ctx context.Context, boardID string, postID, commentID int) error
+}
+
+// SearchIndexLag 색인 지연 현황
+type SearchIndexLag struct {
+	QueueLength   int64      `json:"queue_length"`
+	RetryLength   int64      `json:"retry_length"`
+	DeadLength    int64      `json:"dead_letter_length"`
+	OldestPending *time.Time `json:"oldest_pending_at,omitempty"`
+	LagSeconds    float64    `json:"lag_seconds"`
+	Processed     int64      `json:"processed"`
+	Failed        int64      `json:"failed"`
+	DeadLettered  int64      `json:"dead_lettered"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// SearchIndexer 게시글/댓글 쓰기를 ES 색인에 반영하는 파이프라인
+// Redis가 있으면 큐 + 지수 백오프 재시도로 ES 장애 중에도 작업을 잃지 않고,
+// 없으면 비동기 best-effort로 바로 처리한다.
+type SearchIndexer struct {
+	syncer searchSyncer
+	redis  *redis.Client
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSearchIndexer creates a new SearchIndexer
+func NewSearchIndexer(syncer searchSyncer, redisClient *redis.Client) *SearchIndexer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SearchIndexer{syncer: syncer, redis: redisClient, ctx: ctx, cancel: cancel}
+}
+
+// EnqueuePost 게시글 색인 동기화 예약 (nil receiver 허용: ES 비활성 시 no-op)
+func (ix *SearchIndexer) EnqueuePost(boardID string, postID int, withComments bool) {
+	if ix == nil {
+		return
+	}
+	ix.enqueue(&SearchIndexJob{Type: SearchJobPost, BoardID: boardID, PostID: postID, WithComments: withComments})
+}
+
+// EnqueueComment 댓글 색인 동기화 예약 (postID를 모르면 0)
+func (ix *SearchIndexer) EnqueueComment(boardID string, postID, commentID int) {
+	if ix == nil {
+		return
+	}
+	ix.enqueue(&SearchIndexJob{Type: SearchJobComment, BoardID: boardID, PostID: postID, CommentID: commentID})
+}
+
+func (ix *SearchIndexer) enqueue(job *SearchIndexJob) {
+	job.EnqueuedAt = time.Now().UnixMilli()
+
+	if ix.redis == nil {
+		go func() {
+			if err := ix.process(job); err != nil {
+				pkglogger.Error("[SearchIndexer] %s %s/%d failed: %v", job.Type, job.BoardID, job.PostID, err)
+			}
+		}()
+		return
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return
+	}
+	if err := ix.redis.LPush(ix.ctx, searchIndexQueueKey, data).Err(); err != nil {
+		// Redis 장애 시 즉시 처리로 폴백
+		pkglogger.Info("[SearchIndexer] enqueue failed, indexing inline: %v", err)
+		go ix.process(job) //nolint:errcheck
+	}
+}
+
+// Start 큐 소비 워커 시작 (Redis 없으면 no-op)
+func (ix *SearchIndexer) Start() {
+	if ix == nil || ix.redis == nil {
+		return
+	}
+	ix.recoverProcessing()
+	ix.wg.Add(1)
+	go func() {
+		defer ix.wg.Done()
+		pkglogger.Info("[SearchIndexer] Started")
+		for {
+			select {
+			case <-ix.ctx.Done():
+				pkglogger.Info("[SearchIndexer] Stopped")
+				return
+			default:
+			}
+			ix.promoteRetries()
+			ix.consumeOne()
+		}
+	}()
+}
+
+// Stop 워커 정지
+func (ix *SearchIndexer) Stop() {
+	if ix == nil {
+		return
+	}
+	ix.cancel()
+	ix.wg.Wait()
+}
+
+// recoverProcessing 이전 프로세스가 처리 중에 종료되어 남은 작업을 대기 큐로 되돌린다.
+// 다른 인스턴스가 처리 중인 작업이 함께 옮겨질 수 있으나 작업은 중복 실행해도 안전하다.
+func (ix *SearchIndexer) recoverProcessing() {
+	recovered := 0
+	for {
+		err := ix.redis.LMove(ix.ctx, searchIndexProcessingKey, searchIndexQueueKey, "RIGHT", "RIGHT").Err()
+		if err != nil {
+			if err != redis.Nil {
+				pkglogger.Error("[SearchIndexer] processing list recovery failed: %v", err)
+			}
+			break
+		}
+		recovered++
+	}
+	if recovered > 0 {
+		pkglogger.Info("[SearchIndexer] Requeued %d in-flight jobs", recovered)
+	}
+}
+
+// consumeOne 큐에서 작업 하나를 처리 중 목록으로 옮겨 처리 (최대 1초 대기)
+// 완료(성공, 재시도 예약, dead letter) 후에 처리 중 목록에서 제거하므로 처리 도중 종료되어도 작업을 잃지 않는다.
+func (ix *SearchIndexer) consumeOne() {
+	raw, err := ix.redis.BLMove(ix.ctx, searchIndexQueueKey, searchIndexProcessingKey, "RIGHT", "LEFT", time.Second).Result()
+	if err != nil {
+		if err != redis.Nil && ix.ctx.Err() == nil {
+			time.Sleep(time.Second) // Redis 장애 시 과도한 재시도 방지
+		}
+		return
+	}
+	defer ix.redis.LRem(context.Background(), searchIndexProcessingKey, 1, raw) //nolint:errcheck // 남으면 재시작 시 중복 처리될 뿐
+
+	var job SearchIndexJob
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		pkglogger.Info("[SearchIndexer] invalid job dropped: %s", raw)
+		return
+	}
+
+	if err := ix.process(&job); err != nil {
+		ix.scheduleRetry(&job, err)
+		return
+	}
+	ix.recordSuccess()
+}
+
+func (ix *SearchIndexer) process(job *SearchIndexJob) error {
+	ctx, cancel := context.WithTimeout(context.Background(), searchIndexTimeout)
+	defer cancel()
+
+	switch job.Type {
+	case SearchJobPost:
+		return ix.syncer.SyncPost(ctx, job.BoardID, job.PostID, job.WithComments)
+	case SearchJobComment:
+		return ix.syncer.SyncComment(ctx, job.BoardID, job.PostID, job.CommentID)
+	}
+	return nil
+}
+
+// scheduleRetry 실패한 작업을 지수 백오프로 재시도 큐에 등록
+// searchIndexMaxAttempts회 실패하면 dead letter 목록으로 옮긴다 (ES 복구 후 수동 재색인 대상).
+func (ix *SearchIndexer) scheduleRetry(job *SearchIndexJob, cause error) {
+	job.Attempts++
+	next := time.Now().Add(searchIndexRetryDelay(job.Attempts))
+
+	ctx := context.Background()
+	pipe := ix.redis.TxPipeline()
+	pipe.HIncrBy(ctx, searchIndexStatsKey, "failed", 1)
+	pipe.HSet(ctx, searchIndexStatsKey, "last_error", cause.Error(), "last_error_at", time.Now().Unix())
+	if data, err := json.Marshal(job); err == nil {
+		if job.Attempts >= searchIndexMaxAttempts {
+			pipe.LPush(ctx, searchIndexDeadKey, data)
+			pipe.HIncrBy(ctx, searchIndexStatsKey, "dead_lettered", 1)
+			pkglogger.Error("[SearchIndexer] %s %s/%d dead-lettered after %d attempts: %v", job.Type, job.BoardID, job.PostID, job.Attempts, cause)
+		} else {
+			pipe.ZAdd(ctx, searchIndexRetryKey, redis.Z{Score: float64(next.Unix()), Member: data})
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		pkglogger.Error("[SearchIndexer] retry schedule failed for %s %s/%d: %v", job.Type, job.BoardID, job.PostID, err)
+	}
+}
+
+// promoteRetries 재시도 시각이 된 작업을 처리 큐로 이동
+// ZREM에 성공한 인스턴스만 LPUSH 하므로 다중 인스턴스에서도 중복 이동되지 않는다.
+func (ix *SearchIndexer) promoteRetries() {
+	due, err := ix.redis.ZRangeByScore(ix.ctx, searchIndexRetryKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().Unix(), 10),
+		Count: 100,
+	}).Result()
+	if err != nil {
+		return
+	}
+	for _, member := range due {
+		removed, err := ix.redis.ZRem(ix.ctx, searchIndexRetryKey, member).Result()
+		if err != nil || removed == 0 {
+			continue
+		}
+		ix.redis.LPush(ix.ctx, searchIndexQueueKey, member) //nolint:errcheck
+	}
+}
+
+func (ix *SearchIndexer) recordSuccess() {
+	ctx := context.Background()
+	pipe := ix.redis.TxPipeline()
+	pipe.HIncrBy(ctx, searchIndexStatsKey, "processed", 1)
+	pipe.HSet(ctx, searchIndexStatsKey, "last_success_at", time.Now().Unix())
+	pipe.Exec(ctx) //nolint:errcheck
+}
+
+// Lag 큐 길이, 가장 오래된 대기 작업, 처리 통계 조회
+func (ix *SearchIndexer) Lag(ctx context.Context) (*SearchIndexLag, error) {
+	lag := &SearchIndexLag{}
+	if ix == nil || ix.redis == nil {
+		return lag, nil
+	}
+
+	var err error
+	if lag.QueueLength, err = ix.redis.LLen(ctx, searchIndexQueueKey).Result(); err != nil {
+		return nil, err
+	}
+	if lag.RetryLength, err = ix.redis.ZCard(ctx, searchIndexRetryKey).Result(); err != nil {
+		return nil, err
+	}
+	if lag.DeadLength, err = ix.redis.LLen(ctx, searchIndexDeadKey).Result(); err != nil {
+		return nil, err
+	}
+
+	// 큐의 가장 오래된 작업(오른쪽 끝)과 재시도 대기 중 가장 이른 작업 중 더 오래된 것
+	var oldest int64
+	candidates := []string{}
+	if tail, err := ix.redis.LIndex(ctx, searchIndexQueueKey, -1).Result(); err == nil {
+		candidates = append(candidates, tail)
+	}
+	if first, err := ix.redis.ZRange(ctx, searchIndexRetryKey, 0, 0).Result(); err == nil {
+		candidates = append(candidates, first...)
+	}
+	for _, raw := range candidates {
+		var job SearchIndexJob
+		if json.Unmarshal([]byte(raw), &job) == nil && job.EnqueuedAt > 0 && (oldest == 0 || job.EnqueuedAt < oldest) {
+			oldest = job.EnqueuedAt
+		}
+	}
+	if oldest > 0 {
+		t := time.UnixMilli(oldest)
+		lag.OldestPending = &t
+		lag.LagSeconds = time.Since(t).Seconds()
+	}
+
+	stats, err := ix.redis.HGetAll(ctx, searchIndexStatsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	lag.Processed, _ = strconv.ParseInt(stats["processed"], 10, 64)
+	lag.Failed, _ = strconv.ParseInt(stats["failed"], 10, 64)
+	lag.DeadLettered, _ = strconv.ParseInt(stats["dead_lettered"], 10, 64)
+	lag.LastError = stats["last_error"]
+	lag.LastSuccessAt = unixStatTime(stats["last_success_at"])
+	lag.LastErrorAt = unixStatTime(stats["last_error_at"])
+	return lag, nil
+}
+
+// searchIndexRetryDelay 지수 백오프 (2s, 4s, 8s ... 최대 5분)
+func searchIndexRetryDelay(attempts int) time.Duration {
+	if attempts > 8 {
+		return searchIndexMaxBackoff
+	}
+	d := time.Second << uint(attempts) //nolint:gosec // attempts <= 8
+	if d > searchIndexMaxBackoff {
+		return searchIndexMaxBackoff
+	}
+	return d
+}
+
+func unixStatTime(v string) *time.Time {
+	sec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || sec == 0 {
+		return nil
+	}
+	t := time.Unix(sec, 0)
+	return &t
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSearchSyncer struct {
+	mu    sync.Mutex
+	posts []int
+	done  chan struct{}
+}
+
+func (f *fakeSearchSyncer) SyncPost(_ context.Context, _ string, postID int, _ bool) error {
+	f.mu.Lock()
+	f.posts = append(f.posts, postID)
+	f.mu.Unlock()
+	f.done <- struct{}{}
+	return nil
+}
+
+func (f *fakeSearchSyncer) SyncComment(_ context.Context, _ string, _, _ int) error {
+	f.done <- struct{}{}
+	return nil
+}
+
+func TestSearchIndexRetryDelay(t *testing.T) {
+	assert.Equal(t, 2*time.Second, searchIndexRetryDelay(1))
+	assert.Equal(t, 16*time.Second, searchIndexRetryDelay(4))
+	assert.Equal(t, searchIndexMaxBackoff, searchIndexRetryDelay(9))
+	assert.Equal(t, searchIndexMaxBackoff, searchIndexRetryDelay(100))
+}
+
+func TestSearchIndexerWithoutRedisIndexesInline(t *testing.T) {
+	syncer := &fakeSearchSyncer{done: make(chan struct{}, 2)}
+	ix := NewSearchIndexer(syncer, nil)
+	ix.Start()
+	defer ix.Stop()
+
+	ix.EnqueuePost("free", 10, false)
+	ix.EnqueueComment("free", 10, 11)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-syncer.done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("job was not processed")
+		}
+	}
+	assert.Equal(t, []int{10}, syncer.posts)
+
+	// nil indexer (ES 비활성)는 no-op
+	var nilIx *SearchIndexer
+	nilIx.EnqueuePost("free", 1, true)
+	lag, err := nilIx.Lag(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, lag.QueueLength)
+}
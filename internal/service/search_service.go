@@ -171,6 +171,156 @@ func (s *SearchService) DeleteComment(ctx context.Context, boardID string, postI
 	return s.esClient.DeleteDocument(ctx, CommentsIndex, docID)
 }
 
+// DeletePostComments removes all comments of a post from the index
+func (s *SearchService) DeletePostComments(ctx context.Context, boardID string, postID int) error {
+	return s.esClient.DeleteByQuery(ctx, CommentsIndex, map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": []map[string]interface{}{
+				{"term": map[string]interface{}{"board_id": boardID}},
+				{"term": map[string]interface{}{"post_id": postID}},
+			},
+		},
+	})
+}
+
+// searchWriteRow g5_write_{board} 색인용 컬럼
+type searchWriteRow struct {
+	WrID       int    `gorm:"column:wr_id"`
+	WrParent   int    `gorm:"column:wr_parent"`
+	WrSubject  string `gorm:"column:wr_subject"`
+	WrContent  string `gorm:"column:wr_content"`
+	WrName     string `gorm:"column:wr_name"`
+	MbID       string `gorm:"column:mb_id"`
+	CaName     string `gorm:"column:ca_name"`
+	WrDatetime string `gorm:"column:wr_datetime"`
+	WrHit      int    `gorm:"column:wr_hit"`
+	WrGood     int    `gorm:"column:wr_good"`
+}
+
+const searchNotDeleted = "(wr_deleted_at IS NULL OR wr_deleted_at = '0000-00-00 00:00:00')"
+
+func (s *SearchService) isSearchable(boardID string) (bool, error) {
+	var count int64
+	err := s.db.Table("g5_board").Where("bo_table = ? AND bo_use_search = ?", boardID, 1).Count(&count).Error
+	return count > 0, err
+}
+
+func postDocumentFromRow(boardID string, row *searchWriteRow) PostDocument {
+	return PostDocument{
+		BoardID:   boardID,
+		PostID:    row.WrID,
+		Title:     row.WrSubject,
+		Content:   stripHTML(row.WrContent),
+		Author:    row.WrName,
+		AuthorID:  row.MbID,
+		Category:  row.CaName,
+		CreatedAt: row.WrDatetime,
+		Views:     row.WrHit,
+		Good:      row.WrGood,
+		TitleSuggest: map[string]interface{}{
+			"input": strings.Fields(row.WrSubject),
+		},
+	}
+}
+
+func commentDocumentFromRow(boardID string, row *searchWriteRow) CommentDocument {
+	return CommentDocument{
+		BoardID:   boardID,
+		PostID:    row.WrParent,
+		CommentID: row.WrID,
+		Content:   stripHTML(row.WrContent),
+		Author:    row.WrName,
+		AuthorID:  row.MbID,
+		CreatedAt: row.WrDatetime,
+	}
+}
+
+// SyncPost DB의 현재 상태로 게시글 색인을 맞춤
+// 삭제/이동/비검색 게시판이면 색인(및 댓글 색인)에서 제거하고, 그 외에는 재색인한다.
+// withComments가 true면 댓글도 함께 재색인한다 (복구, 이동 시).
+func (s *SearchService) SyncPost(ctx context.Context, boardID string, postID int, withComments bool) error {
+	if s.db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	tableName := fmt.Sprintf("g5_write_%s", boardID)
+	searchable, err := s.isSearchable(boardID)
+	if err != nil {
+		return err
+	}
+	var rows []searchWriteRow
+	if searchable {
+		if err := s.db.Table(tableName).
+			Where("wr_id = ? AND wr_is_comment = 0 AND "+searchNotDeleted, postID).
+			Limit(1).Find(&rows).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(rows) == 0 {
+		if err := s.DeletePost(ctx, boardID, postID); err != nil {
+			return err
+		}
+		return s.DeletePostComments(ctx, boardID, postID)
+	}
+
+	doc := postDocumentFromRow(boardID, &rows[0])
+	if err := s.esClient.IndexDocument(ctx, PostsIndex, fmt.Sprintf("%s_%d", boardID, postID), doc); err != nil {
+		return err
+	}
+	if !withComments {
+		return nil
+	}
+
+	var comments []searchWriteRow
+	if err := s.db.Table(tableName).
+		Where("wr_parent = ? AND wr_is_comment = 1 AND "+searchNotDeleted, postID).
+		Find(&comments).Error; err != nil {
+		return err
+	}
+	docs := make(map[string]interface{}, len(comments))
+	for i := range comments {
+		docs[fmt.Sprintf("%s_%d_%d", boardID, postID, comments[i].WrID)] = commentDocumentFromRow(boardID, &comments[i])
+	}
+	return s.esClient.BulkIndex(ctx, CommentsIndex, docs)
+}
+
+// SyncComment DB의 현재 상태로 댓글 색인을 맞춤 (postID가 0이면 DB에서 wr_parent 조회)
+func (s *SearchService) SyncComment(ctx context.Context, boardID string, postID, commentID int) error {
+	if s.db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	tableName := fmt.Sprintf("g5_write_%s", boardID)
+	if postID == 0 {
+		if err := s.db.Table(tableName).Select("wr_parent").Where("wr_id = ?", commentID).Scan(&postID).Error; err != nil {
+			return err
+		}
+		if postID == 0 {
+			return nil // 원본 행이 없으면 문서 ID를 알 수 없음
+		}
+	}
+
+	searchable, err := s.isSearchable(boardID)
+	if err != nil {
+		return err
+	}
+	var rows []searchWriteRow
+	if searchable {
+		if err := s.db.Table(tableName).
+			Where("wr_id = ? AND wr_is_comment = 1 AND "+searchNotDeleted, commentID).
+			Limit(1).Find(&rows).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(rows) == 0 {
+		return s.DeleteComment(ctx, boardID, postID, commentID)
+	}
+	doc := commentDocumentFromRow(boardID, &rows[0])
+	return s.IndexComment(ctx, &doc)
+}
+
 // SearchPosts searches posts with highlighting
 func (s *SearchService) SearchPosts(ctx context.Context, keyword, boardID string, page, perPage int) (*es.SearchResponse, error) {
 	must := []map[string]interface{}{
@@ -322,28 +472,15 @@ func (s *SearchService) BulkIndexPosts(ctx context.Context, boardID string, limi
 	}
 
 	// Skip boards where bo_use_search is disabled
-	var searchCount int64
-	s.db.Table("g5_board").Where("bo_table = ? AND bo_use_search = ?", boardID, 1).Count(&searchCount)
-	if searchCount == 0 {
-		return 0, nil
+	if searchable, err := s.isSearchable(boardID); err != nil || !searchable {
+		return 0, err
 	}
 
 	tableName := fmt.Sprintf("g5_write_%s", boardID)
 
-	var rows []struct {
-		WrID       int    `gorm:"column:wr_id"`
-		WrSubject  string `gorm:"column:wr_subject"`
-		WrContent  string `gorm:"column:wr_content"`
-		WrName     string `gorm:"column:wr_name"`
-		MbID       string `gorm:"column:mb_id"`
-		CaName     string `gorm:"column:ca_name"`
-		WrDatetime string `gorm:"column:wr_datetime"`
-		WrHit      int    `gorm:"column:wr_hit"`
-		WrGood     int    `gorm:"column:wr_good"`
-	}
-
+	var rows []searchWriteRow
 	err := s.db.Table(tableName).
-		Where("wr_is_comment = 0 AND (wr_deleted_at IS NULL OR wr_deleted_at = '0000-00-00 00:00:00')").
+		Where("wr_is_comment = 0 AND " + searchNotDeleted).
 		Order("wr_id DESC").
 		Limit(limit).
 		Find(&rows).Error
@@ -352,23 +489,9 @@ func (s *SearchService) BulkIndexPosts(ctx context.Context, boardID string, limi
 	}
 
 	docs := make(map[string]interface{})
-	for _, row := range rows {
-		docID := fmt.Sprintf("%s_%d", boardID, row.WrID)
-		docs[docID] = PostDocument{
-			BoardID:   boardID,
-			PostID:    row.WrID,
-			Title:     row.WrSubject,
-			Content:   stripHTML(row.WrContent),
-			Author:    row.WrName,
-			AuthorID:  row.MbID,
-			Category:  row.CaName,
-			CreatedAt: row.WrDatetime,
-			Views:     row.WrHit,
-			Good:      row.WrGood,
-			TitleSuggest: map[string]interface{}{
-				"input": strings.Fields(row.WrSubject),
-			},
-		}
+	for i := range rows {
+		docID := fmt.Sprintf("%s_%d", boardID, rows[i].WrID)
+		docs[docID] = postDocumentFromRow(boardID, &rows[i])
 	}
 
 	if err := s.esClient.BulkIndex(ctx, PostsIndex, docs); err != nil {
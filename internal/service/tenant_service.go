@@ -209,9 +209,19 @@ func (s *TenantService) GetUsage(ctx context.Context, siteID string, days int) (
 		stats.TotalAPICalls += int64(u.APICalls)
 		stats.TotalPosts += int64(u.PostsCreated)
 		stats.TotalComments += int64(u.CommentsCreated)
-		stats.StorageUsedMB += u.StorageUsedMB
 		stats.BandwidthUsedMB += u.BandwidthUsedMB
 	}
+	// 저장 용량은 일별 스냅샷이므로 합산하지 않고 가장 최근 값을 쓴다
+	if n := len(usages); n > 0 {
+		stats.StorageUsedMB = usages[n-1].StorageUsedMB
+	} else {
+		var latest domain.SiteUsage
+		err := s.db.WithContext(ctx).Where("site_id = ?", siteID).Order("date DESC").First(&latest).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		stats.StorageUsedMB = latest.StorageUsedMB
+	}
 
 	return stats, nil
 }
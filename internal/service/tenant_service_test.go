@@ -1,10 +1,17 @@
 package service
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/damoang/angple-backend/internal/domain"
 	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/repository"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestGetDBStrategyByPlan_TenantService(t *testing.T) {
@@ -37,3 +44,23 @@ func TestPlanLimits(t *testing.T) {
 	unknown := middleware.GetPlanLimits("unknown")
 	assert.Equal(t, free.MaxStorage, unknown.MaxStorage)
 }
+
+func TestTenantService_GetUsageStorageIsLatestSnapshot(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Site{}, &domain.SiteUsage{}))
+	require.NoError(t, db.Create(&domain.Site{ID: "s1", Subdomain: "s1", Plan: "pro", DBStrategy: "shared", Active: true}).Error)
+
+	today := time.Now().Truncate(24 * time.Hour)
+	for i, mb := range []float64{100, 120, 90} {
+		require.NoError(t, db.Create(&domain.SiteUsage{
+			SiteID: "s1", Date: today.AddDate(0, 0, i-2), PageViews: 10, StorageUsedMB: mb,
+		}).Error)
+	}
+
+	svc := NewTenantService(repository.NewSiteRepository(db), db, nil)
+	stats, err := svc.GetUsage(context.Background(), "s1", 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(30), stats.TotalPageViews)
+	assert.InDelta(t, 90, stats.StorageUsedMB, 0.001) // 스냅샷 합계(310)가 아니라 최신 값
+}
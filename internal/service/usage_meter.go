@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/damoang/angple-backend/internal/repository"
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 사용량 집계 Redis 키 (날짜별, 3일 보관)
+//
+//	usage:<yyyymmdd>:sites              SET  — 집계 대상 사이트
+//	usage:<yyyymmdd>:<site_id>          HASH — api_calls, page_views, bandwidth_bytes, posts_created, comments_created, storage_bytes(당일 증감, 음수 가능)
+//	usage:<yyyymmdd>:<site_id>:visitors HLL  — 순 방문자
+const (
+	usageKeyTTL        = 72 * time.Hour
+	usageFlushInterval = 5 * time.Minute
+
+	usageFieldAPICalls  = "api_calls"
+	usageFieldPageViews = "page_views"
+	usageFieldBandwidth = "bandwidth_bytes"
+	usageFieldPosts     = "posts_created"
+	usageFieldComments  = "comments_created"
+	usageFieldStorage   = "storage_bytes"
+)
+
+// UsageMeter meters per-tenant usage in Redis and rolls it up into daily SiteUsage rows.
+// 메인 사이트(사이트 ID 없음)는 집계하지 않는다. Redis가 없으면 모든 기록이 no-op이다.
+type UsageMeter struct {
+	db     *gorm.DB
+	redis  *redis.Client
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewUsageMeter creates a new UsageMeter
+func NewUsageMeter(db *gorm.DB, redisClient *redis.Client) *UsageMeter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &UsageMeter{db: db, redis: redisClient, ctx: ctx, cancel: cancel}
+}
+
+// RecordRequest records one API call (middleware.UsageMetering)
+func (m *UsageMeter) RecordRequest(ctx context.Context, siteID, visitor string, bytes int64, pageView bool) {
+	if m == nil || m.redis == nil || siteID == "" {
+		return
+	}
+	day := m.day()
+	key := usageKey(day, siteID)
+
+	pipe := m.redis.Pipeline()
+	pipe.SAdd(ctx, usageSitesKey(day), siteID)
+	pipe.HIncrBy(ctx, key, usageFieldAPICalls, 1)
+	if bytes > 0 {
+		pipe.HIncrBy(ctx, key, usageFieldBandwidth, bytes)
+	}
+	if pageView {
+		pipe.HIncrBy(ctx, key, usageFieldPageViews, 1)
+	}
+	if visitor != "" {
+		pipe.PFAdd(ctx, key+":visitors", visitor)
+		pipe.Expire(ctx, key+":visitors", usageKeyTTL)
+	}
+	pipe.Expire(ctx, key, usageKeyTTL)
+	pipe.Expire(ctx, usageSitesKey(day), usageKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		pkglogger.Info("[UsageMeter] record request failed for %s: %v", siteID, err)
+	}
+}
+
+// AddStorage records uploaded bytes for the request's site (MediaService)
+func (m *UsageMeter) AddStorage(ctx context.Context, bytes int64) {
+	m.incr(ctx, repository.SiteIDFromContext(ctx), usageFieldStorage, bytes)
+}
+
+// RemoveStorage records deleted bytes for a site (MediaLibrary 삭제/GC)
+func (m *UsageMeter) RemoveStorage(ctx context.Context, siteID string, bytes int64) {
+	if bytes <= 0 {
+		return
+	}
+	m.incrBy(ctx, siteID, usageFieldStorage, -bytes)
+}
+
+// RegisterHooks counts posts and comments from write events (post/comment.after_create의 site_id)
+func (m *UsageMeter) RegisterHooks(hm *plugin.HookManager) {
+	if m == nil || hm == nil {
+		return
+	}
+	counter := func(field string) plugin.HookHandler {
+		return func(hc *plugin.HookContext) error {
+			siteID, _ := hc.Input["site_id"].(string) //nolint:errcheck // type assertion, not error
+			m.incr(m.ctx, siteID, field, 1)
+			return nil
+		}
+	}
+	hm.Register(plugin.HookPostAfterCreate, "core:usage", counter(usageFieldPosts), 100)
+	hm.Register(plugin.HookCommentAfterCreate, "core:usage", counter(usageFieldComments), 100)
+}
+
+func (m *UsageMeter) incr(ctx context.Context, siteID, field string, n int64) {
+	if n <= 0 {
+		return
+	}
+	m.incrBy(ctx, siteID, field, n)
+}
+
+func (m *UsageMeter) incrBy(ctx context.Context, siteID, field string, n int64) {
+	if m == nil || m.redis == nil || siteID == "" {
+		return
+	}
+	day := m.day()
+	key := usageKey(day, siteID)
+
+	pipe := m.redis.Pipeline()
+	pipe.SAdd(ctx, usageSitesKey(day), siteID)
+	pipe.HIncrBy(ctx, key, field, n)
+	pipe.Expire(ctx, key, usageKeyTTL)
+	pipe.Expire(ctx, usageSitesKey(day), usageKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		pkglogger.Info("[UsageMeter] %s increment failed for %s: %v", field, siteID, err)
+	}
+}
+
+// Start 주기적으로 Flush (Redis가 없으면 시작하지 않음)
+func (m *UsageMeter) Start() {
+	if m == nil || m.redis == nil {
+		return
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(usageFlushInterval)
+		defer ticker.Stop()
+		pkglogger.Info("[UsageMeter] Started")
+		for {
+			select {
+			case <-m.ctx.Done():
+				// 종료 직전 마지막 반영
+				if err := m.Flush(context.Background()); err != nil {
+					pkglogger.Info("[UsageMeter] final flush failed: %v", err)
+				}
+				pkglogger.Info("[UsageMeter] Stopped")
+				return
+			case <-ticker.C:
+				if err := m.Flush(m.ctx); err != nil {
+					pkglogger.Info("[UsageMeter] flush failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop 플러셔 정지
+func (m *UsageMeter) Stop() {
+	if m == nil {
+		return
+	}
+	m.cancel()
+	m.wg.Wait()
+}
+
+// Flush writes yesterday's and today's Redis totals into SiteUsage rows.
+// Redis 값이 그날의 누적치이므로 같은 날을 여러 번 반영해도 결과가 같다 (upsert).
+func (m *UsageMeter) Flush(ctx context.Context) error {
+	if m == nil || m.redis == nil {
+		return nil
+	}
+	today := m.day()
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		siteIDs, err := m.redis.SMembers(ctx, usageSitesKey(day)).Result()
+		if err != nil {
+			return fmt.Errorf("usage sites: %w", err)
+		}
+		for _, siteID := range siteIDs {
+			if err := m.flushSite(ctx, day, siteID); err != nil {
+				pkglogger.Info("[UsageMeter] flush %s %s failed: %v", siteID, day.Format("2006-01-02"), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *UsageMeter) flushSite(ctx context.Context, day time.Time, siteID string) error {
+	key := usageKey(day, siteID)
+	fields, err := m.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	visitors, err := m.redis.PFCount(ctx, key+":visitors").Result()
+	if err != nil {
+		return err
+	}
+
+	// 저장 용량은 그날 끝 기준 스냅샷: 전날 행의 사용량 + 오늘 증감 (합산하지 말고 최신 행을 쓸 것)
+	var prev domain.SiteUsage
+	var prevStorageMB float64
+	err = m.db.WithContext(ctx).Where("site_id = ? AND date < ?", siteID, day).Order("date DESC").First(&prev).Error
+	if err == nil {
+		prevStorageMB = prev.StorageUsedMB
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	usage := domain.SiteUsage{
+		SiteID:          siteID,
+		Date:            day,
+		UniqueVisitors:  int(visitors),
+		PageViews:       int(usageField(fields, usageFieldPageViews)),
+		APICalls:        int(usageField(fields, usageFieldAPICalls)),
+		PostsCreated:    int(usageField(fields, usageFieldPosts)),
+		CommentsCreated: int(usageField(fields, usageFieldComments)),
+		BandwidthUsedMB: bytesToMB(usageField(fields, usageFieldBandwidth)),
+		StorageUsedMB:   max(prevStorageMB+bytesToMB(usageField(fields, usageFieldStorage)), 0),
+	}
+	return m.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "site_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"unique_visitors", "page_views", "api_calls", "posts_created",
+			"comments_created", "bandwidth_used_mb", "storage_used_mb",
+		}),
+	}).Create(&usage).Error
+}
+
+// day returns today's date (local midnight)
+func (m *UsageMeter) day() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+func usageSitesKey(day time.Time) string {
+	return "usage:" + day.Format("20060102") + ":sites"
+}
+
+func usageKey(day time.Time, siteID string) string {
+	return "usage:" + day.Format("20060102") + ":" + siteID
+}
+
+func usageField(fields map[string]string, name string) int64 {
+	n, _ := strconv.ParseInt(fields[name], 10, 64) //nolint:errcheck // missing field = 0
+	return n
+}
+
+func bytesToMB(n int64) float64 {
+	return float64(n) / (1024 * 1024)
+}
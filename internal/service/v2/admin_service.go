@@ -46,6 +46,13 @@ type DashboardStats struct {
 	RecentPosts   int64 `json:"recent_posts"`
 }
 
+// WithBoardRepository returns a copy that manages boards in the given repository (테넌트 DB)
+func (s *AdminService) WithBoardRepository(repo v2repo.BoardRepository) *AdminService {
+	cp := *s
+	cp.boardRepo = repo
+	return &cp
+}
+
 // ListAllBoards returns all boards including inactive ones
 func (s *AdminService) ListAllBoards() ([]*v2domain.V2Board, error) {
 	return s.boardRepo.FindAllIncludingInactive()
@@ -1,17 +1,21 @@
 package v2
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/damoang/angple-backend/internal/common"
 	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	"github.com/damoang/angple-backend/internal/plugin"
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
 	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/damoang/angple-backend/pkg/mailer"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -20,11 +24,25 @@ import (
 //
 //nolint:revive
 type V2AuthService struct {
-	userRepo   v2repo.UserRepository
-	jwtManager *jwt.Manager
-	expRepo    v2repo.ExpRepository
-	notiRepo   gnurepo.NotiRepository
-	db         *gorm.DB
+	userRepo    v2repo.UserRepository
+	jwtManager  *jwt.Manager
+	expRepo     v2repo.ExpRepository
+	notiRepo    gnurepo.NotiRepository
+	sessionRepo v2repo.SessionRepository
+	loginGuard  *LoginGuard
+	attemptRepo v2repo.LoginAttemptRepository
+	twoFactor   *TwoFactorService
+	db          *gorm.DB
+
+	// v2_users에 없는 계정(OAuth)의 토큰 갱신 (SetExternalAccountResolver)
+	externalAccounts ExternalAccountResolver
+
+	// 회원가입 / 메일 인증 / 비밀번호 재설정 (SetRegistration)
+	tokenRepo    v2repo.UserTokenRepository
+	mailer       mailer.Mailer
+	registration RegistrationPolicy
+	hooks        *plugin.HookManager
+	mailWG       sync.WaitGroup // 백그라운드 인증/재설정 메일
 }
 
 // NewV2AuthService creates a new V2AuthService
@@ -42,32 +60,58 @@ func (s *V2AuthService) SetPromotionDeps(db *gorm.DB, notiRepo gnurepo.NotiRepos
 	s.notiRepo = notiRepo
 }
 
-// V2LoginResponse represents v2 login response
+// SetLoginProtection sets brute-force protection and the login audit log (각각 nil이면 비활성)
+func (s *V2AuthService) SetLoginProtection(guard *LoginGuard, attemptRepo v2repo.LoginAttemptRepository) {
+	s.loginGuard = guard
+	s.attemptRepo = attemptRepo
+}
+
+// SetTwoFactorService enables TOTP second factor at login (nil이면 비활성)
+func (s *V2AuthService) SetTwoFactorService(twoFactor *TwoFactorService) {
+	s.twoFactor = twoFactor
+}
+
+// V2LoginResponse represents v2 login response.
+// 2단계 인증이 켜진 회원은 토큰 대신 TwoFactorRequired와 ChallengeToken만 채워진다.
 //
 //nolint:revive
 type V2LoginResponse struct {
-	User         *v2domain.V2User `json:"user"`
-	AccessToken  string           `json:"access_token"`
-	RefreshToken string           `json:"refresh_token"`
+	User              *v2domain.V2User `json:"user"`
+	AccessToken       string           `json:"access_token"`
+	RefreshToken      string           `json:"refresh_token"`
+	TwoFactorRequired bool             `json:"two_factor_required,omitempty"`
+	ChallengeToken    string           `json:"challenge_token,omitempty"`
 }
 
 // Login authenticates a user against v2_users table.
 // Supports both bcrypt (new) and legacy gnuboard password hashing (migrated users).
-func (s *V2AuthService) Login(username, password string) (*V2LoginResponse, error) {
+func (s *V2AuthService) Login(username, password string, client SessionClient) (*V2LoginResponse, error) {
+	ctx := context.Background()
+	if err := s.checkLoginAllowed(ctx, username, client); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.FindByUsername(username)
 	if err != nil {
-		return nil, common.ErrInvalidCredentials
+		return nil, s.loginFailed(ctx, username, nil, client)
 	}
 
 	// 이용제한 사용자도 로그인 가능 (소명게시판 접근 허용)
 	// banned 상태 체크 제거
 	if user.Status == "inactive" {
+		s.recordLoginAttempt(username, &user.ID, client, v2domain.LoginResultInactive)
 		return nil, errors.New("account is inactive")
 	}
 
 	// Try bcrypt first (new accounts), then legacy gnuboard hashing (migrated)
 	if !verifyPassword(password, user.Password) {
-		return nil, common.ErrInvalidCredentials
+		return nil, s.loginFailed(ctx, username, &user.ID, client)
+	}
+	s.loginGuard.Success(ctx, username)
+
+	if user.EmailPending && s.registration.RequireEmailVerification {
+		s.recordLoginAttempt(username, &user.ID, client, v2domain.LoginResultEmailUnverified)
+		return nil, ErrEmailNotVerified
 	}
 
 	// If the password is legacy format, upgrade to bcrypt (best-effort, non-blocking)
@@ -80,6 +124,58 @@ func (s *V2AuthService) Login(username, password string) (*V2LoginResponse, erro
 		}
 	}
 
+	userIDStr := strconv.FormatUint(user.ID, 10)
+	required, err := s.twoFactor.Enabled(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("check two-factor: %w", err)
+	}
+	if required {
+		challenge, err := s.twoFactor.Challenge(TwoFactorPurposeLogin, userIDStr, user.Username, user.Nickname, int(user.Level))
+		if err != nil {
+			return nil, fmt.Errorf("generate two-factor challenge: %w", err)
+		}
+		s.recordLoginAttempt(username, &user.ID, client, v2domain.LoginResultTwoFactorPending)
+		return &V2LoginResponse{TwoFactorRequired: true, ChallengeToken: challenge}, nil
+	}
+
+	return s.completeLogin(user, client, false)
+}
+
+// VerifyTwoFactor completes a login with the challenge token from Login and a TOTP or recovery code
+func (s *V2AuthService) VerifyTwoFactor(challengeToken, code string, client SessionClient) (*V2LoginResponse, error) {
+	if s.twoFactor == nil {
+		return nil, ErrTwoFactorUnavailable
+	}
+	claims, err := s.twoFactor.ParseChallenge(challengeToken, TwoFactorPurposeLogin)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := strconv.ParseUint(claims.UserID, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+
+	if err := s.twoFactor.Verify(claims.UserID, code); err != nil {
+		result := v2domain.LoginResultTwoFactorFailed
+		var le *common.LoginThrottleError
+		if errors.As(err, &le) {
+			result = v2domain.LoginResultLocked
+		}
+		s.recordLoginAttempt(claims.Username, &userID, client, result)
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.Status == "inactive" {
+		return nil, common.ErrUnauthorized
+	}
+	return s.completeLogin(user, client, true)
+}
+
+// completeLogin grants login rewards and issues tokens once all login factors have passed
+func (s *V2AuthService) completeLogin(user *v2domain.V2User, client SessionClient, mfa bool) (*V2LoginResponse, error) {
+	username := user.Username
+
 	// Grant daily login XP synchronously (mb_login_days must be updated before promotion check)
 	if s.expRepo != nil {
 		s.grantLoginXP(username)
@@ -95,16 +191,16 @@ func (s *V2AuthService) Login(username, password string) (*V2LoginResponse, erro
 	}
 
 	// Generate JWT tokens
-	userIDStr := strconv.FormatUint(user.ID, 10)
-	accessToken, err := s.jwtManager.GenerateAccessToken(userIDStr, user.Username, user.Nickname, level)
+	accessToken, err := s.generateAccessToken(user, level, mfa)
 	if err != nil {
 		return nil, fmt.Errorf("generate access token: %w", err)
 	}
-	refreshToken, err := s.jwtManager.GenerateRefreshToken(userIDStr)
+	refreshToken, err := s.issueRefreshToken(user.ID, client, mfa)
 	if err != nil {
 		return nil, fmt.Errorf("generate refresh token: %w", err)
 	}
 
+	s.recordLoginAttempt(username, &user.ID, client, v2domain.LoginResultSuccess)
 	return &V2LoginResponse{
 		User:         user,
 		AccessToken:  accessToken,
@@ -149,8 +245,8 @@ func (s *V2AuthService) grantLoginXP(username string) {
 	}
 }
 
-// RefreshToken validates a refresh token and issues new token pair
-func (s *V2AuthService) RefreshToken(refreshToken string) (*V2LoginResponse, error) {
+// RefreshToken validates a refresh token and issues new token pair (세션 사용 시 리프레시 토큰 회전)
+func (s *V2AuthService) RefreshToken(refreshToken string, _ SessionClient) (*V2LoginResponse, error) {
 	claims, err := s.jwtManager.VerifyToken(refreshToken)
 	if err != nil {
 		return nil, common.ErrUnauthorized
@@ -158,7 +254,7 @@ func (s *V2AuthService) RefreshToken(refreshToken string) (*V2LoginResponse, err
 
 	userID, err := strconv.ParseUint(claims.UserID, 10, 64)
 	if err != nil {
-		return nil, common.ErrUnauthorized
+		return s.refreshExternal(claims, refreshToken)
 	}
 
 	user, err := s.userRepo.FindByID(userID)
@@ -166,12 +262,14 @@ func (s *V2AuthService) RefreshToken(refreshToken string) (*V2LoginResponse, err
 		return nil, common.ErrUnauthorized
 	}
 
-	userIDStr := strconv.FormatUint(user.ID, 10)
-	newAccess, err := s.jwtManager.GenerateAccessToken(userIDStr, user.Username, user.Nickname, int(user.Level))
+	newAccess, err := s.generateAccessToken(user, int(user.Level), claims.MFA)
 	if err != nil {
 		return nil, fmt.Errorf("generate access token: %w", err)
 	}
-	newRefresh, err := s.jwtManager.GenerateRefreshToken(userIDStr)
+	newRefresh, err := s.rotateRefreshToken(claims, refreshToken)
+	if errors.Is(err, ErrRefreshTokenReused) || errors.Is(err, common.ErrUnauthorized) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("generate refresh token: %w", err)
 	}
@@ -183,6 +281,14 @@ func (s *V2AuthService) RefreshToken(refreshToken string) (*V2LoginResponse, err
 	}, nil
 }
 
+func (s *V2AuthService) generateAccessToken(user *v2domain.V2User, level int, mfa bool) (string, error) {
+	userIDStr := strconv.FormatUint(user.ID, 10)
+	if mfa {
+		return s.jwtManager.GenerateMFAAccessToken(userIDStr, user.Username, user.Nickname, level)
+	}
+	return s.jwtManager.GenerateAccessToken(userIDStr, user.Username, user.Nickname, level)
+}
+
 // GetCurrentUser returns the user for the given ID
 func (s *V2AuthService) GetCurrentUser(userID uint64) (*v2domain.V2User, error) {
 	return s.userRepo.FindByID(userID)
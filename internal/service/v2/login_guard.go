@@ -0,0 +1,298 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/common"
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+const loginGuardPrefix = "login_guard:"
+
+// 잠금 대상 종류
+const (
+	LockoutAccount = "account"
+	LockoutIP      = "ip"
+)
+
+// LoginGuardConfig brute-force protection thresholds
+type LoginGuardConfig struct {
+	Window         time.Duration // 실패 횟수 보관 기간 (마지막 실패 기준)
+	FreeAttempts   int           // 지연 없이 허용하는 계정별 실패 횟수
+	BaseDelay      time.Duration // 첫 지연, 이후 실패마다 2배
+	MaxDelay       time.Duration
+	CaptchaAfter   int // 계정별 실패가 이 횟수 이상이면 CAPTCHA 요구 신호
+	LockAfter      int // 계정 잠금 기준 실패 횟수
+	LockDuration   time.Duration
+	IPCaptchaAfter int // IP별 실패 (여러 계정 대상 credential stuffing)
+	IPLockAfter    int
+}
+
+// DefaultLoginGuardConfig returns the default thresholds
+func DefaultLoginGuardConfig() LoginGuardConfig {
+	return LoginGuardConfig{
+		Window:         time.Hour,
+		FreeAttempts:   3,
+		BaseDelay:      2 * time.Second,
+		MaxDelay:       5 * time.Minute,
+		CaptchaAfter:   3,
+		LockAfter:      10,
+		LockDuration:   15 * time.Minute,
+		IPCaptchaAfter: 10,
+		IPLockAfter:    50,
+	}
+}
+
+// LoginLockout is an active lockout as shown to admins
+type LoginLockout struct {
+	Type       string `json:"type"` // account | ip
+	Target     string `json:"target"`
+	RetryAfter int    `json:"retry_after"` // 초
+}
+
+// LoginGuard throttles login attempts with per-account and per-IP failure counters in Redis.
+// 계정별 실패는 지수 지연 후 잠금, IP별 실패는 CAPTCHA 신호 후 잠금으로 이어진다.
+// Redis 장애 시에는 로그만 남기고 로그인을 막지 않는다.
+type LoginGuard struct {
+	redis *redis.Client
+	cfg   LoginGuardConfig
+}
+
+// NewLoginGuard creates a LoginGuard (redisClient가 nil이면 nil — 보호 비활성)
+func NewLoginGuard(redisClient *redis.Client, cfg LoginGuardConfig) *LoginGuard {
+	if redisClient == nil {
+		return nil
+	}
+	return &LoginGuard{redis: redisClient, cfg: cfg}
+}
+
+func loginGuardKey(kind, target, name string) string {
+	return loginGuardPrefix + kind + ":" + target + ":" + name
+}
+
+func normalizeLoginName(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// Check refuses an attempt while the account or IP is locked or the account is in backoff
+func (g *LoginGuard) Check(ctx context.Context, username, ip string) error {
+	if g == nil {
+		return nil
+	}
+	name := normalizeLoginName(username)
+	pipe := g.redis.Pipeline()
+	acctLock := pipe.PTTL(ctx, loginGuardKey("lock", LockoutAccount, name))
+	ipLock := pipe.PTTL(ctx, loginGuardKey("lock", LockoutIP, ip))
+	delay := pipe.PTTL(ctx, loginGuardKey("delay", LockoutAccount, name))
+	acctFails := pipe.Get(ctx, loginGuardKey("fail", LockoutAccount, name))
+	ipFails := pipe.Get(ctx, loginGuardKey("fail", LockoutIP, ip))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("[v2-auth] login guard check failed: %v", err)
+		return nil
+	}
+
+	captcha := g.captchaRequired(int(intOrZero(acctFails)), int(intOrZero(ipFails)))
+	lock := max(acctLock.Val(), ipLock.Val())
+	if lock > 0 {
+		return &common.LoginThrottleError{Err: common.ErrLoginThrottled, Locked: true, RetryAfter: seconds(lock), CaptchaRequired: captcha}
+	}
+	if d := delay.Val(); d > 0 {
+		return &common.LoginThrottleError{Err: common.ErrLoginThrottled, RetryAfter: seconds(d), CaptchaRequired: captcha}
+	}
+	return nil
+}
+
+// Failure counts a failed attempt and returns the resulting backoff/lockout state
+func (g *LoginGuard) Failure(ctx context.Context, username, ip string) *common.LoginThrottleError {
+	if g == nil {
+		return nil
+	}
+	name := normalizeLoginName(username)
+	acctKey := loginGuardKey("fail", LockoutAccount, name)
+	ipKey := loginGuardKey("fail", LockoutIP, ip)
+
+	pipe := g.redis.TxPipeline()
+	acctFails := pipe.Incr(ctx, acctKey)
+	pipe.Expire(ctx, acctKey, g.cfg.Window)
+	ipFails := pipe.Incr(ctx, ipKey)
+	pipe.Expire(ctx, ipKey, g.cfg.Window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[v2-auth] login guard failure count failed: %v", err)
+		return nil
+	}
+
+	acct, ipCount := int(acctFails.Val()), int(ipFails.Val())
+	state := &common.LoginThrottleError{Err: common.ErrInvalidCredentials, CaptchaRequired: g.captchaRequired(acct, ipCount)}
+	var err error
+	switch {
+	case acct >= g.cfg.LockAfter:
+		state.Locked, state.RetryAfter = true, seconds(g.cfg.LockDuration)
+		err = g.redis.Set(ctx, loginGuardKey("lock", LockoutAccount, name), 1, g.cfg.LockDuration).Err()
+		log.Printf("[v2-auth] account %q locked after %d failed logins", name, acct)
+	case ipCount >= g.cfg.IPLockAfter:
+		state.Locked, state.RetryAfter = true, seconds(g.cfg.LockDuration)
+		err = g.redis.Set(ctx, loginGuardKey("lock", LockoutIP, ip), 1, g.cfg.LockDuration).Err()
+		log.Printf("[v2-auth] IP %s locked after %d failed logins", ip, ipCount)
+	default:
+		if d := backoffDelay(g.cfg, acct); d > 0 {
+			state.RetryAfter = seconds(d)
+			err = g.redis.Set(ctx, loginGuardKey("delay", LockoutAccount, name), 1, d).Err()
+		}
+	}
+	if err != nil {
+		log.Printf("[v2-auth] login guard lock failed: %v", err)
+	}
+	return state
+}
+
+// Success clears the account's failure state (IP 실패 횟수는 유지)
+func (g *LoginGuard) Success(ctx context.Context, username string) {
+	if g == nil {
+		return
+	}
+	name := normalizeLoginName(username)
+	if err := g.redis.Del(ctx,
+		loginGuardKey("fail", LockoutAccount, name),
+		loginGuardKey("delay", LockoutAccount, name),
+	).Err(); err != nil {
+		log.Printf("[v2-auth] login guard reset failed: %v", err)
+	}
+}
+
+// Lockouts lists active account and IP lockouts
+func (g *LoginGuard) Lockouts(ctx context.Context) ([]LoginLockout, error) {
+	lockouts := []LoginLockout{}
+	if g == nil {
+		return lockouts, nil
+	}
+	prefix := loginGuardPrefix + "lock:"
+	iter := g.redis.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		kind, target, ok := strings.Cut(strings.TrimPrefix(key, prefix), ":")
+		if !ok {
+			continue
+		}
+		ttl, err := g.redis.PTTL(ctx, key).Result()
+		if err != nil || ttl <= 0 {
+			continue
+		}
+		lockouts = append(lockouts, LoginLockout{Type: kind, Target: target, RetryAfter: seconds(ttl)})
+	}
+	return lockouts, iter.Err()
+}
+
+// Clear removes the lockout and failure state of an account and/or IP
+func (g *LoginGuard) Clear(ctx context.Context, username, ip string) error {
+	if g == nil {
+		return nil
+	}
+	var keys []string
+	if name := normalizeLoginName(username); name != "" {
+		for _, k := range []string{"fail", "delay", "lock"} {
+			keys = append(keys, loginGuardKey(k, LockoutAccount, name))
+		}
+	}
+	if ip != "" {
+		keys = append(keys, loginGuardKey("fail", LockoutIP, ip), loginGuardKey("lock", LockoutIP, ip))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return g.redis.Del(ctx, keys...).Err()
+}
+
+func (g *LoginGuard) captchaRequired(acctFails, ipFails int) bool {
+	return acctFails >= g.cfg.CaptchaAfter || ipFails >= g.cfg.IPCaptchaAfter
+}
+
+// backoffDelay returns the wait after the n-th consecutive failure (FreeAttempts 이후 2배씩 증가)
+func backoffDelay(cfg LoginGuardConfig, fails int) time.Duration {
+	over := fails - cfg.FreeAttempts
+	if over <= 0 {
+		return 0
+	}
+	if over > 30 {
+		return cfg.MaxDelay
+	}
+	return min(cfg.BaseDelay<<(over-1), cfg.MaxDelay)
+}
+
+func intOrZero(cmd *redis.StringCmd) int64 {
+	n, err := cmd.Int64()
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// checkLoginAllowed refuses throttled attempts before the password is checked
+func (s *V2AuthService) checkLoginAllowed(ctx context.Context, username string, client SessionClient) error {
+	err := s.loginGuard.Check(ctx, username, client.IPAddress)
+	if err == nil {
+		return nil
+	}
+	result := v2domain.LoginResultThrottled
+	var le *common.LoginThrottleError
+	if errors.As(err, &le) && le.Locked {
+		result = v2domain.LoginResultLocked
+	}
+	s.recordLoginAttempt(username, nil, client, result)
+	return err
+}
+
+// loginFailed records a failed attempt and returns the error with backoff/CAPTCHA hints
+func (s *V2AuthService) loginFailed(ctx context.Context, username string, userID *uint64, client SessionClient) error {
+	s.recordLoginAttempt(username, userID, client, v2domain.LoginResultFailed)
+	if state := s.loginGuard.Failure(ctx, username, client.IPAddress); state != nil {
+		return state
+	}
+	return common.ErrInvalidCredentials
+}
+
+// recordLoginAttempt appends a login audit log entry (best-effort)
+func (s *V2AuthService) recordLoginAttempt(username string, userID *uint64, client SessionClient, result string) {
+	if s.attemptRepo == nil {
+		return
+	}
+	attempt := &v2domain.V2LoginAttempt{
+		Username:  truncate(username, 50),
+		UserID:    userID,
+		IPAddress: client.IPAddress,
+		Result:    result,
+	}
+	if ua := truncate(client.UserAgent, maxSessionUserAgent); ua != "" {
+		attempt.UserAgent = &ua
+	}
+	if err := s.attemptRepo.Create(attempt); err != nil {
+		log.Printf("[v2-auth] login audit failed for %s: %v", username, err)
+	}
+}
+
+// ListLockouts lists active login lockouts (관리자)
+func (s *V2AuthService) ListLockouts(ctx context.Context) ([]LoginLockout, error) {
+	return s.loginGuard.Lockouts(ctx)
+}
+
+// ClearLockout clears the lockout of an account and/or IP (관리자)
+func (s *V2AuthService) ClearLockout(ctx context.Context, username, ip string) error {
+	return s.loginGuard.Clear(ctx, username, ip)
+}
+
+// ListLoginAttempts lists the login audit log (관리자)
+func (s *V2AuthService) ListLoginAttempts(username, ip string, page, limit int) ([]*v2domain.V2LoginAttempt, int64, error) {
+	if s.attemptRepo == nil {
+		return []*v2domain.V2LoginAttempt{}, 0, nil
+	}
+	return s.attemptRepo.FindAll(username, ip, page, limit)
+}
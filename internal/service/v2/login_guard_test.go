@@ -0,0 +1,27 @@
+package v2
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	cfg := DefaultLoginGuardConfig()
+	cases := []struct {
+		fails int
+		want  time.Duration
+	}{
+		{1, 0},
+		{3, 0},
+		{4, 2 * time.Second},
+		{5, 4 * time.Second},
+		{8, 32 * time.Second},
+		{12, 5 * time.Minute},
+		{100, 5 * time.Minute},
+	}
+	for _, tc := range cases {
+		if got := backoffDelay(cfg, tc.fails); got != tc.want {
+			t.Errorf("backoffDelay(%d) = %s, want %s", tc.fails, got, tc.want)
+		}
+	}
+}
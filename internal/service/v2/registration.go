@@ -0,0 +1,513 @@
+package v2
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	"github.com/damoang/angple-backend/internal/plugin"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/mailer"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// 회원가입/메일 인증/비밀번호 재설정 에러
+var (
+	ErrRegistrationClosed = errors.New("registration is closed")
+	ErrEmailNotVerified   = errors.New("email address not verified")
+	ErrInvalidUserToken   = errors.New("invalid or expired token")
+	// ErrRegistrationUnavailable 토큰 저장소가 설정되지 않음
+	ErrRegistrationUnavailable = errors.New("registration is not available")
+)
+
+const (
+	verifyEmailTokenTTL   = 24 * time.Hour
+	resetPasswordTokenTTL = time.Hour
+	userTokenMailLimit    = 5 // 회원·용도별 시간당 메일 발송 한도 (메일 폭탄 방지)
+	minPasswordLength     = 8
+	maxPasswordLength     = 72 // bcrypt 입력 한도
+	defaultRegisterLevel  = 2
+)
+
+// DefaultReservedNames 그누보드 기본 가입 금지 아이디/닉네임 (cf_prohibit_id)
+var DefaultReservedNames = []string{
+	"admin", "administrator", "관리자", "운영자", "어드민", "주인장", "webmaster", "웹마스터",
+	"sysop", "시삽", "시샵", "manager", "매니저", "메니저", "root", "루트", "su", "guest", "방문객",
+}
+
+// g5_member 규칙: 아이디는 영문자·숫자·_ 3~20자, 닉네임은 공백 없이 한글·영문·숫자
+var (
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,20}$`)
+	nicknamePattern = regexp.MustCompile(`^[0-9A-Za-z가-힣]+$`)
+)
+
+// RegistrationPolicy is the site-wide sign-up policy
+type RegistrationPolicy struct {
+	Closed                   bool
+	Level                    int // 가입 시 회원 레벨 (0이면 2)
+	RequireEmailVerification bool
+	LinkBaseURL              string   // 메일 링크의 프론트엔드 주소
+	ReservedNames            []string // 비어 있으면 DefaultReservedNames
+	BlockedEmailDomains      []string
+}
+
+// RegistrationCheckResult is returned by CheckRegistration to indicate whether the values can be used.
+type RegistrationCheckResult struct {
+	CanRegister bool   `json:"can_register"`
+	Field       string `json:"field,omitempty"` // 처음 실패한 항목 (username, nickname, email, password)
+	Reason      string `json:"reason,omitempty"`
+	Conflict    bool   `json:"-"` // 이미 사용 중 (형식 오류가 아님)
+}
+
+// RegistrationError rejects a sign-up field
+type RegistrationError struct {
+	*RegistrationCheckResult
+}
+
+func (e *RegistrationError) Error() string {
+	return fmt.Sprintf("registration rejected (%s): %s", e.Field, e.Reason)
+}
+
+// RegisterRequest is the sign-up input
+type RegisterRequest struct {
+	Username  string
+	Nickname  string
+	Email     string
+	Password  string
+	IPAddress string
+}
+
+// RegisterResult is returned after sign-up
+type RegisterResult struct {
+	User             *v2domain.V2User `json:"user"`
+	VerificationSent bool             `json:"verification_sent"`
+}
+
+// SetRegistration enables sign-up, email verification and password reset (mailer가 nil이면 메일 인증 없음)
+func (s *V2AuthService) SetRegistration(tokenRepo v2repo.UserTokenRepository, m mailer.Mailer, policy RegistrationPolicy) {
+	if policy.Level <= 0 {
+		policy.Level = defaultRegisterLevel
+	}
+	if len(policy.ReservedNames) == 0 {
+		policy.ReservedNames = DefaultReservedNames
+	}
+	s.tokenRepo = tokenRepo
+	s.mailer = m
+	s.registration = policy
+}
+
+// SetHookManager sets the plugin hooks run on sign-up (user.before_register / user.after_register)
+func (s *V2AuthService) SetHookManager(hm *plugin.HookManager) {
+	s.hooks = hm
+}
+
+// CheckRegistration validates the given sign-up values (빈 값은 검사하지 않음, 가입 폼 실시간 확인용)
+// 인증 없이 호출되므로 이메일은 형식과 도메인만 검사하고 사용 중인지는 알려주지 않는다 (중복은 가입 시 409).
+func (s *V2AuthService) CheckRegistration(req *RegisterRequest) (*RegistrationCheckResult, error) {
+	return s.checkRegistration(req, s.checkEmailFormat)
+}
+
+func (s *V2AuthService) checkRegistration(req *RegisterRequest, checkEmail func(string) (*RegistrationCheckResult, error)) (*RegistrationCheckResult, error) {
+	if s.registration.Closed {
+		return &RegistrationCheckResult{Reason: "현재 회원가입을 받지 않습니다"}, nil
+	}
+	checks := []struct {
+		value string
+		check func(string) (*RegistrationCheckResult, error)
+	}{
+		{req.Username, s.checkUsername},
+		{req.Nickname, s.checkNickname},
+		{req.Email, checkEmail},
+		{req.Password, checkPassword},
+	}
+	for _, c := range checks {
+		if c.value == "" {
+			continue
+		}
+		result, err := c.check(c.value)
+		if err != nil || !result.CanRegister {
+			return result, err
+		}
+	}
+	return &RegistrationCheckResult{CanRegister: true}, nil
+}
+
+// Register creates a member after policy checks and the user.before_register hook.
+// 메일이 설정되어 있으면 인증 메일을 보내며, 발송 실패는 재발송으로 복구할 수 있어 가입을 실패시키지 않는다.
+func (s *V2AuthService) Register(req *RegisterRequest) (*RegisterResult, error) {
+	if s.tokenRepo == nil {
+		return nil, ErrRegistrationUnavailable
+	}
+	if s.registration.Closed {
+		return nil, ErrRegistrationClosed
+	}
+	req.Username = strings.TrimSpace(req.Username)
+	req.Nickname = strings.TrimSpace(req.Nickname)
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	for _, f := range []struct{ name, value string }{
+		{"username", req.Username}, {"nickname", req.Nickname}, {"email", req.Email}, {"password", req.Password},
+	} {
+		if f.value == "" {
+			return nil, &RegistrationError{rejectField(f.name, "필수 항목입니다")}
+		}
+	}
+	check, err := s.checkRegistration(req, s.checkEmail)
+	if err != nil {
+		return nil, err
+	}
+	if !check.CanRegister {
+		return nil, &RegistrationError{check}
+	}
+
+	// 플러그인 Before Hook (스팸 가입 차단 등, 거부 시 *plugin.HookRejectError)
+	if s.hooks != nil {
+		if _, err := s.hooks.ApplyBefore(plugin.HookUserBeforeRegister, map[string]interface{}{
+			"username":   req.Username,
+			"nickname":   req.Nickname,
+			"email":      req.Email,
+			"ip_address": req.IPAddress,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	user := &v2domain.V2User{
+		Username:     req.Username,
+		Email:        req.Email,
+		Password:     string(hash),
+		Nickname:     req.Nickname,
+		Level:        uint8(s.registration.Level), //nolint:gosec // 회원 레벨은 1~10
+		Status:       "active",
+		EmailPending: s.mailer != nil,
+	}
+	if err := s.userRepo.Create(user); errors.Is(err, v2repo.ErrUserExists) {
+		// 검사 이후 같은 아이디/이메일로 먼저 가입된 경우
+		if check, checkErr := s.checkRegistration(req, s.checkEmail); checkErr == nil && !check.CanRegister {
+			return nil, &RegistrationError{check}
+		}
+		return nil, &RegistrationError{conflictField("username", "이미 가입된 회원 정보입니다")}
+	} else if err != nil {
+		return nil, fmt.Errorf("create user: %w", err)
+	}
+
+	return &RegisterResult{User: user, VerificationSent: s.afterRegister(user)}, nil
+}
+
+// afterRegister sends the verification mail and runs user.after_register (메일 발송 여부 반환)
+func (s *V2AuthService) afterRegister(user *v2domain.V2User) bool {
+	sent := false
+	if s.mailer != nil {
+		if err := s.sendUserToken(user, v2domain.UserTokenVerifyEmail); err != nil {
+			log.Printf("[v2-auth] verification mail failed for %s: %v", user.Username, err)
+		} else {
+			sent = true
+		}
+	}
+	if s.hooks != nil {
+		s.hooks.Do(plugin.HookUserAfterRegister, map[string]interface{}{
+			"user_id":  user.ID,
+			"username": user.Username,
+			"nickname": user.Nickname,
+			"email":    user.Email,
+		})
+	}
+	return sent
+}
+
+// VerifyEmail consumes a verification token and marks the member's email verified
+func (s *V2AuthService) VerifyEmail(token string) (*v2domain.V2User, error) {
+	user, err := s.consumeUserToken(v2domain.UserTokenVerifyEmail, token)
+	if err != nil {
+		return nil, err
+	}
+	if user.EmailPending {
+		user.EmailPending = false
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// ResendVerification queues a new verification mail.
+// 가입 여부가 응답이나 응답 시간으로 드러나지 않도록 조회와 발송은 백그라운드에서 처리한다.
+func (s *V2AuthService) ResendVerification(email string) error {
+	if s.tokenRepo == nil || s.mailer == nil {
+		return ErrRegistrationUnavailable
+	}
+	s.mailInBackground(email, v2domain.UserTokenVerifyEmail)
+	return nil
+}
+
+// RequestPasswordReset queues a password reset mail (가입 여부와 관계없이 같은 응답, ResendVerification 참고)
+func (s *V2AuthService) RequestPasswordReset(email string) error {
+	if s.tokenRepo == nil || s.mailer == nil {
+		return ErrRegistrationUnavailable
+	}
+	s.mailInBackground(email, v2domain.UserTokenResetPassword)
+	return nil
+}
+
+// WaitPendingMail blocks until queued verification/reset mails are sent (종료 시 호출)
+func (s *V2AuthService) WaitPendingMail() {
+	s.mailWG.Wait()
+}
+
+func (s *V2AuthService) mailInBackground(email, purpose string) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	s.mailWG.Add(1)
+	go func() {
+		defer s.mailWG.Done()
+		if err := s.mailUserToken(email, purpose); err != nil {
+			log.Printf("[v2-auth] %s mail failed: %v", purpose, err)
+		}
+	}()
+}
+
+// mailUserToken sends a token mail to the member with email (없는 메일, 인증 완료, 비활성 회원은 건너뜀)
+func (s *V2AuthService) mailUserToken(email, purpose string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	switch {
+	case purpose == v2domain.UserTokenVerifyEmail && !user.EmailPending:
+		return nil
+	case purpose == v2domain.UserTokenResetPassword && user.Status == "inactive":
+		return nil
+	}
+	return s.sendUserToken(user, purpose)
+}
+
+// ResetPassword sets a new password with a reset token.
+// 모든 세션을 종료하고 로그인 잠금을 해제하며, 메일 소유가 확인되었으므로 메일 인증도 완료 처리한다.
+func (s *V2AuthService) ResetPassword(token, password string) error {
+	if check, _ := checkPassword(password); !check.CanRegister { //nolint:errcheck // checkPassword never fails
+		return &RegistrationError{check}
+	}
+	user, err := s.consumeUserToken(v2domain.UserTokenResetPassword, token)
+	if err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hash)
+	user.EmailPending = false
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.InvalidatePending(user.ID, v2domain.UserTokenResetPassword); err != nil {
+		log.Printf("[v2-auth] reset token cleanup failed for %s: %v", user.Username, err)
+	}
+	if _, err := s.RevokeOtherSessions(user.ID, ""); err != nil {
+		log.Printf("[v2-auth] session revoke after password reset failed for %s: %v", user.Username, err)
+	}
+	if err := s.loginGuard.Clear(context.Background(), user.Username, ""); err != nil {
+		log.Printf("[v2-auth] lockout clear after password reset failed for %s: %v", user.Username, err)
+	}
+	return nil
+}
+
+// sendUserToken replaces the member's pending tokens for purpose and mails a new one
+func (s *V2AuthService) sendUserToken(user *v2domain.V2User, purpose string) error {
+	sent, err := s.tokenRepo.CountSince(user.ID, purpose, time.Now().Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if sent >= userTokenMailLimit {
+		return fmt.Errorf("%s mail limit reached", purpose)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(b)
+	ttl := verifyEmailTokenTTL
+	if purpose == v2domain.UserTokenResetPassword {
+		ttl = resetPasswordTokenTTL
+	}
+	if err := s.tokenRepo.InvalidatePending(user.ID, purpose); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.Create(&v2domain.V2UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashUserToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	return s.mailer.Send(ctx, s.userTokenMessage(user, purpose, token, ttl))
+}
+
+func (s *V2AuthService) userTokenMessage(user *v2domain.V2User, purpose, token string, ttl time.Duration) *mailer.Message {
+	base := strings.TrimRight(s.registration.LinkBaseURL, "/")
+	if purpose == v2domain.UserTokenResetPassword {
+		return &mailer.Message{
+			To:      user.Email,
+			Subject: "비밀번호 재설정 안내",
+			Body: fmt.Sprintf("%s님, 아래 링크에서 새 비밀번호를 설정해주세요. (%d분 동안 유효)\n\n%s/auth/reset-password?token=%s\n\n"+
+				"본인이 요청하지 않았다면 이 메일을 무시하셔도 됩니다.\n", user.Nickname, int(ttl.Minutes()), base, token),
+		}
+	}
+	return &mailer.Message{
+		To:      user.Email,
+		Subject: "이메일 주소 인증",
+		Body: fmt.Sprintf("%s님, 가입을 환영합니다. 아래 링크에서 이메일 주소를 인증해주세요. (%d시간 동안 유효)\n\n%s/auth/verify-email?token=%s\n",
+			user.Nickname, int(ttl.Hours()), base, token),
+	}
+}
+
+// consumeUserToken marks a token used and loads its member
+func (s *V2AuthService) consumeUserToken(purpose, token string) (*v2domain.V2User, error) {
+	if s.tokenRepo == nil {
+		return nil, ErrRegistrationUnavailable
+	}
+	if token == "" {
+		return nil, ErrInvalidUserToken
+	}
+	t, err := s.tokenRepo.Consume(purpose, hashUserToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidUserToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(t.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidUserToken
+	}
+	return user, err
+}
+
+func (s *V2AuthService) checkUsername(username string) (*RegistrationCheckResult, error) {
+	if !usernamePattern.MatchString(username) {
+		return rejectField("username", "아이디는 영문자, 숫자, _ 만 3~20자로 입력해주세요"), nil
+	}
+	if s.isReservedName(username) {
+		return rejectField("username", "사용할 수 없는 아이디입니다"), nil
+	}
+	if _, err := s.userRepo.FindByUsername(username); err == nil {
+		return conflictField("username", "이미 사용 중인 아이디입니다"), nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if taken, err := s.legacyMemberExists("mb_id", username); err != nil || taken {
+		return conflictField("username", "이미 사용 중인 아이디입니다"), err
+	}
+	return &RegistrationCheckResult{CanRegister: true}, nil
+}
+
+// checkNickname 그누보드 규칙: 공백 없이 한글·영문·숫자, 한글 2글자(영문 4글자) 이상
+func (s *V2AuthService) checkNickname(nickname string) (*RegistrationCheckResult, error) {
+	if !nicknamePattern.MatchString(nickname) {
+		return rejectField("nickname", "닉네임은 공백없이 한글, 영문, 숫자만 입력 가능합니다"), nil
+	}
+	if len(nickname) < 4 || utf8.RuneCountInString(nickname) > 20 {
+		return rejectField("nickname", "닉네임은 한글 2글자, 영문 4글자 이상 20자 이하로 입력해주세요"), nil
+	}
+	if s.isReservedName(nickname) {
+		return rejectField("nickname", "사용할 수 없는 닉네임입니다"), nil
+	}
+	if _, err := s.userRepo.FindByNickname(nickname); err == nil {
+		return conflictField("nickname", "이미 사용 중인 닉네임입니다"), nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if taken, err := s.legacyMemberExists("mb_nick", nickname); err != nil || taken {
+		return conflictField("nickname", "이미 사용 중인 닉네임입니다"), err
+	}
+	return &RegistrationCheckResult{CanRegister: true}, nil
+}
+
+func (s *V2AuthService) checkEmail(email string) (*RegistrationCheckResult, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if result, _ := s.checkEmailFormat(email); !result.CanRegister { //nolint:errcheck // checkEmailFormat never fails
+		return result, nil
+	}
+	if _, err := s.userRepo.FindByEmail(email); err == nil {
+		return conflictField("email", "이미 사용 중인 이메일 주소입니다"), nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return &RegistrationCheckResult{CanRegister: true}, nil
+}
+
+// checkEmailFormat checks the address and blocked domains without looking up members
+func (s *V2AuthService) checkEmailFormat(email string) (*RegistrationCheckResult, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > 255 {
+		return rejectField("email", "올바른 이메일 주소를 입력해주세요"), nil
+	}
+	domain := email[strings.LastIndex(email, "@")+1:]
+	for _, blocked := range s.registration.BlockedEmailDomains {
+		if strings.EqualFold(domain, strings.TrimSpace(blocked)) {
+			return rejectField("email", "가입할 수 없는 메일 도메인입니다"), nil
+		}
+	}
+	return &RegistrationCheckResult{CanRegister: true}, nil
+}
+
+func checkPassword(password string) (*RegistrationCheckResult, error) {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return rejectField("password", fmt.Sprintf("비밀번호는 %d자 이상 %d자 이하로 입력해주세요", minPasswordLength, maxPasswordLength)), nil
+	}
+	return &RegistrationCheckResult{CanRegister: true}, nil
+}
+
+func (s *V2AuthService) isReservedName(name string) bool {
+	for _, reserved := range s.registration.ReservedNames {
+		if strings.EqualFold(name, strings.TrimSpace(reserved)) {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyMemberExists 그누보드 g5_member에서 이미 쓰는 아이디/닉네임인지 확인 (이관 전 회원과의 충돌 방지)
+func (s *V2AuthService) legacyMemberExists(column, value string) (bool, error) {
+	if s.db == nil || !s.db.Migrator().HasTable("g5_member") {
+		return false, nil
+	}
+	var count int64
+	err := s.db.Table("g5_member").Where(column+" = ?", value).Count(&count).Error
+	return count > 0, err
+}
+
+func rejectField(field, reason string) *RegistrationCheckResult {
+	return &RegistrationCheckResult{Field: field, Reason: reason}
+}
+
+func conflictField(field, reason string) *RegistrationCheckResult {
+	return &RegistrationCheckResult{Field: field, Reason: reason, Conflict: true}
+}
+
+func hashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,238 @@
+package v2
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/common"
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/jwt"
+)
+
+// 세션 에러
+var (
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+	ErrSessionNotFound    = errors.New("session not found")
+)
+
+const maxSessionUserAgent = 500
+
+// SessionClient is the login client recorded on a session (기기 목록 표시용)
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
+// V2SessionInfo is a session as listed to its member
+//
+//nolint:revive
+type V2SessionInfo struct {
+	*v2domain.V2Session
+	Current bool `json:"current"`
+}
+
+// ExternalAccountResolver looks up a member that is not a v2 user (OAuth 계정) when refreshing its tokens
+type ExternalAccountResolver interface {
+	ExternalAccount(subject string) (nickname string, level int, err error)
+}
+
+// SetExternalAccountResolver enables token refresh for OAuth members (nil이면 v2 회원만 갱신)
+func (s *V2AuthService) SetExternalAccountResolver(r ExternalAccountResolver) {
+	s.externalAccounts = r
+}
+
+// SetSessionRepository enables server-side sessions (설정하지 않으면 무상태 JWT 리프레시 토큰 사용)
+func (s *V2AuthService) SetSessionRepository(repo v2repo.SessionRepository) {
+	s.sessionRepo = repo
+}
+
+// issueRefreshToken creates a session for a new login and returns its refresh token
+func (s *V2AuthService) issueRefreshToken(userID uint64, client SessionClient, mfa bool) (string, error) {
+	return s.createSession(strconv.FormatUint(userID, 10), &v2domain.V2Session{UserID: userID}, client, mfa)
+}
+
+// IssueExternalRefreshToken creates a session for an OAuth login and returns its refresh token
+// v2 회원과 같은 회전·재사용 탐지를 거치며, 세션은 JWT subject로 구분한다.
+func (s *V2AuthService) IssueExternalRefreshToken(subject string, client SessionClient, mfa bool) (string, error) {
+	return s.createSession(subject, &v2domain.V2Session{Subject: subject}, client, mfa)
+}
+
+func (s *V2AuthService) createSession(subject string, session *v2domain.V2Session, client SessionClient, mfa bool) (string, error) {
+	if s.sessionRepo == nil {
+		return s.jwtManager.GenerateSessionRefreshToken(subject, "", mfa)
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		return "", err
+	}
+	token, err := s.jwtManager.GenerateSessionRefreshToken(subject, id, mfa)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	session.ID = id
+	session.RefreshHash = hashRefreshToken(token)
+	session.ExpiresAt = now.Add(s.jwtManager.RefreshExpiry())
+	session.LastUsedAt = now
+	if ua := truncate(client.UserAgent, maxSessionUserAgent); ua != "" {
+		session.UserAgent = &ua
+	}
+	if client.IPAddress != "" {
+		session.IPAddress = &client.IPAddress
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// rotateRefreshToken replaces the session's refresh token.
+// 이미 회전된 토큰이 다시 제시되면 탈취로 보고 세션(family) 전체를 폐기한다.
+func (s *V2AuthService) rotateRefreshToken(claims *jwt.Claims, oldToken string) (string, error) {
+	if s.sessionRepo == nil {
+		return s.jwtManager.GenerateSessionRefreshToken(claims.UserID, "", claims.MFA)
+	}
+	// 세션 없이 발급된 토큰(세션 도입 전, 레거시 SSO)은 폐기 기록이 없어 재사용을 막을 수 없으므로 거부 — 다시 로그인
+	if claims.SessionID == "" {
+		return "", common.ErrUnauthorized
+	}
+
+	token, err := s.jwtManager.GenerateSessionRefreshToken(claims.UserID, claims.SessionID, claims.MFA)
+	if err != nil {
+		return "", err
+	}
+	rotated, err := s.sessionRepo.Rotate(claims.SessionID, hashRefreshToken(oldToken), hashRefreshToken(token),
+		time.Now().Add(s.jwtManager.RefreshExpiry()))
+	if err != nil {
+		return "", err
+	}
+	if rotated {
+		return token, nil
+	}
+
+	session, err := s.sessionRepo.FindByID(claims.SessionID)
+	if err != nil || session.RevokedAt != nil {
+		return "", common.ErrUnauthorized
+	}
+	if err := s.sessionRepo.Revoke(session.ID); err != nil {
+		log.Printf("[v2-auth] revoke reused session %s failed: %v", session.ID, err)
+	}
+	log.Printf("[v2-auth] refresh token reuse detected for user %s, session %s revoked", claims.UserID, session.ID)
+	return "", ErrRefreshTokenReused
+}
+
+// refreshExternal refreshes the tokens of an OAuth member (v2 회원 정보가 없으므로 User는 nil)
+func (s *V2AuthService) refreshExternal(claims *jwt.Claims, refreshToken string) (*V2LoginResponse, error) {
+	if s.externalAccounts == nil {
+		return nil, common.ErrUnauthorized
+	}
+	nickname, level, err := s.externalAccounts.ExternalAccount(claims.UserID)
+	if err != nil {
+		return nil, common.ErrUnauthorized
+	}
+	generate := s.jwtManager.GenerateAccessToken
+	if claims.MFA {
+		generate = s.jwtManager.GenerateMFAAccessToken
+	}
+	newAccess, err := generate(claims.UserID, claims.UserID, nickname, level)
+	if err != nil {
+		return nil, fmt.Errorf("generate access token: %w", err)
+	}
+	newRefresh, err := s.rotateRefreshToken(claims, refreshToken)
+	if errors.Is(err, ErrRefreshTokenReused) || errors.Is(err, common.ErrUnauthorized) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("generate refresh token: %w", err)
+	}
+	return &V2LoginResponse{AccessToken: newAccess, RefreshToken: newRefresh}, nil
+}
+
+// Logout revokes the session of a refresh token (만료/무효 토큰은 무시)
+func (s *V2AuthService) Logout(refreshToken string) error {
+	sessionID := s.SessionIDFromToken(refreshToken)
+	if sessionID == "" {
+		return nil
+	}
+	return s.sessionRepo.Revoke(sessionID)
+}
+
+// SessionIDFromToken returns the session ID of a valid refresh token ("" if none)
+func (s *V2AuthService) SessionIDFromToken(refreshToken string) string {
+	if s.sessionRepo == nil || refreshToken == "" {
+		return ""
+	}
+	claims, err := s.jwtManager.VerifyToken(refreshToken)
+	if err != nil {
+		return ""
+	}
+	return claims.SessionID
+}
+
+// ListSessions lists a member's active sessions
+func (s *V2AuthService) ListSessions(userID uint64, currentID string) ([]V2SessionInfo, error) {
+	if s.sessionRepo == nil {
+		return []V2SessionInfo{}, nil
+	}
+	sessions, err := s.sessionRepo.FindActiveByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]V2SessionInfo, len(sessions))
+	for i, session := range sessions {
+		infos[i] = V2SessionInfo{V2Session: session, Current: session.ID == currentID}
+	}
+	return infos, nil
+}
+
+// RevokeSession terminates one of a member's sessions
+func (s *V2AuthService) RevokeSession(userID uint64, sessionID string) error {
+	if s.sessionRepo == nil {
+		return ErrSessionNotFound
+	}
+	revoked, err := s.sessionRepo.RevokeForUser(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeOtherSessions terminates all of a member's sessions except the current one
+func (s *V2AuthService) RevokeOtherSessions(userID uint64, currentID string) (int64, error) {
+	if s.sessionRepo == nil {
+		return 0, nil
+	}
+	return s.sessionRepo.RevokeOthers(userID, currentID)
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return strings.ToValidUTF8(s[:n], "")
+	}
+	return s
+}
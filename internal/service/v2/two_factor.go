@@ -0,0 +1,312 @@
+package v2
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/common"
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/damoang/angple-backend/pkg/totp"
+	"gorm.io/gorm"
+)
+
+// 2단계 인증 에러
+var (
+	ErrTwoFactorEnabled    = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication not enabled")
+	ErrTwoFactorNotPending = errors.New("two-factor enrollment not started")
+	ErrInvalidOTP          = errors.New("invalid verification code")
+	ErrInvalidChallenge    = errors.New("invalid or expired two-factor challenge")
+	// ErrTwoFactorUnavailable TwoFactorService가 설정되지 않음
+	ErrTwoFactorUnavailable = errors.New("two-factor authentication is not available")
+)
+
+// 2단계 인증 대기 토큰 용도 (비밀번호 로그인 / OAuth 콜백)
+const (
+	TwoFactorPurposeLogin = "2fa:login"
+	TwoFactorPurposeOAuth = "2fa:oauth"
+)
+
+const (
+	// TwoFactorChallengeTTL 1단계 인증 후 OTP 입력까지 허용하는 시간
+	TwoFactorChallengeTTL = 5 * time.Minute
+	recoveryCodeCount     = 10
+	otpSkew               = 1 // 앞뒤 30초 허용 (기기 시계 오차)
+	otpLockAfter          = 5
+	otpLockDuration       = 15 * time.Minute
+)
+
+// TwoFactorStatus is a member's 2FA state
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorSetup is returned when enrollment starts (secret는 QR 코드를 못 찍는 경우 수동 입력용)
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// TwoFactorService manages TOTP enrollment, recovery codes and login challenges.
+// v2 로그인과 OAuth 로그인이 함께 사용하므로 회원은 JWT user_id 문자열로 식별한다.
+type TwoFactorService struct {
+	repo       v2repo.TwoFactorRepository
+	jwtManager *jwt.Manager
+	issuer     string
+	now        func() time.Time
+}
+
+// NewTwoFactorService creates a TwoFactorService (issuer는 OTP 앱에 표시되는 서비스 이름)
+func NewTwoFactorService(repo v2repo.TwoFactorRepository, jwtManager *jwt.Manager, issuer string) *TwoFactorService {
+	return &TwoFactorService{repo: repo, jwtManager: jwtManager, issuer: issuer, now: time.Now}
+}
+
+// Enabled reports whether the member must pass a second factor at login (nil 서비스면 항상 false)
+func (s *TwoFactorService) Enabled(userID string) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+	tf, err := s.repo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return tf.EnabledAt != nil, nil
+}
+
+// Status returns the member's 2FA state
+func (s *TwoFactorService) Status(userID string) (*TwoFactorStatus, error) {
+	tf, err := s.repo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && tf.EnabledAt == nil) {
+		return &TwoFactorStatus{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := s.repo.CountRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &TwoFactorStatus{Enabled: true, EnabledAt: tf.EnabledAt, RecoveryCodesRemaining: remaining}, nil
+}
+
+// Setup starts enrollment with a new secret. Enable로 첫 OTP를 확인해야 활성화된다.
+func (s *TwoFactorService) Setup(userID, account string) (*TwoFactorSetup, error) {
+	enabled, err := s.Enabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SavePending(userID, secret); err != nil {
+		return nil, err
+	}
+	return &TwoFactorSetup{Secret: secret, OTPAuthURI: totp.ProvisioningURI(s.issuer, account, secret)}, nil
+}
+
+// Enable confirms enrollment with a code from the app and returns the recovery codes (한 번만 표시)
+func (s *TwoFactorService) Enable(userID, code string) ([]string, error) {
+	tf, err := s.repo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTwoFactorNotPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tf.EnabledAt != nil {
+		return nil, ErrTwoFactorEnabled
+	}
+	step, ok := totp.Validate(tf.Secret, code, s.now(), otpSkew)
+	if !ok {
+		return nil, ErrInvalidOTP
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	enabled, err := s.repo.Enable(userID, step, hashes)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrTwoFactorNotPending
+	}
+	return codes, nil
+}
+
+// Disable turns 2FA off after verifying a current code or recovery code
+func (s *TwoFactorService) Disable(userID, code string) error {
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+	return s.repo.Delete(userID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after verifying a current code
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	if err := s.Verify(userID, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Verify checks a TOTP code or a one-time recovery code.
+// 연속 실패가 쌓이면 일정 시간 잠그며, 잠금 중에는 LoginThrottleError를 반환한다.
+func (s *TwoFactorService) Verify(userID, code string) error {
+	tf, err := s.repo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && tf.EnabledAt == nil) {
+		return ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	if tf.LockedUntil != nil && tf.LockedUntil.After(now) {
+		return &common.LoginThrottleError{Err: common.ErrLoginThrottled, Locked: true, RetryAfter: seconds(tf.LockedUntil.Sub(now))}
+	}
+
+	ok, err := s.check(tf, code, now)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if err := s.repo.RecordFailure(userID, otpLockAfter, now.Add(otpLockDuration)); err != nil {
+			log.Printf("[v2-auth] 2fa failure count failed for %s: %v", userID, err)
+		}
+		return ErrInvalidOTP
+	}
+	if tf.FailedCount > 0 || tf.LockedUntil != nil {
+		if err := s.repo.ResetFailures(userID); err != nil {
+			log.Printf("[v2-auth] 2fa failure reset failed for %s: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// check matches a 6-digit TOTP (이미 사용된 time step은 거부) or else a recovery code
+func (s *TwoFactorService) check(tf *v2domain.V2TwoFactor, code string, now time.Time) (bool, error) {
+	if step, ok := totp.Validate(tf.Secret, code, now, otpSkew); ok {
+		return s.repo.UseStep(tf.UserID, step)
+	}
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+	return s.repo.UseRecoveryCode(tf.UserID, hashRecoveryCode(normalized))
+}
+
+// Challenge issues the short-lived token returned in place of login tokens when 2FA is enabled
+func (s *TwoFactorService) Challenge(purpose, userID, username, nickname string, level int) (string, error) {
+	return s.jwtManager.GenerateChallengeToken(purpose, userID, username, nickname, level, TwoFactorChallengeTTL)
+}
+
+// ParseChallenge validates a challenge token for purpose
+func (s *TwoFactorService) ParseChallenge(token, purpose string) (*jwt.Claims, error) {
+	claims, err := s.jwtManager.VerifyChallengeToken(token, purpose)
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+	return claims, nil
+}
+
+// newRecoveryCodes returns recovery codes ("xxxxx-xxxxx") and their hashes
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(enc.EncodeToString(b))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRecoveryCode(raw)
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != 10 {
+		return ""
+	}
+	return code
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// TwoFactorStatus returns a member's 2FA state
+func (s *V2AuthService) TwoFactorStatus(userID string) (*TwoFactorStatus, error) {
+	if s.twoFactor == nil {
+		return nil, ErrTwoFactorUnavailable
+	}
+	return s.twoFactor.Status(userID)
+}
+
+// SetupTwoFactor starts TOTP enrollment (OTP 앱 계정명은 아이디, OAuth 회원은 user_id)
+func (s *V2AuthService) SetupTwoFactor(userID string) (*TwoFactorSetup, error) {
+	if s.twoFactor == nil {
+		return nil, ErrTwoFactorUnavailable
+	}
+	account := userID
+	if id, err := strconv.ParseUint(userID, 10, 64); err == nil {
+		if user, err := s.userRepo.FindByID(id); err == nil {
+			account = user.Username
+		}
+	}
+	return s.twoFactor.Setup(userID, account)
+}
+
+// EnableTwoFactor confirms TOTP enrollment and returns recovery codes
+func (s *V2AuthService) EnableTwoFactor(userID, code string) ([]string, error) {
+	if s.twoFactor == nil {
+		return nil, ErrTwoFactorUnavailable
+	}
+	return s.twoFactor.Enable(userID, code)
+}
+
+// DisableTwoFactor turns 2FA off
+func (s *V2AuthService) DisableTwoFactor(userID, code string) error {
+	if s.twoFactor == nil {
+		return ErrTwoFactorUnavailable
+	}
+	return s.twoFactor.Disable(userID, code)
+}
+
+// RegenerateRecoveryCodes issues a new set of recovery codes
+func (s *V2AuthService) RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	if s.twoFactor == nil {
+		return nil, ErrTwoFactorUnavailable
+	}
+	return s.twoFactor.RegenerateRecoveryCodes(userID, code)
+}
@@ -5,13 +5,22 @@ import (
 	"sync"
 	"time"
 
+	"github.com/damoang/angple-backend/internal/plugin"
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 )
 
+// SearchIndexQueue receives search index sync requests (implemented by service.SearchIndexer)
+type SearchIndexQueue interface {
+	EnqueuePost(boardID string, postID int, withComments bool)
+	EnqueueComment(boardID string, postID, commentID int)
+}
+
 // DeleteWorker processes pending scheduled deletes in the background
 type DeleteWorker struct {
 	writeRepo gnurepo.WriteRepository
 	sdRepo    gnurepo.ScheduledDeleteRepository
+	hooks     *plugin.HookManager
+	search    SearchIndexQueue
 	stop      chan struct{}
 	wg        sync.WaitGroup
 }
@@ -25,6 +34,16 @@ func NewDeleteWorker(writeRepo gnurepo.WriteRepository, sdRepo gnurepo.Scheduled
 	}
 }
 
+// SetHookManager sets the plugin hook manager for firing after-delete hooks
+func (w *DeleteWorker) SetHookManager(hm *plugin.HookManager) {
+	w.hooks = hm
+}
+
+// SetSearchIndexer sets the search index queue for removing deleted content from the index
+func (w *DeleteWorker) SetSearchIndexer(q SearchIndexQueue) {
+	w.search = q
+}
+
 // Start begins the background worker with a 30-second tick interval
 func (w *DeleteWorker) Start() {
 	w.wg.Add(1)
@@ -83,6 +102,9 @@ func (w *DeleteWorker) processPending() {
 			}
 		}
 
+		w.fireAfterDelete(sd.BoTable, sd.WrID, sd.WrIsComment == 1, sd.RequestedBy)
+		w.syncSearchIndex(sd.BoTable, sd.WrID, sd.WrIsComment == 1)
+
 		// Mark as executed
 		if err := w.sdRepo.MarkExecuted(sd.ID); err != nil {
 			log.Printf("[DeleteWorker] Error marking as executed %d: %v", sd.ID, err)
@@ -92,3 +114,34 @@ func (w *DeleteWorker) processPending() {
 			sd.BoTable, sd.WrID, sd.WrIsComment, sd.DelayMinutes)
 	}
 }
+
+// fireAfterDelete fires the plugin after-delete hook for an executed scheduled delete
+func (w *DeleteWorker) fireAfterDelete(boTable string, wrID int, isComment bool, requestedBy string) {
+	if w.hooks == nil {
+		return
+	}
+	data := map[string]interface{}{
+		"board_id":   boTable,
+		"deleted_by": requestedBy,
+		"scheduled":  true,
+	}
+	if isComment {
+		data["comment_id"] = wrID
+		w.hooks.Do(plugin.HookCommentAfterDelete, data)
+		return
+	}
+	data["post_id"] = wrID
+	w.hooks.Do(plugin.HookPostAfterDelete, data)
+}
+
+// syncSearchIndex queues a search index sync for an executed scheduled delete
+func (w *DeleteWorker) syncSearchIndex(boTable string, wrID int, isComment bool) {
+	if w.search == nil {
+		return
+	}
+	if isComment {
+		w.search.EnqueueComment(boTable, 0, wrID)
+		return
+	}
+	w.search.EnqueuePost(boTable, wrID, false)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"sync"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -30,6 +31,7 @@ type Hub struct {
 
 	mu          sync.RWMutex
 	redisClient *redis.Client
+	instanceID  string // Redis 발행 메시지의 출처 (자기 메시지 중복 전달 방지)
 	ctx         context.Context
 	cancel      context.CancelFunc
 }
@@ -48,6 +50,7 @@ func NewHub(redisClient *redis.Client) *Hub {
 		unregister:  make(chan *Client),
 		broadcast:   make(chan *targetedEvent, 256),
 		redisClient: redisClient,
+		instanceID:  uuid.NewString(),
 		ctx:         ctx,
 		cancel:      cancel,
 	}
@@ -118,7 +121,7 @@ func (h *Hub) SendToMember(memberID string, event *Event) {
 
 	// Publish to Redis for multi-instance support
 	if h.redisClient != nil {
-		msg := &redisMessage{MemberID: memberID, Event: event}
+		msg := &redisMessage{Origin: h.instanceID, MemberID: memberID, Event: event}
 		data, err := json.Marshal(msg)
 		if err == nil {
 			h.redisClient.Publish(h.ctx, redisPubSubChannel, data) //nolint:errcheck
@@ -127,6 +130,7 @@ func (h *Hub) SendToMember(memberID string, event *Event) {
 }
 
 type redisMessage struct {
+	Origin   string `json:"origin,omitempty"`
 	MemberID string `json:"member_id"`
 	Event    *Event `json:"event"`
 }
@@ -144,7 +148,7 @@ func (h *Hub) subscribeRedis() {
 				return
 			}
 			var rm redisMessage
-			if err := json.Unmarshal([]byte(msg.Payload), &rm); err == nil {
+			if err := json.Unmarshal([]byte(msg.Payload), &rm); err == nil && rm.Origin != h.instanceID {
 				// Only local broadcast (don't re-publish to Redis)
 				h.broadcast <- &targetedEvent{MemberID: rm.MemberID, Event: rm.Event}
 			}
@@ -102,6 +102,35 @@ func (c *Client) DeleteDocument(ctx context.Context, index, docID string) error
 	return nil
 }
 
+// DeleteByQuery removes all documents matching the query
+func (c *Client) DeleteByQuery(ctx context.Context, index string, query map[string]interface{}) error {
+	data, err := json.Marshal(map[string]interface{}{"query": query})
+	if err != nil {
+		return err
+	}
+
+	req := esapi.DeleteByQueryRequest{
+		Index:     []string{index},
+		Body:      bytes.NewReader(data),
+		Conflicts: "proceed",
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() && res.StatusCode != 404 {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("delete by query error [%s]: failed to read response body: %w", res.Status(), err)
+		}
+		return fmt.Errorf("delete by query error [%s]: %s", res.Status(), string(body))
+	}
+	return nil
+}
+
 // BulkIndex indexes multiple documents in a single request
 func (c *Client) BulkIndex(ctx context.Context, index string, docs map[string]interface{}) error {
 	if len(docs) == 0 {
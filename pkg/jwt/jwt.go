@@ -1,6 +1,10 @@
 package jwt
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -19,19 +23,28 @@ type Claims struct {
 	Username string `json:"username,omitempty"`
 	Nickname string `json:"nickname"`
 	Level    int    `json:"level"`
+	// SessionID 서버 세션(v2_sessions) ID — 세션 기반 리프레시 토큰에만 포함
+	SessionID string `json:"sid,omitempty"`
+	// MFA 2단계 인증(TOTP)을 거친 로그인에서 발급된 토큰
+	MFA bool `json:"mfa,omitempty"`
 }
 
 // Manager JWT token manager
 type Manager struct {
 	secretKey     []byte
+	challengeKey  []byte
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
 }
 
 // NewManager creates a new JWT manager
 func NewManager(secret string, accessExpiry, refreshExpiry int) *Manager {
+	// 인증 중간 단계 토큰은 파생 키로 서명해 액세스/리프레시 토큰으로 쓰이지 않게 한다
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("angple-auth-challenge"))
 	return &Manager{
 		secretKey:     []byte(secret),
+		challengeKey:  mac.Sum(nil),
 		accessExpiry:  time.Duration(accessExpiry) * time.Second,
 		refreshExpiry: time.Duration(refreshExpiry) * time.Second,
 	}
@@ -39,11 +52,21 @@ func NewManager(secret string, accessExpiry, refreshExpiry int) *Manager {
 
 // GenerateAccessToken generates an access token
 func (m *Manager) GenerateAccessToken(userID, username, nickname string, level int) (string, error) {
+	return m.generateAccessToken(userID, username, nickname, level, false)
+}
+
+// GenerateMFAAccessToken generates an access token for a login completed with a second factor
+func (m *Manager) GenerateMFAAccessToken(userID, username, nickname string, level int) (string, error) {
+	return m.generateAccessToken(userID, username, nickname, level, true)
+}
+
+func (m *Manager) generateAccessToken(userID, username, nickname string, level int, mfa bool) (string, error) {
 	claims := &Claims{
 		UserID:   userID,
 		Username: username,
 		Nickname: nickname,
 		Level:    level,
+		MFA:      mfa,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.accessExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -70,16 +93,80 @@ func (m *Manager) GenerateRefreshToken(userID string) (string, error) {
 	return token.SignedString(m.secretKey)
 }
 
+// GenerateSessionRefreshToken generates a refresh token bound to a server-side session.
+// 토큰마다 고유 jti를 넣어 같은 초에 회전해도 이전 토큰과 구분된다.
+// mfa는 갱신 시 새 액세스 토큰에 그대로 이어진다.
+func (m *Manager) GenerateSessionRefreshToken(userID, sessionID string, mfa bool) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	claims := &Claims{
+		UserID:    userID,
+		SessionID: sessionID,
+		MFA:       mfa,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(jti),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.refreshExpiry)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(m.secretKey)
+}
+
+// RefreshExpiry returns the refresh token lifetime
+func (m *Manager) RefreshExpiry() time.Duration {
+	return m.refreshExpiry
+}
+
+// GenerateChallengeToken generates a short-lived token for an intermediate auth step
+// (예: 비밀번호 확인 후 2단계 인증 대기). purpose가 다른 검증에는 쓸 수 없다.
+func (m *Manager) GenerateChallengeToken(purpose, userID, username, nickname string, level int, ttl time.Duration) (string, error) {
+	claims := &Claims{
+		UserID:   userID,
+		Username: username,
+		Nickname: nickname,
+		Level:    level,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   purpose,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(m.challengeKey)
+}
+
+// VerifyChallengeToken verifies a challenge token issued for purpose
+func (m *Manager) VerifyChallengeToken(tokenString, purpose string) (*Claims, error) {
+	claims, err := m.parse(tokenString, m.challengeKey)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject != purpose {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
 // VerifyToken verifies and parses a token
-//
-//nolint:dupl // JWT 검증 로직은 표준 패턴을 따르므로 유사함
 func (m *Manager) VerifyToken(tokenString string) (*Claims, error) {
+	return m.parse(tokenString, m.secretKey)
+}
+
+//nolint:dupl // JWT 검증 로직은 표준 패턴을 따르므로 유사함
+func (m *Manager) parse(tokenString string, key []byte) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		// Validate signing method
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, ErrInvalidToken
 		}
-		return m.secretKey, nil
+		return key, nil
 	})
 
 	if err != nil {
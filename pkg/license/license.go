@@ -0,0 +1,161 @@
+// Package license implements the signed plugin license token format.
+//
+// 토큰 형식: base64url(claims JSON) + "." + base64url(Ed25519 서명)
+//
+// 서명 검증과 만료/플러그인/도메인 확인은 공개 키만으로 가능하므로
+// 셀프 호스팅 사이트는 라이선스 서버에 접속하지 않고도 Verify로 검증할 수 있다.
+// 폐기(revocation) 여부는 서버 검증(/api/v1/licenses/verify)에서만 확인된다.
+package license
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Token verification errors
+var (
+	ErrMalformed      = errors.New("license: malformed token")
+	ErrBadSignature   = errors.New("license: invalid signature")
+	ErrExpired        = errors.New("license: expired")
+	ErrPluginMismatch = errors.New("license: plugin mismatch")
+	ErrDomainMismatch = errors.New("license: domain not allowed")
+	ErrDomainRequired = errors.New("license: domain required")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Claims is the signed payload of a license token
+type Claims struct {
+	LicenseID   uint64 `json:"lid"`
+	PluginID    string `json:"plugin"`
+	DeveloperID uint64 `json:"dev"`
+	Domain      string `json:"dom,omitempty"` // 비어 있으면 도메인 제한 없음
+	Seats       int    `json:"seats"`
+	IssuedAt    int64  `json:"iat"`
+	ExpiresAt   int64  `json:"exp,omitempty"` // 0 = 무기한
+}
+
+// Sign encodes and signs claims with the private key
+func Sign(priv ed25519.PrivateKey, claims *Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	sig := ed25519.Sign(priv, payload)
+	return encoding.EncodeToString(payload) + "." + encoding.EncodeToString(sig), nil
+}
+
+// Parse checks the signature and decodes the claims (만료/도메인은 확인하지 않음)
+func Parse(pub ed25519.PublicKey, token string) (*Claims, error) {
+	payloadPart, sigPart, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return nil, ErrMalformed
+	}
+	payload, err := encoding.DecodeString(payloadPart)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	sig, err := encoding.DecodeString(sigPart)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return nil, ErrMalformed
+	}
+	if !ed25519.Verify(pub, payload, sig) {
+		return nil, ErrBadSignature
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrMalformed
+	}
+	return &claims, nil
+}
+
+// Verify validates a token offline: signature, expiry, plugin and domain binding.
+// pluginID가 비어 있으면 플러그인은 확인하지 않는다.
+// 도메인에 묶인 라이선스는 domain 없이 검증하면 ErrDomainRequired (다른 사이트에서 통과하지 않도록).
+func Verify(pub ed25519.PublicKey, token, pluginID, domain string, now time.Time) (*Claims, error) {
+	claims, err := Parse(pub, token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.ExpiresAt > 0 && now.Unix() >= claims.ExpiresAt {
+		return claims, ErrExpired
+	}
+	if pluginID != "" && claims.PluginID != pluginID {
+		return claims, ErrPluginMismatch
+	}
+	if NormalizeDomain(claims.Domain) != "" {
+		if NormalizeDomain(domain) == "" {
+			return claims, ErrDomainRequired
+		}
+		if !DomainAllowed(claims.Domain, domain) {
+			return claims, ErrDomainMismatch
+		}
+	}
+	return claims, nil
+}
+
+// DomainAllowed reports whether host is covered by the bound domain (하위 도메인 포함)
+func DomainAllowed(bound, host string) bool {
+	bound = NormalizeDomain(bound)
+	if bound == "" {
+		return true
+	}
+	host = NormalizeDomain(host)
+	return host == bound || strings.HasSuffix(host, "."+bound)
+}
+
+// NormalizeDomain lowercases a domain and strips scheme, port, path and "www."
+func NormalizeDomain(domain string) string {
+	d := strings.ToLower(strings.TrimSpace(domain))
+	if i := strings.Index(d, "://"); i >= 0 {
+		d = d[i+3:]
+	}
+	if i := strings.IndexAny(d, "/?#"); i >= 0 {
+		d = d[:i]
+	}
+	if i := strings.LastIndex(d, ":"); i >= 0 {
+		d = d[:i]
+	}
+	d = strings.TrimSuffix(d, ".")
+	return strings.TrimPrefix(d, "www.")
+}
+
+// ParsePrivateKey decodes a base64 Ed25519 seed (32 bytes) or private key (64 bytes)
+func ParsePrivateKey(encoded string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("license: signing key is not base64: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("license: signing key must be %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+}
+
+// ParsePublicKey decodes a base64 Ed25519 public key (셀프 호스팅 사이트 설정용)
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("license: public key is not base64: %w", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("license: public key must be %d bytes, got %d", ed25519.PublicKeySize, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// GenerateKey creates a new random signing key
+func GenerateKey() (ed25519.PrivateKey, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	return priv, err
+}
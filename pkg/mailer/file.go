@@ -0,0 +1,69 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileConfig holds file-drop mailer configuration
+type FileConfig struct {
+	Dir string // .eml 파일을 쓸 디렉터리
+}
+
+// FileMailer writes each message as an .eml file instead of sending it.
+// 개발/테스트 환경에서 SMTP 없이 인증 메일 내용을 확인할 때 사용한다.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer creates a file-drop mailer writing into cfg.Dir
+func NewFileMailer(cfg FileConfig, from string) (*FileMailer, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("mail drop directory is empty")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create mail drop directory: %w", err)
+	}
+	return &FileMailer{dir: cfg.Dir, from: from}, nil
+}
+
+// Send writes msg to <dir>/<timestamp>-<random>.eml (임시 파일 후 rename)
+func (m *FileMailer) Send(_ context.Context, msg *Message) error {
+	now := time.Now()
+	data, _, err := render(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+	dst := filepath.Join(m.dir, name)
+	tmp := dst + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// writeBase64Lines writes body as base64 wrapped at 76 columns (RFC 2045)
+func writeBase64Lines(buf *bytes.Buffer, body []byte) {
+	enc := base64.StdEncoding.EncodeToString(body)
+	for len(enc) > 76 {
+		buf.WriteString(enc[:76])
+		buf.WriteString("\r\n")
+		enc = enc[76:]
+	}
+	buf.WriteString(enc)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,69 @@
+package mailer
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	m, err := Open(Options{Backend: BackendFile, From: "Angple <noreply@example.com>", File: FileConfig{Dir: dir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := "인증 링크: https://example.com/verify?token=abc\n" + strings.Repeat("가", 60)
+	if err := m.Send(context.Background(), &Message{To: "hong@example.com", Subject: "이메일 인증", Body: body}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml")) //nolint:errcheck // pattern is valid
+	if len(files) != 1 {
+		t.Fatalf("expected 1 .eml file, got %d", len(files))
+	}
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint:errcheck // read-only file
+	parsed, err := mail.ReadMessage(f)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := parsed.Header.Get("To"); got != "<hong@example.com>" {
+		t.Errorf("unexpected To %q", got)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject")); subject != "이메일 인증" { //nolint:errcheck // compared below
+		t.Errorf("unexpected subject %q", subject)
+	}
+	decoded, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, parsed.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decoded) != body {
+		t.Errorf("body mismatch: %q", decoded)
+	}
+}
+
+func TestMailerRejectsBadInput(t *testing.T) {
+	m, err := NewFileMailer(FileConfig{Dir: t.TempDir()}, "noreply@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send(context.Background(), &Message{To: "not an address", Subject: "x"}); !errors.Is(err, ErrInvalidRecipient) {
+		t.Errorf("expected ErrInvalidRecipient, got %v", err)
+	}
+	if err := m.Send(context.Background(), &Message{To: "a@example.com", Subject: "x\r\nBcc: b@example.com"}); err == nil {
+		t.Error("expected header injection to be rejected")
+	}
+	if _, err := Open(Options{Backend: "pigeon", From: "noreply@example.com"}); err == nil {
+		t.Error("expected unknown backend to fail")
+	}
+}
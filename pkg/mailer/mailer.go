@@ -0,0 +1,84 @@
+// Package mailer sends transactional mail (가입 인증, 비밀번호 재설정 등).
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Backend names used by MailConfig.Backend
+const (
+	BackendSMTP = "smtp"
+	BackendFile = "file"
+)
+
+// Message is a plain-text mail message
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+var (
+	_ Mailer = (*SMTPMailer)(nil)
+	_ Mailer = (*FileMailer)(nil)
+)
+
+// Options selects and configures a mailer backend
+type Options struct {
+	Backend string // "smtp" or "file"
+	From    string // 발신 주소 ("이름 <addr>" 형식 가능)
+	SMTP    SMTPConfig
+	File    FileConfig
+}
+
+// Open creates the mailer selected by opts.Backend
+func Open(opts Options) (Mailer, error) {
+	if _, err := mail.ParseAddress(opts.From); err != nil {
+		return nil, fmt.Errorf("invalid mail from address %q: %w", opts.From, err)
+	}
+	switch opts.Backend {
+	case BackendSMTP:
+		return NewSMTPMailer(opts.SMTP, opts.From)
+	case BackendFile:
+		return NewFileMailer(opts.File, opts.From)
+	default:
+		return nil, fmt.Errorf("unknown mail backend %q", opts.Backend)
+	}
+}
+
+// ErrInvalidRecipient is returned for an unparsable To address
+var ErrInvalidRecipient = errors.New("invalid mail recipient")
+
+// render builds an RFC 5322 message (UTF-8 본문은 base64 인코딩)
+func render(from string, msg *Message, now time.Time) ([]byte, string, error) {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return nil, "", ErrInvalidRecipient
+	}
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return nil, "", errors.New("mail subject contains a line break")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	writeBase64Lines(&buf, []byte(msg.Body))
+	return buf.Bytes(), to.Address, nil
+}
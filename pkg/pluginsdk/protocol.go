@@ -0,0 +1,124 @@
+// Package pluginsdk implements the protocol between angple-backend and
+// out-of-process plugins.
+//
+// 외부 플러그인은 plugin.yaml의 runtime.command로 지정된 실행 파일이며,
+// 호스트는 이를 서브프로세스로 실행해 stdin/stdout으로 줄 단위 JSON-RPC 2.0 메시지를 주고받는다.
+// stderr 출력은 호스트 로그에 그대로 기록된다.
+//
+//	{"jsonrpc":"2.0","id":1,"method":"http.handle","params":{...}}
+//	{"jsonrpc":"2.0","id":1,"result":{"status":200,"body":"..."}}
+//
+// 호스트 → 플러그인 메서드: initialize, http.handle, hook.call, event.deliver, health, shutdown.
+// 플러그인은 Serve로 핸들러를 등록하기만 하면 된다.
+package pluginsdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ProtocolVersion 프로토콜 버전 (initialize에서 교환)
+const ProtocolVersion = "1"
+
+// 호스트 → 플러그인 메서드
+const (
+	MethodInitialize   = "initialize"
+	MethodHTTPHandle   = "http.handle"
+	MethodHookCall     = "hook.call"
+	MethodEventDeliver = "event.deliver"
+	MethodHealth       = "health"
+	MethodShutdown     = "shutdown"
+)
+
+// JSON-RPC 에러 코드
+const (
+	CodeParseError     = -32700
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Message JSON-RPC 2.0 요청/응답 (한 줄에 하나)
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error JSON-RPC 에러 객체
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("plugin rpc error %d: %s", e.Code, e.Message)
+}
+
+// InitializeParams initialize 요청
+type InitializeParams struct {
+	ProtocolVersion string                 `json:"protocol_version"`
+	Name            string                 `json:"name"`
+	Version         string                 `json:"version"`
+	Config          map[string]interface{} `json:"config"`
+}
+
+// InitializeResult initialize 응답
+type InitializeResult struct {
+	ProtocolVersion string `json:"protocol_version"`
+}
+
+// User 인증된 요청 사용자 (비로그인이면 nil)
+type User struct {
+	ID       string `json:"id"`
+	Nickname string `json:"nickname"`
+	Level    int    `json:"level"`
+}
+
+// HTTPRequest http.handle 요청. Handler는 plugin.yaml routes[].handler 값
+// Body는 JSON에서 base64로 인코딩된다.
+type HTTPRequest struct {
+	Handler string              `json:"handler"`
+	Method  string              `json:"method"`
+	Path    string              `json:"path"`
+	Params  map[string]string   `json:"params,omitempty"`
+	Query   map[string][]string `json:"query,omitempty"`
+	Headers map[string]string   `json:"headers,omitempty"`
+	Body    []byte              `json:"body,omitempty"`
+	User    *User               `json:"user,omitempty"`
+}
+
+// HTTPResponse http.handle 응답 (Status가 0이면 200)
+type HTTPResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    []byte            `json:"body,omitempty"`
+}
+
+// HookCall hook.call 요청. Handler는 plugin.yaml hooks[].handler 값
+type HookCall struct {
+	Event   string                 `json:"event"`
+	Handler string                 `json:"handler"`
+	Input   map[string]interface{} `json:"input"`
+}
+
+// HookResult hook.call 응답
+// Output이 있으면 Filter 결과로 사용되고, Reject가 있으면 Before Hook이 쓰기를 거부한다.
+type HookResult struct {
+	Output map[string]interface{} `json:"output,omitempty"`
+	Reject string                 `json:"reject,omitempty"`
+}
+
+// Event event.deliver 요청
+// 같은 이벤트가 두 번 이상 전달될 수 있으므로 ID로 중복 처리를 막는다. 에러를 반환하면 재전달된다.
+type Event struct {
+	ID        string                 `json:"id"`
+	Topic     string                 `json:"topic"`
+	Source    string                 `json:"source"`
+	Payload   map[string]interface{} `json:"payload"`
+	Timestamp time.Time              `json:"timestamp"`
+}
@@ -0,0 +1,176 @@
+package pluginsdk
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// maxMessageSize 한 메시지(줄)의 최대 크기
+const maxMessageSize = 16 << 20
+
+// HTTPHandler routes[].handler 구현
+type HTTPHandler func(req *HTTPRequest) (*HTTPResponse, error)
+
+// HookHandler hooks[].handler 구현
+type HookHandler func(call *HookCall) (*HookResult, error)
+
+// EventHandler events[] 토픽 구독 구현 (에러를 반환하면 호스트가 백오프 후 재전달)
+type EventHandler func(event *Event) error
+
+// Plugin 외부 플러그인 핸들러 모음
+type Plugin struct {
+	// OnInitialize 시작/재시작 시 호출 (설정 전달)
+	OnInitialize func(params *InitializeParams) error
+	// HTTP 라우트 핸들러 (이름 → 함수)
+	HTTP map[string]HTTPHandler
+	// Hook 핸들러 (이름 → 함수)
+	Hooks map[string]HookHandler
+	// 이벤트 핸들러 (토픽 → 함수)
+	Events map[string]EventHandler
+	// Health 헬스 체크 (nil이면 항상 정상)
+	Health func() error
+	// OnShutdown 종료 직전 호출
+	OnShutdown func() error
+}
+
+// Serve stdin/stdout으로 호스트 요청을 처리 (shutdown 요청 또는 stdin 종료 시 반환)
+func Serve(p *Plugin) error {
+	return ServeConn(os.Stdin, os.Stdout, p)
+}
+
+// ServeConn r/w로 호스트 요청 처리. 요청은 병렬로 처리되며 응답 순서는 보장하지 않는다.
+func ServeConn(r io.Reader, w io.Writer, p *Plugin) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+
+	var (
+		wmu sync.Mutex
+		wg  sync.WaitGroup
+	)
+	write := func(msg *Message) {
+		msg.JSONRPC = "2.0"
+		data, err := json.Marshal(msg)
+		if err != nil {
+			data, _ = json.Marshal(&Message{JSONRPC: "2.0", ID: msg.ID, Error: &Error{Code: CodeInternalError, Message: err.Error()}}) //nolint:errcheck // plain struct
+		}
+		wmu.Lock()
+		defer wmu.Unlock()
+		_, _ = w.Write(append(data, '\n')) //nolint:errcheck // host gone; nothing to do
+	}
+
+	for scanner.Scan() {
+		var msg Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			write(&Message{Error: &Error{Code: CodeParseError, Message: err.Error()}})
+			continue
+		}
+		if msg.ID == nil {
+			continue // 알림은 현재 사용하지 않음
+		}
+
+		if msg.Method == MethodShutdown {
+			wg.Wait()
+			var err error
+			if p.OnShutdown != nil {
+				err = p.OnShutdown()
+			}
+			write(reply(msg.ID, struct{}{}, err))
+			return err
+		}
+
+		wg.Add(1)
+		go func(msg Message) {
+			defer wg.Done()
+			result, err := dispatch(p, &msg)
+			write(reply(msg.ID, result, err))
+		}(msg)
+	}
+	wg.Wait()
+	return scanner.Err()
+}
+
+// dispatch 메서드별 핸들러 호출
+func dispatch(p *Plugin, msg *Message) (interface{}, error) {
+	switch msg.Method {
+	case MethodInitialize:
+		var params InitializeParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		if p.OnInitialize != nil {
+			if err := p.OnInitialize(&params); err != nil {
+				return nil, err
+			}
+		}
+		return &InitializeResult{ProtocolVersion: ProtocolVersion}, nil
+
+	case MethodHTTPHandle:
+		var req HTTPRequest
+		if err := decodeParams(msg, &req); err != nil {
+			return nil, err
+		}
+		h, ok := p.HTTP[req.Handler]
+		if !ok {
+			return nil, &Error{Code: CodeMethodNotFound, Message: "unknown http handler: " + req.Handler}
+		}
+		return h(&req)
+
+	case MethodHookCall:
+		var call HookCall
+		if err := decodeParams(msg, &call); err != nil {
+			return nil, err
+		}
+		h, ok := p.Hooks[call.Handler]
+		if !ok {
+			return nil, &Error{Code: CodeMethodNotFound, Message: "unknown hook handler: " + call.Handler}
+		}
+		return h(&call)
+
+	case MethodEventDeliver:
+		var event Event
+		if err := decodeParams(msg, &event); err != nil {
+			return nil, err
+		}
+		if h, ok := p.Events[event.Topic]; ok {
+			return struct{}{}, h(&event)
+		}
+		return struct{}{}, nil
+
+	case MethodHealth:
+		if p.Health != nil {
+			return struct{}{}, p.Health()
+		}
+		return struct{}{}, nil
+
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: "unknown method: " + msg.Method}
+	}
+}
+
+func decodeParams(msg *Message, v interface{}) error {
+	if err := json.Unmarshal(msg.Params, v); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("%s: %v", msg.Method, err)}
+	}
+	return nil
+}
+
+// reply 결과 또는 에러 응답 메시지
+func reply(id *int64, result interface{}, err error) *Message {
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+		return &Message{ID: id, Error: rpcErr}
+	}
+	data, mErr := json.Marshal(result)
+	if mErr != nil {
+		return &Message{ID: id, Error: &Error{Code: CodeInternalError, Message: mErr.Error()}}
+	}
+	return &Message{ID: id, Result: data}
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Backend names used by StorageConfig.Backend
+const (
+	BackendS3    = "s3"
+	BackendLocal = "local"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("storage object not found")
+
+// Backend is an object store for uploaded media (S3-compatible or local disk).
+// Keys passed to Download/Delete/Exists/URL methods are the full keys returned by Upload.
+type Backend interface {
+	Upload(ctx context.Context, key string, body io.Reader, contentType string, size int64) (*UploadResult, error)
+	Download(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	// List calls fn for every full key under prefix
+	List(ctx context.Context, prefix string, fn func(key string) error) error
+	GetPresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	GetCDNURL(key string) string
+	// FullKey returns the full key Upload will store key under (base path applied)
+	FullKey(key string) string
+}
+
+var (
+	_ Backend = (*S3Client)(nil)
+	_ Backend = (*LocalStorage)(nil)
+)
+
+// Options selects and configures a storage backend
+type Options struct {
+	Backend string // "s3" or "local"
+	S3      S3Config
+	Local   LocalConfig
+}
+
+// Open creates the backend selected by opts.Backend
+func Open(opts Options) (Backend, error) {
+	switch opts.Backend {
+	case BackendS3:
+		return NewS3Client(opts.S3)
+	case BackendLocal:
+		return NewLocalStorage(opts.Local)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", opts.Backend)
+	}
+}
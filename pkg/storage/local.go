@@ -0,0 +1,286 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+)
+
+// Local storage errors
+var (
+	ErrInvalidKey       = errors.New("invalid storage key")
+	ErrInvalidSignature = errors.New("invalid or expired download signature")
+)
+
+// LocalStorage stores objects on local disk and serves them through a signed download route
+type LocalStorage struct {
+	root        string
+	downloadURL string // signed download route (e.g. https://api.angple.com/api/v2/media/files)
+	cdnURL      string // optional public base URL serving root (nginx/CDN)
+	basePath    string
+	secret      []byte
+}
+
+// LocalConfig holds local-disk storage configuration
+type LocalConfig struct {
+	Root          string // upload directory (data_paths.upload_path)
+	DownloadURL   string
+	CDNURL        string
+	BasePath      string
+	SigningSecret string // HMAC key for download URLs
+}
+
+// NewLocalStorage creates a local-disk storage backend rooted at cfg.Root
+func NewLocalStorage(cfg LocalConfig) (*LocalStorage, error) {
+	if cfg.Root == "" {
+		return nil, errors.New("local storage root is empty")
+	}
+	if cfg.SigningSecret == "" {
+		return nil, errors.New("local storage signing secret is empty")
+	}
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil { //nolint:gosec // uploaded media is world-readable
+		return nil, fmt.Errorf("failed to create storage root: %w", err)
+	}
+
+	pkglogger.GetLogger().Info().
+		Str("root", root).
+		Msg("local storage initialized")
+
+	return &LocalStorage{
+		root:        root,
+		downloadURL: strings.TrimRight(cfg.DownloadURL, "/"),
+		cdnURL:      strings.TrimRight(cfg.CDNURL, "/"),
+		basePath:    cfg.BasePath,
+		secret:      []byte(cfg.SigningSecret),
+	}, nil
+}
+
+// Upload writes a file atomically (temp file + rename) under the storage root
+func (s *LocalStorage) Upload(_ context.Context, key string, body io.Reader, contentType string, _ int64) (*UploadResult, error) {
+	fullKey := s.FullKey(key)
+	dst, err := s.path(fullKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil { //nolint:gosec // uploaded media is world-readable
+		return nil, fmt.Errorf("local upload failed: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("local upload failed: %w", err)
+	}
+	written, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o644) //nolint:gosec // uploaded media is world-readable
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), dst)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name()) //nolint:errcheck // cleanup; the upload error takes precedence
+		return nil, fmt.Errorf("local upload failed: %w", err)
+	}
+
+	result := &UploadResult{
+		Key:         fullKey,
+		URL:         s.signedURL(fullKey, ""),
+		ContentType: contentType,
+		Size:        written,
+	}
+	if s.cdnURL != "" {
+		result.CDNURL = s.cdnURL + "/" + escapeKey(fullKey)
+	}
+	return result, nil
+}
+
+// Download opens a stored file for reading
+func (s *LocalStorage) Download(_ context.Context, key string) (io.ReadCloser, error) {
+	return s.open(key)
+}
+
+// Delete removes a file; missing files are not an error
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("local delete failed: %w", err)
+	}
+	return nil
+}
+
+// Exists reports whether a file exists
+func (s *LocalStorage) Exists(_ context.Context, key string) (bool, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return info.Mode().IsRegular(), nil
+}
+
+// List calls fn for every file key under prefix
+func (s *LocalStorage) List(ctx context.Context, prefix string, fn func(key string) error) error {
+	start := s.root
+	if dir := path.Dir(prefix); dir != "." {
+		p, err := s.path(dir)
+		if err != nil {
+			return err
+		}
+		start = p
+	}
+
+	err := filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if p != start && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		return fn(key)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// GetPresignedURL returns a download URL that expires after expiry
+func (s *LocalStorage) GetPresignedURL(_ context.Context, key string, expiry time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	return s.signedURL(key, strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)), nil
+}
+
+// GetCDNURL returns the CDN URL for a key, falling back to a non-expiring signed download URL
+func (s *LocalStorage) GetCDNURL(key string) string {
+	if s.cdnURL != "" {
+		return s.cdnURL + "/" + escapeKey(key)
+	}
+	return s.signedURL(key, "")
+}
+
+// FullKey returns the full key for an upload key
+func (s *LocalStorage) FullKey(key string) string {
+	return s.basePath + key
+}
+
+// OpenSigned verifies a download signature and opens the file.
+// expires is a unix timestamp, or empty for non-expiring URLs.
+func (s *LocalStorage) OpenSigned(key, expires, signature string) (*os.File, error) {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, s.sign(key, expires)) {
+		return nil, ErrInvalidSignature
+	}
+	if expires != "" {
+		exp, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil || time.Now().Unix() > exp {
+			return nil, ErrInvalidSignature
+		}
+	}
+	return s.open(key)
+}
+
+func (s *LocalStorage) open(key string) (*os.File, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p) // #nosec G304 -- p is validated by path() to stay under root
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info, statErr := f.Stat(); statErr != nil || !info.Mode().IsRegular() {
+		_ = f.Close() //nolint:errcheck // read-only file
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return f, nil
+}
+
+// path maps a key to a file under root, rejecting absolute, non-canonical,
+// parent-relative and hidden (temp file) segments
+func (s *LocalStorage) path(key string) (string, error) {
+	if key == "" || strings.Contains(key, "\\") || path.Clean(key) != key || path.IsAbs(key) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if strings.HasPrefix(seg, ".") {
+			return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStorage) sign(key, expires string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return mac.Sum(nil)
+}
+
+func (s *LocalStorage) signedURL(key, expires string) string {
+	q := url.Values{}
+	if expires != "" {
+		q.Set("expires", expires)
+	}
+	q.Set("signature", hex.EncodeToString(s.sign(key, expires)))
+	return s.downloadURL + "/" + escapeKey(key) + "?" + q.Encode()
+}
+
+// escapeKey escapes each path segment of a key
+func escapeKey(key string) string {
+	segs := strings.Split(key, "/")
+	for i, seg := range segs {
+		segs[i] = url.PathEscape(seg)
+	}
+	return strings.Join(segs, "/")
+}
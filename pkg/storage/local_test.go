@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signedQuery(t *testing.T, rawURL string) (string, string) {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Query().Get("expires"), u.Query().Get("signature")
+}
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(LocalConfig{Root: t.TempDir(), DownloadURL: "https://api.example.com/api/v2/media/files", SigningSecret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := GenerateKey("images", "사진.jpg")
+	res, err := s.Upload(ctx, key, strings.NewReader("jpeg"), "image/jpeg", 4)
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if res.Key != key || res.Size != 4 || !strings.HasPrefix(res.URL, "https://api.example.com/api/v2/media/files/images/") {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	_, sig := signedQuery(t, res.URL)
+	f, err := s.OpenSigned(key, "", sig)
+	if err != nil {
+		t.Fatalf("open permanent URL: %v", err)
+	}
+	data, _ := io.ReadAll(f) //nolint:errcheck // compared below
+	_ = f.Close()            //nolint:errcheck // read-only file
+	if string(data) != "jpeg" {
+		t.Errorf("unexpected content %q", data)
+	}
+
+	presigned, err := s.GetPresignedURL(ctx, key, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires, sig := signedQuery(t, presigned)
+	if f, err := s.OpenSigned(key, expires, sig); err != nil {
+		t.Errorf("open presigned URL: %v", err)
+	} else {
+		_ = f.Close() //nolint:errcheck // read-only file
+	}
+	if _, err := s.OpenSigned(key, "", sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected stripped expiry to be rejected, got %v", err)
+	}
+	expired, _ := s.GetPresignedURL(ctx, key, -time.Minute) //nolint:errcheck // valid key
+	expires, sig = signedQuery(t, expired)
+	if _, err := s.OpenSigned(key, expires, sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected expired URL to be rejected, got %v", err)
+	}
+
+	for _, bad := range []string{"../etc/passwd", "/etc/passwd", "images/../../x", "images/.upload-1", ""} {
+		if _, err := s.Upload(ctx, bad, strings.NewReader("x"), "text/plain", 1); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected %q to be rejected, got %v", bad, err)
+		}
+	}
+
+	var keys []string
+	if err := s.List(ctx, "images/", func(k string) error { keys = append(keys, k); return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != key {
+		t.Errorf("unexpected list: %v", keys)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.Exists(ctx, key); ok {
+		t.Error("expected file to be deleted")
+	}
+	if _, err := s.Download(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
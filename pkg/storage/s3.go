@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -12,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	pkglogger "github.com/damoang/angple-backend/pkg/logger"
 )
 
@@ -21,6 +23,7 @@ type S3Client struct {
 	bucket   string
 	cdnURL   string // optional CDN base URL (e.g. https://cdn.angple.com)
 	basePath string // prefix for all objects (e.g. "uploads/")
+	cacheCtl string // optional Cache-Control for uploaded objects
 }
 
 // S3Config holds S3-compatible storage configuration
@@ -32,7 +35,8 @@ type S3Config struct {
 	Bucket          string
 	CDNURL          string
 	BasePath        string
-	ForcePathStyle  bool // true for MinIO/R2
+	CacheControl    string // e.g. "public, max-age=31536000, immutable"
+	ForcePathStyle  bool   // true for MinIO/R2
 }
 
 // NewS3Client creates a new S3-compatible storage client
@@ -58,6 +62,7 @@ func NewS3Client(cfg S3Config) (*S3Client, error) {
 		bucket:   cfg.Bucket,
 		cdnURL:   strings.TrimRight(cfg.CDNURL, "/"),
 		basePath: cfg.BasePath,
+		cacheCtl: cfg.CacheControl,
 	}, nil
 }
 
@@ -72,7 +77,7 @@ type UploadResult struct {
 
 // Upload uploads a file to S3-compatible storage
 func (c *S3Client) Upload(ctx context.Context, key string, body io.Reader, contentType string, size int64) (*UploadResult, error) {
-	fullKey := c.basePath + key
+	fullKey := c.FullKey(key)
 
 	input := &s3.PutObjectInput{
 		Bucket:      aws.String(c.bucket),
@@ -80,6 +85,9 @@ func (c *S3Client) Upload(ctx context.Context, key string, body io.Reader, conte
 		Body:        body,
 		ContentType: aws.String(contentType),
 	}
+	if c.cacheCtl != "" {
+		input.CacheControl = aws.String(c.cacheCtl)
+	}
 
 	if _, err := c.client.PutObject(ctx, input); err != nil {
 		return nil, fmt.Errorf("s3 upload failed: %w", err)
@@ -112,6 +120,61 @@ func (c *S3Client) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Download opens a stored object for reading; key is the full key returned by Upload
+func (c *S3Client) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}
+
+	out, err := c.client.GetObject(ctx, input)
+	if err != nil {
+		var noKey *types.NoSuchKey
+		if errors.As(err, &noKey) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+		return nil, fmt.Errorf("s3 download failed: %w", err)
+	}
+	return out.Body, nil
+}
+
+// Exists reports whether an object exists
+func (c *S3Client) Exists(ctx context.Context, key string) (bool, error) {
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}
+
+	if _, err := c.client.HeadObject(ctx, input); err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("s3 head failed: %w", err)
+	}
+	return true, nil
+}
+
+// List calls fn for every object key under prefix
+func (c *S3Client) List(ctx context.Context, prefix string, fn func(key string) error) error {
+	paginator := s3.NewListObjectsV2Paginator(c.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(c.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("s3 list failed: %w", err)
+		}
+		for _, obj := range page.Contents {
+			if err := fn(aws.ToString(obj.Key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // GetPresignedURL generates a pre-signed URL for direct download
 func (c *S3Client) GetPresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
 	presignClient := s3.NewPresignClient(c.client)
@@ -137,6 +200,11 @@ func (c *S3Client) GetCDNURL(key string) string {
 	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", c.bucket, key)
 }
 
+// FullKey returns the full object key for an upload key
+func (c *S3Client) FullKey(key string) string {
+	return c.basePath + key
+}
+
 // GenerateKey creates a unique storage key with timestamp prefix
 func GenerateKey(prefix, filename string) string {
 	now := time.Now()
@@ -0,0 +1,116 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/handler"
+	"github.com/damoang/angple-backend/internal/payment"
+	"github.com/damoang/angple-backend/internal/repository"
+	v2routes "github.com/damoang/angple-backend/internal/routes/v2"
+	"github.com/damoang/angple-backend/internal/service"
+	"github.com/damoang/angple-backend/pkg/jwt"
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestSaaSSubscriptionRoutesRequireSiteOwner checks that plan changes, cancellation,
+// invoices and community deletion are only reachable by the site owner and that
+// rejected requests never reach the payment gateway.
+func TestSaaSSubscriptionRoutesRequireSiteOwner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	pkglogger.Init()
+
+	db, err := gorm.Open(sqlite.Open("file:saas_billing_auth?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&domain.Site{}, &domain.SiteUser{}))
+
+	siteRepo := repository.NewSiteRepository(db)
+	subRepo := repository.NewSubscriptionRepository(db)
+	require.NoError(t, subRepo.AutoMigrate())
+
+	const siteID = "site-billing-0001"
+	require.NoError(t, db.Create(&domain.Site{ID: siteID, Subdomain: "billing", SiteName: "Billing", OwnerEmail: "owner@example.com", Plan: "pro", DBStrategy: "schema", Active: true}).Error)
+	require.NoError(t, db.Create(&domain.SiteUser{SiteID: siteID, UserID: "owner", Role: "owner"}).Error)
+	require.NoError(t, db.Create(&domain.SiteUser{SiteID: siteID, UserID: "viewer", Role: "viewer"}).Error)
+	now := time.Now()
+	require.NoError(t, subRepo.Create(context.Background(), &domain.Subscription{
+		SiteID: siteID, Plan: "pro", Status: "active", BillingCycle: "monthly",
+		PaymentProvider: payment.ProviderFake, ExternalSubID: "fake_bk_" + siteID,
+		CurrentPeriodStart: now.AddDate(0, 0, -1), CurrentPeriodEnd: now.AddDate(0, 1, -1),
+	}))
+
+	gateway := payment.NewFakeProvider("")
+	billingSvc := service.NewBillingService(subRepo, siteRepo)
+	billingSvc.RegisterProvider(gateway)
+	provisioningSvc := service.NewProvisioningService(siteRepo, subRepo, nil, db, "angple.com")
+	provisioningSvc.SetBillingService(billingSvc)
+
+	jwtManager := jwt.NewManager("saas-billing-test-secret", 900, 3600)
+	router := gin.New()
+	v2routes.SetupSaaS(router, handler.NewProvisioningHandler(provisioningSvc), handler.NewBillingHandler(billingSvc), jwtManager, service.NewSiteService(siteRepo), nil)
+
+	tokenFor := func(userID string) string {
+		token, err := jwtManager.GenerateAccessToken(userID, userID, userID, 2)
+		require.NoError(t, err)
+		return token
+	}
+
+	base := "/api/v2/saas/communities/" + siteID
+	routes := []struct {
+		method, path, body string
+	}{
+		{http.MethodPut, base + "/subscription/plan", `{"plan":"business"}`},
+		{http.MethodPost, base + "/subscription/cancel", ""},
+		{http.MethodGet, base + "/invoices", ""},
+		{http.MethodDelete, base, ""},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"unauthenticated", "", http.StatusUnauthorized},
+		{"site viewer", tokenFor("viewer"), http.StatusForbidden},
+		{"outsider", tokenFor("outsider"), http.StatusForbidden},
+	} {
+		for _, r := range routes {
+			req := httptest.NewRequest(r.method, r.path, strings.NewReader(r.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tc.status, w.Code, "%s %s %s", tc.name, r.method, r.path)
+		}
+	}
+	assert.Empty(t, gateway.Charges, "rejected requests must not reach the payment gateway")
+
+	sub, err := subRepo.FindBySiteID(context.Background(), siteID)
+	require.NoError(t, err)
+	assert.Equal(t, "pro", sub.Plan)
+	assert.Equal(t, "active", sub.Status)
+
+	// 소유자의 업그레이드는 일할 계산 금액을 결제사에 청구한다
+	req := httptest.NewRequest(http.MethodPut, base+"/subscription/plan", strings.NewReader(`{"plan":"business"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+tokenFor("owner"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, gateway.Charges, 1)
+}
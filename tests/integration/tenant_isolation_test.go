@@ -0,0 +1,212 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2handler "github.com/damoang/angple-backend/internal/handler/v2"
+	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/repository"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	v2routes "github.com/damoang/angple-backend/internal/routes/v2"
+	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var tenantContentDDL = []string{
+	`CREATE TABLE IF NOT EXISTS v2_users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		username VARCHAR(50) UNIQUE, email VARCHAR(255) UNIQUE,
+		password VARCHAR(255), nickname VARCHAR(100),
+		level INTEGER DEFAULT 1, point INTEGER DEFAULT 0,
+		exp INTEGER DEFAULT 0, nariya_level INTEGER DEFAULT 1,
+		nariya_max INTEGER DEFAULT 1000,
+		status TEXT DEFAULT 'active',
+		avatar_url VARCHAR(500), bio TEXT,
+		email_pending BOOLEAN DEFAULT 0,
+		created_at DATETIME, updated_at DATETIME)`,
+	`CREATE TABLE IF NOT EXISTS v2_boards (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		site_id VARCHAR(36) NOT NULL DEFAULT '',
+		slug VARCHAR(50), name VARCHAR(100),
+		description TEXT, category_id INTEGER,
+		settings TEXT, is_active BOOLEAN DEFAULT 1,
+		order_num INTEGER DEFAULT 0,
+		list_level INTEGER DEFAULT 0, read_level INTEGER DEFAULT 0,
+		write_level INTEGER DEFAULT 1, reply_level INTEGER DEFAULT 1,
+		comment_level INTEGER DEFAULT 1, upload_level INTEGER DEFAULT 1,
+		download_level INTEGER DEFAULT 1,
+		write_point INTEGER DEFAULT 0, comment_point INTEGER DEFAULT 0,
+		download_point INTEGER DEFAULT 0,
+		created_at DATETIME, updated_at DATETIME,
+		UNIQUE (site_id, slug))`,
+	`CREATE TABLE IF NOT EXISTS v2_posts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		site_id VARCHAR(36) NOT NULL DEFAULT '',
+		board_id INTEGER, user_id INTEGER,
+		title VARCHAR(255), content TEXT,
+		status TEXT DEFAULT 'published',
+		view_count INTEGER DEFAULT 0, comment_count INTEGER DEFAULT 0,
+		is_notice BOOLEAN DEFAULT 0, is_secret BOOLEAN DEFAULT 0,
+		deleted_at DATETIME, deleted_by INTEGER,
+		created_at DATETIME, updated_at DATETIME)`,
+	`CREATE TABLE IF NOT EXISTS v2_comments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		site_id VARCHAR(36) NOT NULL DEFAULT '',
+		post_id INTEGER, user_id INTEGER,
+		parent_id INTEGER, content TEXT,
+		depth INTEGER DEFAULT 0, status TEXT DEFAULT 'active',
+		deleted_at DATETIME, deleted_by INTEGER,
+		created_at DATETIME, updated_at DATETIME)`,
+}
+
+// openTenantTestDB opens a named in-memory SQLite DB with the tenant scope installed
+func openTenantTestDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", name)), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, ddl := range tenantContentDDL {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	require.NoError(t, repository.RegisterTenantScope(db))
+	return db
+}
+
+// seedTenantBoard creates a "free" board with one post for the given site ("" = 메인 사이트)
+func seedTenantBoard(t *testing.T, db *gorm.DB, siteID, title string) *v2domain.V2Post {
+	t.Helper()
+	tdb := db.WithContext(repository.ContextWithSiteID(context.Background(), siteID))
+
+	board := &v2domain.V2Board{Slug: "free", Name: title + " board", IsActive: true}
+	require.NoError(t, tdb.Create(board).Error)
+	post := &v2domain.V2Post{BoardID: board.ID, UserID: 1, Title: title, Content: title, Status: "published"}
+	require.NoError(t, tdb.Create(post).Error)
+	return post
+}
+
+func TestTenantIsolation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := openTenantTestDB(t, "tenant_isolation_main")
+	require.NoError(t, db.Exec(`CREATE TABLE IF NOT EXISTS sites (
+		id VARCHAR(36) PRIMARY KEY, subdomain VARCHAR(50) UNIQUE,
+		site_name VARCHAR(100), owner_email VARCHAR(255),
+		plan VARCHAR(20) DEFAULT 'free', db_strategy VARCHAR(20) DEFAULT 'shared',
+		db_schema_name VARCHAR(100), db_host VARCHAR(255), db_port INTEGER DEFAULT 3306,
+		active BOOLEAN DEFAULT 1, suspended BOOLEAN DEFAULT 0, trial_ends_at DATETIME,
+		created_at DATETIME, updated_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE IF NOT EXISTS site_settings (
+		site_id VARCHAR(50) PRIMARY KEY, custom_domain VARCHAR(255),
+		created_at DATETIME, updated_at DATETIME)`).Error)
+
+	schemaName := "tenant_isolation_gamma"
+	for _, site := range []*domain.Site{
+		{ID: "site-alpha", Subdomain: "alpha", SiteName: "Alpha", Plan: "free", DBStrategy: "shared", Active: true},
+		{ID: "site-beta", Subdomain: "beta", SiteName: "Beta", Plan: "free", DBStrategy: "shared", Active: true},
+		{ID: "site-gamma", Subdomain: "gamma", SiteName: "Gamma", Plan: "pro", DBStrategy: "schema", DBSchemaName: &schemaName, Active: true},
+	} {
+		require.NoError(t, db.Create(site).Error)
+	}
+	require.NoError(t, db.Exec(`INSERT INTO site_settings (site_id, custom_domain) VALUES (?, ?)`, "site-beta", "beta.example.org").Error)
+
+	seedTenantBoard(t, db, "", "main post")
+	alphaPost := seedTenantBoard(t, db, "site-alpha", "alpha post")
+	betaPost := seedTenantBoard(t, db, "site-beta", "beta post")
+
+	// schema 전략: 별도 DB (SQLite에서는 이름이 다른 in-memory DB)
+	schemaDB := openTenantTestDB(t, schemaName)
+	gammaPost := seedTenantBoard(t, schemaDB, "site-gamma", "gamma post")
+
+	resolver := middleware.NewTenantDBResolver(db)
+	resolver.SetConnector(func(_ string, _ int, database string) (*gorm.DB, error) {
+		if database != schemaName {
+			return nil, fmt.Errorf("unknown tenant database %q", database)
+		}
+		return schemaDB, nil
+	})
+
+	boardRepo := v2repo.NewBoardRepository(db)
+	permChecker := middleware.NewDBBoardPermissionChecker(boardRepo)
+	h := v2handler.NewV2Handler(v2repo.NewUserRepository(db), v2repo.NewPostRepository(db),
+		v2repo.NewCommentRepository(db), boardRepo, permChecker)
+
+	router := gin.New()
+	router.Use(middleware.TenantMiddleware(repository.NewSiteRepository(db), "example.com", resolver))
+	v2routes.Setup(router, h, jwt.NewManager("tenant-test-secret", 900, 86400), permChecker, db, nil)
+
+	get := func(host, path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Host = host
+		if len(header) == 2 {
+			req.Header.Set(header[0], header[1])
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	postTitles := func(w *httptest.ResponseRecorder) []string {
+		var resp struct {
+			Data []struct {
+				Title string `json:"title"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		titles := make([]string, 0, len(resp.Data))
+		for _, p := range resp.Data {
+			titles = append(titles, p.Title)
+		}
+		return titles
+	}
+
+	cases := []struct {
+		host  string
+		title string
+		own   *v2domain.V2Post
+		other *v2domain.V2Post
+	}{
+		{"alpha.example.com", "alpha post", alphaPost, betaPost},
+		{"beta.example.com", "beta post", betaPost, alphaPost},
+		{"gamma.example.com", "gamma post", gammaPost, alphaPost},
+		{"example.com", "main post", nil, betaPost},
+	}
+	for _, tc := range cases {
+		w := get(tc.host, "/api/v2/boards/free/posts")
+		require.Equal(t, http.StatusOK, w.Code, tc.host)
+		assert.Equal(t, []string{tc.title}, postTitles(w), tc.host)
+
+		w = get(tc.host, "/api/v2/boards")
+		require.Equal(t, http.StatusOK, w.Code, tc.host)
+		assert.Contains(t, w.Body.String(), tc.title+" board", tc.host)
+		assert.NotContains(t, w.Body.String(), tc.other.Title+" board", tc.host)
+
+		if tc.own != nil {
+			w = get(tc.host, fmt.Sprintf("/api/v2/boards/free/posts/%d", tc.own.ID))
+			assert.Equal(t, http.StatusOK, w.Code, tc.host)
+		}
+		w = get(tc.host, fmt.Sprintf("/api/v2/boards/free/posts/%d", tc.other.ID))
+		assert.Equal(t, http.StatusNotFound, w.Code, tc.host)
+	}
+
+	// 커스텀 도메인 (Caddy X-Custom-Domain 헤더)
+	w := get("beta.example.org", "/api/v2/boards/free/posts", "X-Custom-Domain", "beta.example.org")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"beta post"}, postTitles(w))
+
+	// 등록되지 않은 서브도메인
+	w = get("nobody.example.com", "/api/v2/boards")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -2,18 +2,32 @@ package integration
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
 	v2handler "github.com/damoang/angple-backend/internal/handler/v2"
 	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/plugin"
 	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
 	v2routes "github.com/damoang/angple-backend/internal/routes/v2"
 	v2svc "github.com/damoang/angple-backend/internal/service/v2"
 	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/damoang/angple-backend/pkg/mailer"
+	"github.com/damoang/angple-backend/pkg/totp"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -28,6 +42,8 @@ type V2APISuite struct {
 	db         *gorm.DB
 	router     *gin.Engine
 	jwtManager *jwt.Manager
+	authSvc    *v2svc.V2AuthService
+	mailDir    string
 }
 
 func TestV2APISuite(t *testing.T) {
@@ -57,10 +73,12 @@ func (s *V2APISuite) SetupSuite() {
 			nariya_max INTEGER DEFAULT 1000,
 			status TEXT DEFAULT 'active',
 			avatar_url VARCHAR(500), bio TEXT,
+			email_pending BOOLEAN DEFAULT 0,
 			created_at DATETIME, updated_at DATETIME)`,
 		`CREATE TABLE IF NOT EXISTS v2_boards (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			slug VARCHAR(50) UNIQUE, name VARCHAR(100),
+			site_id VARCHAR(36) NOT NULL DEFAULT '',
+			slug VARCHAR(50), name VARCHAR(100),
 			description TEXT, category_id INTEGER,
 			settings TEXT, is_active BOOLEAN DEFAULT 1,
 			order_num INTEGER DEFAULT 0,
@@ -70,9 +88,11 @@ func (s *V2APISuite) SetupSuite() {
 			download_level INTEGER DEFAULT 1,
 			write_point INTEGER DEFAULT 0, comment_point INTEGER DEFAULT 0,
 			download_point INTEGER DEFAULT 0,
-			created_at DATETIME, updated_at DATETIME)`,
+			created_at DATETIME, updated_at DATETIME,
+			UNIQUE (site_id, slug))`,
 		`CREATE TABLE IF NOT EXISTS v2_posts (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			site_id VARCHAR(36) NOT NULL DEFAULT '',
 			board_id INTEGER, user_id INTEGER,
 			title VARCHAR(255), content TEXT,
 			status TEXT DEFAULT 'published',
@@ -82,6 +102,7 @@ func (s *V2APISuite) SetupSuite() {
 			created_at DATETIME, updated_at DATETIME)`,
 		`CREATE TABLE IF NOT EXISTS v2_comments (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			site_id VARCHAR(36) NOT NULL DEFAULT '',
 			post_id INTEGER, user_id INTEGER,
 			parent_id INTEGER, content TEXT,
 			depth INTEGER DEFAULT 0, status TEXT DEFAULT 'active',
@@ -113,6 +134,8 @@ func (s *V2APISuite) SetupSuite() {
 	} {
 		s.Require().NoError(db.Exec(ddl).Error)
 	}
+	s.Require().NoError(db.AutoMigrate(&v2domain.V2Session{}, &v2domain.V2LoginAttempt{},
+		&v2domain.V2TwoFactor{}, &v2domain.V2RecoveryCode{}, &v2domain.V2UserToken{}))
 
 	// JWT manager
 	s.jwtManager = jwt.NewManager("test-secret-key-for-integration-tests", 900, 86400)
@@ -127,10 +150,32 @@ func (s *V2APISuite) SetupSuite() {
 	v2Handler := v2handler.NewV2Handler(userRepo, postRepo, commentRepo, boardRepo, permChecker)
 	expRepo := v2repo.NewExpRepository(db)
 	authSvc := v2svc.NewV2AuthService(userRepo, s.jwtManager, expRepo)
+	authSvc.SetSessionRepository(v2repo.NewSessionRepository(db))
+	authSvc.SetExternalAccountResolver(oauthAccounts{"oauth_kakao_42": "카카오회원"})
+	s.authSvc = authSvc
+	authSvc.SetLoginProtection(nil, v2repo.NewLoginAttemptRepository(db))
+	authSvc.SetTwoFactorService(v2svc.NewTwoFactorService(v2repo.NewTwoFactorRepository(db), s.jwtManager, "Angple"))
+	authSvc.SetPromotionDeps(db, nil)
+	s.mailDir = s.T().TempDir()
+	fileMailer, err := mailer.NewFileMailer(mailer.FileConfig{Dir: s.mailDir}, "Angple <noreply@example.com>")
+	s.Require().NoError(err)
+	authSvc.SetRegistration(v2repo.NewUserTokenRepository(db), fileMailer, v2svc.RegistrationPolicy{
+		RequireEmailVerification: true,
+		LinkBaseURL:              "https://example.com",
+		BlockedEmailDomains:      []string{"spam.example"},
+	})
+	hooks := plugin.NewHookManager(plugin.NewDefaultLogger("test"))
+	hooks.Register(plugin.HookUserBeforeRegister, "signup-guard", func(ctx *plugin.HookContext) error {
+		if ctx.Input["nickname"] == "스팸봇" {
+			return plugin.Reject("가입이 차단되었습니다")
+		}
+		return nil
+	}, 10)
+	authSvc.SetHookManager(hooks)
 	authHandler := v2handler.NewV2AuthHandler(authSvc)
 
 	s.router = gin.New()
-	v2routes.Setup(s.router, v2Handler, s.jwtManager, permChecker, s.db)
+	v2routes.Setup(s.router, v2Handler, s.jwtManager, permChecker, s.db, nil)
 	v2routes.SetupAuth(s.router, authHandler, s.jwtManager)
 
 	// Seed test data
@@ -149,6 +194,14 @@ func (s *V2APISuite) seedTestData() {
 		Status:   "active",
 	}
 	s.db.Create(user)
+	s.db.Create(&v2domain.V2User{
+		Username: "otpuser",
+		Email:    "otp@example.com",
+		Password: string(hashed),
+		Nickname: "OTPUser",
+		Level:    10,
+		Status:   "active",
+	})
 
 	board := &v2domain.V2Board{
 		Slug:     "free",
@@ -214,6 +267,11 @@ func (s *V2APISuite) TestLogin_InvalidPassword() {
 	s.router.ServeHTTP(w, req)
 
 	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+
+	var attempt v2domain.V2LoginAttempt
+	s.Require().NoError(s.db.Where("username = ?", "testuser").Order("id DESC").First(&attempt).Error)
+	assert.Equal(s.T(), v2domain.LoginResultFailed, attempt.Result)
+	assert.NotNil(s.T(), attempt.UserID)
 }
 
 func (s *V2APISuite) TestLogin_NonexistentUser() {
@@ -230,6 +288,460 @@ func (s *V2APISuite) TestLogin_NonexistentUser() {
 	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
 }
 
+// authRequest sends an auth request with an optional refresh_token cookie and returns the new cookie value
+func (s *V2APISuite) authRequest(method, path, refreshToken string, body interface{}) (*httptest.ResponseRecorder, string) {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if refreshToken != "" {
+		req.AddCookie(&http.Cookie{Name: "refresh_token", Value: refreshToken})
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "refresh_token" {
+			return w, c.Value
+		}
+	}
+	return w, ""
+}
+
+func (s *V2APISuite) TestRefresh_RotationAndReuseDetection() {
+	w, first := s.authRequest(http.MethodPost, "/api/v2/auth/login", "", map[string]string{
+		"username": "testuser", "password": "password123",
+	})
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.Require().NotEmpty(first)
+
+	w, second := s.authRequest(http.MethodPost, "/api/v2/auth/refresh", first, nil)
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.Require().NotEmpty(second)
+	assert.NotEqual(s.T(), first, second, "refresh token should rotate")
+
+	// 회전된 이전 토큰 재사용 → 세션 전체 폐기, 최신 토큰도 무효
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/refresh", first, nil)
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/refresh", second, nil)
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+}
+
+func (s *V2APISuite) TestRefresh_RejectsTokensWithoutSession() {
+	countSessions := func() int64 {
+		var count int64
+		s.Require().NoError(s.db.Model(&v2domain.V2Session{}).Where("user_id = ?", 1).Count(&count).Error)
+		return count
+	}
+	before := countSessions()
+	legacy, err := s.jwtManager.GenerateRefreshToken("1")
+	s.Require().NoError(err)
+	w, _ := s.authRequest(http.MethodPost, "/api/v2/auth/refresh", legacy, nil)
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+	assert.Equal(s.T(), before, countSessions(), "a session-less token must not mint a new session")
+}
+
+// oauthAccounts resolves OAuth subjects to nicknames for token refresh
+type oauthAccounts map[string]string
+
+func (a oauthAccounts) ExternalAccount(subject string) (string, int, error) {
+	nickname, ok := a[subject]
+	if !ok {
+		return "", 0, gorm.ErrRecordNotFound
+	}
+	return nickname, 1, nil
+}
+
+func (s *V2APISuite) TestRefresh_OAuthSessionRotation() {
+	first, err := s.authSvc.IssueExternalRefreshToken("oauth_kakao_42", v2svc.SessionClient{UserAgent: "test"}, true)
+	s.Require().NoError(err)
+
+	w, second := s.authRequest(http.MethodPost, "/api/v2/auth/refresh", first, nil)
+	s.Require().Equal(http.StatusOK, w.Code)
+	var refreshed struct {
+		Data struct {
+			AccessToken string `json:"access_token"`
+		} `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &refreshed))
+	claims, err := s.jwtManager.VerifyToken(refreshed.Data.AccessToken)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "oauth_kakao_42", claims.UserID)
+	assert.True(s.T(), claims.MFA, "2FA status should survive the refresh")
+
+	// 회전된 이전 토큰 재사용 → 세션 폐기
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/refresh", first, nil)
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/refresh", second, nil)
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+}
+
+func (s *V2APISuite) TestLogout_RevokesSession() {
+	w, token := s.authRequest(http.MethodPost, "/api/v2/auth/login", "", map[string]string{
+		"username": "testuser", "password": "password123",
+	})
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/logout", token, nil)
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/refresh", token, nil)
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+}
+
+func (s *V2APISuite) TestSessions_ListAndRevoke() {
+	login := map[string]string{"username": "testuser", "password": "password123"}
+	w, current := s.authRequest(http.MethodPost, "/api/v2/auth/login", "", login)
+	s.Require().Equal(http.StatusOK, w.Code)
+	var loginResp struct {
+		Data struct {
+			AccessToken string `json:"access_token"`
+		} `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &loginResp))
+	_, other := s.authRequest(http.MethodPost, "/api/v2/auth/login", "", login)
+
+	list := func() []map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/me/sessions", nil)
+		req.Header.Set("Authorization", "Bearer "+loginResp.Data.AccessToken)
+		req.AddCookie(&http.Cookie{Name: "refresh_token", Value: current})
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		s.Require().Equal(http.StatusOK, w.Code)
+		var resp struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	claims, err := s.jwtManager.VerifyToken(other)
+	s.Require().NoError(err)
+	otherID := claims.SessionID
+
+	sessions := list()
+	currentCount, found := 0, false
+	for _, session := range sessions {
+		if session["current"].(bool) {
+			currentCount++
+		}
+		found = found || session["id"] == otherID
+	}
+	assert.Equal(s.T(), 1, currentCount)
+	assert.True(s.T(), found)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v2/me/sessions/"+otherID, nil)
+	req.Header.Set("Authorization", "Bearer "+loginResp.Data.AccessToken)
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusOK, w.Code)
+	assert.Len(s.T(), list(), len(sessions)-1)
+
+	// 종료된 기기의 토큰으로는 갱신 불가
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/refresh", other, nil)
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+}
+
+// twoFactorData is the union of the 2FA-related response payloads
+type twoFactorData struct {
+	AccessToken       string   `json:"access_token"`
+	Secret            string   `json:"secret"`
+	OTPAuthURI        string   `json:"otpauth_uri"`
+	RecoveryCodes     []string `json:"recovery_codes"`
+	TwoFactorRequired bool     `json:"two_factor_required"`
+	ChallengeToken    string   `json:"challenge_token"`
+	Enabled           bool     `json:"enabled"`
+	RecoveryRemaining int      `json:"recovery_codes_remaining"`
+}
+
+// twoFactorCall sends a JSON request with an optional bearer token and returns status, data and refresh cookie
+func (s *V2APISuite) twoFactorCall(method, path, bearer string, body interface{}) (int, twoFactorData, string) {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+
+	var resp struct {
+		Data twoFactorData `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	var cookie string
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "refresh_token" {
+			cookie = c.Value
+		}
+	}
+	return w.Code, resp.Data, cookie
+}
+
+func (s *V2APISuite) TestTwoFactor_EnrollAndLogin() {
+	login := map[string]string{"username": "otpuser", "password": "password123"}
+	verify := func(challenge, code string) (int, twoFactorData, string) {
+		return s.twoFactorCall(http.MethodPost, "/api/v2/auth/2fa/verify", "",
+			map[string]string{"challenge_token": challenge, "code": code})
+	}
+
+	// 등록: setup → 첫 OTP로 enable → 복구 코드 10개
+	status, data, _ := s.twoFactorCall(http.MethodPost, "/api/v2/auth/login", "", login)
+	s.Require().Equal(http.StatusOK, status)
+	access := data.AccessToken
+
+	status, data, _ = s.twoFactorCall(http.MethodPost, "/api/v2/me/2fa/setup", access, nil)
+	s.Require().Equal(http.StatusOK, status)
+	secret := data.Secret
+	assert.Contains(s.T(), data.OTPAuthURI, "otpauth://totp/Angple:otpuser?")
+
+	now := totp.Step(time.Now())
+	first, _ := totp.Code(secret, now)
+	status, data, _ = s.twoFactorCall(http.MethodPost, "/api/v2/me/2fa/enable", access, map[string]string{"code": first})
+	s.Require().Equal(http.StatusOK, status)
+	s.Require().Len(data.RecoveryCodes, 10)
+	recovery := data.RecoveryCodes
+
+	// 비밀번호만으로는 토큰 대신 challenge 발급
+	status, data, cookie := s.twoFactorCall(http.MethodPost, "/api/v2/auth/login", "", login)
+	s.Require().Equal(http.StatusOK, status)
+	s.Require().True(data.TwoFactorRequired)
+	assert.Empty(s.T(), data.AccessToken)
+	assert.Empty(s.T(), cookie)
+	challenge := data.ChallengeToken
+
+	// challenge 토큰은 액세스/리프레시 토큰으로 쓸 수 없음
+	status, _, _ = s.twoFactorCall(http.MethodGet, "/api/v2/auth/me", challenge, nil)
+	assert.Equal(s.T(), http.StatusUnauthorized, status)
+	w, _ := s.authRequest(http.MethodPost, "/api/v2/auth/refresh", challenge, nil)
+	assert.Equal(s.T(), http.StatusUnauthorized, w.Code)
+
+	// 등록 때 사용한 OTP 재사용 거부
+	status, _, _ = verify(challenge, first)
+	assert.Equal(s.T(), http.StatusUnauthorized, status)
+
+	next, _ := totp.Code(secret, now+1)
+	status, data, cookie = verify(challenge, next)
+	s.Require().Equal(http.StatusOK, status)
+	claims, err := s.jwtManager.VerifyToken(data.AccessToken)
+	s.Require().NoError(err)
+	assert.True(s.T(), claims.MFA)
+
+	// 리프레시 후에도 2단계 인증 여부 유지
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/refresh", cookie, nil)
+	s.Require().Equal(http.StatusOK, w.Code)
+	var refreshed struct {
+		Data twoFactorData `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &refreshed))
+	claims, err = s.jwtManager.VerifyToken(refreshed.Data.AccessToken)
+	s.Require().NoError(err)
+	assert.True(s.T(), claims.MFA)
+
+	// 복구 코드는 한 번만 사용 가능
+	status, _, _ = verify(challenge, recovery[0])
+	s.Require().Equal(http.StatusOK, status)
+	status, _, _ = verify(challenge, recovery[0])
+	assert.Equal(s.T(), http.StatusUnauthorized, status)
+
+	status, data, _ = s.twoFactorCall(http.MethodGet, "/api/v2/me/2fa", access, nil)
+	s.Require().Equal(http.StatusOK, status)
+	assert.True(s.T(), data.Enabled)
+	assert.Equal(s.T(), 9, data.RecoveryRemaining)
+
+	// 해제 후에는 비밀번호만으로 로그인
+	status, _, _ = s.twoFactorCall(http.MethodDelete, "/api/v2/me/2fa", access, map[string]string{"code": recovery[1]})
+	s.Require().Equal(http.StatusOK, status)
+	status, data, _ = s.twoFactorCall(http.MethodPost, "/api/v2/auth/login", "", login)
+	s.Require().Equal(http.StatusOK, status)
+	assert.False(s.T(), data.TwoFactorRequired)
+	assert.NotEmpty(s.T(), data.AccessToken)
+}
+
+// mailedToken returns the token from the most recent mail in the file-drop directory sent to "to"
+func (s *V2APISuite) mailedToken(to, path string) string {
+	s.authSvc.WaitPendingMail()
+	files, err := filepath.Glob(filepath.Join(s.mailDir, "*.eml"))
+	s.Require().NoError(err)
+	sort.Sort(sort.Reverse(sort.StringSlice(files)))
+	pattern := regexp.MustCompile(regexp.QuoteMeta(path) + `\?token=([0-9a-f]{64})`)
+	for _, name := range files {
+		f, err := os.Open(name)
+		s.Require().NoError(err)
+		msg, err := mail.ReadMessage(f)
+		s.Require().NoError(err)
+		body, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, msg.Body))
+		_ = f.Close() //nolint:errcheck // read-only file
+		s.Require().NoError(err)
+		if msg.Header.Get("To") != "<"+to+">" {
+			continue
+		}
+		if m := pattern.FindSubmatch(body); m != nil {
+			return string(m[1])
+		}
+	}
+	s.FailNow("no mail with " + path + " sent to " + to)
+	return ""
+}
+
+func (s *V2APISuite) TestRegister_VerifyEmailAndLogin() {
+	register := map[string]string{"username": "newbie", "nickname": "새내기", "email": "Newbie@Example.com", "password": "s3cret-pass"}
+	w, _ := s.authRequest(http.MethodPost, "/api/v2/auth/register", "", register)
+	s.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	var resp struct {
+		Data struct {
+			User             v2domain.V2User `json:"user"`
+			VerificationSent bool            `json:"verification_sent"`
+		} `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(s.T(), resp.Data.VerificationSent)
+	assert.Equal(s.T(), "newbie@example.com", resp.Data.User.Email)
+	assert.EqualValues(s.T(), 2, resp.Data.User.Level)
+
+	// 메일 인증 전에는 로그인 불가
+	login := map[string]string{"username": "newbie", "password": "s3cret-pass"}
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/login", "", login)
+	assert.Equal(s.T(), http.StatusForbidden, w.Code)
+	assert.Contains(s.T(), w.Body.String(), "EMAIL_NOT_VERIFIED")
+
+	// 재발송하면 이전 토큰은 무효
+	old := s.mailedToken("newbie@example.com", "/auth/verify-email")
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/verify-email/resend", "", map[string]string{"email": "newbie@example.com"})
+	s.Require().Equal(http.StatusOK, w.Code)
+	token := s.mailedToken("newbie@example.com", "/auth/verify-email")
+	s.Require().NotEqual(old, token)
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/verify-email", "", map[string]string{"token": old})
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/verify-email", "", map[string]string{"token": token})
+	s.Require().Equal(http.StatusOK, w.Code)
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/verify-email", "", map[string]string{"token": token})
+	assert.Equal(s.T(), http.StatusBadRequest, w.Code, "verification token is single use")
+
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/login", "", login)
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+}
+
+func (s *V2APISuite) TestRegister_Rules() {
+	s.Require().NoError(s.db.Exec("INSERT INTO g5_member (mb_id, mb_nick) VALUES ('legacyid', '옛회원')").Error)
+	valid := func(overrides map[string]string) map[string]string {
+		body := map[string]string{"username": "ruleuser", "nickname": "규칙회원", "email": "rule@example.com", "password": "s3cret-pass"}
+		for k, v := range overrides {
+			body[k] = v
+		}
+		return body
+	}
+	cases := []struct {
+		name   string
+		body   map[string]string
+		status int
+		field  string
+	}{
+		{"short username", valid(map[string]string{"username": "ab"}), http.StatusBadRequest, "username"},
+		{"username symbols", valid(map[string]string{"username": "bad-id"}), http.StatusBadRequest, "username"},
+		{"reserved username", valid(map[string]string{"username": "Admin"}), http.StatusBadRequest, "username"},
+		{"taken username", valid(map[string]string{"username": "testuser"}), http.StatusConflict, "username"},
+		{"legacy username", valid(map[string]string{"username": "legacyid"}), http.StatusConflict, "username"},
+		{"one hangul nickname", valid(map[string]string{"nickname": "김"}), http.StatusBadRequest, "nickname"},
+		{"nickname with space", valid(map[string]string{"nickname": "새 회원"}), http.StatusBadRequest, "nickname"},
+		{"reserved nickname", valid(map[string]string{"nickname": "관리자"}), http.StatusBadRequest, "nickname"},
+		{"taken nickname", valid(map[string]string{"nickname": "TestUser"}), http.StatusConflict, "nickname"},
+		{"legacy nickname", valid(map[string]string{"nickname": "옛회원"}), http.StatusConflict, "nickname"},
+		{"bad email", valid(map[string]string{"email": "Rule <rule@example.com>"}), http.StatusBadRequest, "email"},
+		{"blocked domain", valid(map[string]string{"email": "rule@spam.example"}), http.StatusBadRequest, "email"},
+		{"taken email", valid(map[string]string{"email": "test@example.com"}), http.StatusConflict, "email"},
+		{"short password", valid(map[string]string{"password": "short"}), http.StatusBadRequest, "password"},
+	}
+	for _, tc := range cases {
+		w, _ := s.authRequest(http.MethodPost, "/api/v2/auth/register", "", tc.body)
+		s.Equal(tc.status, w.Code, tc.name)
+		s.Contains(w.Body.String(), `"field":"`+tc.field+`"`, tc.name)
+	}
+
+	// 플러그인 Before Hook 거부
+	w, _ := s.authRequest(http.MethodPost, "/api/v2/auth/register", "", valid(map[string]string{"nickname": "스팸봇"}))
+	s.Equal(http.StatusForbidden, w.Code)
+	s.Contains(w.Body.String(), "가입이 차단되었습니다")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/auth/register/check?nickname=TestUser", nil)
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), `"can_register":false`)
+
+	// 인증 없는 확인 API는 이메일 가입 여부를 알려주지 않음 (형식만 검사)
+	for email, want := range map[string]string{"test@example.com": `"can_register":true`, "rule@spam.example": `"can_register":false`} {
+		req = httptest.NewRequest(http.MethodGet, "/api/v2/auth/register/check?email="+email, nil)
+		w = httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		s.Equal(http.StatusOK, w.Code, email)
+		s.Contains(w.Body.String(), want, email)
+	}
+}
+
+// staleUserRepo misses a member created after the sign-up check (동시 가입 경합 재현)
+type staleUserRepo struct {
+	v2repo.UserRepository
+}
+
+func (staleUserRepo) FindByUsername(string) (*v2domain.V2User, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *V2APISuite) TestRegister_ConcurrentDuplicateIsConflict() {
+	authSvc := v2svc.NewV2AuthService(staleUserRepo{v2repo.NewUserRepository(s.db)}, s.jwtManager, nil)
+	authSvc.SetRegistration(v2repo.NewUserTokenRepository(s.db), nil, v2svc.RegistrationPolicy{})
+	_, err := authSvc.Register(&v2svc.RegisterRequest{
+		Username: "testuser", Nickname: "경합회원", Email: "race@example.com", Password: "s3cret-pass",
+	})
+	var regErr *v2svc.RegistrationError
+	s.Require().ErrorAs(err, &regErr)
+	s.True(regErr.Conflict, "a unique index violation should be reported as a conflict, not a server error")
+}
+
+func (s *V2APISuite) TestPasswordReset_RevokesSessions() {
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.DefaultCost)
+	s.Require().NoError(s.db.Create(&v2domain.V2User{
+		Username: "forgetful", Email: "forgetful@example.com", Password: string(hashed), Nickname: "깜빡이", Level: 2, Status: "active",
+	}).Error)
+	w, session := s.authRequest(http.MethodPost, "/api/v2/auth/login", "", map[string]string{"username": "forgetful", "password": "old-password"})
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	// 가입 여부와 관계없이 같은 응답
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/password/forgot", "", map[string]string{"email": "nobody@example.com"})
+	s.Equal(http.StatusOK, w.Code)
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/password/forgot", "", map[string]string{"email": "forgetful@example.com"})
+	s.Require().Equal(http.StatusOK, w.Code)
+	token := s.mailedToken("forgetful@example.com", "/auth/reset-password")
+
+	// 비밀번호 규칙 위반은 토큰을 소모하지 않음
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/password/reset", "", map[string]string{"token": token, "password": "short"})
+	s.Equal(http.StatusBadRequest, w.Code)
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/password/reset", "", map[string]string{"token": token, "password": "new-password"})
+	s.Require().Equal(http.StatusOK, w.Code)
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/password/reset", "", map[string]string{"token": token, "password": "another-password"})
+	s.Equal(http.StatusBadRequest, w.Code, "reset token is single use")
+
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/refresh", session, nil)
+	s.Equal(http.StatusUnauthorized, w.Code, "existing sessions are revoked")
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/login", "", map[string]string{"username": "forgetful", "password": "old-password"})
+	s.Equal(http.StatusUnauthorized, w.Code)
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/login", "", map[string]string{"username": "forgetful", "password": "new-password"})
+	s.Equal(http.StatusOK, w.Code)
+
+	// 만료된 토큰
+	expired := strings.Repeat("ab", 32)
+	sum := sha256.Sum256([]byte(expired))
+	s.Require().NoError(s.db.Create(&v2domain.V2UserToken{
+		UserID: 1, Purpose: v2domain.UserTokenResetPassword, TokenHash: hex.EncodeToString(sum[:]), ExpiresAt: time.Now().Add(-time.Minute),
+	}).Error)
+	w, _ = s.authRequest(http.MethodPost, "/api/v2/auth/password/reset", "", map[string]string{"token": expired, "password": "new-password"})
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
 // --- Board Tests ---
 
 func (s *V2APISuite) TestListBoards() {
@@ -312,6 +824,30 @@ func (s *V2APISuite) TestCreatePost_Authenticated() {
 	assert.Equal(s.T(), http.StatusCreated, w.Code)
 }
 
+func (s *V2APISuite) TestUpdatePost_AdminRequiresTwoFactor() {
+	middleware.SetAdminMFARequired(true)
+	defer middleware.SetAdminMFARequired(false)
+
+	update := func(token string) int {
+		body, _ := json.Marshal(map[string]string{"title": "Moderated"})
+		req := httptest.NewRequest(http.MethodPut, "/api/v2/boards/free/posts/1", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 관리자 등급이어도 2단계 인증 없이 발급된 토큰으로는 남의 글을 수정할 수 없다
+	token, err := s.jwtManager.GenerateAccessToken("2", "otpuser", "OTPUser", 10)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), http.StatusForbidden, update(token))
+
+	token, err = s.jwtManager.GenerateMFAAccessToken("2", "otpuser", "OTPUser", 10)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), http.StatusOK, update(token))
+}
+
 // --- Comment Tests ---
 
 func (s *V2APISuite) TestListComments() {